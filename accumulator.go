@@ -0,0 +1,154 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// PairingAccumulator collects verification statements, with any keys and
+// messages, and checks them all at Flush. Every statement
+// e(sigma_1, X_acc) = e(sigma_2, g) is weighted with a fresh scalar r_i, and
+// the product
+//
+//	e(r_1*sigma_(1,1), X_acc,1) * ... * e(r_n*sigma_(1,n), X_acc,n) * e(-(sum r_i*sigma_(2,i)), g)
+//
+// is compared with the identity in a single multi-pairing where the suite
+// is a PairingChecker. If it fails, the statements are checked one by one
+// to find the invalid ones. As for VerifyBatch, the weights must be
+// unpredictable to the signers, so the random stream is an argument.
+//
+// Statements are numbered from 0 in the order they were added. A
+// PairingAccumulator must not be used by several goroutines at once.
+type PairingAccumulator struct {
+	suite      pairing.Suite
+	rand       cipher.Stream
+	o          *options
+	statements []accumulated
+}
+
+// accumulated is a statement of a PairingAccumulator: the signature and the
+// key point X_acc it is checked against. Statements that cannot hold, such
+// as signatures with identity components, are marked bad when added.
+type accumulated struct {
+	X   kyber.Point
+	sig *Signature
+	bad bool
+}
+
+// NewAccumulator returns an empty PairingAccumulator drawing its weights
+// from rand. Of the options, only WithConfig applies: it bounds the number
+// of statements held between flushes.
+func NewAccumulator(suite pairing.Suite, rand cipher.Stream, opts ...Option) (*PairingAccumulator, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if rand == nil {
+		return nil, fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	return &PairingAccumulator{suite: suite, rand: rand, o: o}, nil
+}
+
+// AddVerify adds the statement Verify checks. Invalid arguments, including
+// malformed signature encodings, are reported at once and the statement is
+// not added.
+func (a *PairingAccumulator) AddVerify(pubKey []kyber.Point, msg []byte, S [][]byte, opts ...Option) error {
+	if err := checkMessages(msg); err != nil {
+		return err
+	}
+	return a.AddBatchVerify(pubKey, [][]byte{msg}, S, opts...)
+}
+
+// AddBatchVerify adds the statement PSBatchVerify checks, with the same
+// options. Once the accumulator holds MaxBatchItems statements, it returns
+// ErrLimitExceeded until the next Flush.
+func (a *PairingAccumulator) AddBatchVerify(pubKey []kyber.Point, msgs [][]byte, S [][]byte, opts ...Option) error {
+	if err := a.o.checkBatchItems(len(a.statements) + 1); err != nil {
+		return err
+	}
+	m, o, err := batchVerifyScalars(a.suite, pubKey, msgs, S == nil, opts)
+	if err != nil {
+		return err
+	}
+	sig, err := parseSignature(a.suite, S, o.validatePoints)
+	if err != nil {
+		return err
+	}
+	Y, err := multiScalarMul(a.suite.G2(), m, pubKey[1:len(m)+1])
+	if err != nil {
+		return err
+	}
+	g1 := a.suite.G1()
+	a.statements = append(a.statements, accumulated{
+		X:   a.suite.G2().Point().Add(Y, pubKey[0]),
+		sig: sig,
+		bad: isIdentity(g1, sig.Sigma1) || isIdentity(g1, sig.Sigma2),
+	})
+	return nil
+}
+
+// Len returns the number of statements added since the last Flush.
+func (a *PairingAccumulator) Len() int {
+	return len(a.statements)
+}
+
+// Flush checks the statements added since the last Flush and empties the
+// accumulator. It reports whether all of them hold and, if not, the
+// increasing indices of those that do not. An empty accumulator holds.
+func (a *PairingAccumulator) Flush() (bool, []int) {
+	statements := a.statements
+	a.statements = nil
+
+	var bad []int
+	for i, st := range statements {
+		if st.bad {
+			bad = append(bad, i)
+		}
+	}
+	if len(bad) == 0 && a.holds(statements) {
+		return true, nil
+	}
+	bad = bad[:0]
+	g := a.suite.G2().Point().Base()
+	for i, st := range statements {
+		if st.bad || verifySignature(a.suite, st.X, g, st.sig) != nil {
+			bad = append(bad, i)
+		}
+	}
+	return len(bad) == 0, bad
+}
+
+// holds checks the weighted product of statements. A failure to draw the
+// weights counts as a failed check, leaving the statements to be checked one
+// by one.
+func (a *PairingAccumulator) holds(statements []accumulated) bool {
+	if len(statements) == 0 {
+		return true
+	}
+	g1 := a.suite.G1()
+	p1 := make([]kyber.Point, 0, len(statements)+1)
+	p2 := make([]kyber.Point, 0, len(statements)+1)
+	s2 := g1.Point().Null()
+	for _, st := range statements {
+		r, err := pickScalar(a.suite, a.rand)
+		if err != nil {
+			return false
+		}
+		p1 = append(p1, g1.Point().Mul(r, st.sig.Sigma1))
+		p2 = append(p2, st.X)
+		s2.Add(s2, g1.Point().Mul(r, st.sig.Sigma2))
+	}
+	p1 = append(p1, s2.Neg(s2))
+	p2 = append(p2, a.suite.G2().Point().Base())
+	if pc, ok := a.suite.(PairingChecker); ok {
+		return pc.PairingCheck(p1, p2)
+	}
+	product := a.suite.GT().Point().Null()
+	for i := range p1 {
+		product.Add(product, a.suite.Pair(p1[i], p2[i]))
+	}
+	return product.Equal(a.suite.GT().Point().Null())
+}
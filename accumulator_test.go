@@ -0,0 +1,117 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestPairingAccumulator(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("tx 1"), []byte("tx 2"), []byte("tx 3")}
+		privA, pubA := newTestKeys(t, suite, 1)
+		privB, pubB := newTestKeys(t, suite, len(msgs))
+
+		acc, err := NewAccumulator(suite, random.New())
+		require.Nil(t, err)
+		ok, bad := acc.Flush()
+		require.True(t, ok)
+		require.Nil(t, bad)
+
+		var statements int
+		add := func(good bool) {
+			msg := msgs[statements%len(msgs)]
+			S, err := Sign(suite, privA.Scalars(), msg)
+			require.Nil(t, err)
+			if !good {
+				msg = []byte("tx 0")
+			}
+			require.Nil(t, acc.AddVerify(pubA.Points(), msg, S))
+			S, err = BatchSign(suite, privB.Scalars(), msgs)
+			require.Nil(t, err)
+			require.Nil(t, acc.AddBatchVerify(pubB.Points(), msgs, S))
+			statements += 2
+		}
+		for i := 0; i < 5; i++ {
+			add(true)
+		}
+		require.Equal(t, statements, acc.Len())
+		ok, bad = acc.Flush()
+		require.True(t, ok)
+		require.Nil(t, bad)
+		require.Equal(t, 0, acc.Len())
+
+		// One bad statement among good ones is singled out.
+		statements = 0
+		add(true)
+		add(false)
+		add(true)
+		ok, bad = acc.Flush()
+		require.False(t, ok)
+		require.Equal(t, []int{2}, bad)
+	})
+}
+
+func TestPairingAccumulatorInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("tx 1")
+		priv, pub := newTestKeys(t, suite, 1)
+		S, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+
+		_, err = NewAccumulator(suite, nil)
+		require.True(t, errors.Is(err, ErrEntropyFailure))
+		acc, err := NewAccumulator(suite, random.New())
+		require.Nil(t, err)
+
+		// Malformed statements are refused when added.
+		require.Equal(t, ErrEmptyMessage, acc.AddVerify(pub.Points(), nil, S))
+		require.Equal(t, ErrNilSignature, acc.AddVerify(pub.Points(), msg, nil))
+		err = acc.AddVerify(pub.Points(), msg, [][]byte{S[0]})
+		require.True(t, errors.Is(err, ErrMalformedSignature))
+		err = acc.AddBatchVerify(pub.Points(), [][]byte{msg, msg}, S)
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Equal(t, 0, acc.Len())
+
+		// An identity signature holds for every key and message, so it is
+		// flagged when added rather than weighted into the product.
+		null, err := suite.G1().Point().Null().MarshalBinary()
+		require.Nil(t, err)
+		require.Nil(t, acc.AddVerify(pub.Points(), msg, S))
+		require.Nil(t, acc.AddVerify(pub.Points(), msg, [][]byte{null, null}))
+		ok, bad := acc.Flush()
+		require.False(t, ok)
+		require.Equal(t, []int{1}, bad)
+	})
+}
+
+func BenchmarkPairingAccumulator(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msg := []byte("tx 1")
+		priv, pub := newTestKeys(b, suite, 1)
+		sigs := make([][][]byte, 32)
+		for i := range sigs {
+			sigs[i], _ = Sign(suite, priv.Scalars(), msg)
+		}
+		acc, _ := NewAccumulator(suite, random.New())
+
+		b.Run("Flush", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, S := range sigs {
+					acc.AddVerify(pub.Points(), msg, S)
+				}
+				acc.Flush()
+			}
+		})
+		b.Run("Verify", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, S := range sigs {
+					Verify(suite, pub.Points(), msg, S)
+				}
+			}
+		})
+	})
+}
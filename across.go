@@ -0,0 +1,112 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Sequential aggregation across signers lets independent key pairs each add a
+// message to one signature. Signer i signs its message m_i with x_i and the
+// first slot y_i of its own key, so that after k signers
+//
+//	(sigma_1, sigma_2) = (h, h^(x_1 + ... + x_k + y_1*m_1 + ... + y_k*m_k)),
+//
+// which AggregateVerify checks with e(sigma_1, X_1 * ... * X_k * Y_1^(m_1) *
+// ... * Y_k^(m_k)) = e(sigma_2, g), pairing message i with the first Y of key
+// i. Like AggregatePSSign, each step re-randomizes the signature.
+
+// AggregateSignAcross adds msg, signed with x and y_1 of priKey, to the
+// aggregate S of previous signers. A nil S starts a new aggregate.
+func AggregateSignAcross(suite pairing.Suite, priKey *PrivateKey, S *Signature, msg []byte, opts ...Option) (*Signature, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allow(OpAggregate, 0); err != nil {
+		return nil, err
+	}
+	if err := checkMessages(msg); err != nil {
+		return nil, err
+	}
+	g1 := suite.G1()
+	var s1, s2 kyber.Point
+	if S == nil {
+		if s1, err = pickPoint(g1, suite.RandomStream()); err != nil {
+			return nil, err
+		}
+		s2 = g1.Point().Null()
+	} else {
+		comps, err := S.Components()
+		if err != nil {
+			return nil, err
+		}
+		prev, err := parseSignature(suite, comps, o.validatePoints)
+		if err != nil {
+			return nil, err
+		}
+		if isIdentity(g1, prev.Sigma1) {
+			return nil, ErrInvalidSignature
+		}
+		s1, s2 = prev.Sigma1, prev.Sigma2
+	}
+	t, err := pickScalar(suite, suite.RandomStream())
+	if err != nil {
+		return nil, err
+	}
+	// (sigma_1^t, (sigma_2 * sigma_1^(x + y*m))^t)
+	e := newScalar(suite).Mul(priKey.Y[0], hashToScalar(suite, o.dst, msg))
+	e.Add(e, priKey.X)
+	s2 = g1.Point().Add(s2, g1.Point().Mul(e, s1))
+	return &Signature{suite: suite, Sigma1: g1.Point().Mul(t, s1), Sigma2: s2.Mul(t, s2)}, nil
+}
+
+// AggregateVerify checks an aggregate made by AggregateSignAcross, where
+// msgs[i] was signed by the owner of pubKeys[i]. It returns
+// ErrKeyLengthMismatch unless there is one key per message. With
+// WithPossessionProofs it also checks a proof of possession per key.
+func AggregateVerify(suite pairing.Suite, pubKeys []*PublicKey, msgs [][]byte, S *Signature, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return ErrNoMessages
+	}
+	if len(pubKeys) != len(msgs) {
+		return fmt.Errorf("%w: %d keys for %d messages", ErrKeyLengthMismatch, len(pubKeys), len(msgs))
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return err
+	}
+	X := suite.G2().Point().Null()
+	for i, pub := range pubKeys {
+		if err := pub.check(); err != nil {
+			return fmt.Errorf("%w: key %d", err, i)
+		}
+		X.Add(X, pub.X)
+		X.Add(X, suite.G2().Point().Mul(hashToScalar(suite, o.dst, msgs[i]), pub.Y[0]))
+	}
+	if err := o.checkPossession(suite, pubKeys); err != nil {
+		return err
+	}
+	comps, err := S.Components()
+	if err != nil {
+		return err
+	}
+	sig, err := parseSignature(suite, comps, o.validatePoints)
+	if err != nil {
+		return err
+	}
+	if isIdentity(suite.G1(), sig.Sigma1) || isIdentity(suite.G1(), sig.Sigma2) {
+		return ErrInvalidSignature
+	}
+	if !suite.Pair(sig.Sigma1, X).Equal(suite.Pair(sig.Sigma2, suite.G2().Point().Base())) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestAggregateAcross(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("issued by org A"), []byte("endorsed by org B"), []byte("approved by org C")}
+		var pubs []*PublicKey
+		var agg *Signature
+		for i, msg := range msgs {
+			priv, pub := newTestKeys(t, suite, 1)
+			pubs = append(pubs, pub)
+			var err error
+			agg, err = AggregateSignAcross(suite, priv, agg, msg)
+			require.Nil(t, err)
+			require.Nil(t, AggregateVerify(suite, pubs, msgs[:i+1], agg))
+		}
+
+		// Serialized aggregates verify too.
+		buf, err := agg.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalSignature(suite, buf)
+		require.Nil(t, err)
+		require.Nil(t, AggregateVerify(suite, pubs, msgs, dec))
+
+		// Flipping a message, swapping keys or dropping a signer fails.
+		flipped := [][]byte{msgs[0], []byte("endorsed by org X"), msgs[2]}
+		require.Equal(t, ErrInvalidSignature, AggregateVerify(suite, pubs, flipped, agg))
+		swapped := []*PublicKey{pubs[1], pubs[0], pubs[2]}
+		require.Equal(t, ErrInvalidSignature, AggregateVerify(suite, swapped, msgs, agg))
+		require.Equal(t, ErrInvalidSignature, AggregateVerify(suite, pubs[:2], msgs[:2], agg))
+
+		err = AggregateVerify(suite, pubs[:2], msgs, agg)
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Equal(t, ErrNoMessages, AggregateVerify(suite, nil, nil, agg))
+	})
+}
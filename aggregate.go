@@ -0,0 +1,207 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A sequential aggregate signs message i, counting from 1, with y_i, and
+// PSBatchVerify pairs the i-th message it is given with Y_i. Signing a message
+// with the wrong y_i yields an aggregate that fails verification without a
+// hint why. Aggregate carries a header of the slots signed so far, so that
+// such mistakes surface as ErrAggregationOrder instead.
+
+// ErrAggregationOrder is returned when the slots of a sequential aggregate
+// do not cover its messages exactly once each.
+var ErrAggregationOrder = errors.New("ps: aggregation order mismatch")
+
+// Aggregate is a sequential aggregate signature S together with its header:
+// the slots whose messages it covers, in the order they were signed. Slots
+// may be signed in any order.
+type Aggregate struct {
+	S       [][]byte
+	Indices []int
+}
+
+// NewAggregate starts an aggregate with AggreSign on the initial messages,
+// which take slots 1 to len(msgs).
+func NewAggregate(suite pairing.Suite, priKey *PrivateKey, msgs [][]byte, opts ...Option) (*Aggregate, error) {
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allow(OpAggregate, slotRange(0, len(msgs))...); err != nil {
+		return nil, err
+	}
+	S, err := AggreSign(suite, priKey.Scalars(), msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a := &Aggregate{S: S}
+	for i := range msgs {
+		a.Indices = append(a.Indices, i+1)
+	}
+	return a, nil
+}
+
+// AggregatePSSignAt adds msg to the aggregate S in the given slot, signing
+// it with y_index of priKey. It refuses a slot S already covers with
+// ErrAggregationOrder.
+func AggregatePSSignAt(suite pairing.Suite, priKey *PrivateKey, index int, S *Aggregate, msg []byte, opts ...Option) (*Aggregate, error) {
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if S == nil {
+		return nil, ErrNilSignature
+	}
+	if index < 1 || index > len(priKey.Y) {
+		return nil, fmt.Errorf("%w: slot %d outside 1..%d", ErrAggregationOrder, index, len(priKey.Y))
+	}
+	if err := priKey.policy.allow(OpAggregate, index-1); err != nil {
+		return nil, err
+	}
+	for _, i := range S.Indices {
+		if i == index {
+			return nil, fmt.Errorf("%w: slot %d signed twice", ErrAggregationOrder, index)
+		}
+	}
+	sig, err := AggregatePSSign(suite, priKey.Y[index-1], S.S, msg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	indices := append(append([]int{}, S.Indices...), index)
+	return &Aggregate{S: sig, Indices: indices}, nil
+}
+
+// AggregateSignAll performs a whole sequential aggregation chain in one
+// call: it signs msgs[0] with AggreSign and appends every further msgs[i]
+// with AggregatePSSign and y_(i+1), drawing all randomness from rand in that
+// order. The result is the signature the step-by-step chain gives with the
+// same randomness, and verifies with PSBatchVerify on msgs.
+func AggregateSignAll(suite pairing.Suite, priKey *PrivateKey, msgs [][]byte, rand cipher.Stream, opts ...Option) (*Signature, error) {
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if rand == nil {
+		return nil, fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if err := checkMessageCount(len(priKey.Y)+1, len(msgs)); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allow(OpAggregate, slotRange(0, len(msgs))...); err != nil {
+		return nil, err
+	}
+	opts = append(opts[:len(opts):len(opts)], withStream(rand))
+	sig, err := batchSign(suite, priKey.Scalars()[:2], msgs[:1], opts)
+	if err != nil {
+		return nil, err
+	}
+	return ResumeAggregateSign(suite, priKey, sig, 1, msgs[1:], rand, opts...)
+}
+
+// ResumeAggregateSign continues the chain that produced sig, an aggregate of
+// the first signed messages, by appending msgs to slots signed+1 onwards as
+// AggregateSignAll does.
+func ResumeAggregateSign(suite pairing.Suite, priKey *PrivateKey, sig *Signature, signed int, msgs [][]byte, rand cipher.Stream, opts ...Option) (*Signature, error) {
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := sig.check(); err != nil {
+		return nil, err
+	}
+	if rand == nil {
+		return nil, fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	if signed < 1 || signed+len(msgs) > len(priKey.Y) {
+		return nil, fmt.Errorf("%w: slots %d to %d outside 1..%d", ErrAggregationOrder, signed+1, signed+len(msgs), len(priKey.Y))
+	}
+	if err := priKey.policy.allow(OpAggregate, slotRange(signed, len(msgs))...); err != nil {
+		return nil, err
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := sig.checkPoints(o.validatePoints); err != nil {
+		return nil, err
+	}
+	for i, msg := range msgs {
+		if sig, err = aggregateSign(suite, priKey.Y[signed+i], sig, msg, rand, o); err != nil {
+			return nil, err
+		}
+	}
+	return sig, nil
+}
+
+// WithIndices makes PSBatchVerify check the aggregation header indices: it
+// must list every slot from 1 to the number of messages exactly once.
+func WithIndices(indices []int) Option {
+	return func(o *options) {
+		o.indices = append([]int{}, indices...)
+		o.checkIndices = true
+	}
+}
+
+// Verify checks the aggregate on msgs with PSBatchVerify, including its
+// header.
+func (a *Aggregate) Verify(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, opts ...Option) error {
+	if a == nil {
+		return ErrNilSignature
+	}
+	opts = append(opts[:len(opts):len(opts)], WithIndices(a.Indices))
+	return PSBatchVerify(suite, pubKey, msgs, a.S, opts...)
+}
+
+// VerifyPartialAggregate checks S as the aggregate over the first len(msgs)
+// messages of a chain under pubKey, the prefix a signer receives before
+// adding its own message. Every hop of a chain that crosses trust boundaries
+// should call it on what it received before signing: aggregation does not
+// check its input, so a hop that corrupts the aggregate is otherwise only
+// noticed by the final verifier, with no hint which hop was at fault.
+//
+// The equation e(sigma_1, X * Y_1^(m_1) * ... * Y_k^(m_k)) = e(sigma_2, g)
+// fixes sigma_2 = sigma_1^(x + y_1*m_1 + ... + y_k*m_k), so it also
+// establishes that the slots after k are unused: a contribution y_j*m_j from
+// any of them would break the equality, message scalars being nonzero. The
+// prefix must fit in the key, and the points of S are always checked to lie
+// in the subgroup, whatever ValidatePoints says, since S comes from the
+// previous hop.
+func VerifyPartialAggregate(suite pairing.Suite, pubKey *PublicKey, msgs [][]byte, S *Signature, opts ...Option) error {
+	if err := pubKey.check(); err != nil {
+		return err
+	}
+	if len(msgs) > len(pubKey.Y) {
+		return fmt.Errorf("%w: prefix of %d messages, key has %d slots", ErrAggregationOrder, len(msgs), len(pubKey.Y))
+	}
+	opts = append(opts[:len(opts):len(opts)], ValidatePoints(true))
+	return PSBatchVerifyPoints(suite, pubKey.Points(), msgs, S, opts...)
+}
+
+// checkIndices ensures indices covers the slots 1 to n exactly once.
+func checkIndices(indices []int, n int) error {
+	seen := make([]bool, n+1)
+	for _, i := range indices {
+		if i < 1 || i > n {
+			return fmt.Errorf("%w: slot %d outside 1..%d", ErrAggregationOrder, i, n)
+		}
+		if seen[i] {
+			return fmt.Errorf("%w: slot %d signed twice", ErrAggregationOrder, i)
+		}
+		seen[i] = true
+	}
+	for i := 1; i <= n; i++ {
+		if !seen[i] {
+			return fmt.Errorf("%w: slot %d not signed", ErrAggregationOrder, i)
+		}
+	}
+	return nil
+}
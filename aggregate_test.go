@@ -0,0 +1,219 @@
+package ps
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestAggregateOutOfOrder(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3"), []byte("attribute 4")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		a, err := NewAggregate(suite, priv, msgs[:1])
+		require.Nil(t, err)
+		for _, i := range []int{4, 2, 3} {
+			a, err = AggregatePSSignAt(suite, priv, i, a, msgs[i-1])
+			require.Nil(t, err)
+		}
+		require.Equal(t, []int{1, 4, 2, 3}, a.Indices)
+		require.Nil(t, a.Verify(suite, pub.Points(), msgs))
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, a.S, WithIndices(a.Indices)))
+	})
+}
+
+func TestAggregateOrderErrors(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		a, err := NewAggregate(suite, priv, msgs[:1])
+		require.Nil(t, err)
+		a, err = AggregatePSSignAt(suite, priv, 3, a, msgs[2])
+		require.Nil(t, err)
+
+		// Slot 2 was skipped.
+		err = a.Verify(suite, pub.Points(), msgs)
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		require.Contains(t, err.Error(), "slot 2 not signed")
+
+		// A slot cannot be signed twice.
+		_, err = AggregatePSSignAt(suite, priv, 3, a, msgs[2])
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		_, err = AggregatePSSignAt(suite, priv, 4, a, msgs[2])
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		_, err = AggregatePSSignAt(suite, priv, 0, a, msgs[2])
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+
+		a, err = AggregatePSSignAt(suite, priv, 2, a, msgs[1])
+		require.Nil(t, err)
+		require.Nil(t, a.Verify(suite, pub.Points(), msgs))
+
+		// A forged header is caught before the pairing check.
+		err = PSBatchVerify(suite, pub.Points(), msgs, a.S, WithIndices([]int{1, 2, 2}))
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		require.Contains(t, err.Error(), "slot 2 signed twice")
+	})
+}
+
+// seededSuite returns suite with a random stream fixed by seed.
+func seededSuite(suite pairing.Suite, seed string) pairing.Suite {
+	return brokenSuite{Suite: suite, stream: seededStream(seed)}
+}
+
+func TestAggregatePoints(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		sig, err := AggreSignPoints(suite, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		for i := 1; i < len(msgs); i++ {
+			sig, err = AggregatePSSignPoints(suite, priv.Y[i], sig, msgs[i])
+			require.Nil(t, err)
+		}
+		require.Nil(t, PSBatchVerifyPoints(suite, pub.Points(), msgs, sig))
+		S, err := sig.Components()
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		err = PSBatchVerifyPoints(suite, pub.Points(), [][]byte{msgs[0], msgs[2], msgs[1]}, sig)
+		require.Equal(t, ErrInvalidSignature, err)
+		err = PSBatchVerifyPoints(suite, pub.Points(), msgs, sig, WithIndices([]int{1, 1, 2}))
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		_, err = AggregatePSSignPoints(suite, priv.Y[0], &Signature{suite: suite, Sigma1: suite.G1().Point().Null(), Sigma2: sig.Sigma2}, msgs[0])
+		require.Equal(t, ErrInvalidSignature, err)
+	})
+}
+
+func TestAggregatePointsMatchBytes(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, _ := newTestKeys(t, suite, len(msgs))
+		dst := DefaultDST(suite)
+
+		S, err := AggreSign(seededSuite(suite, "step 0"), priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		sig, err := AggreSignPoints(seededSuite(suite, "step 0"), priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		for i := 1; i < len(msgs); i++ {
+			seed := "step " + strconv.Itoa(i)
+
+			// The aggregation step as computed on the encoded signature,
+			// with the same randomness.
+			tt, err := pickScalar(suite, seededSuite(suite, seed).RandomStream())
+			require.Nil(t, err)
+			prev, err := NewSignature(suite, S)
+			require.Nil(t, err)
+			ym := newScalar(suite).Mul(priv.Y[i], hashToScalar(suite, dst, msgs[i]))
+			s2 := suite.G1().Point().Add(suite.G1().Point().Mul(ym, prev.Sigma1), prev.Sigma2)
+			want, err := (&Signature{
+				suite:  suite,
+				Sigma1: suite.G1().Point().Mul(tt, prev.Sigma1),
+				Sigma2: suite.G1().Point().Mul(tt, s2),
+			}).Components()
+			require.Nil(t, err)
+
+			S, err = AggregatePSSign(seededSuite(suite, seed), priv.Y[i], S, msgs[i])
+			require.Nil(t, err)
+			require.Equal(t, want, S)
+			sig, err = AggregatePSSignPoints(seededSuite(suite, seed), priv.Y[i], sig, msgs[i])
+			require.Nil(t, err)
+			comps, err := sig.Components()
+			require.Nil(t, err)
+			require.Equal(t, want, comps)
+		}
+	})
+}
+
+func TestAggregateSignAll(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3"), []byte("attribute 4")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		sig, err := AggregateSignAll(suite, priv, msgs, seededStream("chain"))
+		require.Nil(t, err)
+		S, err := sig.Components()
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		// The step-by-step chain drawing from the same stream.
+		stepped := seededSuite(suite, "chain")
+		want, err := AggreSign(stepped, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		for i := 1; i < len(msgs); i++ {
+			want, err = AggregatePSSign(stepped, priv.Y[i], want, msgs[i])
+			require.Nil(t, err)
+		}
+		require.Equal(t, want, S)
+
+		// Resuming a chain of the first two messages.
+		start, err := AggregateSignAll(suite, priv, msgs[:2], seededStream("start"))
+		require.Nil(t, err)
+		resumed, err := ResumeAggregateSign(suite, priv, start, 2, msgs[2:], seededStream("resume"))
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerifyPoints(suite, pub.Points(), msgs, resumed))
+
+		// A chain longer than the key, or resumed past its end.
+		_, err = AggregateSignAll(suite, priv, append(msgs, msgs[0]), seededStream("chain"))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = ResumeAggregateSign(suite, priv, start, 3, msgs[2:], seededStream("resume"))
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		_, err = ResumeAggregateSign(suite, priv, start, 0, msgs[2:], seededStream("resume"))
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+		_, err = AggregateSignAll(suite, priv, msgs, nil)
+		require.True(t, errors.Is(err, ErrEntropyFailure))
+	})
+}
+
+func TestVerifyPartialAggregate(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("org 1"), []byte("org 2"), []byte("org 3"), []byte("org 4")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		// hop signs slot i+1 of the chain with y, after checking the prefix it
+		// received.
+		hop := func(i int, y int, sig *Signature) (*Signature, error) {
+			if err := VerifyPartialAggregate(suite, pub, msgs[:i], sig); err != nil {
+				return nil, err
+			}
+			return AggregatePSSignPoints(suite, priv.Y[y], sig, msgs[i])
+		}
+		start, err := AggreSign(suite, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		first, err := NewSignature(suite, start)
+		require.Nil(t, err)
+
+		sig := first
+		for i := 1; i < len(msgs); i++ {
+			sig, err = hop(i, i, sig)
+			require.Nil(t, err, "hop %d", i+1)
+		}
+		require.Nil(t, VerifyPartialAggregate(suite, pub, msgs, sig))
+
+		// Hop 2 signs its message in slot 3: hop 3 refuses to go on.
+		poisoned, err := hop(1, 2, first)
+		require.Nil(t, err)
+		_, err = hop(2, 2, poisoned)
+		require.Equal(t, ErrInvalidSignature, err)
+
+		// Hop 2 also fills slot 4, which the prefix leaves unused.
+		extra, err := hop(1, 1, first)
+		require.Nil(t, err)
+		extra, err = AggregatePSSignPoints(suite, priv.Y[3], extra, msgs[3])
+		require.Nil(t, err)
+		_, err = hop(2, 2, extra)
+		require.Equal(t, ErrInvalidSignature, err)
+
+		// A forged identity aggregate satisfies every equation.
+		null := &Signature{suite: suite, Sigma1: suite.G1().Point().Null(), Sigma2: suite.G1().Point().Null()}
+		require.Equal(t, ErrInvalidSignature, VerifyPartialAggregate(suite, pub, msgs[:2], null))
+
+		err = VerifyPartialAggregate(suite, pub, append(msgs, msgs[0]), sig)
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+	})
+}
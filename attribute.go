@@ -0,0 +1,126 @@
+package ps
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Numeric attributes are signed as messages, hashed to scalars like any
+// other: there is no separate mode that signs small integers as raw scalars,
+// so signer and verifier need only agree on the message. A signed 64-bit
+// integer is encoded as
+//
+//	KindInt64 || uint64(v) XOR 2^63
+//
+// and an unsigned one as
+//
+//	KindUint64 || v,
+//
+// both big-endian. Offsetting the signed value by 2^63 keeps the encoding
+// order-preserving, so that -1 sorts before 0, and the kind byte keeps 42
+// as int64, 42 as uint64 and the string "42" apart. The encodings are those
+// of the schema kinds, so a disclosed schema attribute decodes with the
+// functions below.
+
+// Boolean flags are packed into a single attribute, so that a handful of
+// entitlements costs one slot and one Y component rather than one each:
+//
+//	KindFlags || uint8(n) || bits,
+//
+// with flag i the bit 1<<i of bits, a big-endian uint64, and the bits from
+// n upwards zero. Disclosing the attribute discloses every flag in it: a
+// verifier learns all the bits, not only the one it checks, and flags that
+// must be hidden from one another belong in separate attributes.
+
+// MaxFlags is the most flags PackFlags packs into one attribute.
+const MaxFlags = 64
+
+// ErrAttributeRange is returned when a disclosed integer does not fit the
+// type it is decoded to.
+var ErrAttributeRange = errors.New("ps: attribute out of range")
+
+// AttributeFromInt64 returns the message encoding v.
+func AttributeFromInt64(v int64) []byte {
+	return appendUint64([]byte{byte(KindInt64)}, uint64(v)^(1<<63))
+}
+
+// AttributeFromUint64 returns the message encoding v.
+func AttributeFromUint64(v uint64) []byte {
+	return appendUint64([]byte{byte(KindUint64)}, v)
+}
+
+// Int64FromAttribute decodes an integer attribute made by AttributeFromInt64
+// or, if it is at most math.MaxInt64, by AttributeFromUint64.
+func Int64FromAttribute(msg []byte) (int64, error) {
+	kind, v, err := integerAttribute(msg)
+	if err != nil {
+		return 0, err
+	}
+	if kind == KindInt64 {
+		return int64(v ^ (1 << 63)), nil
+	}
+	if v > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d exceeds int64", ErrAttributeRange, v)
+	}
+	return int64(v), nil
+}
+
+// Uint64FromAttribute decodes an integer attribute made by
+// AttributeFromUint64 or, if it is not negative, by AttributeFromInt64.
+func Uint64FromAttribute(msg []byte) (uint64, error) {
+	kind, v, err := integerAttribute(msg)
+	if err != nil {
+		return 0, err
+	}
+	if kind == KindUint64 {
+		return v, nil
+	}
+	n := int64(v ^ (1 << 63))
+	if n < 0 {
+		return 0, fmt.Errorf("%w: %d is negative", ErrAttributeRange, n)
+	}
+	return uint64(n), nil
+}
+
+// integerAttribute splits an integer attribute into its kind and encoded
+// value.
+func integerAttribute(msg []byte) (AttributeKind, uint64, error) {
+	if len(msg) != 9 || (msg[0] != byte(KindInt64) && msg[0] != byte(KindUint64)) {
+		return 0, 0, fmt.Errorf("%w: not an integer attribute", ErrAttributeType)
+	}
+	return AttributeKind(msg[0]), binary.BigEndian.Uint64(msg[1:]), nil
+}
+
+// PackFlags returns the message encoding flags, of which there may be at
+// most MaxFlags.
+func PackFlags(flags []bool) ([]byte, error) {
+	if len(flags) > MaxFlags {
+		return nil, fmt.Errorf("%w: %d flags, at most %d", ErrLimitExceeded, len(flags), MaxFlags)
+	}
+	var bits uint64
+	for i, f := range flags {
+		if f {
+			bits |= 1 << uint(i)
+		}
+	}
+	return appendUint64([]byte{byte(KindFlags), byte(len(flags))}, bits), nil
+}
+
+// UnpackFlags decodes a flags attribute made by PackFlags.
+func UnpackFlags(msg []byte) ([]bool, error) {
+	if len(msg) != 10 || msg[0] != byte(KindFlags) || msg[1] > MaxFlags {
+		return nil, fmt.Errorf("%w: not a flags attribute", ErrAttributeType)
+	}
+	n := uint(msg[1])
+	bits := binary.BigEndian.Uint64(msg[2:])
+	if n < 64 && bits>>n != 0 {
+		return nil, fmt.Errorf("%w: bits set beyond flag %d", ErrAttributeType, n)
+	}
+	flags := make([]bool, n)
+	for i := range flags {
+		flags[i] = bits&(1<<uint(i)) != 0
+	}
+	return flags, nil
+}
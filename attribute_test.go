@@ -0,0 +1,171 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+var int64Edges = []int64{math.MinInt64, math.MinInt64 + 1, -1 << 32, -1, 0, 1, 1 << 32, math.MaxInt64 - 1, math.MaxInt64}
+
+func TestInt64Attribute(t *testing.T) {
+	roundTrip := func(v int64) bool {
+		got, err := Int64FromAttribute(AttributeFromInt64(v))
+		return err == nil && got == v
+	}
+	// The encoding preserves order, so distinct values encode apart.
+	ordered := func(a, b int64) bool {
+		c := bytes.Compare(AttributeFromInt64(a), AttributeFromInt64(b))
+		return (a < b) == (c < 0) && (a == b) == (c == 0)
+	}
+	require.Nil(t, quick.Check(roundTrip, &quick.Config{MaxCount: 10000}))
+	require.Nil(t, quick.Check(ordered, &quick.Config{MaxCount: 10000}))
+	for _, a := range int64Edges {
+		require.True(t, roundTrip(a), "%d", a)
+		for _, b := range int64Edges {
+			require.True(t, ordered(a, b), "%d, %d", a, b)
+		}
+	}
+
+	for _, v := range int64Edges {
+		u, err := Uint64FromAttribute(AttributeFromInt64(v))
+		if v < 0 {
+			require.True(t, errors.Is(err, ErrAttributeRange), "%d", v)
+		} else {
+			require.Nil(t, err)
+			require.Equal(t, uint64(v), u)
+		}
+	}
+}
+
+func TestUint64Attribute(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		got, err := Uint64FromAttribute(AttributeFromUint64(v))
+		return err == nil && got == v
+	}
+	require.Nil(t, quick.Check(roundTrip, &quick.Config{MaxCount: 10000}))
+	for _, v := range []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		require.True(t, roundTrip(v), "%d", v)
+		n, err := Int64FromAttribute(AttributeFromUint64(v))
+		if v > math.MaxInt64 {
+			require.True(t, errors.Is(err, ErrAttributeRange), "%d", v)
+		} else {
+			require.Nil(t, err)
+			require.Equal(t, int64(v), n)
+		}
+	}
+
+	// Integers of either kind and strings of the same digits are distinct
+	// messages.
+	require.NotEqual(t, AttributeFromInt64(42), AttributeFromUint64(42))
+	for _, bad := range [][]byte{nil, []byte("42"), AttributeFromInt64(42)[:8], append(AttributeFromUint64(42), 0)} {
+		_, err := Int64FromAttribute(bad)
+		require.True(t, errors.Is(err, ErrAttributeType), "%x", bad)
+		_, err = Uint64FromAttribute(bad)
+		require.True(t, errors.Is(err, ErrAttributeType), "%x", bad)
+	}
+}
+
+func TestIntegerAttributeSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		schema, err := NewSchema(AttributeDef{Name: "age", Kind: KindUint64}, AttributeDef{Name: "balance", Kind: KindInt64})
+		require.Nil(t, err)
+		priv, pub := newTestKeys(t, suite, schema.Len())
+		values := map[string]interface{}{"age": uint64(34), "balance": int64(-1250)}
+		S, err := SignSchema(suite, priv.Scalars(), schema, values)
+		require.Nil(t, err)
+
+		// The verifier re-encodes disclosed values with the same helpers.
+		msgs := [][]byte{schema.SchemaAttribute(), AttributeFromUint64(34), AttributeFromInt64(-1250)}
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+		decoded, err := schema.Decode(msgs)
+		require.Nil(t, err)
+		require.Equal(t, values, decoded)
+		msgs[2] = AttributeFromInt64(1250)
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, S))
+		msgs[1], msgs[2] = AttributeFromInt64(34), AttributeFromInt64(-1250)
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, S))
+	})
+}
+
+func TestPackFlags(t *testing.T) {
+	alternate := make([]bool, MaxFlags)
+	for i := range alternate {
+		alternate[i] = i%3 == 0
+	}
+	for _, flags := range [][]bool{{}, {false}, {true}, alternate, make([]bool, MaxFlags)} {
+		msg, err := PackFlags(flags)
+		require.Nil(t, err)
+		got, err := UnpackFlags(msg)
+		require.Nil(t, err)
+		require.Equal(t, flags, got)
+	}
+	roundTrip := func(bits uint64, n uint8) bool {
+		flags := make([]bool, n%(MaxFlags+1))
+		for i := range flags {
+			flags[i] = bits&(1<<uint(i)) != 0
+		}
+		msg, err := PackFlags(flags)
+		if err != nil {
+			return false
+		}
+		got, err := UnpackFlags(msg)
+		return err == nil && reflect.DeepEqual(flags, got)
+	}
+	require.Nil(t, quick.Check(roundTrip, nil))
+
+	// The number of flags is part of the encoding.
+	require.NotEqual(t, mustPackFlags(t, []bool{true}), mustPackFlags(t, []bool{true, false}))
+
+	_, err := PackFlags(make([]bool, MaxFlags+1))
+	require.True(t, errors.Is(err, ErrLimitExceeded))
+
+	// Encodings are canonical: no bits beyond the last flag, and no more
+	// than MaxFlags flags.
+	one := mustPackFlags(t, []bool{true})
+	for _, bad := range [][]byte{nil, one[:9], append(one, 0), AttributeFromUint64(1), {byte(KindFlags), 1, 0, 0, 0, 0, 0, 0, 0, 3}, {byte(KindFlags), MaxFlags + 1, 0, 0, 0, 0, 0, 0, 0, 0}} {
+		_, err := UnpackFlags(bad)
+		require.True(t, errors.Is(err, ErrAttributeType), "%x", bad)
+	}
+}
+
+func mustPackFlags(t *testing.T, flags []bool) []byte {
+	msg, err := PackFlags(flags)
+	require.Nil(t, err)
+	return msg
+}
+
+func TestFlagsAttributeDisclosure(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		schema, err := NewSchema(AttributeDef{Name: "name", Kind: KindString}, AttributeDef{Name: "entitlements", Kind: KindFlags})
+		require.Nil(t, err)
+		priv, pub := newTestKeys(t, suite, schema.Len())
+		values := map[string]interface{}{"name": "alice", "entitlements": []bool{true, false, true}}
+		S, err := SignSchema(suite, priv.Scalars(), schema, values)
+		require.Nil(t, err)
+		msgs, err := schema.Encode(values)
+		require.Nil(t, err)
+
+		// Disclosing the packed attribute lets the verifier read every flag.
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		i, _ := schema.Index("entitlements")
+		nonce := []byte("session 1")
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{i: true}, nonce)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartial(suite, pub, map[int][]byte{i: msgs[i]}, proof, nonce))
+		v, err := schema.DecodeAttribute("entitlements", msgs[i])
+		require.Nil(t, err)
+		require.Equal(t, []bool{true, false, true}, v)
+
+		// A flag cannot be set after signing.
+		granted := mustPackFlags(t, []bool{true, true, true})
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, map[int][]byte{i: granted}, proof, nonce))
+	})
+}
@@ -0,0 +1,247 @@
+package ps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// An audit hook keeps a record of the signatures a key produces. It is
+// attached to a *PrivateKey with SetAudit and carried over to the
+// ConcurrentSigner made from it, and called synchronously after every
+// successful PrivateKey.BatchSign, SignReserved, ConcurrentSigner.Sign and
+// BatchSign, BlindSign and SignCommitment, before the signature is returned.
+// The aggregation functions are not audited: keys whose every signature
+// must be recorded should not allow OpAggregate.
+//
+// The hook sees the key ID of the key, the SHA-256 digests of the messages
+// in slot order and the SHA-256 digest of the signature components,
+// concatenated; never the messages themselves. BlindSign and SignCommitment
+// do not see the messages either, and pass the digest of the commitment.
+// Digests of low-entropy attributes can be found by trying the likely
+// values, so the record must still be kept from those who should not learn
+// the attributes.
+//
+// With Enforce set, a hook that fails or panics fails the signing with
+// ErrAudit and the signature is discarded. Otherwise the failure is passed
+// to Warn and the signature returned.
+
+// ErrAudit is returned when an enforced audit hook fails.
+var ErrAudit = errors.New("ps: audit hook failed")
+
+// AuditHook records the signatures of a key.
+type AuditHook interface {
+	Signed(ctx context.Context, keyID string, msgDigests [][]byte, sigFingerprint []byte, at time.Time) error
+}
+
+// Audit configures the audit of a key.
+type Audit struct {
+	Hook AuditHook
+	// Enforce makes a failure of Hook fail the signing with ErrAudit.
+	Enforce bool
+	// Warn, if set, receives the failures of Hook when Enforce is not set.
+	Warn func(error)
+}
+
+// auditor is an Audit bound to the ID of its key.
+type auditor struct {
+	Audit
+	keyID string
+}
+
+// newAuditor binds a to the key pub, returning nil for a nil a.
+func newAuditor(a *Audit, pub *PublicKey) (*auditor, error) {
+	if a == nil {
+		return nil, nil
+	}
+	if a.Hook == nil {
+		return nil, fmt.Errorf("%w: nil hook", ErrAudit)
+	}
+	id, err := pub.KeyID()
+	if err != nil {
+		return nil, err
+	}
+	return &auditor{Audit: *a, keyID: id}, nil
+}
+
+// SetAudit attaches a to the key, replacing its audit. A nil a removes it.
+// Like SetPolicy it must not be called while the key signs.
+func (k *PrivateKey) SetAudit(a *Audit) error {
+	if err := k.check(); err != nil {
+		return err
+	}
+	au, err := newAuditor(a, k.Public())
+	if err != nil {
+		return err
+	}
+	k.audit = au
+	return nil
+}
+
+// SetAudit attaches a to the signer, replacing the audit of its key. A nil
+// a removes it. It must not be called while the signer signs.
+func (s *ConcurrentSigner) SetAudit(a *Audit) error {
+	key := &PrivateKey{suite: s.suite, X: s.priKey[0], Y: s.priKey[1:]}
+	au, err := newAuditor(a, key.Public())
+	if err != nil {
+		return err
+	}
+	s.audit = au
+	return nil
+}
+
+// WithAuditContext sets the context passed to the audit hook of the key.
+// Without it the hook gets context.Background().
+func WithAuditContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.auditCtx = ctx
+	}
+}
+
+// auditContext returns the context of WithAuditContext in opts.
+func auditContext(opts []Option) context.Context {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.auditCtx == nil {
+		return context.Background()
+	}
+	return o.auditCtx
+}
+
+// signed reports the signature S on msgs to the hook, returning the error
+// that should fail the signing, if any. A nil auditor does nothing.
+func (a *auditor) signed(ctx context.Context, msgs [][]byte, S [][]byte) error {
+	if a == nil {
+		return nil
+	}
+	digests := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		sum := sha256.Sum256(msg)
+		digests[i] = sum[:]
+	}
+	h := sha256.New()
+	for _, c := range S {
+		h.Write(c)
+	}
+	err := a.call(ctx, digests, h.Sum(nil))
+	if err == nil {
+		return nil
+	}
+	err = &auditError{err: err}
+	if a.Enforce {
+		return err
+	}
+	if a.Warn != nil {
+		a.Warn(err)
+	}
+	return nil
+}
+
+// signedSignature is signed for a *Signature.
+func (a *auditor) signedSignature(ctx context.Context, msgs [][]byte, sig *Signature) error {
+	if a == nil {
+		return nil
+	}
+	S, err := sig.Components()
+	if err != nil {
+		return err
+	}
+	return a.signed(ctx, msgs, S)
+}
+
+// auditError is a failure of an audit hook, matching both ErrAudit and the
+// error of the hook.
+type auditError struct {
+	err error
+}
+
+func (e *auditError) Error() string { return ErrAudit.Error() + ": " + e.err.Error() }
+
+func (e *auditError) Unwrap() error { return e.err }
+
+func (e *auditError) Is(target error) bool { return target == ErrAudit }
+
+// call calls the hook, turning a panic into an error.
+func (a *auditor) call(ctx context.Context, digests [][]byte, fingerprint []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return a.Hook.Signed(ctx, a.keyID, digests, fingerprint, time.Now())
+}
+
+// FileAuditLog is an AuditHook appending one JSON object per signature to a
+// file, synced before Signed returns:
+//
+//	{"time":"...","kid":"...","messages":["<hex>",...],"signature":"<hex>"}
+//
+// Its methods are safe for concurrent use.
+type FileAuditLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// auditRecord is a line of a FileAuditLog.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	KeyID     string    `json:"kid"`
+	Messages  []string  `json:"messages"`
+	Signature string    `json:"signature"`
+}
+
+// NewFileAuditLog opens the log at path for appending, creating it readable
+// by its owner alone if needed.
+func NewFileAuditLog(path string) (*FileAuditLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditLog{f: f}, nil
+}
+
+// Signed appends a record to the log.
+func (l *FileAuditLog) Signed(_ context.Context, keyID string, msgDigests [][]byte, sigFingerprint []byte, at time.Time) error {
+	r := auditRecord{
+		Time:      at.UTC(),
+		KeyID:     keyID,
+		Messages:  make([]string, len(msgDigests)),
+		Signature: hex.EncodeToString(sigFingerprint),
+	}
+	for i, d := range msgDigests {
+		r.Messages[i] = hex.EncodeToString(d)
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return os.ErrClosed
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// Close closes the log. Later records fail with os.ErrClosed.
+func (l *FileAuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
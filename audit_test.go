@@ -0,0 +1,262 @@
+package ps
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// auditCall is a call of a recordingHook.
+type auditCall struct {
+	ctx         context.Context
+	keyID       string
+	digests     [][]byte
+	fingerprint []byte
+	at          time.Time
+}
+
+// recordingHook records its calls and fails with err or panics if set.
+type recordingHook struct {
+	mu    sync.Mutex
+	calls []auditCall
+	err   error
+	panic bool
+}
+
+func (h *recordingHook) Signed(ctx context.Context, keyID string, msgDigests [][]byte, sigFingerprint []byte, at time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, auditCall{ctx, keyID, msgDigests, sigFingerprint, at})
+	if h.panic {
+		panic("audit backend down")
+	}
+	return h.err
+}
+
+func digestsOf(msgs ...[]byte) [][]byte {
+	out := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		sum := sha256.Sum256(msg)
+		out[i] = sum[:]
+	}
+	return out
+}
+
+func fingerprintOf(S [][]byte) []byte {
+	h := sha256.New()
+	for _, c := range S {
+		h.Write(c)
+	}
+	return h.Sum(nil)
+}
+
+func TestAudit(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		id, err := pub.KeyID()
+		require.Nil(t, err)
+		hook := &recordingHook{}
+		require.Nil(t, priv.SetAudit(&Audit{Hook: hook, Enforce: true}))
+
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "request")
+		before := time.Now()
+		S, err := priv.BatchSign(msgs, WithAuditContext(ctx))
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+		require.Len(t, hook.calls, 1)
+		call := hook.calls[0]
+		require.Equal(t, "request", call.ctx.Value(key{}))
+		require.Equal(t, id, call.keyID)
+		require.Equal(t, digestsOf(msgs...), call.digests)
+		require.Equal(t, fingerprintOf(S), call.fingerprint)
+		require.False(t, call.at.Before(before))
+
+		// Failed signing is not audited.
+		_, err = priv.BatchSign(append(msgs, []byte("attribute 3")))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Len(t, hook.calls, 1)
+
+		// The signer made from the key carries its audit.
+		signer, err := NewConcurrentSigner(suite, priv, nil)
+		require.Nil(t, err)
+		S, err = signer.Sign(msgs[0])
+		require.Nil(t, err)
+		require.Len(t, hook.calls, 2)
+		require.Equal(t, context.Background(), hook.calls[1].ctx)
+		require.Equal(t, digestsOf(msgs[0]), hook.calls[1].digests)
+		require.Equal(t, fingerprintOf(S), hook.calls[1].fingerprint)
+
+		require.Nil(t, priv.SetAudit(nil))
+		_, err = priv.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Len(t, hook.calls, 2)
+
+		other := &recordingHook{}
+		require.Nil(t, signer.SetAudit(&Audit{Hook: other}))
+		_, err = signer.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Len(t, hook.calls, 2)
+		require.Len(t, other.calls, 1)
+		require.Equal(t, id, other.calls[0].keyID)
+
+		require.True(t, errors.Is(priv.SetAudit(&Audit{}), ErrAudit))
+	})
+}
+
+func TestAuditSignReserved(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, pub := newTestKeys(t, suite, 3)
+	require.Nil(t, priv.SetPolicy(&KeyPolicy{Reserved: []int{0}, Operations: AllOperations}))
+	hook := &recordingHook{}
+	require.Nil(t, priv.SetAudit(&Audit{Hook: hook, Enforce: true}))
+
+	schema := []byte("schema")
+	msgs := [][]byte{[]byte("Alice"), []byte("Paris")}
+	S, err := priv.SignReserved(IndexedMessages{{Index: 0, Msg: schema}}, msgs)
+	require.Nil(t, err)
+	require.Nil(t, PSBatchVerify(suite, pub.Points(), [][]byte{schema, msgs[0], msgs[1]}, S))
+	require.Len(t, hook.calls, 1)
+	require.Equal(t, digestsOf(schema, msgs[0], msgs[1]), hook.calls[0].digests)
+}
+
+func TestAuditBlindSign(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msgs := [][]byte{[]byte("secret 1"), []byte("secret 2")}
+	priv, pub := newTestKeys(t, suite, len(msgs))
+	hook := &recordingHook{}
+	require.Nil(t, priv.SetAudit(&Audit{Hook: hook, Enforce: true}))
+
+	req, state, err := PrepareBlindSign(suite, pub, priv.BlindingKey(), msgs, random.New())
+	require.Nil(t, err)
+	blindSig, err := BlindSign(suite, priv, req)
+	require.Nil(t, err)
+	_, err = Unblind(suite, state, blindSig)
+	require.Nil(t, err)
+
+	// The signer sees the commitment only, and so does the hook.
+	require.Len(t, hook.calls, 1)
+	C, err := req.Commitment.MarshalBinary()
+	require.Nil(t, err)
+	require.Equal(t, digestsOf(C), hook.calls[0].digests)
+	S, err := blindSig.Components()
+	require.Nil(t, err)
+	require.Equal(t, fingerprintOf(S), hook.calls[0].fingerprint)
+}
+
+func TestAuditFailure(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msg := []byte("attribute 1")
+	backend := errors.New("disk full")
+	for _, hook := range []*recordingHook{{err: backend}, {panic: true}} {
+		priv, pub := newTestKeys(t, suite, 1)
+
+		// Enforced, the failure fails the signing.
+		require.Nil(t, priv.SetAudit(&Audit{Hook: hook, Enforce: true}))
+		S, err := priv.BatchSign([][]byte{msg})
+		require.True(t, errors.Is(err, ErrAudit))
+		require.Nil(t, S)
+		signer, err := NewConcurrentSigner(suite, priv, nil)
+		require.Nil(t, err)
+		_, err = signer.Sign(msg)
+		require.True(t, errors.Is(err, ErrAudit))
+
+		// Otherwise it is a warning and the signature is returned.
+		var warnings []error
+		require.Nil(t, priv.SetAudit(&Audit{Hook: hook, Warn: func(err error) { warnings = append(warnings, err) }}))
+		S, err = priv.BatchSign([][]byte{msg})
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, S))
+		require.Len(t, warnings, 1)
+		require.True(t, errors.Is(warnings[0], ErrAudit))
+		if hook.err != nil {
+			require.True(t, errors.Is(warnings[0], backend))
+		}
+
+		// Without Warn the failure is dropped.
+		require.Nil(t, priv.SetAudit(&Audit{Hook: hook}))
+		_, err = priv.BatchSign([][]byte{msg})
+		require.Nil(t, err)
+		require.Len(t, hook.calls, 4)
+	}
+}
+
+func TestFileAuditLog(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := NewFileAuditLog(path)
+	require.Nil(t, err)
+
+	msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+	priv, pub := newTestKeys(t, suite, len(msgs))
+	id, err := pub.KeyID()
+	require.Nil(t, err)
+	require.Nil(t, priv.SetAudit(&Audit{Hook: log, Enforce: true}))
+	signer, err := NewConcurrentSigner(suite, priv, nil)
+	require.Nil(t, err)
+
+	const goroutines = 16
+	sigs := make([][][]byte, goroutines)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			sigs[g], errs[g] = signer.BatchSign(msgs)
+		}(g)
+	}
+	wg.Wait()
+	require.Nil(t, log.Close())
+
+	// Reopening appends.
+	log, err = NewFileAuditLog(path)
+	require.Nil(t, err)
+	require.Nil(t, priv.SetAudit(&Audit{Hook: log, Enforce: true}))
+	last, err := priv.BatchSign(msgs[:1])
+	require.Nil(t, err)
+	require.Nil(t, log.Close())
+	_, err = priv.BatchSign(msgs[:1])
+	require.True(t, errors.Is(err, ErrAudit))
+	require.True(t, errors.Is(err, os.ErrClosed))
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	f, err := os.Open(path)
+	require.Nil(t, err)
+	defer f.Close()
+	fingerprints := make(map[string]bool)
+	for g := range sigs {
+		require.Nil(t, errs[g])
+		fingerprints[hex.EncodeToString(fingerprintOf(sigs[g]))] = true
+	}
+	var records []auditRecord
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		var r auditRecord
+		require.Nil(t, json.Unmarshal(sc.Bytes(), &r))
+		require.Equal(t, id, r.KeyID)
+		require.Equal(t, time.UTC, r.Time.Location())
+		records = append(records, r)
+	}
+	require.Len(t, records, goroutines+1)
+	for _, r := range records[:goroutines] {
+		require.True(t, fingerprints[r.Signature])
+		require.Equal(t, []string{hex.EncodeToString(digestsOf(msgs[0])[0]), hex.EncodeToString(digestsOf(msgs[1])[0])}, r.Messages)
+	}
+	require.Equal(t, hex.EncodeToString(fingerprintOf(last)), records[goroutines].Signature)
+}
@@ -0,0 +1,234 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Batch verification checks n signatures (sigma_(1,i), sigma_(2,i)) on the
+// messages m_i under one key at once. Raising the i-th verification equation
+// to a random coefficient r_i and multiplying them gives
+//
+//	e(sum r_i*sigma_(1,i), X) * e(sum r_i*m_i*sigma_(1,i), Y) = e(sum r_i*sigma_(2,i), g),
+//
+// three pairings instead of 2n. If any signature is invalid, the combined
+// equation holds only with probability 1/q over the choice of the r_i, so
+// the coefficients must be fresh and unpredictable to the signer: the random
+// stream is a mandatory argument. With BisectFailures, a failing batch is
+// split in halves, each checked again with fresh coefficients, until the
+// invalid signatures are found.
+
+// BatchError reports the failing items of a bulk operation such as
+// VerifyAll, VerifyMany, VerifyBatch or PSBatchVerify. Op names what was done
+// to each item, Index is the first item it failed on and Err its error, and
+// Indices lists every failing item in increasing order, starting with Index.
+// Bulk operations that also return one error per item summarize them with
+// errors.Join of a *BatchError per failing item, in index order, so that
+// errors.As finds the first.
+type BatchError struct {
+	Index   int
+	Op      string
+	Err     error
+	Indices []int
+}
+
+// itemError returns the *BatchError of op failing on item i alone.
+func itemError(op string, i int, err error) *BatchError {
+	return &BatchError{Index: i, Op: op, Err: err, Indices: []int{i}}
+}
+
+// summarize joins the *BatchError of every non-nil entry of errs, or returns
+// nil if all are nil.
+func summarize(op string, errs []error) error {
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, itemError(op, i, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Indices) <= 1 {
+		return fmt.Sprintf("%v (%s, index %d)", e.Err, e.Op, e.Index)
+	}
+	idx := make([]string, len(e.Indices))
+	for i, j := range e.Indices {
+		idx[i] = fmt.Sprint(j)
+	}
+	return fmt.Sprintf("%v (%s, indices %s)", e.Err, e.Op, strings.Join(idx, ", "))
+}
+
+// Unwrap returns the error of the first failing item.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BisectFailures makes VerifyBatch locate the invalid signatures of a failing
+// batch and return them in a *BatchError, at the cost of further pairings.
+func BisectFailures() Option {
+	return func(o *options) {
+		o.bisect = true
+	}
+}
+
+// batchItem is a parsed signature of a batch and its message scalar.
+type batchItem struct {
+	index int
+	m     kyber.Scalar
+	sig   *Signature
+}
+
+// VerifyBatch checks the signatures sigs, the i-th on msgs[i] as verified by
+// Verify, under pubKey with a random linear combination drawn from rand. It
+// returns ErrInvalidSignature if any of them is invalid, or a *BatchError
+// naming them with BisectFailures.
+func VerifyBatch(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, sigs []*Signature, rand cipher.Stream, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	ob := observer{o.inst}
+	start := ob.start()
+	err = verifyBatch(suite, pubKey, msgs, sigs, rand, o)
+	ob.verified(start, err, 1)
+	return err
+}
+
+// verifyBatch implements VerifyBatch.
+func verifyBatch(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, sigs []*Signature, rand cipher.Stream, o *options) error {
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if len(sigs) == 0 {
+		return ErrNoMessages
+	}
+	if len(msgs) != len(sigs) {
+		return fmt.Errorf("%w: %d messages for %d signatures", ErrBatchLengthMismatch, len(msgs), len(sigs))
+	}
+	if err := o.checkBatchItems(len(sigs)); err != nil {
+		return err
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return err
+	}
+	if rand == nil {
+		return fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	items := make([]batchItem, 0, len(sigs))
+	var bad []int
+	for i, sig := range sigs {
+		if err := sig.check(); err != nil {
+			return fmt.Errorf("%w: signature %d", err, i)
+		}
+		// Malformed signatures are reported without a pairing check.
+		if !batchItemValid(suite, sig, o.validatePoints) {
+			if !o.bisect {
+				return ErrInvalidSignature
+			}
+			bad = append(bad, i)
+			continue
+		}
+		items = append(items, batchItem{index: i, m: hashToScalar(suite, o.dst, msgs[i]), sig: sig})
+	}
+	ok, err := batchHolds(suite, pubKey, items, rand)
+	if err != nil {
+		return err
+	}
+	if ok && len(bad) == 0 {
+		return nil
+	}
+	if !o.bisect {
+		return ErrInvalidSignature
+	}
+	if !ok {
+		found, err := bisect(suite, pubKey, items, rand)
+		if err != nil {
+			return err
+		}
+		bad = mergeIndices(bad, found)
+	}
+	return &BatchError{Index: bad[0], Op: "verify signature", Err: ErrInvalidSignature, Indices: bad}
+}
+
+// batchItemValid rejects signatures with identity components, which satisfy
+// every equation, and, if validate is set, points outside the subgroup.
+func batchItemValid(suite pairing.Suite, sig *Signature, validate bool) bool {
+	g1 := suite.G1()
+	for _, p := range []kyber.Point{sig.Sigma1, sig.Sigma2} {
+		if isIdentity(g1, p) {
+			return false
+		}
+		if validate && checkSubgroup(g1, p) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// batchHolds checks the combined equation of items with fresh coefficients.
+// An empty batch holds.
+func batchHolds(suite pairing.Suite, pubKey []kyber.Point, items []batchItem, rand cipher.Stream) (bool, error) {
+	if len(items) == 0 {
+		return true, nil
+	}
+	g1 := suite.G1()
+	s1, s1m, s2 := g1.Point().Null(), g1.Point().Null(), g1.Point().Null()
+	for _, it := range items {
+		r, err := pickScalar(suite, rand)
+		if err != nil {
+			return false, err
+		}
+		p := g1.Point().Mul(r, it.sig.Sigma1)
+		s1.Add(s1, p)
+		s1m.Add(s1m, p.Mul(it.m, p))
+		s2.Add(s2, g1.Point().Mul(r, it.sig.Sigma2))
+	}
+	left := suite.Pair(s1, pubKey[0])
+	left.Add(left, suite.Pair(s1m, pubKey[1]))
+	return left.Equal(suite.Pair(s2, suite.G2().Point().Base())), nil
+}
+
+// bisect returns the indices of the invalid signatures of items, a batch
+// known to fail.
+func bisect(suite pairing.Suite, pubKey []kyber.Point, items []batchItem, rand cipher.Stream) ([]int, error) {
+	if len(items) == 1 {
+		return []int{items[0].index}, nil
+	}
+	var bad []int
+	half := len(items) / 2
+	for _, part := range [][]batchItem{items[:half], items[half:]} {
+		ok, err := batchHolds(suite, pubKey, part, rand)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			continue
+		}
+		found, err := bisect(suite, pubKey, part, rand)
+		if err != nil {
+			return nil, err
+		}
+		bad = append(bad, found...)
+	}
+	return bad, nil
+}
+
+// mergeIndices merges two increasing lists of indices.
+func mergeIndices(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	return append(append(merged, a...), b...)
+}
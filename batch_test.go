@@ -0,0 +1,128 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// signBatch signs n distinct messages with priv, one signature each.
+func signBatch(t testing.TB, suite pairing.Suite, priv *PrivateKey, n int) ([][]byte, []*Signature) {
+	msgs := make([][]byte, n)
+	sigs := make([]*Signature, n)
+	for i := range msgs {
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		S, err := Sign(suite, priv.Scalars(), msgs[i])
+		require.Nil(t, err)
+		sigs[i], err = NewSignature(suite, S)
+		require.Nil(t, err)
+	}
+	return msgs, sigs
+}
+
+func TestVerifyBatch(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		msgs, sigs := signBatch(t, suite, priv, 9)
+		require.Nil(t, VerifyBatch(suite, pub.Points(), msgs, sigs, random.New()))
+		require.Nil(t, VerifyBatch(suite, pub.Points(), msgs, sigs, random.New(), BisectFailures()))
+
+		// One signature on another message.
+		bad := append([]*Signature{}, sigs...)
+		bad[5] = sigs[6]
+		require.Equal(t, ErrInvalidSignature, VerifyBatch(suite, pub.Points(), msgs, bad, random.New()))
+		err := VerifyBatch(suite, pub.Points(), msgs, bad, random.New(), BisectFailures())
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, []int{5}, batchErr.Indices)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		// Several, including an identity signature that satisfies any
+		// equation.
+		bad[0] = &Signature{suite: suite, Sigma1: suite.G1().Point().Null(), Sigma2: suite.G1().Point().Null()}
+		bad[8] = sigs[7]
+		err = VerifyBatch(suite, pub.Points(), msgs, bad, random.New(), BisectFailures())
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, []int{0, 5, 8}, batchErr.Indices)
+		require.Equal(t, 0, batchErr.Index)
+		require.Equal(t, ErrInvalidSignature, VerifyBatch(suite, pub.Points(), msgs, bad, random.New()))
+
+		err = VerifyBatch(suite, pub.Points(), msgs[:2], sigs, random.New())
+		require.True(t, errors.Is(err, ErrBatchLengthMismatch))
+		err = VerifyBatch(suite, pub.Points(), msgs, sigs, nil)
+		require.True(t, errors.Is(err, ErrEntropyFailure))
+		require.Equal(t, ErrNoMessages, VerifyBatch(suite, pub.Points(), nil, nil, random.New()))
+	})
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		priv, pub := newTestKeys(b, suite, 1)
+		msgs, sigs := signBatch(b, suite, priv, 64)
+		b.Run("Verify", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j, sig := range sigs {
+					S, _ := sig.Components()
+					if err := Verify(suite, pub.Points(), msgs[j], S); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run("VerifyBatch", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := VerifyBatch(suite, pub.Points(), msgs, sigs, random.New()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
+// failedIndices returns the indices of the *BatchErrors joined in err, the
+// summary of a bulk operation.
+func failedIndices(t *testing.T, err error) []int {
+	var joined interface{ Unwrap() []error }
+	require.True(t, errors.As(err, &joined))
+	var indices []int
+	for _, e := range joined.Unwrap() {
+		var batchErr *BatchError
+		require.True(t, errors.As(e, &batchErr))
+		require.Equal(t, []int{batchErr.Index}, batchErr.Indices)
+		indices = append(indices, batchErr.Index)
+	}
+	return indices
+}
+
+func TestBatchErrorIndex(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 3)
+		msgs := [][]byte{[]byte("name"), []byte("age"), []byte("city")}
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+
+		// A truncated second component.
+		bad := [][]byte{S[0], S[1][1:]}
+		err = fmt.Errorf("credential 4: %w", PSBatchVerify(suite, pub.Points(), msgs, bad))
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 1, batchErr.Index)
+		require.Equal(t, "unmarshal component", batchErr.Op)
+		require.True(t, errors.Is(err, ErrMalformedSignature))
+
+		// A malformed signature as a whole has no index.
+		err = PSBatchVerify(suite, pub.Points(), msgs, S[:1])
+		require.True(t, errors.Is(err, ErrMalformedSignature))
+		require.False(t, errors.As(err, &batchErr))
+
+		err = PSBatchVerify(suite, pub.Points(), [][]byte{msgs[0], msgs[1], nil}, S)
+		require.True(t, errors.As(fmt.Errorf("credential 4: %w", err), &batchErr))
+		require.Equal(t, 2, batchErr.Index)
+		require.Equal(t, ErrEmptyMessage, batchErr.Err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+	})
+}
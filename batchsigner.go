@@ -0,0 +1,77 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// BatchSigner signs messages that arrive one at a time, e.g. from a database
+// cursor, without buffering them: Add hashes each message and folds y_i*m_i
+// into a running sum, and Finalize turns the sum into the signature BatchSign
+// would make on the same messages. A BatchSigner must not be used by several
+// goroutines at once.
+type BatchSigner struct {
+	suite  pairing.Suite
+	priKey []kyber.Scalar
+	dst    []byte
+	sum    kyber.Scalar
+	n      int
+}
+
+// NewBatchSigner returns a BatchSigner for priKey, hashing messages under the
+// tag set by opts. DeterministicSign does not apply: Finalize takes the
+// randomness as an argument.
+func NewBatchSigner(suite pairing.Suite, priKey []kyber.Scalar, opts ...Option) (*BatchSigner, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	key := make([]kyber.Scalar, len(priKey))
+	for i, k := range priKey {
+		key[i] = k.Clone()
+	}
+	return &BatchSigner{suite: suite, priKey: key, dst: o.dst, sum: newScalar(suite)}, nil
+}
+
+// Add signs msg as the next message. It returns ErrKeyLengthMismatch with the
+// index of msg, counting from 0, once the key has no component left for it.
+func (s *BatchSigner) Add(msg []byte) error {
+	if err := checkMessages(msg); err != nil {
+		return err
+	}
+	if s.n+1 >= len(s.priKey) {
+		return fmt.Errorf("%w: message %d needs %d key components, got %d", ErrKeyLengthMismatch, s.n, s.n+2, len(s.priKey))
+	}
+	m := hashToScalar(s.suite, s.dst, msg)
+	s.sum.Add(s.sum, m.Mul(s.priKey[s.n+1], m))
+	s.n++
+	return nil
+}
+
+// Len returns the number of messages added since the signer was created or
+// last reset.
+func (s *BatchSigner) Len() int {
+	return s.n
+}
+
+// Finalize returns the signature (h, h^(x + y_1*m_1 + ... + y_k*m_k)) on the
+// messages added so far, for h picked from rand. The messages stay added, so
+// Finalize may be called again for another randomization; Reset starts over.
+func (s *BatchSigner) Finalize(rand cipher.Stream) (*Signature, error) {
+	if s.n == 0 {
+		return nil, ErrNoMessages
+	}
+	return signSum(s.suite, s.priKey[0], s.sum, rand)
+}
+
+// Reset discards the messages added so far, keeping the key and tag.
+func (s *BatchSigner) Reset() {
+	s.sum.Zero()
+	s.n = 0
+}
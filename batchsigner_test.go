@@ -0,0 +1,82 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBatchSigner(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		signer, err := NewBatchSigner(suite, priv.Scalars())
+		require.Nil(t, err)
+		_, err = signer.Finalize(random.New())
+		require.Equal(t, ErrNoMessages, err)
+		for _, msg := range msgs {
+			require.Nil(t, signer.Add(msg))
+		}
+		require.Equal(t, len(msgs), signer.Len())
+
+		// The streamed signature is the one BatchSign makes with the same
+		// randomness.
+		sig, err := signer.Finalize(seededStream("batch signer"))
+		require.Nil(t, err)
+		S, err := sig.Components()
+		require.Nil(t, err)
+		want, err := BatchSign(seededSuite(suite, "batch signer"), priv.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Equal(t, want, S)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		// The key has no component for a fourth message.
+		err = signer.Add([]byte("attribute 4"))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Contains(t, err.Error(), "message 3")
+		require.Equal(t, len(msgs), signer.Len())
+
+		// After Reset the signer serves a new set of messages.
+		signer.Reset()
+		require.Equal(t, 0, signer.Len())
+		for _, msg := range msgs[:2] {
+			require.Nil(t, signer.Add(msg))
+		}
+		sig, err = signer.Finalize(random.New())
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerifyPoints(suite, pub.Points(), msgs[:2], sig))
+	})
+}
+
+func TestBatchSignerOptions(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		dst := WithDST([]byte("EXAMPLE-APP-V1"))
+
+		signer, err := NewBatchSigner(suite, priv.Scalars(), dst)
+		require.Nil(t, err)
+		for _, msg := range msgs {
+			require.Nil(t, signer.Add(msg))
+		}
+		require.Equal(t, ErrEmptyMessage, signer.Add(nil))
+		sig, err := signer.Finalize(random.New())
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerifyPoints(suite, pub.Points(), msgs, sig, dst))
+		require.Equal(t, ErrInvalidSignature, PSBatchVerifyPoints(suite, pub.Points(), msgs, sig))
+
+		// The signer keeps its own copy of the key.
+		scalars := priv.Scalars()
+		signer, err = NewBatchSigner(suite, scalars)
+		require.Nil(t, err)
+		scalars[1].Zero()
+		require.Nil(t, signer.Add(msgs[0]))
+		sig, err = signer.Finalize(random.New())
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerifyPoints(suite, pub.Points(), msgs[:1], sig))
+	})
+}
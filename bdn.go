@@ -0,0 +1,89 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Rogue-key resistant aggregation follows Boneh, Drijvers and Neven,
+// "Compact Multi-Signatures for Smaller Blockchains" (ASIACRYPT 2018). Each
+// key pk_i of the set pk_1,...,pk_n is raised to a coefficient a_i before the
+// keys are multiplied, and each signature alike before the signatures are.
+// A rogue key cannot cancel an honest one, since the coefficients depend on
+// the rogue key itself. No proofs of possession are needed.
+//
+// The coefficients are derived as
+//
+//	a_i = H("PS-BDN-<CURVE>-V1", n || K_1 || ... || K_n || i),
+//
+// for i = 0,...,n-1, where n and i are 16-bit big-endian integers and K_j is
+// the canonical encoding of pk_j: its suite identifier byte, its number r of
+// Y points as a 16-bit big-endian integer, then X and Y_1,...,Y_r as
+// uncompressed G2 points. H is the hash to scalars used for messages, see
+// hash.go. The coefficients depend on the order of the keys, which signers
+// and verifiers must agree on.
+
+// aggregationCoefficients derives the coefficients a_i of the key set pubs.
+func aggregationCoefficients(suite pairing.Suite, pubs []*PublicKey) ([]kyber.Scalar, error) {
+	buf, err := appendCount(nil, len(pubs))
+	if err != nil {
+		return nil, err
+	}
+	for i, pub := range pubs {
+		enc, err := pub.canonical()
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %d", err, i)
+		}
+		buf = append(buf, enc...)
+	}
+	dst := protocolDST(suite, "BDN")
+	coeffs := make([]kyber.Scalar, len(pubs))
+	for i := range coeffs {
+		input, err := appendCount(append([]byte{}, buf...), i)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = hashToScalar(suite, dst, input)
+	}
+	return coeffs, nil
+}
+
+// AggregatePublicKeysSecure aggregates the keys of a multi-signature with
+// rogue-key resistant coefficients, so that keys need no proof of
+// possession. Signatures are combined with CombineSignaturesSecure on the
+// same keys, in the same order, and checked with VerifyMultiSig.
+func AggregatePublicKeysSecure(suite pairing.Suite, pubs []*PublicKey) (*PublicKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if len(pubs) == 0 {
+		return nil, ErrNilKey
+	}
+	coeffs, err := aggregationCoefficients(suite, pubs)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateKeys(suite, pubs, coeffs)
+}
+
+// CombineSignaturesSecure combines the signatures made by MultiSign, sigs[i]
+// by the owner of pubs[i], into a multi-signature verifying under
+// AggregatePublicKeysSecure of pubs.
+func CombineSignaturesSecure(suite pairing.Suite, pubs []*PublicKey, sigs []*Signature) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if len(pubs) != len(sigs) {
+		return nil, fmt.Errorf("%w: %d keys for %d signatures", ErrKeyLengthMismatch, len(pubs), len(sigs))
+	}
+	if len(pubs) == 0 {
+		return nil, ErrNilSignature
+	}
+	coeffs, err := aggregationCoefficients(suite, pubs)
+	if err != nil {
+		return nil, err
+	}
+	return combineSignatures(suite, sigs, coeffs)
+}
@@ -0,0 +1,95 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestAggregateSecure(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("committee decision 42")
+		h, err := MultiSigBase(suite, msg)
+		require.Nil(t, err)
+		var pubs []*PublicKey
+		var sigs []*Signature
+		for i := 0; i < 3; i++ {
+			priv, pub := newTestKeys(t, suite, 1)
+			sig, err := MultiSign(suite, priv, h, msg)
+			require.Nil(t, err)
+			pubs, sigs = append(pubs, pub), append(sigs, sig)
+		}
+
+		agg, err := AggregatePublicKeysSecure(suite, pubs)
+		require.Nil(t, err)
+		multi, err := CombineSignaturesSecure(suite, pubs, sigs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyMultiSig(suite, agg, msg, multi))
+
+		// The coefficients are deterministic and depend on the key set.
+		again, err := AggregatePublicKeysSecure(suite, pubs)
+		require.Nil(t, err)
+		require.True(t, again.X.Equal(agg.X))
+		plain, err := AggregatePublicKeys(suite, pubs)
+		require.Nil(t, err)
+		require.False(t, plain.X.Equal(agg.X))
+		a, err := aggregationCoefficients(suite, pubs)
+		require.Nil(t, err)
+		b, err := aggregationCoefficients(suite, pubs[:2])
+		require.Nil(t, err)
+		require.False(t, a[0].Equal(b[0]))
+
+		// Leaving out a signer, or combining without coefficients, fails.
+		partial, err := CombineSignaturesSecure(suite, pubs[:2], sigs[:2])
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, msg, partial))
+		unweighted, err := CombineSignatures(suite, sigs)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, msg, unweighted))
+
+		_, err = CombineSignaturesSecure(suite, pubs[:2], sigs)
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = AggregatePublicKeysSecure(suite, nil)
+		require.Equal(t, ErrNilKey, err)
+	})
+}
+
+func TestAggregateSecureRogueKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("committee decision 42")
+		_, target := newTestKeys(t, suite, 1)
+		chosen, chosenPub := newTestKeys(t, suite, 1)
+
+		// rogue = chosen / target, so that target * rogue = chosen.
+		rogue := &PublicKey{
+			suite: suite,
+			X:     suite.G2().Point().Sub(chosenPub.X, target.X),
+			Y:     []kyber.Point{suite.G2().Point().Sub(chosenPub.Y[0], target.Y[0])},
+		}
+		pubs := []*PublicKey{target, rogue}
+		h, err := MultiSigBase(suite, msg)
+		require.Nil(t, err)
+		forged, err := MultiSign(suite, chosen, h, msg)
+		require.Nil(t, err)
+
+		// Plain aggregation falls for it.
+		plain, err := AggregatePublicKeys(suite, pubs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyMultiSig(suite, plain, msg, forged))
+
+		// With coefficients, neither the chosen key's signature nor its
+		// weighted variants verify.
+		agg, err := AggregatePublicKeysSecure(suite, pubs)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, msg, forged))
+		coeffs, err := aggregationCoefficients(suite, pubs)
+		require.Nil(t, err)
+		for _, c := range coeffs {
+			scaled := &Signature{suite: suite, Sigma1: forged.Sigma1, Sigma2: suite.G1().Point().Mul(c, forged.Sigma2)}
+			require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, msg, scaled))
+		}
+	})
+}
@@ -0,0 +1,417 @@
+package ps
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Blind issuance follows section 6.1 of Pointcheval and Sanders, "Short
+// Randomizable Signatures". The holder of messages m_1,...,m_k commits to
+// them in G1 as
+//
+//	C = g^t * Y_1^(m_1) * ... * Y_k^(m_k)
+//
+// for a random t, where Y_i = g^(y_i) are published in the signer's
+// BlindingKey, and proves knowledge of the opening (t, m_1,...,m_k) with a
+// Schnorr proof made non-interactive by Fiat-Shamir. C is a uniformly random
+// point whatever the messages, so the signer learns nothing about them. The
+// signer picks u and returns the blind signature
+//
+//	(g^u, (X * C)^u), where X = g^x,
+//
+// which the holder unblinds to (g^u, (X * C)^u / (g^u)^t), an ordinary
+// signature (h, h^(x + y_1*m_1 + ... + y_k*m_k)) on the messages in slots 1 to
+// k. Messages are hashed to scalars as by BatchSign.
+
+// ErrInvalidBlindRequest is returned by BlindSign for requests whose proof of
+// knowledge does not verify.
+var ErrInvalidBlindRequest = errors.New("ps: invalid blind sign request")
+
+// BlindingKey holds the points Y_i = g^(y_i) on the curve G1 that holders
+// need to commit to messages. Signers publish it next to the public key.
+type BlindingKey struct {
+	suite pairing.Suite
+	Y     []kyber.Point
+}
+
+// BlindingKey derives the blinding key matching the private key. It returns
+// nil for an incomplete key.
+func (k *PrivateKey) BlindingKey() *BlindingKey {
+	if k.check() != nil {
+		return nil
+	}
+	bk := &BlindingKey{suite: k.suite}
+	for _, y := range k.Y {
+		bk.Y = append(bk.Y, k.suite.G1().Point().Mul(y, nil))
+	}
+	return bk
+}
+
+// check reports whether the key is complete, see PrivateKey.check.
+func (k *BlindingKey) check() error {
+	if k == nil {
+		return ErrNilKey
+	}
+	if k.suite == nil {
+		return ErrNilSuite
+	}
+	return checkPublicKey(k.Y, 1)
+}
+
+// checkMatches ensures the blinding key has the exponents of pub, that is
+// e(Y_i, g2) = e(g1, Y'_i) for the G2 points Y'_i of pub.
+func (k *BlindingKey) checkMatches(pub *PublicKey) error {
+	if len(k.Y) != len(pub.Y) {
+		return fmt.Errorf("%w: blinding key has %d points, public key %d", ErrKeyLengthMismatch, len(k.Y), len(pub.Y))
+	}
+	g1, g2 := k.suite.G1().Point().Base(), k.suite.G2().Point().Base()
+	for i, y := range k.Y {
+		if !k.suite.Pair(y, g2).Equal(k.suite.Pair(g1, pub.Y[i])) {
+			return fmt.Errorf("%w: blinding key point %d does not match the public key", ErrInvalidPoint, i)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the blinding key as header || r || Y_1 || ... || Y_r.
+func (k *BlindingKey) MarshalBinary() ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(k.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(k.Y)); err != nil {
+		return nil, err
+	}
+	return appendPoints(buf, k.suite.G1(), false, k.Y...)
+}
+
+// UnmarshalBinary decodes a blinding key, see PrivateKey.UnmarshalBinary.
+func (k *BlindingKey) UnmarshalBinary(data []byte) error {
+	if k == nil {
+		return ErrNilKey
+	}
+	suite, body, newer, err := readHeader(k.suite, data)
+	if err != nil {
+		return err
+	}
+	r, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	points, rest, err := decodePoints(suite.G1(), body, r, false)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	dec := &BlindingKey{suite: suite, Y: points}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*k = *dec
+	return nil
+}
+
+// UnmarshalBlindingKey decodes a blinding key produced under suite.
+func UnmarshalBlindingKey(suite pairing.Suite, data []byte) (*BlindingKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	k := &BlindingKey{suite: suite}
+	if err := k.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// BlindSignRequest is what the holder sends to the signer: the commitment C
+// to the messages and the proof (c, z_0,...,z_k) that the holder knows its
+// opening, z_0 answering for t and z_i for m_i.
+type BlindSignRequest struct {
+	suite      pairing.Suite
+	Commitment kyber.Point
+	Challenge  kyber.Scalar
+	Responses  []kyber.Scalar
+}
+
+// check reports whether the request is complete.
+func (r *BlindSignRequest) check() error {
+	if r == nil || r.Commitment == nil || r.Challenge == nil {
+		return fmt.Errorf("%w: incomplete", ErrInvalidBlindRequest)
+	}
+	if r.suite == nil {
+		return ErrNilSuite
+	}
+	if len(r.Responses) < 2 {
+		return fmt.Errorf("%w: %d responses", ErrInvalidBlindRequest, len(r.Responses))
+	}
+	for i, z := range r.Responses {
+		if z == nil {
+			return fmt.Errorf("%w: response %d is nil", ErrInvalidBlindRequest, i)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the request as header || k || C || c || z_0 || ... ||
+// z_k.
+func (r *BlindSignRequest) MarshalBinary() ([]byte, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(r.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(r.Responses)-1); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, r.suite.G1(), false, r.Commitment); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, r.suite.G1(), append([]kyber.Scalar{r.Challenge}, r.Responses...)...)
+}
+
+// UnmarshalBinary decodes a request, see PrivateKey.UnmarshalBinary.
+func (r *BlindSignRequest) UnmarshalBinary(data []byte) error {
+	if r == nil {
+		return ErrInvalidBlindRequest
+	}
+	suite, body, newer, err := readHeader(r.suite, data)
+	if err != nil {
+		return err
+	}
+	k, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 1, false)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, k+2)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	dec := &BlindSignRequest{suite: suite, Commitment: points[0], Challenge: scalars[0], Responses: scalars[1:]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*r = *dec
+	return nil
+}
+
+// UnmarshalBlindSignRequest decodes a request produced under suite.
+func UnmarshalBlindSignRequest(suite pairing.Suite, data []byte) (*BlindSignRequest, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	r := &BlindSignRequest{suite: suite}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BlindState is the holder's secret from PrepareBlindSign, needed to unblind
+// the signature. It must not be sent to the signer.
+type BlindState struct {
+	suite pairing.Suite
+	pub   *PublicKey
+	msgs  [][]byte
+	opts  []Option
+	t     kyber.Scalar
+}
+
+// commit computes g^s_0 * Y_1^(s_1) * ... * Y_k^(s_k).
+func commit(suite pairing.Suite, bk *BlindingKey, s []kyber.Scalar) kyber.Point {
+	c := suite.G1().Point().Mul(s[0], nil)
+	for i, si := range s[1:] {
+		c.Add(c, suite.G1().Point().Mul(si, bk.Y[i]))
+	}
+	return c
+}
+
+// blindChallenge derives the Fiat-Shamir challenge binding the proof to the
+// commitment C, the prover's first message R and the signer's keys.
+func blindChallenge(suite pairing.Suite, pub *PublicKey, bk *BlindingKey, C, R kyber.Point) (kyber.Scalar, error) {
+	buf, err := pub.canonical()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.G1(), false, bk.Y...); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.G1(), false, C, R); err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "BLIND"), buf), nil
+}
+
+// PrepareBlindSign commits to msgs, which take slots 1 to len(msgs) of the
+// signer's keys pubKey and bk, and returns the request for the signer along
+// with the state to unblind its answer. Randomness is read from rand.
+func PrepareBlindSign(suite pairing.Suite, pubKey *PublicKey, bk *BlindingKey, msgs [][]byte, rand cipher.Stream, opts ...Option) (*BlindSignRequest, *BlindState, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := pubKey.check(); err != nil {
+		return nil, nil, err
+	}
+	if err := bk.check(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkMessageCount(len(pubKey.Y)+1, len(msgs)); err != nil {
+		return nil, nil, err
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, nil, err
+	}
+	if err := bk.checkMatches(pubKey); err != nil {
+		return nil, nil, err
+	}
+
+	// witness = (t, m_1,...,m_k), nonces = (a_0,...,a_k)
+	witness := make([]kyber.Scalar, len(msgs)+1)
+	nonces := make([]kyber.Scalar, len(msgs)+1)
+	for i := range nonces {
+		if nonces[i], err = pickScalar(suite, rand); err != nil {
+			return nil, nil, err
+		}
+	}
+	if witness[0], err = pickScalar(suite, rand); err != nil {
+		return nil, nil, err
+	}
+	for i, msg := range msgs {
+		witness[i+1] = hashToScalar(suite, o.dst, msg)
+	}
+	C := commit(suite, bk, witness)
+	R := commit(suite, bk, nonces)
+	c, err := blindChallenge(suite, pubKey, bk, C, R)
+	if err != nil {
+		return nil, nil, err
+	}
+	responses := make([]kyber.Scalar, len(nonces))
+	for i := range responses {
+		responses[i] = newScalar(suite).Mul(c, witness[i])
+		responses[i].Add(responses[i], nonces[i])
+		nonces[i].Zero()
+	}
+
+	req := &BlindSignRequest{suite: suite, Commitment: C, Challenge: c, Responses: responses}
+	state := &BlindState{
+		suite: suite,
+		pub:   pubKey,
+		msgs:  append([][]byte{}, msgs...),
+		opts:  append([]Option{}, opts...),
+		t:     witness[0],
+	}
+	return req, state, nil
+}
+
+// BlindSign signs the commitment of a blind sign request after checking its
+// proof of knowledge, without learning the committed messages.
+func BlindSign(suite pairing.Suite, priKey *PrivateKey, req *BlindSignRequest) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	k := len(req.Responses) - 1
+	if err := checkMessageCount(len(priKey.Y)+1, k); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allowCount(OpBlindSign, k); err != nil {
+		return nil, err
+	}
+	if isIdentity(suite.G1(), req.Commitment) {
+		return nil, fmt.Errorf("%w: identity commitment", ErrInvalidBlindRequest)
+	}
+	if err := checkSubgroup(suite.G1(), req.Commitment); err != nil {
+		return nil, err
+	}
+
+	pub := priKey.Public()
+	bk := priKey.BlindingKey()
+	// R = g^(z_0) * Y_1^(z_1) * ... * Y_k^(z_k) / C^c
+	R := commit(suite, bk, req.Responses)
+	R.Sub(R, suite.G1().Point().Mul(req.Challenge, req.Commitment))
+	c, err := blindChallenge(suite, pub, bk, req.Commitment, R)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Equal(req.Challenge) {
+		return nil, fmt.Errorf("%w: proof of knowledge does not verify", ErrInvalidBlindRequest)
+	}
+
+	return signCommitted(suite, priKey, req.Commitment)
+}
+
+// signCommitted signs the commitment C as (g^u, (X * C)^u) for a random u,
+// and reports the signature of C to the audit hook of priKey.
+func signCommitted(suite pairing.Suite, priKey *PrivateKey, C kyber.Point) (*Signature, error) {
+	u, err := pickScalar(suite, suite.RandomStream())
+	if err != nil {
+		return nil, err
+	}
+	s2 := suite.G1().Point().Mul(priKey.X, nil)
+	s2.Add(s2, C)
+	s2.Mul(u, s2)
+	sig := &Signature{suite: suite, Sigma1: suite.G1().Point().Mul(u, nil), Sigma2: s2}
+	if priKey.audit != nil {
+		c, err := C.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := priKey.audit.signedSignature(context.Background(), [][]byte{c}, sig); err != nil {
+			return nil, err
+		}
+	}
+	return sig, nil
+}
+
+// Unblind turns the signer's answer to a blind sign request into a signature
+// on the committed messages, checking it with PSBatchVerify under the public
+// key given to PrepareBlindSign.
+func Unblind(suite pairing.Suite, state *BlindState, blindSig *Signature) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if state == nil || state.t == nil {
+		return nil, errors.New("ps: nil blind state")
+	}
+	if err := blindSig.check(); err != nil {
+		return nil, err
+	}
+	s2 := suite.G1().Point().Mul(state.t, blindSig.Sigma1)
+	s2.Sub(blindSig.Sigma2, s2)
+	sig := &Signature{suite: suite, Sigma1: blindSig.Sigma1.Clone(), Sigma2: s2}
+	S, err := sig.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := PSBatchVerify(suite, state.pub.Points(), state.msgs, S, state.opts...); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -0,0 +1,116 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBlindSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		buf, err := priv.BlindingKey().MarshalBinary()
+		require.Nil(t, err)
+		bk, err := UnmarshalBlindingKey(suite, buf)
+		require.Nil(t, err)
+
+		for k := 1; k <= len(msgs); k++ {
+			req, state, err := PrepareBlindSign(suite, pub, bk, msgs[:k], random.New())
+			require.Nil(t, err)
+
+			// The request travels to the signer in binary form.
+			buf, err := req.MarshalBinary()
+			require.Nil(t, err)
+			received, err := UnmarshalBlindSignRequest(suite, buf)
+			require.Nil(t, err)
+
+			blindSig, err := BlindSign(suite, priv, received)
+			require.Nil(t, err)
+			sig, err := Unblind(suite, state, blindSig)
+			require.Nil(t, err)
+			S, err := sig.Components()
+			require.Nil(t, err)
+			require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs[:k], S))
+			wrong := append([][]byte{[]byte("attribute 0")}, msgs[1:k]...)
+			require.NotNil(t, PSBatchVerify(suite, pub.Points(), wrong, S))
+		}
+	})
+}
+
+func TestBlindSignHiding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		a := [][]byte{[]byte("age=31"), []byte("country=FR")}
+		b := [][]byte{[]byte("age=67"), []byte("country=NZ")}
+		priv, pub := newTestKeys(t, suite, 2)
+		bk := priv.BlindingKey()
+
+		reqA, _, err := PrepareBlindSign(suite, pub, bk, a, random.New())
+		require.Nil(t, err)
+		reqB, _, err := PrepareBlindSign(suite, pub, bk, b, random.New())
+		require.Nil(t, err)
+		bufA, err := reqA.MarshalBinary()
+		require.Nil(t, err)
+		bufB, err := reqB.MarshalBinary()
+		require.Nil(t, err)
+		require.Equal(t, len(bufA), len(bufB))
+
+		// Every commitment to a opens to b as well: for C = g^t * Y^a there
+		// is t' = t + sum(y_i*(a_i - b_i)) with C = g^(t') * Y^b. The signer's
+		// view is therefore the same distribution for both attribute sets.
+		dst := DefaultDST(suite)
+		tA := newScalar(suite).Pick(random.New())
+		tB := tA.Clone()
+		wA, wB := []kyber.Scalar{tA}, []kyber.Scalar{tB}
+		for i := range a {
+			mA, mB := hashToScalar(suite, dst, a[i]), hashToScalar(suite, dst, b[i])
+			wA, wB = append(wA, mA), append(wB, mB)
+			d := newScalar(suite).Sub(mA, mB)
+			tB.Add(tB, d.Mul(d, priv.Y[i]))
+		}
+		require.True(t, commit(suite, bk, wA).Equal(commit(suite, bk, wB)))
+	})
+}
+
+func TestBlindSignInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, 2)
+		other, otherPub := newTestKeys(t, suite, 2)
+
+		_, _, err := PrepareBlindSign(suite, pub, other.BlindingKey(), msgs, random.New())
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+		_, _, err = PrepareBlindSign(suite, pub, priv.BlindingKey(), append(msgs, msgs[0]), random.New())
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+
+		req, state, err := PrepareBlindSign(suite, pub, priv.BlindingKey(), msgs, random.New())
+		require.Nil(t, err)
+
+		// The proof is bound to the signer's keys.
+		_, err = BlindSign(suite, other, req)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		forged := *req
+		forged.Responses = append([]kyber.Scalar{}, req.Responses...)
+		forged.Responses[1] = newScalar(suite).Pick(random.New())
+		_, err = BlindSign(suite, priv, &forged)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		forged = *req
+		forged.Commitment = suite.G1().Point().Pick(random.New())
+		_, err = BlindSign(suite, priv, &forged)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		// A blind signature from the wrong signer does not unblind.
+		otherReq, _, err := PrepareBlindSign(suite, otherPub, other.BlindingKey(), msgs, random.New())
+		require.Nil(t, err)
+		blindSig, err := BlindSign(suite, other, otherReq)
+		require.Nil(t, err)
+		_, err = Unblind(suite, state, blindSig)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+	})
+}
@@ -0,0 +1,325 @@
+package bls12381
+
+import (
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	circl "github.com/cloudflare/circl/ecc/bls12381"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var (
+	domainG1 = []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_KYBER_PS_")
+	domainG2 = []byte("BLS12381G2_XMD:SHA-256_SSWU_RO_KYBER_PS_")
+)
+
+var errUnsupported = "bls12381: unsupported operation"
+
+func marshalTo(m kyber.Marshaling, w io.Writer) (int, error) {
+	buf, err := m.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+func unmarshalFrom(m kyber.Marshaling, r io.Reader) (int, error) {
+	buf := make([]byte, m.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, m.UnmarshalBinary(buf)
+}
+
+func pickSeed(rand cipher.Stream) []byte {
+	return random.Bits(256, false, rand)
+}
+
+// G1Elt is a point on the G1 group, marshalled in compressed form.
+type G1Elt struct {
+	inner circl.G1
+}
+
+func newG1() *G1Elt {
+	p := new(G1Elt)
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *G1Elt) point(a kyber.Point) *circl.G1 {
+	return &a.(*G1Elt).inner
+}
+
+func (p *G1Elt) MarshalBinary() ([]byte, error) { return p.inner.BytesCompressed(), nil }
+
+// UnmarshalBinary decodes a compressed point and checks that it lies in the
+// prime-order subgroup.
+func (p *G1Elt) UnmarshalBinary(data []byte) error {
+	if len(data) != p.MarshalSize() {
+		return errors.New("bls12381: wrong G1 point length")
+	}
+	return p.inner.SetBytes(data)
+}
+
+func (p *G1Elt) String() string                         { return hex.EncodeToString(p.inner.BytesCompressed()) }
+func (p *G1Elt) MarshalSize() int                       { return circl.G1SizeCompressed }
+func (p *G1Elt) MarshalTo(w io.Writer) (int, error)     { return marshalTo(p, w) }
+func (p *G1Elt) UnmarshalFrom(r io.Reader) (int, error) { return unmarshalFrom(p, r) }
+func (p *G1Elt) Equal(p2 kyber.Point) bool              { return p.inner.IsEqual(p.point(p2)) }
+
+func (p *G1Elt) Null() kyber.Point {
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *G1Elt) Base() kyber.Point {
+	p.inner = *circl.G1Generator()
+	return p
+}
+
+// Pick sets the point to a random group element with unknown discrete log.
+func (p *G1Elt) Pick(rand cipher.Stream) kyber.Point {
+	p.inner.Hash(pickSeed(rand), domainG1)
+	return p
+}
+
+func (p *G1Elt) Set(p2 kyber.Point) kyber.Point {
+	p.inner = *p.point(p2)
+	return p
+}
+
+func (p *G1Elt) Clone() kyber.Point {
+	c := new(G1Elt)
+	c.inner = p.inner
+	return c
+}
+
+func (p *G1Elt) EmbedLen() int                                  { panic(errUnsupported) }
+func (p *G1Elt) Embed(data []byte, r cipher.Stream) kyber.Point { panic(errUnsupported) }
+func (p *G1Elt) Data() ([]byte, error)                          { return nil, errors.New(errUnsupported) }
+
+func (p *G1Elt) Add(a, b kyber.Point) kyber.Point {
+	p.inner.Add(p.point(a), p.point(b))
+	return p
+}
+
+func (p *G1Elt) Sub(a, b kyber.Point) kyber.Point {
+	neg := *p.point(b)
+	neg.Neg()
+	p.inner.Add(p.point(a), &neg)
+	return p
+}
+
+func (p *G1Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner = *p.point(a)
+	p.inner.Neg()
+	return p
+}
+
+// Mul sets the point to s*q, or to s times the generator when q is nil.
+func (p *G1Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	base := circl.G1Generator()
+	if q != nil {
+		base = p.point(q)
+	}
+	p.inner.ScalarMult(&s.(*Scalar).inner, base)
+	return p
+}
+
+// Hash maps msg to a point of G1.
+func (p *G1Elt) Hash(msg []byte) kyber.Point {
+	p.inner.Hash(msg, domainG1)
+	return p
+}
+
+// G2Elt is a point on the G2 group, marshalled in compressed form.
+type G2Elt struct {
+	inner circl.G2
+}
+
+func newG2() *G2Elt {
+	p := new(G2Elt)
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *G2Elt) point(a kyber.Point) *circl.G2 {
+	return &a.(*G2Elt).inner
+}
+
+func (p *G2Elt) MarshalBinary() ([]byte, error) { return p.inner.BytesCompressed(), nil }
+
+// UnmarshalBinary decodes a compressed point and checks that it lies in the
+// prime-order subgroup.
+func (p *G2Elt) UnmarshalBinary(data []byte) error {
+	if len(data) != p.MarshalSize() {
+		return errors.New("bls12381: wrong G2 point length")
+	}
+	return p.inner.SetBytes(data)
+}
+
+func (p *G2Elt) String() string                         { return hex.EncodeToString(p.inner.BytesCompressed()) }
+func (p *G2Elt) MarshalSize() int                       { return circl.G2SizeCompressed }
+func (p *G2Elt) MarshalTo(w io.Writer) (int, error)     { return marshalTo(p, w) }
+func (p *G2Elt) UnmarshalFrom(r io.Reader) (int, error) { return unmarshalFrom(p, r) }
+func (p *G2Elt) Equal(p2 kyber.Point) bool              { return p.inner.IsEqual(p.point(p2)) }
+
+func (p *G2Elt) Null() kyber.Point {
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *G2Elt) Base() kyber.Point {
+	p.inner = *circl.G2Generator()
+	return p
+}
+
+// Pick sets the point to a random group element with unknown discrete log.
+func (p *G2Elt) Pick(rand cipher.Stream) kyber.Point {
+	p.inner.Hash(pickSeed(rand), domainG2)
+	return p
+}
+
+func (p *G2Elt) Set(p2 kyber.Point) kyber.Point {
+	p.inner = *p.point(p2)
+	return p
+}
+
+func (p *G2Elt) Clone() kyber.Point {
+	c := new(G2Elt)
+	c.inner = p.inner
+	return c
+}
+
+func (p *G2Elt) EmbedLen() int                                  { panic(errUnsupported) }
+func (p *G2Elt) Embed(data []byte, r cipher.Stream) kyber.Point { panic(errUnsupported) }
+func (p *G2Elt) Data() ([]byte, error)                          { return nil, errors.New(errUnsupported) }
+
+func (p *G2Elt) Add(a, b kyber.Point) kyber.Point {
+	p.inner.Add(p.point(a), p.point(b))
+	return p
+}
+
+func (p *G2Elt) Sub(a, b kyber.Point) kyber.Point {
+	neg := *p.point(b)
+	neg.Neg()
+	p.inner.Add(p.point(a), &neg)
+	return p
+}
+
+func (p *G2Elt) Neg(a kyber.Point) kyber.Point {
+	p.inner = *p.point(a)
+	p.inner.Neg()
+	return p
+}
+
+// Mul sets the point to s*q, or to s times the generator when q is nil.
+func (p *G2Elt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	base := circl.G2Generator()
+	if q != nil {
+		base = p.point(q)
+	}
+	p.inner.ScalarMult(&s.(*Scalar).inner, base)
+	return p
+}
+
+// Hash maps msg to a point of G2.
+func (p *G2Elt) Hash(msg []byte) kyber.Point {
+	p.inner.Hash(msg, domainG2)
+	return p
+}
+
+// GTElt is an element of the target group. Following kyber's convention the
+// group law is written additively, so Add multiplies and Mul exponentiates.
+type GTElt struct {
+	inner circl.Gt
+}
+
+func newGT() *GTElt {
+	p := new(GTElt)
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *GTElt) point(a kyber.Point) *circl.Gt {
+	return &a.(*GTElt).inner
+}
+
+func (p *GTElt) MarshalBinary() ([]byte, error) { return p.inner.MarshalBinary() }
+
+func (p *GTElt) UnmarshalBinary(data []byte) error {
+	if len(data) != p.MarshalSize() {
+		return errors.New("bls12381: wrong GT element length")
+	}
+	return p.inner.UnmarshalBinary(data)
+}
+
+func (p *GTElt) String() string                         { return p.inner.String() }
+func (p *GTElt) MarshalSize() int                       { return circl.GtSize }
+func (p *GTElt) MarshalTo(w io.Writer) (int, error)     { return marshalTo(p, w) }
+func (p *GTElt) UnmarshalFrom(r io.Reader) (int, error) { return unmarshalFrom(p, r) }
+func (p *GTElt) Equal(p2 kyber.Point) bool              { return p.inner.IsEqual(p.point(p2)) }
+
+func (p *GTElt) Null() kyber.Point {
+	p.inner.SetIdentity()
+	return p
+}
+
+func (p *GTElt) Base() kyber.Point {
+	p.inner = *circl.Pair(circl.G1Generator(), circl.G2Generator())
+	return p
+}
+
+func (p *GTElt) Pick(rand cipher.Stream) kyber.Point {
+	s := new(Scalar).Pick(rand)
+	return p.Mul(s, nil)
+}
+
+func (p *GTElt) Set(p2 kyber.Point) kyber.Point {
+	p.inner = *p.point(p2)
+	return p
+}
+
+func (p *GTElt) Clone() kyber.Point {
+	c := new(GTElt)
+	c.inner = p.inner
+	return c
+}
+
+func (p *GTElt) EmbedLen() int                                  { panic(errUnsupported) }
+func (p *GTElt) Embed(data []byte, r cipher.Stream) kyber.Point { panic(errUnsupported) }
+func (p *GTElt) Data() ([]byte, error)                          { return nil, errors.New(errUnsupported) }
+
+func (p *GTElt) Add(a, b kyber.Point) kyber.Point {
+	p.inner.Mul(p.point(a), p.point(b))
+	return p
+}
+
+func (p *GTElt) Sub(a, b kyber.Point) kyber.Point {
+	inv := new(circl.Gt)
+	inv.Inv(p.point(b))
+	p.inner.Mul(p.point(a), inv)
+	return p
+}
+
+func (p *GTElt) Neg(a kyber.Point) kyber.Point {
+	p.inner.Inv(p.point(a))
+	return p
+}
+
+// Mul sets the element to q^s, or to the pairing of the generators raised to
+// s when q is nil.
+func (p *GTElt) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	var base *circl.Gt
+	if q != nil {
+		base = p.point(q)
+	} else {
+		base = &newGT().Base().(*GTElt).inner
+	}
+	p.inner.Exp(base, &s.(*Scalar).inner)
+	return p
+}
@@ -0,0 +1,136 @@
+package bls12381
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+	"math/big"
+
+	circl "github.com/cloudflare/circl/ecc/bls12381"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var order = new(big.Int).SetBytes(circl.Order())
+
+// Scalar is an element of the scalar field shared by G1, G2 and GT.
+type Scalar struct {
+	inner circl.Scalar
+}
+
+func (s *Scalar) scalar(a kyber.Scalar) *circl.Scalar {
+	return &a.(*Scalar).inner
+}
+
+// MarshalBinary returns the big-endian encoding of the scalar.
+func (s *Scalar) MarshalBinary() ([]byte, error) { return s.inner.MarshalBinary() }
+
+// UnmarshalBinary reads a big-endian scalar that must be reduced.
+func (s *Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != s.MarshalSize() {
+		return errors.New("bls12381: wrong scalar length")
+	}
+	return s.inner.UnmarshalBinary(data)
+}
+
+func (s *Scalar) String() string { return s.inner.String() }
+
+// MarshalSize returns the length of a marshalled scalar.
+func (s *Scalar) MarshalSize() int { return circl.ScalarSize }
+
+// MarshalTo writes the marshalled scalar to w.
+func (s *Scalar) MarshalTo(w io.Writer) (int, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// UnmarshalFrom reads a marshalled scalar from r.
+func (s *Scalar) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, s.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, s.UnmarshalBinary(buf)
+}
+
+func (s *Scalar) Equal(s2 kyber.Scalar) bool { return s.inner.IsEqual(s.scalar(s2)) == 1 }
+
+func (s *Scalar) Set(a kyber.Scalar) kyber.Scalar {
+	s.inner.Set(s.scalar(a))
+	return s
+}
+
+func (s *Scalar) Clone() kyber.Scalar {
+	c := new(Scalar)
+	c.inner.Set(&s.inner)
+	return c
+}
+
+func (s *Scalar) SetInt64(v int64) kyber.Scalar {
+	if v >= 0 {
+		s.inner.SetUint64(uint64(v))
+		return s
+	}
+	s.inner.SetUint64(uint64(-v))
+	s.inner.Neg()
+	return s
+}
+
+func (s *Scalar) Zero() kyber.Scalar {
+	s.inner.SetUint64(0)
+	return s
+}
+
+func (s *Scalar) Add(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Add(s.scalar(a), s.scalar(b))
+	return s
+}
+
+func (s *Scalar) Sub(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Sub(s.scalar(a), s.scalar(b))
+	return s
+}
+
+func (s *Scalar) Neg(a kyber.Scalar) kyber.Scalar {
+	s.inner.Set(s.scalar(a))
+	s.inner.Neg()
+	return s
+}
+
+func (s *Scalar) One() kyber.Scalar {
+	s.inner.SetOne()
+	return s
+}
+
+func (s *Scalar) Mul(a, b kyber.Scalar) kyber.Scalar {
+	s.inner.Mul(s.scalar(a), s.scalar(b))
+	return s
+}
+
+func (s *Scalar) Div(a, b kyber.Scalar) kyber.Scalar {
+	inv := new(circl.Scalar)
+	inv.Inv(s.scalar(b))
+	s.inner.Mul(s.scalar(a), inv)
+	return s
+}
+
+func (s *Scalar) Inv(a kyber.Scalar) kyber.Scalar {
+	s.inner.Inv(s.scalar(a))
+	return s
+}
+
+// Pick sets the scalar to a uniformly random value read from rand.
+func (s *Scalar) Pick(rand cipher.Stream) kyber.Scalar {
+	s.inner.SetBytes(random.Int(order, rand).Bytes())
+	return s
+}
+
+// SetBytes interprets buf as a big-endian integer reduced modulo the order.
+func (s *Scalar) SetBytes(buf []byte) kyber.Scalar {
+	s.inner.SetBytes(buf)
+	return s
+}
@@ -0,0 +1,122 @@
+// Package bls12381 provides a kyber pairing suite over the BLS12-381 curve,
+// backed by the CIRCL implementation. Points are marshalled in the compressed
+// form of the IETF pairing-friendly curves draft.
+package bls12381
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	circl "github.com/cloudflare/circl/ecc/bls12381"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+)
+
+type groupG1 struct{}
+
+func (g *groupG1) String() string       { return "bls12381.G1" }
+func (g *groupG1) ScalarLen() int       { return circl.ScalarSize }
+func (g *groupG1) Scalar() kyber.Scalar { return new(Scalar) }
+func (g *groupG1) PointLen() int        { return circl.G1SizeCompressed }
+func (g *groupG1) Point() kyber.Point   { return newG1() }
+
+type groupG2 struct{}
+
+func (g *groupG2) String() string       { return "bls12381.G2" }
+func (g *groupG2) ScalarLen() int       { return circl.ScalarSize }
+func (g *groupG2) Scalar() kyber.Scalar { return new(Scalar) }
+func (g *groupG2) PointLen() int        { return circl.G2SizeCompressed }
+func (g *groupG2) Point() kyber.Point   { return newG2() }
+
+type groupGT struct{}
+
+func (g *groupGT) String() string       { return "bls12381.GT" }
+func (g *groupGT) ScalarLen() int       { return circl.ScalarSize }
+func (g *groupGT) Scalar() kyber.Scalar { return new(Scalar) }
+func (g *groupGT) PointLen() int        { return circl.GtSize }
+func (g *groupGT) Point() kyber.Point   { return newGT() }
+
+// Suite implements pairing.Suite for BLS12-381.
+type Suite struct {
+	g1 groupG1
+	g2 groupG2
+	gt groupGT
+}
+
+// NewSuite returns a BLS12-381 pairing suite.
+func NewSuite() *Suite {
+	return &Suite{}
+}
+
+func (s *Suite) G1() kyber.Group { return &s.g1 }
+func (s *Suite) G2() kyber.Group { return &s.g2 }
+func (s *Suite) GT() kyber.Group { return &s.gt }
+
+// Pair computes the optimal ate pairing of a G1 and a G2 point.
+func (s *Suite) Pair(p1, p2 kyber.Point) kyber.Point {
+	return &GTElt{inner: *circl.Pair(&p1.(*G1Elt).inner, &p2.(*G2Elt).inner)}
+}
+
+// PairingCheck reports whether the product of the pairings e(p1[i], p2[i])
+// is the identity of GT. The Miller loops share one final exponentiation,
+// which makes the check cheaper than computing the pairings one by one.
+func (s *Suite) PairingCheck(p1, p2 []kyber.Point) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+	P := make([]*circl.G1, len(p1))
+	Q := make([]*circl.G2, len(p2))
+	signs := make([]int, len(p1))
+	for i := range p1 {
+		P[i], Q[i], signs[i] = &p1[i].(*G1Elt).inner, &p2[i].(*G2Elt).inner, 1
+	}
+	return circl.ProdPairFrac(P, Q, signs).IsIdentity()
+}
+
+// Hash returns a SHA-256 hash.
+func (s *Suite) Hash() hash.Hash { return sha256.New() }
+
+// XOF returns a blake2xb extendable output function seeded with seed.
+func (s *Suite) XOF(seed []byte) kyber.XOF { return blake2xb.New(seed) }
+
+// RandomStream returns a cipher.Stream reading from the system randomness.
+func (s *Suite) RandomStream() cipher.Stream { return random.New() }
+
+// Write marshals objs to w. Kyber objects use their own encoding, anything
+// else falls back to encoding/binary in big-endian order.
+func (s *Suite) Write(w io.Writer, objs ...interface{}) error {
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case kyber.Marshaling:
+			if _, err := o.MarshalTo(w); err != nil {
+				return err
+			}
+		default:
+			if err := binary.Write(w, binary.BigEndian, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Read unmarshals objs from r, see Write.
+func (s *Suite) Read(r io.Reader, objs ...interface{}) error {
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case kyber.Marshaling:
+			if _, err := o.UnmarshalFrom(r); err != nil {
+				return err
+			}
+		default:
+			if err := binary.Read(r, binary.BigEndian, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
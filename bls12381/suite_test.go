@@ -0,0 +1,73 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBilinearity(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	b := suite.G2().Scalar().Pick(random.New())
+
+	p1 := suite.G1().Point().Mul(a, nil)
+	p2 := suite.G2().Point().Mul(b, nil)
+	left := suite.Pair(p1, p2)
+
+	ab := suite.GT().Scalar().Mul(a, b)
+	right := suite.GT().Point().Mul(ab, suite.Pair(suite.G1().Point().Base(), suite.G2().Point().Base()))
+	require.True(t, left.Equal(right))
+	require.True(t, right.Equal(suite.GT().Point().Mul(ab, nil)))
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2(), suite.GT()} {
+		p := g.Point().Pick(random.New())
+		buf, err := p.MarshalBinary()
+		require.Nil(t, err)
+		require.Equal(t, g.PointLen(), len(buf))
+
+		q := g.Point()
+		require.Nil(t, q.UnmarshalBinary(buf))
+		require.True(t, p.Equal(q), g.String())
+
+		s := g.Scalar().Pick(random.New())
+		buf, err = s.MarshalBinary()
+		require.Nil(t, err)
+		require.Equal(t, g.ScalarLen(), len(buf))
+		r := g.Scalar()
+		require.Nil(t, r.UnmarshalBinary(buf))
+		require.True(t, s.Equal(r))
+	}
+}
+
+func TestGroupLaw(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		a := g.Scalar().Pick(random.New())
+		b := g.Scalar().Pick(random.New())
+		pa := g.Point().Mul(a, nil)
+		pb := g.Point().Mul(b, nil)
+		sum := g.Point().Mul(g.Scalar().Add(a, b), nil)
+		require.True(t, sum.Equal(g.Point().Add(pa, pb)), g.String())
+		require.True(t, pa.Equal(g.Point().Sub(sum, pb)), g.String())
+		require.True(t, g.Point().Null().Equal(g.Point().Add(pa, g.Point().Neg(pa))), g.String())
+	}
+}
+
+func TestPairingCheck(t *testing.T) {
+	suite := NewSuite()
+	a := suite.G1().Scalar().Pick(random.New())
+	p1 := suite.G1().Point().Mul(a, nil)
+	g1, g2 := suite.G1().Point().Base(), suite.G2().Point().Base()
+	p2 := suite.G2().Point().Mul(a, nil)
+
+	// e(a*g1, g2) * e(-g1, a*g2) = 1
+	require.True(t, suite.PairingCheck([]kyber.Point{p1, suite.G1().Point().Neg(g1)}, []kyber.Point{g2, p2}))
+	require.False(t, suite.PairingCheck([]kyber.Point{p1, g1}, []kyber.Point{g2, p2}))
+	require.False(t, suite.PairingCheck([]kyber.Point{p1}, []kyber.Point{g2, p2}))
+}
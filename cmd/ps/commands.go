@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// keygen writes a new private key to -out, encrypted with -encrypt, and
+// its public key to -pub. It prints the key ID.
+func keygen(e *env, args []string) error {
+	fs := newFlags(e, "keygen")
+	attrs := fs.Int("attrs", 0, "number of attribute slots")
+	name := fs.String("suite", "bn256", "pairing suite")
+	encrypt := fs.Bool("encrypt", false, "encrypt the key under a passphrase")
+	out := fs.String("out", "", "private key file to create")
+	pubOut := fs.String("pub", "", "public key file to write")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *attrs < 1 || *out == "" || fs.NArg() > 0 {
+		return errUsage
+	}
+	suite, err := ps.SuiteByName(*name)
+	if err != nil {
+		return err
+	}
+	priv, pub, err := ps.GenerateKey(suite, *attrs, nil)
+	if err != nil {
+		return err
+	}
+	defer priv.Wipe()
+
+	block := &pem.Block{Type: pemPrivateKey}
+	if *encrypt {
+		pass, err := e.passphrase(true)
+		if err != nil {
+			return err
+		}
+		block.Type = pemEncryptedPrivateKey
+		block.Headers = map[string]string{suiteHeader: suiteName(suite)}
+		block.Bytes, err = ps.EncryptPrivateKey(priv, pass)
+		if err != nil {
+			return err
+		}
+	} else if block.Bytes, err = priv.MarshalBinary(); err != nil {
+		return err
+	}
+	if err := e.writePEM(*out, block, true); err != nil {
+		return err
+	}
+	if *pubOut != "" {
+		if err := writePublicKey(e, *pubOut, pub); err != nil {
+			return err
+		}
+	}
+	return printKeyID(e, pub)
+}
+
+func writePublicKey(e *env, path string, pub *ps.PublicKey) error {
+	buf, err := pub.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return e.writePEM(path, &pem.Block{Type: pemPublicKey, Bytes: buf}, false)
+}
+
+func printKeyID(e *env, pub *ps.PublicKey) error {
+	id, err := pub.KeyID()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "key ID %s\n", id)
+	return nil
+}
+
+// pubkey writes the public key of -key, or prints its key ID and
+// fingerprint.
+func pubkey(e *env, args []string) error {
+	fs := newFlags(e, "pubkey")
+	key := fs.String("key", "", "private or public key file")
+	out := fs.String("out", "", "public key file to write")
+	fingerprint := fs.Bool("fingerprint", false, "print the key ID and fingerprint instead")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *key == "" || fs.NArg() > 0 {
+		return errUsage
+	}
+	pub, err := e.readPublicKey(*key)
+	if err != nil {
+		return err
+	}
+	if !*fingerprint {
+		return writePublicKey(e, *out, pub)
+	}
+	id, err := pub.KeyID()
+	if err != nil {
+		return err
+	}
+	fp, err := pub.Fingerprint()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "%s %x\n", id, fp)
+	return nil
+}
+
+func writeSignature(e *env, path string, sig *ps.Signature) error {
+	buf, err := sig.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return e.writePEM(path, &pem.Block{Type: pemSignature, Bytes: buf}, false)
+}
+
+// sign signs one message, on the first slot of the key.
+func sign(e *env, args []string) error {
+	fs := newFlags(e, "sign")
+	key := fs.String("key", "", "private key file")
+	multi := fs.Bool("multi", false, "make a multi-signature, for aggregate")
+	fromFiles := fs.Bool("f", false, "read the message from the named file")
+	out := fs.String("out", "", "signature file to write")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *key == "" || fs.NArg() != 1 {
+		return errUsage
+	}
+	msgs, err := messages(fs.Args(), *fromFiles)
+	if err != nil {
+		return err
+	}
+	priv, err := e.readPrivateKey(*key)
+	if err != nil {
+		return err
+	}
+	defer priv.Wipe()
+	var sig *ps.Signature
+	if *multi {
+		sig, err = multiSign(priv, msgs[0])
+	} else {
+		sig, err = batchSignWith(priv, msgs)
+	}
+	if err != nil {
+		return err
+	}
+	return writeSignature(e, *out, sig)
+}
+
+// multiSign signs msg on its MultiSigBase, for CombineSignatures.
+func multiSign(priv *ps.PrivateKey, msg []byte) (*ps.Signature, error) {
+	h, err := ps.MultiSigBase(priv.Suite(), msg)
+	if err != nil {
+		return nil, err
+	}
+	return ps.MultiSign(priv.Suite(), priv, h, msg)
+}
+
+// batchSignWith signs msgs with priv into one signature.
+func batchSignWith(priv *ps.PrivateKey, msgs [][]byte) (*ps.Signature, error) {
+	S, err := priv.BatchSign(msgs)
+	if err != nil {
+		return nil, err
+	}
+	return ps.NewSignature(priv.Suite(), S)
+}
+
+// batchSign signs the messages on consecutive slots of the key.
+func batchSign(e *env, args []string) error {
+	fs := newFlags(e, "batch-sign")
+	key := fs.String("key", "", "private key file")
+	fromFiles := fs.Bool("f", false, "read the messages from the named files")
+	out := fs.String("out", "", "signature file to write")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *key == "" || fs.NArg() == 0 {
+		return errUsage
+	}
+	msgs, err := messages(fs.Args(), *fromFiles)
+	if err != nil {
+		return err
+	}
+	priv, err := e.readPrivateKey(*key)
+	if err != nil {
+		return err
+	}
+	defer priv.Wipe()
+	sig, err := batchSignWith(priv, msgs)
+	if err != nil {
+		return err
+	}
+	return writeSignature(e, *out, sig)
+}
+
+// verify checks a signature on the messages and prints OK.
+func verify(e *env, args []string) error {
+	fs := newFlags(e, "verify")
+	pubFile := fs.String("pub", "", "public key file")
+	sigFile := fs.String("sig", "", "signature file")
+	multi := fs.Bool("multi", false, "verify a multi-signature")
+	fromFiles := fs.Bool("f", false, "read the messages from the named files")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *pubFile == "" || *sigFile == "" || fs.NArg() == 0 || (*multi && fs.NArg() != 1) {
+		return errUsage
+	}
+	msgs, err := messages(fs.Args(), *fromFiles)
+	if err != nil {
+		return err
+	}
+	pub, err := e.readPublicKey(*pubFile)
+	if err != nil {
+		return err
+	}
+	sig, err := readSignature(*sigFile)
+	if err != nil {
+		return err
+	}
+	if *multi {
+		err = ps.VerifyMultiSig(pub.Suite(), pub, msgs[0], sig)
+	} else {
+		var S [][]byte
+		if S, err = sig.Components(); err == nil {
+			err = ps.PSBatchVerify(pub.Suite(), pub.Points(), msgs, S)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, "OK")
+	return nil
+}
+
+// aggregate combines multi-signatures on one message, or with -keys the
+// public keys that verify them.
+func aggregate(e *env, args []string) error {
+	fs := newFlags(e, "aggregate")
+	keys := fs.Bool("keys", false, "aggregate public keys instead of signatures")
+	out := fs.String("out", "", "file to write")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errUsage
+	}
+	if *keys {
+		pubs := make([]*ps.PublicKey, fs.NArg())
+		for i, path := range fs.Args() {
+			var err error
+			if pubs[i], err = e.readPublicKey(path); err != nil {
+				return err
+			}
+		}
+		agg, err := ps.AggregatePublicKeys(pubs[0].Suite(), pubs)
+		if err != nil {
+			return err
+		}
+		return writePublicKey(e, *out, agg)
+	}
+	sigs := make([]*ps.Signature, fs.NArg())
+	for i, path := range fs.Args() {
+		var err error
+		if sigs[i], err = readSignature(path); err != nil {
+			return err
+		}
+	}
+	agg, err := ps.CombineSignatures(sigs[0].Suite(), sigs)
+	if err != nil {
+		return err
+	}
+	return writeSignature(e, *out, agg)
+}
+
+// inspect prints the contents of a key or signature file. Of a private key
+// it prints only what the public key and policy reveal.
+func inspect(e *env, args []string) error {
+	fs := newFlags(e, "inspect")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	path := fs.Arg(0)
+	block, err := readBlock(path)
+	if err != nil {
+		return err
+	}
+	w := e.stdout
+	fmt.Fprintf(w, "type: %s\n", block.Type)
+	switch block.Type {
+	case pemEncryptedPrivateKey:
+		fmt.Fprintf(w, "suite: %s\n", block.Headers[suiteHeader])
+		fmt.Fprintf(w, "size: %d bytes\n", len(block.Bytes))
+		return nil
+	case pemPrivateKey:
+		priv := new(ps.PrivateKey)
+		if err := priv.UnmarshalBinary(block.Bytes); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer priv.Wipe()
+		if err := printPublicKey(e, priv.Public()); err != nil {
+			return err
+		}
+		if p := priv.Policy(); p != nil {
+			fmt.Fprintf(w, "policy: reserved %v, max messages %d, operations %#x\n", p.Reserved, p.MaxMessages, uint8(p.Operations))
+		}
+		return nil
+	case pemPublicKey:
+		pub, err := e.readPublicKey(path)
+		if err != nil {
+			return err
+		}
+		return printPublicKey(e, pub)
+	case pemSignature:
+		sig, err := readSignature(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "suite: %s\n", suiteName(sig.Suite()))
+		printPoint(e, "sigma_1", sig.Sigma1)
+		printPoint(e, "sigma_2", sig.Sigma2)
+		return nil
+	}
+	return fmt.Errorf("%s: unknown PEM type %s", path, block.Type)
+}
+
+// printPublicKey prints the suite, slots, identifiers and points of pub,
+// which is all a private key reveals too.
+func printPublicKey(e *env, pub *ps.PublicKey) error {
+	id, err := pub.KeyID()
+	if err != nil {
+		return err
+	}
+	fp, err := pub.Fingerprint()
+	if err != nil {
+		return err
+	}
+	w := e.stdout
+	fmt.Fprintf(w, "suite: %s\n", suiteName(pub.Suite()))
+	fmt.Fprintf(w, "attributes: %d\n", len(pub.Y))
+	fmt.Fprintf(w, "key ID: %s\n", id)
+	fmt.Fprintf(w, "fingerprint: %x\n", fp)
+	printPoint(e, "X", pub.X)
+	for i, Y := range pub.Y {
+		printPoint(e, fmt.Sprintf("Y_%d", i+1), Y)
+	}
+	return nil
+}
+
+func printPoint(e *env, name string, p kyber.Point) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		fmt.Fprintf(e.stdout, "%s: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(e.stdout, "%s: %s\n", name, hex.EncodeToString(buf))
+}
+
+// suiteName returns the registered name of suite.
+func suiteName(suite pairing.Suite) string {
+	id, err := ps.SuiteIDOf(suite)
+	if err != nil {
+		return suite.G1().String()
+	}
+	return ps.SuiteName(id)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bithinalangot/ps"
+	"golang.org/x/term"
+)
+
+// The PEM block types of the files read and written.
+const (
+	pemPrivateKey          = "PS PRIVATE KEY"
+	pemEncryptedPrivateKey = "PS ENCRYPTED PRIVATE KEY"
+	pemPublicKey           = "PS PUBLIC KEY"
+	pemSignature           = "PS SIGNATURE"
+)
+
+// suiteHeader is the PEM header naming the suite of an encrypted key, which
+// the key file itself does not record.
+const suiteHeader = "Suite"
+
+// passphraseEnv is the environment variable holding the passphrase of
+// encrypted keys.
+const passphraseEnv = "PS_PASSPHRASE"
+
+// terminalPassword reads a passphrase from the terminal without echo.
+func terminalPassword(prompt string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("%w: set $%s or run on a terminal", ps.ErrEmptyPassphrase, passphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	return term.ReadPassword(fd)
+}
+
+// passphrase returns the passphrase of $PS_PASSPHRASE or the terminal,
+// asking twice when confirm is set.
+func (e *env) passphrase(confirm bool) ([]byte, error) {
+	if p := e.getenv(passphraseEnv); p != "" {
+		return []byte(p), nil
+	}
+	p, err := e.readPassword("Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return nil, ps.ErrEmptyPassphrase
+	}
+	if confirm {
+		again, err := e.readPassword("Repeat passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(p, again) {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+	return p, nil
+}
+
+// readBlock returns the first PEM block of the file at path.
+func readBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", path)
+	}
+	return block, nil
+}
+
+// expect checks the type of block.
+func expect(path string, block *pem.Block, types ...string) error {
+	for _, t := range types {
+		if block.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %s where %s was expected", path, block.Type, types[0])
+}
+
+// readPrivateKey reads a private key, decrypting it if needed.
+func (e *env) readPrivateKey(path string) (*ps.PrivateKey, error) {
+	block, err := readBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := expect(path, block, pemPrivateKey, pemEncryptedPrivateKey); err != nil {
+		return nil, err
+	}
+	if block.Type == pemPrivateKey {
+		priv := new(ps.PrivateKey)
+		if err := priv.UnmarshalBinary(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return priv, nil
+	}
+	suite, err := ps.SuiteByName(block.Headers[suiteHeader])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	pass, err := e.passphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := ps.DecryptPrivateKey(suite, block.Bytes, pass)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return priv, nil
+}
+
+// readPublicKey reads a public key, or derives it from a private key.
+func (e *env) readPublicKey(path string) (*ps.PublicKey, error) {
+	block, err := readBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != pemPublicKey {
+		priv, err := e.readPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Public(), nil
+	}
+	pub := new(ps.PublicKey)
+	if err := pub.UnmarshalBinary(block.Bytes); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pub, nil
+}
+
+// readSignature reads a signature.
+func readSignature(path string) (*ps.Signature, error) {
+	block, err := readBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := expect(path, block, pemSignature); err != nil {
+		return nil, err
+	}
+	sig := new(ps.Signature)
+	if err := sig.UnmarshalBinary(block.Bytes); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sig, nil
+}
+
+// writePEM writes block to the file at path, or to stdout if path is empty.
+// Secret blocks go to new files only, readable by their owner alone.
+func (e *env) writePEM(path string, block *pem.Block, secret bool) error {
+	if path == "" {
+		return pem.Encode(e.stdout, block)
+	}
+	flag, perm := os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(0o644)
+	if secret {
+		flag, perm = os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600
+	}
+	f, err := os.OpenFile(path, flag, perm)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, block); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// messages returns the messages of args, read from the files they name if
+// fromFiles is set.
+func messages(args []string, fromFiles bool) ([][]byte, error) {
+	msgs := make([][]byte, len(args))
+	for i, a := range args {
+		if !fromFiles {
+			msgs[i] = []byte(a)
+			continue
+		}
+		var err error
+		if msgs[i], err = os.ReadFile(a); err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
+}
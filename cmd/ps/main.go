@@ -0,0 +1,163 @@
+// Command ps manages PS issuer keys and signatures from the command line:
+//
+//	ps keygen -attrs n [-suite name] [-encrypt] [-pub file] -out file
+//	ps pubkey -key file [-out file] [-fingerprint]
+//	ps sign -key file [-multi] [-f] [-out file] message
+//	ps batch-sign -key file [-f] [-out file] message...
+//	ps verify -pub file -sig file [-multi] [-f] message...
+//	ps aggregate [-keys] [-out file] file...
+//	ps inspect file
+//
+// Keys and signatures are PEM files whose blocks hold the binary encodings
+// of the ps package. With -f, messages name files to read instead. Private
+// keys are written with -encrypt as key files of ps.EncryptPrivateKey under
+// a passphrase read from $PS_PASSPHRASE or, failing that, the terminal.
+//
+// On failure ps prints the name of the ps error behind it, if any, e.g.
+// "ps verify: ErrInvalidSignature: ps: invalid signature", and exits with
+// status 1; usage errors exit with status 2.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bithinalangot/ps"
+)
+
+// errUsage reports a command line that cannot be run; the flag package has
+// printed why.
+var errUsage = errors.New("usage")
+
+// command is a subcommand, run with its arguments after the name.
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+var commands = []command{
+	{"keygen", "-attrs n [-suite name] [-encrypt] [-pub file] -out file", keygen},
+	{"pubkey", "-key file [-out file] [-fingerprint]", pubkey},
+	{"sign", "-key file [-multi] [-f] [-out file] message", sign},
+	{"batch-sign", "-key file [-f] [-out file] message...", batchSign},
+	{"verify", "-pub file -sig file [-multi] [-f] message...", verify},
+	{"aggregate", "[-keys] [-out file] file...", aggregate},
+	{"inspect", "file", inspect},
+}
+
+// env is the environment of a command.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	getenv         func(string) string
+	// readPassword reads a passphrase from the terminal, or fails if
+	// there is none.
+	readPassword func(prompt string) ([]byte, error)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{
+		stdin:        os.Stdin,
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+		getenv:       os.Getenv,
+		readPassword: terminalPassword,
+	}))
+}
+
+// run runs the command line args and returns the exit status.
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		printUsage(e.stderr)
+		return 2
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(e, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(e.stderr, "usage: ps %s %s\n", c.name, c.usage)
+			return 2
+		}
+		if name := errorName(err); name != "" {
+			fmt.Fprintf(e.stderr, "ps %s: %s: %v\n", c.name, name, err)
+		} else {
+			fmt.Fprintf(e.stderr, "ps %s: %v\n", c.name, err)
+		}
+		return 1
+	}
+	fmt.Fprintf(e.stderr, "ps: unknown command %q\n", args[0])
+	printUsage(e.stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "\tps %s %s\n", c.name, c.usage)
+	}
+}
+
+// newFlags returns the flag set of a command, printing its errors to the
+// command's stderr.
+func newFlags(e *env, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {}
+	return fs
+}
+
+// parse parses args, turning flag errors into errUsage.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// namedErrors are the errors of the ps package named on failure. The first
+// match wins, so errors wrapping several keep the most specific.
+var namedErrors = []struct {
+	name string
+	err  error
+}{
+	{"ErrDecryption", ps.ErrDecryption},
+	{"ErrEmptyPassphrase", ps.ErrEmptyPassphrase},
+	{"ErrInvalidKDFParams", ps.ErrInvalidKDFParams},
+	{"ErrPolicyViolation", ps.ErrPolicyViolation},
+	{"ErrLimitExceeded", ps.ErrLimitExceeded},
+	{"ErrSuiteMismatch", ps.ErrSuiteMismatch},
+	{"ErrIncompatibleSuite", ps.ErrIncompatibleSuite},
+	{"ErrUnsupportedVersion", ps.ErrUnsupportedVersion},
+	{"ErrCorruptKey", ps.ErrCorruptKey},
+	{"ErrWeakPublicKey", ps.ErrWeakPublicKey},
+	{"ErrMalformedSignature", ps.ErrMalformedSignature},
+	{"ErrInvalidPoint", ps.ErrInvalidPoint},
+	{"ErrKeyLengthMismatch", ps.ErrKeyLengthMismatch},
+	{"ErrKeyTooShort", ps.ErrKeyTooShort},
+	{"ErrNoMessages", ps.ErrNoMessages},
+	{"ErrEmptyMessage", ps.ErrEmptyMessage},
+	{"ErrZeroAttribute", ps.ErrZeroAttribute},
+	{"ErrInvalidSignature", ps.ErrInvalidSignature},
+	{"ErrNilKey", ps.ErrNilKey},
+	{"ErrNilSignature", ps.ErrNilSignature},
+	{"ErrNilSuite", ps.ErrNilSuite},
+}
+
+// errorName returns the name of the ps error behind err, or "".
+func errorName(err error) string {
+	for _, n := range namedErrors {
+		if errors.Is(err, n.err) {
+			return n.name
+		}
+	}
+	return ""
+}
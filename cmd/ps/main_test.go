@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The test binary runs as ps when this variable is set, so that the tests
+// drive the command through os/exec like a user would.
+const runAsPS = "PS_TEST_RUN_AS_PS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runAsPS) != "" {
+		main()
+	}
+	os.Exit(m.Run())
+}
+
+type cli struct {
+	t   *testing.T
+	dir string
+	env []string
+}
+
+func newCLI(t *testing.T) *cli {
+	return &cli{t: t, dir: t.TempDir(), env: []string{runAsPS + "=1"}}
+}
+
+// path returns the path of name in the working directory.
+func (c *cli) path(name string) string { return filepath.Join(c.dir, name) }
+
+// run runs ps with args and returns its exit status, stdout and stderr.
+func (c *cli) run(args ...string) (int, string, string) {
+	c.t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = c.dir
+	cmd.Env = append(os.Environ(), c.env...)
+	cmd.Stdin = strings.NewReader("")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	if exit, ok := err.(*exec.ExitError); ok {
+		return exit.ExitCode(), stdout.String(), stderr.String()
+	}
+	require.NoError(c.t, err)
+	return 0, stdout.String(), stderr.String()
+}
+
+// ok runs ps with args, requiring success, and returns its stdout.
+func (c *cli) ok(args ...string) string {
+	c.t.Helper()
+	code, stdout, stderr := c.run(args...)
+	require.Equal(c.t, 0, code, stderr)
+	return stdout
+}
+
+// fail runs ps with args, requiring failure with status 1 and the error
+// name on stderr.
+func (c *cli) fail(name string, args ...string) {
+	c.t.Helper()
+	code, _, stderr := c.run(args...)
+	require.Equal(c.t, 1, code, stderr)
+	require.Contains(c.t, stderr, ": "+name+": ")
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, suite := range []string{"bn256", "bls12381"} {
+		t.Run(suite, func(t *testing.T) {
+			c := newCLI(t)
+			c.ok("keygen", "-suite", suite, "-attrs", "3", "-out", "key.pem", "-pub", "pub.pem")
+			info, err := os.Stat(c.path("key.pem"))
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+			c.ok("sign", "-key", "key.pem", "-out", "one.pem", "hello")
+			require.Equal(t, "OK\n", c.ok("verify", "-pub", "pub.pem", "-sig", "one.pem", "hello"))
+			c.fail("ErrInvalidSignature", "verify", "-pub", "pub.pem", "-sig", "one.pem", "hellp")
+
+			c.ok("batch-sign", "-key", "key.pem", "-out", "batch.pem", "a", "b", "c")
+			c.ok("verify", "-pub", "key.pem", "-sig", "batch.pem", "a", "b", "c")
+			c.fail("ErrInvalidSignature", "verify", "-pub", "pub.pem", "-sig", "batch.pem", "b", "a", "c")
+			c.fail("ErrKeyLengthMismatch", "batch-sign", "-key", "key.pem", "a", "b", "c", "d")
+
+			require.NoError(t, os.WriteFile(c.path("msg"), []byte("from a file"), 0o644))
+			c.ok("sign", "-key", "key.pem", "-f", "-out", "file.pem", "msg")
+			c.ok("verify", "-pub", "pub.pem", "-sig", "file.pem", "-f", "msg")
+			c.fail("ErrInvalidSignature", "verify", "-pub", "pub.pem", "-sig", "file.pem", "msg")
+		})
+	}
+}
+
+func TestEncryptedKey(t *testing.T) {
+	c := newCLI(t)
+	c.env = append(c.env, passphraseEnv+"=correct horse")
+	c.ok("keygen", "-attrs", "1", "-encrypt", "-out", "key.pem")
+	block, err := readBlock(c.path("key.pem"))
+	require.NoError(t, err)
+	require.Equal(t, pemEncryptedPrivateKey, block.Type)
+	require.Equal(t, "bn256", block.Headers[suiteHeader])
+
+	c.ok("pubkey", "-key", "key.pem", "-out", "pub.pem")
+	c.ok("sign", "-key", "key.pem", "-out", "sig.pem", "hello")
+	c.ok("verify", "-pub", "pub.pem", "-sig", "sig.pem", "hello")
+
+	c.env = append(c.env, passphraseEnv+"=battery staple")
+	c.fail("ErrDecryption", "sign", "-key", "key.pem", "hello")
+
+	// Without a passphrase or a terminal there is nothing to decrypt with.
+	c.env = []string{runAsPS + "=1", passphraseEnv + "="}
+	c.fail("ErrEmptyPassphrase", "sign", "-key", "key.pem", "hello")
+
+	// Private keys are never overwritten.
+	code, _, stderr := c.run("keygen", "-attrs", "1", "-out", "key.pem")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "file exists")
+}
+
+func TestAggregate(t *testing.T) {
+	c := newCLI(t)
+	var sigs, pubs []string
+	for _, name := range []string{"a", "b", "c"} {
+		c.ok("keygen", "-attrs", "1", "-out", name+".key", "-pub", name+".pub")
+		c.ok("sign", "-multi", "-key", name+".key", "-out", name+".sig", "statement")
+		sigs = append(sigs, name+".sig")
+		pubs = append(pubs, name+".pub")
+	}
+	c.ok(append([]string{"aggregate", "-out", "agg.sig"}, sigs...)...)
+	c.ok(append([]string{"aggregate", "-keys", "-out", "agg.pub"}, pubs...)...)
+	c.ok("verify", "-multi", "-pub", "agg.pub", "-sig", "agg.sig", "statement")
+	c.fail("ErrInvalidSignature", "verify", "-multi", "-pub", "agg.pub", "-sig", "agg.sig", "statemenu")
+	c.fail("ErrInvalidSignature", "verify", "-multi", "-pub", "a.pub", "-sig", "agg.sig", "statement")
+
+	c.ok("sign", "-multi", "-key", "a.key", "-out", "other.sig", "another statement")
+	c.fail("ErrInvalidSignature", "aggregate", "a.sig", "other.sig")
+}
+
+func TestPubkeyInspect(t *testing.T) {
+	c := newCLI(t)
+	c.ok("keygen", "-attrs", "2", "-out", "key.pem", "-pub", "pub.pem")
+	require.Equal(t, c.ok("pubkey", "-key", "key.pem"), c.ok("pubkey", "-key", "pub.pem"))
+
+	fields := strings.Fields(c.ok("pubkey", "-fingerprint", "-key", "key.pem"))
+	require.Len(t, fields, 2)
+	require.Len(t, fields[0], 16)
+
+	out := c.ok("inspect", "key.pem")
+	require.Contains(t, out, "type: "+pemPrivateKey+"\n")
+	require.Contains(t, out, "attributes: 2\n")
+	require.Contains(t, out, "key ID: "+fields[0]+"\n")
+	require.Contains(t, out, "fingerprint: "+fields[1]+"\n")
+	require.Equal(t, strings.SplitN(out, "\n", 2)[1], strings.SplitN(c.ok("inspect", "pub.pem"), "\n", 2)[1])
+
+	c.ok("sign", "-key", "key.pem", "-out", "sig.pem", "hello")
+	out = c.ok("inspect", "sig.pem")
+	require.Contains(t, out, "suite: bn256\n")
+	require.Contains(t, out, "sigma_1: ")
+}
+
+func TestUsage(t *testing.T) {
+	c := newCLI(t)
+	for _, args := range [][]string{
+		{},
+		{"frobnicate"},
+		{"keygen", "-out", "key.pem"},
+		{"keygen", "-attrs", "1"},
+		{"sign", "-key", "key.pem"},
+		{"verify", "-pub", "pub.pem", "hello"},
+		{"aggregate", "one.sig"},
+		{"inspect", "-bogus", "file"},
+	} {
+		code, _, stderr := c.run(args...)
+		require.Equal(t, 2, code, "%q", args)
+		require.Contains(t, stderr, "usage", "%q", args)
+	}
+
+	c.ok("keygen", "-attrs", "1", "-out", "key.pem")
+	code, _, stderr := c.run("verify", "-pub", "key.pem", "-sig", "key.pem", "hello")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "where "+pemSignature+" was expected")
+
+	c.ok("sign", "-key", "key.pem", "-out", "sig.pem", "hello")
+	block, err := readBlock(c.path("sig.pem"))
+	require.NoError(t, err)
+	block.Bytes = block.Bytes[:len(block.Bytes)-1]
+	require.NoError(t, os.WriteFile(c.path("sig.pem"), pem.EncodeToMemory(block), 0o644))
+	code, _, stderr = c.run("inspect", "sig.pem")
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "ps inspect: sig.pem: ")
+}
@@ -0,0 +1,227 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Signing committed messages follows section 4.2 of Pointcheval and Sanders,
+// "Short Randomizable Signatures". The holder commits to a message m with
+// the Pedersen commitment
+//
+//	C = g^t * Y_1^m
+//
+// in G1, whose generators g and Y_1 = g^(y_1) are the signer's Params, and
+// proves knowledge of the opening (t, m). The signer only ever sees C, which
+// is uniformly random whatever m, and answers (g^u, (X * C)^u). The holder
+// finalizes it into (g^u, (X * C)^u / (g^u)^t), an ordinary signature on m
+// that Verify accepts. Unlike PrepareBlindSign, committing needs no public
+// key, so commitments can be made before the signer is known.
+
+// Params are the generators of commitments to messages, g and the points
+// Y_i = g^(y_i) of the signer. They are the signer's BlindingKey and share
+// its encoding.
+type Params = BlindingKey
+
+// Commitment is a commitment C to a message with the proof (c, z_0, z_1)
+// that the holder knows its opening, z_0 answering for t and z_1 for m.
+type Commitment struct {
+	suite     pairing.Suite
+	C         kyber.Point
+	Challenge kyber.Scalar
+	Responses []kyber.Scalar
+}
+
+// Opening is the holder's secret for a commitment: the blinding t and the
+// message. It must not be sent to the signer.
+type Opening struct {
+	suite pairing.Suite
+	t     kyber.Scalar
+	msg   []byte
+}
+
+// check reports whether the commitment is complete.
+func (c *Commitment) check() error {
+	if c == nil || c.C == nil || c.Challenge == nil || len(c.Responses) != 2 {
+		return fmt.Errorf("%w: incomplete commitment", ErrInvalidBlindRequest)
+	}
+	if c.suite == nil {
+		return ErrNilSuite
+	}
+	for i, z := range c.Responses {
+		if z == nil {
+			return fmt.Errorf("%w: response %d is nil", ErrInvalidBlindRequest, i)
+		}
+	}
+	return nil
+}
+
+// commitmentChallenge derives the challenge binding the proof to the
+// generator Y_1, the commitment C and the prover's first message R.
+func commitmentChallenge(suite pairing.Suite, y, C, R kyber.Point) (kyber.Scalar, error) {
+	buf, err := appendPoints(nil, suite.G1(), false, y, C, R)
+	if err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "COMMIT"), buf), nil
+}
+
+// CommitMessage commits to msg under params, reading randomness from rand,
+// and returns the commitment for the signer and the opening to finalize its
+// signature. The message takes the first slot of the signer's key and is
+// hashed as by Sign.
+func CommitMessage(suite pairing.Suite, params *Params, msg []byte, rand cipher.Stream, opts ...Option) (*Commitment, *Opening, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := params.check(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkMessages(msg); err != nil {
+		return nil, nil, err
+	}
+	witness := make([]kyber.Scalar, 2)
+	nonces := make([]kyber.Scalar, 2)
+	if witness[0], err = pickScalar(suite, rand); err != nil {
+		return nil, nil, err
+	}
+	witness[1] = hashToScalar(suite, o.dst, msg)
+	for i := range nonces {
+		if nonces[i], err = pickScalar(suite, rand); err != nil {
+			return nil, nil, err
+		}
+	}
+	C := commit(suite, params, witness)
+	c, err := commitmentChallenge(suite, params.Y[0], C, commit(suite, params, nonces))
+	if err != nil {
+		return nil, nil, err
+	}
+	responses := make([]kyber.Scalar, 2)
+	for i := range responses {
+		responses[i] = newScalar(suite).Mul(c, witness[i])
+		responses[i].Add(responses[i], nonces[i])
+		nonces[i].Zero()
+	}
+	commitment := &Commitment{suite: suite, C: C, Challenge: c, Responses: responses}
+	return commitment, &Opening{suite: suite, t: witness[0], msg: append([]byte{}, msg...)}, nil
+}
+
+// SignCommitment signs a commitment made by CommitMessage under the Params of
+// priKey, after checking its proof of knowledge, without learning the
+// message.
+func SignCommitment(suite pairing.Suite, priKey *PrivateKey, commitment *Commitment) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := commitment.check(); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allowCount(OpBlindSign, 1); err != nil {
+		return nil, err
+	}
+	if isIdentity(suite.G1(), commitment.C) {
+		return nil, fmt.Errorf("%w: identity commitment", ErrInvalidBlindRequest)
+	}
+	if err := checkSubgroup(suite.G1(), commitment.C); err != nil {
+		return nil, err
+	}
+	params := priKey.BlindingKey()
+	// R = g^(z_0) * Y_1^(z_1) / C^c
+	R := commit(suite, params, commitment.Responses)
+	R.Sub(R, suite.G1().Point().Mul(commitment.Challenge, commitment.C))
+	c, err := commitmentChallenge(suite, params.Y[0], commitment.C, R)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Equal(commitment.Challenge) {
+		return nil, fmt.Errorf("%w: proof of knowledge does not verify", ErrInvalidBlindRequest)
+	}
+	return signCommitted(suite, priKey, commitment.C)
+}
+
+// Finalize turns the signature on a commitment into a signature on the
+// committed message, which Verify accepts under the signer's public key.
+func Finalize(suite pairing.Suite, opening *Opening, sig *Signature) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if opening == nil || opening.t == nil {
+		return nil, errors.New("ps: nil opening")
+	}
+	if err := sig.check(); err != nil {
+		return nil, err
+	}
+	s2 := suite.G1().Point().Mul(opening.t, sig.Sigma1)
+	s2.Sub(sig.Sigma2, s2)
+	return &Signature{suite: suite, Sigma1: sig.Sigma1.Clone(), Sigma2: s2}, nil
+}
+
+// Message returns the committed message.
+func (o *Opening) Message() []byte {
+	if o == nil {
+		return nil
+	}
+	return append([]byte{}, o.msg...)
+}
+
+// MarshalBinary encodes the commitment as header || C || c || z_0 || z_1.
+func (c *Commitment) MarshalBinary() ([]byte, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(c.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, c.suite.G1(), false, c.C); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, c.suite.G1(), append([]kyber.Scalar{c.Challenge}, c.Responses...)...)
+}
+
+// UnmarshalBinary decodes a commitment, see PrivateKey.UnmarshalBinary.
+func (c *Commitment) UnmarshalBinary(data []byte) error {
+	if c == nil {
+		return ErrInvalidBlindRequest
+	}
+	suite, body, newer, err := readHeader(c.suite, data)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 1, false)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, 3)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	*c = Commitment{suite: suite, C: points[0], Challenge: scalars[0], Responses: scalars[1:]}
+	return nil
+}
+
+// UnmarshalCommitment decodes a commitment produced under suite.
+func UnmarshalCommitment(suite pairing.Suite, data []byte) (*Commitment, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	c := &Commitment{suite: suite}
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,111 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestSignCommitment(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("age=31")
+		priv, pub := newTestKeys(t, suite, 2)
+		buf, err := priv.BlindingKey().MarshalBinary()
+		require.Nil(t, err)
+		params, err := UnmarshalBlindingKey(suite, buf)
+		require.Nil(t, err)
+
+		commitment, opening, err := CommitMessage(suite, params, msg, random.New())
+		require.Nil(t, err)
+		require.Equal(t, msg, opening.Message())
+
+		// The commitment travels to the signer in binary form.
+		buf, err = commitment.MarshalBinary()
+		require.Nil(t, err)
+		received, err := UnmarshalCommitment(suite, buf)
+		require.Nil(t, err)
+
+		committed, err := SignCommitment(suite, priv, received)
+		require.Nil(t, err)
+		sig, err := Finalize(suite, opening, committed)
+		require.Nil(t, err)
+		S, err := sig.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, S))
+		require.NotNil(t, Verify(suite, pub.Points(), []byte("age=18"), S))
+	})
+}
+
+func TestSignCommitmentHiding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		a, b := []byte("age=31"), []byte("age=67")
+		priv, _ := newTestKeys(t, suite, 1)
+		params := priv.BlindingKey()
+
+		commitA, _, err := CommitMessage(suite, params, a, random.New())
+		require.Nil(t, err)
+		commitB, _, err := CommitMessage(suite, params, b, random.New())
+		require.Nil(t, err)
+		bufA, err := commitA.MarshalBinary()
+		require.Nil(t, err)
+		bufB, err := commitB.MarshalBinary()
+		require.Nil(t, err)
+		require.Equal(t, len(bufA), len(bufB))
+
+		// The signer sees the same transcript for both messages: C = g^t *
+		// Y^a equals g^(t') * Y^b for t' = t + y*(a - b), and its answer
+		// depends on C alone.
+		dst := DefaultDST(suite)
+		mA, mB := hashToScalar(suite, dst, a), hashToScalar(suite, dst, b)
+		tA := newScalar(suite).Pick(random.New())
+		d := newScalar(suite).Sub(mA, mB)
+		tB := newScalar(suite).Add(tA, d.Mul(d, priv.Y[0]))
+		C := commit(suite, params, []kyber.Scalar{tA, mA})
+		require.True(t, C.Equal(commit(suite, params, []kyber.Scalar{tB, mB})))
+	})
+}
+
+func TestSignCommitmentInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("age=31")
+		priv, _ := newTestKeys(t, suite, 1)
+		other, otherPub := newTestKeys(t, suite, 1)
+
+		commitment, opening, err := CommitMessage(suite, priv.BlindingKey(), msg, random.New())
+		require.Nil(t, err)
+
+		// The proof is bound to the signer's generators.
+		_, err = SignCommitment(suite, other, commitment)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		forged := *commitment
+		forged.Responses = []kyber.Scalar{commitment.Responses[0], newScalar(suite).Pick(random.New())}
+		_, err = SignCommitment(suite, priv, &forged)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		forged = *commitment
+		forged.C = suite.G1().Point().Null()
+		_, err = SignCommitment(suite, priv, &forged)
+		require.True(t, errors.Is(err, ErrInvalidBlindRequest))
+
+		// A signature on another commitment does not finalize into a valid one.
+		otherCommitment, _, err := CommitMessage(suite, other.BlindingKey(), msg, random.New())
+		require.Nil(t, err)
+		committed, err := SignCommitment(suite, other, otherCommitment)
+		require.Nil(t, err)
+		sig, err := Finalize(suite, opening, committed)
+		require.Nil(t, err)
+		S, err := sig.Components()
+		require.Nil(t, err)
+		require.NotNil(t, Verify(suite, otherPub.Points(), msg, S))
+
+		_, _, err = CommitMessage(suite, priv.BlindingKey(), nil, random.New())
+		require.NotNil(t, err)
+		_, err = Finalize(suite, nil, committed)
+		require.NotNil(t, err)
+	})
+}
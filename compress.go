@@ -0,0 +1,348 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// The compressed format is the current format with every point replaced by
+// its compressed form, for suites whose default encoding is not compressed
+// already. bn256 points shrink from 64 to 33 bytes on G1 and from 128 to 65
+// bytes on G2: a tag byte followed by the x coordinate, with the tag telling
+// which square root is y. Other suites, such as bls12381, encode points in
+// compressed form by default and are left unchanged.
+//
+// Compressed and uncompressed encodings of bn256 artifacts have different
+// lengths, so reading one as the other fails instead of yielding wrong points.
+
+// ErrInvalidCompressedPoint is returned when a compressed point does not
+// decode to a point of its group.
+var ErrInvalidCompressedPoint = errors.New("ps: invalid compressed point")
+
+// MarshalCompressed encodes the public key with compressed points.
+func (k *PublicKey) MarshalCompressed() ([]byte, error) {
+	return k.marshal(true)
+}
+
+// MarshalCompressed encodes the signature with compressed points.
+func (s *Signature) MarshalCompressed() ([]byte, error) {
+	return s.marshal(true)
+}
+
+// UnmarshalPublicKeyCompressed decodes a public key produced under suite by
+// MarshalCompressed.
+func UnmarshalPublicKeyCompressed(suite pairing.Suite, data []byte) (*PublicKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	k := &PublicKey{suite: suite}
+	if err := k.unmarshal(data, true); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UnmarshalSignatureCompressed decodes a signature produced under suite by
+// MarshalCompressed.
+func UnmarshalSignatureCompressed(suite pairing.Suite, data []byte) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	s := &Signature{suite: suite}
+	if err := s.unmarshal(data, true); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func pointLen(g kyber.Group, compressed bool) int {
+	if !compressed {
+		return g.PointLen()
+	}
+	switch g.String() {
+	case "bn256.G1":
+		return 1 + bn256FpLen
+	case "bn256.G2":
+		return 1 + 2*bn256FpLen
+	}
+	return g.PointLen()
+}
+
+func marshalPoint(g kyber.Group, p kyber.Point, compressed bool) ([]byte, error) {
+	b, err := p.MarshalBinary()
+	if err != nil || !compressed {
+		return b, err
+	}
+	switch g.String() {
+	case "bn256.G1":
+		return compressBN256G1(b)
+	case "bn256.G2":
+		return compressBN256G2(b)
+	}
+	return b, nil
+}
+
+func unmarshalPoint(g kyber.Group, data []byte, compressed bool) (kyber.Point, error) {
+	var err error
+	if compressed {
+		switch g.String() {
+		case "bn256.G1":
+			data, err = decompressBN256G1(data)
+		case "bn256.G2":
+			data, err = decompressBN256G2(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	p := g.Point()
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	if err := checkSubgroup(g, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+const bn256FpLen = 32
+
+// Compressed point tags: the point at infinity, or an affine point whose y
+// has the given sign.
+const (
+	tagInfinity = 0x00
+	tagEven     = 0x02
+	tagOdd      = 0x03
+)
+
+var (
+	bn256P = fromDecimal("65000549695646603732796438742359905742825358107623003571877145026864184071783")
+	// bn256TwistB is the constant of the sextic twist y^2 = x^3 + 3/(i+3).
+	bn256TwistB = fp2{big.NewInt(3), big.NewInt(0)}.mul(fp2{big.NewInt(3), big.NewInt(1)}.inv())
+)
+
+func fromDecimal(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func readFp(b []byte) (*big.Int, error) {
+	n := new(big.Int).SetBytes(b)
+	if n.Cmp(bn256P) >= 0 {
+		return nil, fmt.Errorf("%w: coordinate out of range", ErrInvalidCompressedPoint)
+	}
+	return n, nil
+}
+
+func writeFp(buf []byte, n *big.Int) {
+	n.FillBytes(buf)
+}
+
+func signTag(odd bool) byte {
+	if odd {
+		return tagOdd
+	}
+	return tagEven
+}
+
+// compressBN256G1 compresses kyber's x || y encoding of a G1 point.
+func compressBN256G1(b []byte) ([]byte, error) {
+	if len(b) != 2*bn256FpLen {
+		return nil, fmt.Errorf("ps: invalid bn256 G1 encoding length %d", len(b))
+	}
+	out := make([]byte, 1+bn256FpLen)
+	if isZero(b) {
+		return out, nil
+	}
+	y := new(big.Int).SetBytes(b[bn256FpLen:])
+	out[0] = signTag(y.Bit(0) == 1)
+	copy(out[1:], b[:bn256FpLen])
+	return out, nil
+}
+
+func decompressBN256G1(c []byte) ([]byte, error) {
+	if len(c) != 1+bn256FpLen {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidCompressedPoint, len(c))
+	}
+	out := make([]byte, 2*bn256FpLen)
+	switch c[0] {
+	case tagInfinity:
+		if !isZero(c[1:]) {
+			return nil, fmt.Errorf("%w: non-zero infinity", ErrInvalidCompressedPoint)
+		}
+		return out, nil
+	case tagEven, tagOdd:
+	default:
+		return nil, fmt.Errorf("%w: unknown tag %#x", ErrInvalidCompressedPoint, c[0])
+	}
+	x, err := readFp(c[1:])
+	if err != nil {
+		return nil, err
+	}
+	// y^2 = x^3 + 3
+	rhs := new(big.Int).Exp(x, big.NewInt(3), bn256P)
+	rhs.Add(rhs, big.NewInt(3)).Mod(rhs, bn256P)
+	y := new(big.Int).ModSqrt(rhs, bn256P)
+	if y == nil {
+		return nil, fmt.Errorf("%w: x is not on the curve", ErrInvalidCompressedPoint)
+	}
+	if (y.Bit(0) == 1) != (c[0] == tagOdd) {
+		y.Sub(bn256P, y)
+	}
+	writeFp(out[:bn256FpLen], x)
+	writeFp(out[bn256FpLen:], y)
+	return out, nil
+}
+
+// compressBN256G2 compresses kyber's encoding of a G2 point, the imaginary
+// and real parts of x followed by those of y.
+func compressBN256G2(b []byte) ([]byte, error) {
+	if len(b) != 4*bn256FpLen {
+		return nil, fmt.Errorf("ps: invalid bn256 G2 encoding length %d", len(b))
+	}
+	out := make([]byte, 1+2*bn256FpLen)
+	if isZero(b) {
+		return out, nil
+	}
+	y := fp2{new(big.Int).SetBytes(b[3*bn256FpLen:]), new(big.Int).SetBytes(b[2*bn256FpLen : 3*bn256FpLen])}
+	out[0] = signTag(y.odd())
+	copy(out[1:], b[:2*bn256FpLen])
+	return out, nil
+}
+
+func decompressBN256G2(c []byte) ([]byte, error) {
+	if len(c) != 1+2*bn256FpLen {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidCompressedPoint, len(c))
+	}
+	out := make([]byte, 4*bn256FpLen)
+	switch c[0] {
+	case tagInfinity:
+		if !isZero(c[1:]) {
+			return nil, fmt.Errorf("%w: non-zero infinity", ErrInvalidCompressedPoint)
+		}
+		return out, nil
+	case tagEven, tagOdd:
+	default:
+		return nil, fmt.Errorf("%w: unknown tag %#x", ErrInvalidCompressedPoint, c[0])
+	}
+	xi, err := readFp(c[1 : 1+bn256FpLen])
+	if err != nil {
+		return nil, err
+	}
+	xr, err := readFp(c[1+bn256FpLen:])
+	if err != nil {
+		return nil, err
+	}
+	x := fp2{xr, xi}
+	// y^2 = x^3 + b'
+	y, ok := x.mul(x).mul(x).add(bn256TwistB).sqrt()
+	if !ok {
+		return nil, fmt.Errorf("%w: x is not on the twist", ErrInvalidCompressedPoint)
+	}
+	if y.odd() != (c[0] == tagOdd) {
+		y = y.neg()
+	}
+	copy(out, c[1:])
+	writeFp(out[2*bn256FpLen:3*bn256FpLen], y.i)
+	writeFp(out[3*bn256FpLen:], y.r)
+	return out, nil
+}
+
+// fp2 is an element r + i*i of the quadratic extension Fp[i]/(i^2+1) of the
+// bn256 base field.
+type fp2 struct {
+	r, i *big.Int
+}
+
+func (a fp2) add(b fp2) fp2 {
+	r := new(big.Int).Add(a.r, b.r)
+	i := new(big.Int).Add(a.i, b.i)
+	return fp2{r.Mod(r, bn256P), i.Mod(i, bn256P)}
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	r := new(big.Int).Mul(a.r, b.r)
+	r.Sub(r, new(big.Int).Mul(a.i, b.i))
+	i := new(big.Int).Mul(a.r, b.i)
+	i.Add(i, new(big.Int).Mul(a.i, b.r))
+	return fp2{r.Mod(r, bn256P), i.Mod(i, bn256P)}
+}
+
+func (a fp2) neg() fp2 {
+	r := new(big.Int).Neg(a.r)
+	i := new(big.Int).Neg(a.i)
+	return fp2{r.Mod(r, bn256P), i.Mod(i, bn256P)}
+}
+
+func (a fp2) inv() fp2 {
+	norm := new(big.Int).Mul(a.r, a.r)
+	norm.Add(norm, new(big.Int).Mul(a.i, a.i))
+	norm.ModInverse(norm, bn256P)
+	r := new(big.Int).Mul(a.r, norm)
+	i := new(big.Int).Mul(a.i, norm)
+	i.Neg(i)
+	return fp2{r.Mod(r, bn256P), i.Mod(i, bn256P)}
+}
+
+func (a fp2) exp(e *big.Int) fp2 {
+	z := fp2{big.NewInt(1), big.NewInt(0)}
+	for j := e.BitLen() - 1; j >= 0; j-- {
+		z = z.mul(z)
+		if e.Bit(j) == 1 {
+			z = z.mul(a)
+		}
+	}
+	return z
+}
+
+func (a fp2) equal(b fp2) bool {
+	return a.r.Cmp(b.r) == 0 && a.i.Cmp(b.i) == 0
+}
+
+// odd reports the sign of a: the parity of its real part, or of its imaginary
+// part when the real part is zero.
+func (a fp2) odd() bool {
+	if a.r.Sign() != 0 {
+		return a.r.Bit(0) == 1
+	}
+	return a.i.Bit(0) == 1
+}
+
+// sqrt computes a square root for p = 3 mod 4, following algorithm 9 of
+// Adj and Rodríguez-Henríquez, "Square root computation over even extension
+// fields".
+func (a fp2) sqrt() (fp2, bool) {
+	minusOne := fp2{new(big.Int).Sub(bn256P, big.NewInt(1)), big.NewInt(0)}
+	e := new(big.Int).Sub(bn256P, big.NewInt(3))
+	a1 := a.exp(e.Rsh(e, 2))
+	alpha := a1.mul(a1).mul(a)
+	conj := fp2{alpha.r, new(big.Int).Mod(new(big.Int).Neg(alpha.i), bn256P)}
+	if conj.mul(alpha).equal(minusOne) {
+		return fp2{}, false
+	}
+	x0 := a1.mul(a)
+	var x fp2
+	if alpha.equal(minusOne) {
+		x = fp2{big.NewInt(0), big.NewInt(1)}.mul(x0)
+	} else {
+		e := new(big.Int).Sub(bn256P, big.NewInt(1))
+		x = alpha.add(fp2{big.NewInt(1), big.NewInt(0)}).exp(e.Rsh(e, 1)).mul(x0)
+	}
+	if !x.mul(x).equal(a) {
+		return fp2{}, false
+	}
+	return x, true
+}
@@ -0,0 +1,88 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestCompressedRoundTrip(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 2)
+		sig := newTestSignature(t, suite, priv, msg)
+
+		buf, err := sig.MarshalCompressed()
+		require.Nil(t, err)
+		dec, err := UnmarshalSignatureCompressed(suite, buf)
+		require.Nil(t, err)
+		require.True(t, sig.Sigma1.Equal(dec.Sigma1))
+		require.True(t, sig.Sigma2.Equal(dec.Sigma2))
+
+		buf, err = pub.MarshalCompressed()
+		require.Nil(t, err)
+		decPub, err := UnmarshalPublicKeyCompressed(suite, buf)
+		require.Nil(t, err)
+		for i, p := range pub.Points() {
+			require.True(t, p.Equal(decPub.Points()[i]))
+		}
+
+		S, err := dec.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, decPub.Points(), msg, S))
+	})
+}
+
+func TestCompressedPoints(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		for i := 0; i < 16; i++ {
+			p := g.Point().Pick(suite.RandomStream())
+			b, err := marshalPoint(g, p, true)
+			require.Nil(t, err)
+			require.Equal(t, pointLen(g, true), len(b))
+			require.True(t, len(b) < g.PointLen())
+
+			q, err := unmarshalPoint(g, b, true)
+			require.Nil(t, err)
+			require.True(t, p.Equal(q), g.String())
+		}
+		b, err := marshalPoint(g, g.Point().Null(), true)
+		require.Nil(t, err)
+		q, err := unmarshalPoint(g, b, true)
+		require.Nil(t, err)
+		require.True(t, q.Equal(g.Point().Null()))
+
+		b[0] = 0x07
+		_, err = unmarshalPoint(g, b, true)
+		require.True(t, errors.Is(err, ErrInvalidCompressedPoint))
+	}
+}
+
+func TestCompressedMixing(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, pub := newTestKeys(t, suite, 1)
+	sig := newTestSignature(t, suite, priv, []byte("on-chain"))
+
+	// The default encoding is unchanged by the option.
+	def, err := sig.MarshalBinary()
+	require.Nil(t, err)
+	c, err := sig.MarshalCompressed()
+	require.Nil(t, err)
+	require.False(t, bytes.Equal(def, c))
+	require.True(t, len(c) < len(def))
+
+	_, err = UnmarshalSignature(suite, c)
+	require.NotNil(t, err)
+	_, err = UnmarshalSignatureCompressed(suite, def)
+	require.NotNil(t, err)
+
+	c, err = pub.MarshalCompressed()
+	require.Nil(t, err)
+	_, err = UnmarshalPublicKey(suite, c)
+	require.NotNil(t, err)
+}
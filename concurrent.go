@@ -0,0 +1,133 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// The functions of this package do not modify their arguments, so they may
+// be called from several goroutines at once on shared suites, keys,
+// signatures and messages, as long as no goroutine modifies them meanwhile.
+// PrivateKey.Wipe is such a modification. Signing draws from
+// suite.RandomStream(), which the bundled suites create afresh on every call.
+//
+// Two kinds of values are not safe for concurrent use:
+//
+//   - random streams passed as arguments, e.g. to NewKeyPair, VerifyBatch,
+//     BatchSigner.Finalize or NewAccumulator. A cipher.Stream is stateful
+//     and must not be shared between goroutines unless it is safe for that.
+//   - values accumulating state: BatchSigner, PairingAccumulator and
+//     VerifyScratch.
+//
+// Verifier is immutable and safe for concurrent use. ConcurrentSigner signs
+// from any number of goroutines with a stream it guards itself.
+
+// ConcurrentSigner signs with a private key of its own from any number of
+// goroutines at once. If it was given a random stream, calls take turns
+// reading from it; otherwise every call draws from the suite's random
+// stream.
+type ConcurrentSigner struct {
+	suite  pairing.Suite
+	priKey []kyber.Scalar
+	policy *KeyPolicy
+	audit  *auditor
+	rand   cipher.Stream
+	opts   []Option
+	ob     observer
+}
+
+// lockedStream serializes the reads of a stream shared by goroutines.
+type lockedStream struct {
+	mu     sync.Mutex
+	stream cipher.Stream
+}
+
+func (s *lockedStream) XORKeyStream(dst, src []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream.XORKeyStream(dst, src)
+}
+
+// NewConcurrentSigner returns a ConcurrentSigner holding a copy of priKey.
+// A nil rand uses the suite's random stream. The options apply to every
+// signature, after the options of the call.
+func NewConcurrentSigner(suite pairing.Suite, priKey *PrivateKey, rand cipher.Stream, opts ...Option) (*ConcurrentSigner, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	scalars := priKey.Scalars()
+	if err := checkPrivateKey(scalars, 2); err != nil {
+		return nil, err
+	}
+	key := make([]kyber.Scalar, len(scalars))
+	for i, k := range scalars {
+		key[i] = k.Clone()
+	}
+	s := &ConcurrentSigner{suite: suite, priKey: key, policy: priKey.Policy(), audit: priKey.audit, opts: opts, ob: observer{o.inst}}
+	if rand != nil {
+		s.rand = &lockedStream{stream: rand}
+	}
+	return s, nil
+}
+
+// Sign is Sign with the signer's key, under its policy.
+func (s *ConcurrentSigner) Sign(msg []byte, opts ...Option) ([][]byte, error) {
+	start := s.ob.start()
+	S, err := s.sign(msg, opts)
+	s.ob.signed(start, err, 1)
+	return S, err
+}
+
+func (s *ConcurrentSigner) sign(msg []byte, opts []Option) ([][]byte, error) {
+	if err := s.policy.allow(OpSign, 0); err != nil {
+		return nil, err
+	}
+	S, err := Sign(s.suite, s.priKey, msg, s.options(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.signed(auditContext(opts), [][]byte{msg}, S); err != nil {
+		return nil, err
+	}
+	return S, nil
+}
+
+// BatchSign is BatchSign with the signer's key, under its policy.
+func (s *ConcurrentSigner) BatchSign(msgs [][]byte, opts ...Option) ([][]byte, error) {
+	start := s.ob.start()
+	S, err := s.batchSign(msgs, opts)
+	s.ob.signed(start, err, len(msgs))
+	return S, err
+}
+
+func (s *ConcurrentSigner) batchSign(msgs [][]byte, opts []Option) ([][]byte, error) {
+	if err := s.policy.allow(OpSign, slotRange(0, len(msgs))...); err != nil {
+		return nil, err
+	}
+	S, err := BatchSign(s.suite, s.priKey, msgs, s.options(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.signed(auditContext(opts), msgs, S); err != nil {
+		return nil, err
+	}
+	return S, nil
+}
+
+// options returns the options of a call followed by those of the signer and
+// its stream.
+func (s *ConcurrentSigner) options(opts []Option) []Option {
+	all := make([]Option, 0, len(opts)+len(s.opts)+1)
+	all = append(append(all, opts...), s.opts...)
+	if s.rand != nil {
+		all = append(all, withStream(s.rand))
+	}
+	return all
+}
@@ -0,0 +1,82 @@
+package ps
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestConcurrentSigner(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		// One stream shared by every goroutine, which the signer guards.
+		signer, err := NewConcurrentSigner(suite, priv, seededStream("concurrent"))
+		require.Nil(t, err)
+		const goroutines = 64
+		sigs := make([][][]byte, goroutines)
+		errs := make([]error, goroutines)
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				if g%2 == 0 {
+					sigs[g], errs[g] = signer.Sign(msgs[0])
+				} else {
+					sigs[g], errs[g] = signer.BatchSign(msgs)
+				}
+				if errs[g] == nil {
+					// Verification shares the key and messages too.
+					if g%2 == 0 {
+						errs[g] = Verify(suite, pub.Points(), msgs[0], sigs[g])
+					} else {
+						errs[g] = PSBatchVerify(suite, pub.Points(), msgs, sigs[g])
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		seen := make(map[string]bool)
+		for g := range sigs {
+			require.Nil(t, errs[g], "goroutine %d", g)
+			seen[fmt.Sprint(sigs[g][0])] = true
+		}
+		require.Equal(t, goroutines, len(seen))
+	})
+}
+
+func TestConcurrentSignerOptions(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("attribute 1")
+		priv, pub := newTestKeys(t, suite, 1)
+		dst := WithDST([]byte("EXAMPLE-APP-V1"))
+
+		signer, err := NewConcurrentSigner(suite, priv, nil, dst)
+		require.Nil(t, err)
+		S, err := signer.Sign(msg)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, S, dst))
+
+		// Deterministic signing ignores the stream.
+		signer, err = NewConcurrentSigner(suite, priv, seededStream("concurrent"), DeterministicSign())
+		require.Nil(t, err)
+		S, err = signer.Sign(msg)
+		require.Nil(t, err)
+		want, err := Sign(suite, priv.Scalars(), msg, DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, want, S)
+
+		// The signer keeps its key when the caller wipes theirs.
+		priv.Wipe()
+		S, err = signer.Sign(msg)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, S))
+		_, err = NewConcurrentSigner(suite, priv, nil)
+		require.Equal(t, ErrKeyWiped, err)
+	})
+}
@@ -0,0 +1,60 @@
+package ps_test
+
+import (
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/bithinalangot/ps/pstest"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// genericSuite hides the optional interfaces of the suite it wraps.
+type genericSuite struct {
+	pairing.Suite
+}
+
+var testSuites = []struct {
+	name  string
+	suite pairing.Suite
+}{
+	{"bn256", pairing.NewSuiteBn256()},
+	{"bls12381", bls12381.NewSuite()},
+	{"bls12381-generic", genericSuite{bls12381.NewSuite()}},
+}
+
+func TestConformance(t *testing.T) {
+	for _, ts := range testSuites {
+		suite := ts.suite
+		t.Run(ts.name, func(t *testing.T) { pstest.RunSchemeTests(t, suite, pstest.LocalSigner) })
+	}
+}
+
+func TestFixture(t *testing.T) {
+	for _, ts := range testSuites {
+		suite := ts.suite
+		t.Run(ts.name, func(t *testing.T) {
+			f := pstest.Fixture(t, suite, 3)
+			again := pstest.Fixture(t, suite, 3)
+			require.True(t, f.Public.Equal(again.Public))
+			want, err := f.Signature.MarshalBinary()
+			require.Nil(t, err)
+			got, err := again.Signature.MarshalBinary()
+			require.Nil(t, err)
+			require.Equal(t, want, got)
+
+			v, err := ps.NewVerifier(suite, f.Public)
+			require.Nil(t, err)
+			S, err := f.Signature.Components()
+			require.Nil(t, err)
+			require.Nil(t, v.BatchVerify(f.Messages, S))
+			require.NotNil(t, v.BatchVerify(f.Messages[:2], S))
+		})
+	}
+
+	// The generic path derives the same fixture as the optimized one.
+	f := pstest.Fixture(t, bls12381.NewSuite(), 2)
+	g := pstest.Fixture(t, genericSuite{bls12381.NewSuite()}, 2)
+	require.True(t, f.Private.Public().Equal(g.Public))
+}
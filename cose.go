@@ -0,0 +1,171 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.dedis.ch/kyber/v3"
+)
+
+// A PS public key is represented as a COSE_Key (RFC 8152, section 7) map:
+//
+//	{
+//	  1:  COSEKeyTypePS,     ; kty
+//	  -1: SuiteID,           ; crv
+//	  -2: bstr,              ; x, the marshalled point X
+//	  -3: [+ bstr],          ; y, the marshalled points Y_1,...,Y_r
+//	}
+//
+// The key type is taken from the private-use range of the COSE Key Types
+// registry. Encoding follows the CTAP2 canonical CBOR rules so the same key
+// always produces the same bytes.
+
+// COSEKeyTypePS is the kty value of PS public keys.
+const COSEKeyTypePS = -65537
+
+const (
+	coseLabelKty = 1
+	coseLabelCrv = -1
+	coseLabelX   = -2
+	coseLabelY   = -3
+)
+
+// CBOR major types checked while parsing.
+const (
+	cborUint   = 0
+	cborNegint = 1
+	cborBytes  = 2
+	cborArray  = 4
+)
+
+// ErrInvalidCOSEKey is returned when a COSE_Key is malformed or is not a PS
+// public key.
+var ErrInvalidCOSEKey = errors.New("ps: invalid COSE key")
+
+var (
+	coseEncMode, _ = cbor.CTAP2EncOptions().EncMode()
+	coseDecMode, _ = cbor.DecOptions{DupMapKey: cbor.DupMapKeyEnforcedAPF}.DecMode()
+)
+
+// ToCOSEKey encodes the public key as a COSE_Key.
+func (k *PublicKey) ToCOSEKey() ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	id, err := SuiteIDOf(k.suite)
+	if err != nil {
+		return nil, err
+	}
+	x, err := k.X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ys := make([][]byte, len(k.Y))
+	for i, y := range k.Y {
+		if ys[i], err = y.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return coseEncMode.Marshal(map[int]interface{}{
+		coseLabelKty: COSEKeyTypePS,
+		coseLabelCrv: byte(id),
+		coseLabelX:   x,
+		coseLabelY:   ys,
+	})
+}
+
+// FromCOSEKey decodes a COSE_Key produced by ToCOSEKey. Keys with more than
+// maxAttrs attributes are refused before any of their points is parsed. If the
+// key already has a suite the crv parameter must match it, otherwise the suite
+// is looked up in the registry.
+func (k *PublicKey) FromCOSEKey(data []byte, maxAttrs int) error {
+	if k == nil {
+		return ErrNilKey
+	}
+	var m map[int]cbor.RawMessage
+	if err := coseDecMode.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCOSEKey, err)
+	}
+
+	var kty int64
+	if err := coseParam(m, coseLabelKty, &kty, cborUint, cborNegint); err != nil {
+		return err
+	}
+	if kty != COSEKeyTypePS {
+		return fmt.Errorf("%w: unexpected key type %d", ErrInvalidCOSEKey, kty)
+	}
+	var crv uint64
+	if err := coseParam(m, coseLabelCrv, &crv, cborUint); err != nil {
+		return err
+	}
+	if crv > 0xff {
+		return fmt.Errorf("%w: curve %d out of range", ErrInvalidCOSEKey, crv)
+	}
+	suite, err := checkSuite(k.suite, SuiteID(crv))
+	if err != nil {
+		return err
+	}
+
+	var x []byte
+	if err := coseParam(m, coseLabelX, &x, cborBytes); err != nil {
+		return err
+	}
+	var ys []cbor.RawMessage
+	if err := coseParam(m, coseLabelY, &ys, cborArray); err != nil {
+		return err
+	}
+	if len(ys) == 0 {
+		return fmt.Errorf("%w: no attributes", ErrInvalidCOSEKey)
+	}
+	if len(ys) > maxAttrs {
+		return fmt.Errorf("%w: %d attributes exceed the maximum of %d", ErrInvalidCOSEKey, len(ys), maxAttrs)
+	}
+
+	X, err := unmarshalPoint(suite.G2(), x, false)
+	if err != nil {
+		return err
+	}
+	points := []kyber.Point{X}
+	for i, raw := range ys {
+		var y []byte
+		if err := coseValue(raw, &y, cborBytes); err != nil {
+			return fmt.Errorf("%w: y[%d]", err, i)
+		}
+		p, err := unmarshalPoint(suite.G2(), y, false)
+		if err != nil {
+			return err
+		}
+		points = append(points, p)
+	}
+	return k.set(suite, points)
+}
+
+// coseParam decodes the parameter with the given label into v after checking
+// it has one of the allowed major types.
+func coseParam(m map[int]cbor.RawMessage, label int, v interface{}, types ...byte) error {
+	raw, ok := m[label]
+	if !ok {
+		return fmt.Errorf("%w: missing parameter %d", ErrInvalidCOSEKey, label)
+	}
+	if err := coseValue(raw, v, types...); err != nil {
+		return fmt.Errorf("%w: parameter %d", err, label)
+	}
+	return nil
+}
+
+func coseValue(raw cbor.RawMessage, v interface{}, types ...byte) error {
+	if len(raw) == 0 {
+		return ErrInvalidCOSEKey
+	}
+	major := raw[0] >> 5
+	for _, t := range types {
+		if major == t {
+			if err := coseDecMode.Unmarshal(raw, v); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidCOSEKey, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unexpected CBOR major type %d", ErrInvalidCOSEKey, major)
+}
@@ -0,0 +1,102 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestCOSEKeyRoundTrip(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 3)
+		buf, err := pub.ToCOSEKey()
+		require.Nil(t, err)
+
+		var dec PublicKey
+		require.Nil(t, dec.FromCOSEKey(buf, 3))
+		require.True(t, pub.X.Equal(dec.X))
+		require.Equal(t, len(pub.Y), len(dec.Y))
+		for i := range pub.Y {
+			require.True(t, pub.Y[i].Equal(dec.Y[i]))
+		}
+
+		err = dec.FromCOSEKey(buf, 2)
+		require.True(t, errors.Is(err, ErrInvalidCOSEKey))
+	})
+}
+
+func TestCOSEKeyFixture(t *testing.T) {
+	suite := bls12381.NewSuite()
+	fixture, err := ioutil.ReadFile(filepath.Join("testdata", "pubkey_bls12381.cose"))
+	require.Nil(t, err)
+
+	// The fixture is plain CBOR any generic decoder understands.
+	var generic map[interface{}]interface{}
+	require.Nil(t, cbor.Unmarshal(fixture, &generic))
+	require.Equal(t, 4, len(generic))
+
+	pub, err := UnmarshalPublicKeyLegacy(suite, readFixture(t, "pubkey_v0_bls12381.hex"))
+	require.Nil(t, err)
+	buf, err := pub.ToCOSEKey()
+	require.Nil(t, err)
+	require.True(t, bytes.Equal(fixture, buf))
+
+	dec := &PublicKey{suite: suite}
+	require.Nil(t, dec.FromCOSEKey(fixture, 16))
+	require.True(t, pub.X.Equal(dec.X))
+
+	dec = &PublicKey{suite: pairing.NewSuiteBn256()}
+	require.True(t, errors.Is(dec.FromCOSEKey(fixture, 16), ErrSuiteMismatch))
+}
+
+func TestCOSEKeyInvalid(t *testing.T) {
+	suite := bls12381.NewSuite()
+	_, pub := newTestKeys(t, suite, 1)
+	x, err := pub.X.MarshalBinary()
+	require.Nil(t, err)
+	y, err := pub.Y[0].MarshalBinary()
+	require.Nil(t, err)
+
+	valid := func() map[int]interface{} {
+		return map[int]interface{}{
+			coseLabelKty: COSEKeyTypePS,
+			coseLabelCrv: byte(SuiteBLS12381),
+			coseLabelX:   x,
+			coseLabelY:   [][]byte{y},
+		}
+	}
+	cases := map[string]func(m map[int]interface{}){
+		"missing kty":    func(m map[int]interface{}) { delete(m, coseLabelKty) },
+		"wrong kty":      func(m map[int]interface{}) { m[coseLabelKty] = 2 },
+		"missing crv":    func(m map[int]interface{}) { delete(m, coseLabelCrv) },
+		"negative crv":   func(m map[int]interface{}) { m[coseLabelCrv] = -1 },
+		"missing x":      func(m map[int]interface{}) { delete(m, coseLabelX) },
+		"text x":         func(m map[int]interface{}) { m[coseLabelX] = string(x) },
+		"missing y":      func(m map[int]interface{}) { delete(m, coseLabelY) },
+		"y not an array": func(m map[int]interface{}) { m[coseLabelY] = y },
+		"empty y":        func(m map[int]interface{}) { m[coseLabelY] = [][]byte{} },
+		"int in y":       func(m map[int]interface{}) { m[coseLabelY] = []interface{}{y, 7} },
+	}
+	for name, mutate := range cases {
+		m := valid()
+		mutate(m)
+		buf, err := cbor.Marshal(m)
+		require.Nil(t, err)
+
+		var dec PublicKey
+		err = dec.FromCOSEKey(buf, 4)
+		require.True(t, errors.Is(err, ErrInvalidCOSEKey), name)
+	}
+
+	buf, err := cbor.Marshal(valid())
+	require.Nil(t, err)
+	var dec PublicKey
+	require.Nil(t, dec.FromCOSEKey(buf, 4))
+}
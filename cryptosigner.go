@@ -0,0 +1,131 @@
+package ps
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A CryptoSigner lets code written against crypto.Signer sign with a PS key.
+// It signs the digest as the single message in raw-scalar mode, as
+// SignScalar does: the digest, read as a big-endian integer, is reduced
+// modulo the group order and signed without hashing it again. The signature
+// is returned in the fixed-length encoding of Signature.MarshalBinary and
+// checked with VerifyDigest.
+//
+// The hash named by the SignerOpts must be crypto.Hash(0), for digests the
+// caller computed itself, of at most 64 bytes, or one of SHA-256, SHA-384,
+// SHA-512 and SHA-512/256, for a digest of its size. Since the digest is
+// reduced, two digests congruent modulo the order share their signatures;
+// finding such a pair means breaking the hash.
+
+// maxRawDigestLen bounds the digests signed under crypto.Hash(0).
+const maxRawDigestLen = 64
+
+// ErrUnsupportedHash is returned by CryptoSigner for pre-hashes it does not
+// accept and digests that do not fit them.
+var ErrUnsupportedHash = errors.New("ps: unsupported digest")
+
+// signerHashes are the pre-hashes CryptoSigner accepts besides
+// crypto.Hash(0).
+var signerHashes = map[crypto.Hash]bool{
+	crypto.SHA256:     true,
+	crypto.SHA384:     true,
+	crypto.SHA512:     true,
+	crypto.SHA512_256: true,
+}
+
+// CryptoSigner adapts a PrivateKey to crypto.Signer.
+type CryptoSigner struct {
+	priv *PrivateKey
+}
+
+// NewCryptoSigner returns a crypto.Signer signing with priv, whose policy
+// applies as to a single message.
+func NewCryptoSigner(priv *PrivateKey) (*CryptoSigner, error) {
+	if err := priv.check(); err != nil {
+		return nil, err
+	}
+	return &CryptoSigner{priv: priv}, nil
+}
+
+// Public returns the *PublicKey of the signer.
+func (s *CryptoSigner) Public() crypto.PublicKey {
+	return s.priv.Public()
+}
+
+// Sign signs digest, drawing the signature's randomness from rand or, if
+// rand is nil, the suite's random stream.
+func (s *CryptoSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if err := s.priv.check(); err != nil {
+		return nil, err
+	}
+	if err := s.priv.policy.allow(OpSign, 0); err != nil {
+		return nil, err
+	}
+	suite := s.priv.suite
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	m, err := digestScalar(suite, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+	stream := suite.RandomStream()
+	if rand != nil {
+		stream = &readerStream{r: rand}
+	}
+	sig, err := signPoints(suite, s.priv.Scalars(), []kyber.Scalar{m}, stream)
+	if err != nil {
+		return nil, err
+	}
+	return sig.MarshalBinary()
+}
+
+// VerifyDigest checks a signature made by CryptoSigner on digest under pub,
+// with the same opts.
+func VerifyDigest(pub *PublicKey, digest, sig []byte, opts crypto.SignerOpts) error {
+	if err := pub.check(); err != nil {
+		return err
+	}
+	m, err := digestScalar(pub.suite, digest, opts)
+	if err != nil {
+		return err
+	}
+	dec, err := UnmarshalSignature(pub.suite, sig)
+	if err != nil {
+		return err
+	}
+	S, err := dec.Components()
+	if err != nil {
+		return err
+	}
+	return VerifyScalar(pub.suite, pub.Points(), m, S)
+}
+
+// digestScalar maps digest to the scalar of suite signed for it under opts.
+func digestScalar(suite pairing.Suite, digest []byte, opts crypto.SignerOpts) (kyber.Scalar, error) {
+	h := crypto.Hash(0)
+	if opts != nil {
+		h = opts.HashFunc()
+	}
+	switch {
+	case h == 0:
+		if len(digest) == 0 || len(digest) > maxRawDigestLen {
+			return nil, fmt.Errorf("%w: %d-byte raw digest", ErrUnsupportedHash, len(digest))
+		}
+	case !signerHashes[h]:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedHash, h)
+	case len(digest) != h.Size():
+		return nil, fmt.Errorf("%w: %d-byte digest for %v", ErrUnsupportedHash, len(digest), h)
+	}
+	m := newScalar(suite).SetBytes(digest)
+	if err := checkScalars([]kyber.Scalar{m}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,87 @@
+package ps
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// signWith stands in for infrastructure that only knows crypto.Signer.
+func signWith(s crypto.Signer, msg []byte) (crypto.PublicKey, []byte, error) {
+	digest := sha256.Sum256(msg)
+	sig, err := s.Sign(nil, digest[:], crypto.SHA256)
+	return s.Public(), sig, err
+}
+
+func TestCryptoSigner(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		s, err := NewCryptoSigner(priv)
+		require.Nil(t, err)
+		msg := []byte("audit record 42")
+		key, sig, err := signWith(s, msg)
+		require.Nil(t, err)
+		require.True(t, pub.Equal(key))
+		_, other := newTestKeys(t, suite, 1)
+		require.False(t, other.Equal(key))
+		require.False(t, pub.Equal(sig))
+
+		want, err := newTestSignature(t, suite, priv, msg).MarshalBinary()
+		require.Nil(t, err)
+		require.Len(t, sig, len(want))
+
+		digest := sha256.Sum256(msg)
+		require.Nil(t, VerifyDigest(pub, digest[:], sig, crypto.SHA256))
+		require.Equal(t, ErrInvalidSignature, VerifyDigest(other, digest[:], sig, crypto.SHA256))
+		digest[0] ^= 1
+		require.Equal(t, ErrInvalidSignature, VerifyDigest(pub, digest[:], sig, crypto.SHA256))
+
+		// Raw digests are signed as scalars, as by SignScalar.
+		raw := []byte{0x01, 0x00}
+		sig, err = s.Sign(nil, raw, crypto.Hash(0))
+		require.Nil(t, err)
+		dec, err := UnmarshalSignature(suite, sig)
+		require.Nil(t, err)
+		S, err := dec.Components()
+		require.Nil(t, err)
+		require.Nil(t, VerifyScalar(suite, pub.Points(), newScalar(suite).SetInt64(256), S))
+
+		long := sha512.Sum512(msg)
+		sig, err = s.Sign(nil, long[:], crypto.SHA512)
+		require.Nil(t, err)
+		require.Nil(t, VerifyDigest(pub, long[:], sig, crypto.SHA512))
+	})
+}
+
+func TestCryptoSignerRejects(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, _ := newTestKeys(t, suite, 1)
+	s, err := NewCryptoSigner(priv)
+	require.Nil(t, err)
+	digest := sha256.Sum256([]byte("msg"))
+
+	for _, tc := range []struct {
+		name   string
+		digest []byte
+		opts   crypto.SignerOpts
+	}{
+		{"unsupported hash", digest[:], crypto.SHA1},
+		{"wrong size", digest[:20], crypto.SHA256},
+		{"empty raw", nil, crypto.Hash(0)},
+		{"long raw", make([]byte, 65), crypto.Hash(0)},
+	} {
+		_, err := s.Sign(nil, tc.digest, tc.opts)
+		require.True(t, errors.Is(err, ErrUnsupportedHash), tc.name)
+	}
+	_, err = s.Sign(nil, make([]byte, 32), crypto.SHA256)
+	require.True(t, errors.Is(err, ErrZeroAttribute))
+
+	require.Nil(t, priv.SetPolicy(&KeyPolicy{Operations: OpBlindSign}))
+	_, err = s.Sign(nil, digest[:], crypto.SHA256)
+	require.True(t, errors.Is(err, ErrPolicyViolation))
+}
@@ -0,0 +1,282 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Delegation lets the holder of a credential extend it to another key, such
+// as a device key, without involving the issuer. The holder has a secret s
+// and the key S = g1^s in G1. The issuer signs the holder's messages followed
+// by HolderKeyAttribute(S), so that the holder key takes the slot after the
+// messages. To delegate, the holder signs the delegatee key D in G1 and the
+// delegated attributes with a Schnorr signature under S,
+//
+//	R = g1^r,  c = H(S || D || attributes || R),  z = r + c*s,
+//
+// hashed under "PS-DELEG-" || curve || "-V1". A verifier checks the issuer's
+// signature on the messages and S, then the holder's signature on D and the
+// attributes. Delegation is limited to one level: a delegatee cannot delegate
+// further. Proving possession of the delegatee key, e.g. by answering a
+// challenge, is left to the protocol using the credential.
+
+var (
+	// ErrInvalidDelegation is returned when the holder's signature on the
+	// delegatee key does not verify.
+	ErrInvalidDelegation = errors.New("ps: invalid delegation")
+
+	// ErrDelegationDepth is returned when delegating a delegated credential.
+	ErrDelegationDepth = errors.New("ps: credential is already delegated")
+)
+
+// DelegatedCredential is the issuer's signature on the holder key Holder,
+// extended by the holder to the key Delegatee with the attributes Attrs.
+// Challenge and Response are the holder's Schnorr signature (c, z).
+type DelegatedCredential struct {
+	suite     pairing.Suite
+	Signature *Signature
+	Holder    kyber.Point
+	Delegatee kyber.Point
+	Attrs     [][]byte
+	Challenge kyber.Scalar
+	Response  kyber.Scalar
+}
+
+// HolderKeyAttribute returns the message encoding the holder key S in G1,
+// which the issuer signs after the holder's messages.
+func HolderKeyAttribute(suite pairing.Suite, holderPub kyber.Point) ([]byte, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if holderPub == nil {
+		return nil, ErrNilKey
+	}
+	return appendPoints(nil, suite.G1(), false, holderPub)
+}
+
+// check reports whether the credential is complete.
+func (c *DelegatedCredential) check() error {
+	if c == nil || c.Holder == nil || c.Delegatee == nil || c.Challenge == nil || c.Response == nil {
+		return fmt.Errorf("%w: incomplete credential", ErrInvalidDelegation)
+	}
+	if c.suite == nil {
+		return ErrNilSuite
+	}
+	return c.Signature.check()
+}
+
+// delegationChallenge derives the challenge of the holder's signature on the
+// delegatee key and attributes.
+func delegationChallenge(suite pairing.Suite, holder, delegatee kyber.Point, attrs [][]byte, R kyber.Point) (kyber.Scalar, error) {
+	buf, err := appendPoints(nil, suite.G1(), false, holder, delegatee)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(attrs)); err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if buf, err = appendBytes(buf, attr); err != nil {
+			return nil, err
+		}
+	}
+	if buf, err = appendPoints(buf, suite.G1(), false, R); err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "DELEG"), buf), nil
+}
+
+// Delegate extends holderSig, the issuer's signature on the holder's
+// messages and the key g1^holderSecret, to the delegatee key delegateePub in
+// G1 with the attributes attrs.
+func Delegate(suite pairing.Suite, holderSig *Signature, holderSecret kyber.Scalar, delegateePub kyber.Point, attrs [][]byte) (*DelegatedCredential, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := holderSig.check(); err != nil {
+		return nil, err
+	}
+	if holderSecret == nil || holderSecret.Equal(holderSecret.Clone().Zero()) {
+		return nil, ErrNilKey
+	}
+	if delegateePub == nil {
+		return nil, ErrNilKey
+	}
+	if isIdentity(suite.G1(), delegateePub) {
+		return nil, fmt.Errorf("%w: identity delegatee key", ErrInvalidPoint)
+	}
+	if err := checkSubgroup(suite.G1(), delegateePub); err != nil {
+		return nil, err
+	}
+	if err := checkMessages(attrs...); err != nil {
+		return nil, err
+	}
+	r, err := pickScalar(suite, suite.RandomStream())
+	if err != nil {
+		return nil, err
+	}
+	defer r.Zero()
+	holder := suite.G1().Point().Mul(holderSecret, nil)
+	c, err := delegationChallenge(suite, holder, delegateePub, attrs, suite.G1().Point().Mul(r, nil))
+	if err != nil {
+		return nil, err
+	}
+	z := newScalar(suite).Mul(c, holderSecret)
+	z.Add(z, r)
+	copied := make([][]byte, len(attrs))
+	for i, attr := range attrs {
+		copied[i] = append([]byte{}, attr...)
+	}
+	return &DelegatedCredential{
+		suite:     suite,
+		Signature: &Signature{suite: suite, Sigma1: holderSig.Sigma1.Clone(), Sigma2: holderSig.Sigma2.Clone()},
+		Holder:    holder,
+		Delegatee: delegateePub.Clone(),
+		Attrs:     copied,
+		Challenge: c,
+		Response:  z,
+	}, nil
+}
+
+// Delegate would extend a delegated credential one level further, which is
+// not supported: it always returns ErrDelegationDepth.
+func (c *DelegatedCredential) Delegate(suite pairing.Suite, delegateeSecret kyber.Scalar, delegateePub kyber.Point, attrs [][]byte) (*DelegatedCredential, error) {
+	return nil, ErrDelegationDepth
+}
+
+// VerifyDelegated checks both links of a delegated credential: the issuer's
+// signature under issuerPub on msgs and the holder key, and the holder's
+// signature on the delegatee key and attributes. It returns
+// ErrInvalidSignature if the first does not verify and ErrInvalidDelegation
+// if the second does not.
+func VerifyDelegated(suite pairing.Suite, issuerPub *PublicKey, cred *DelegatedCredential, msgs [][]byte, opts ...Option) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	if err := issuerPub.check(); err != nil {
+		return err
+	}
+	if err := cred.check(); err != nil {
+		return err
+	}
+	g1 := suite.G1()
+	for _, p := range []kyber.Point{cred.Holder, cred.Delegatee} {
+		if isIdentity(g1, p) {
+			return fmt.Errorf("%w: identity key", ErrInvalidDelegation)
+		}
+		if err := checkSubgroup(g1, p); err != nil {
+			return err
+		}
+	}
+	holderAttr, err := HolderKeyAttribute(suite, cred.Holder)
+	if err != nil {
+		return err
+	}
+	S, err := cred.Signature.Components()
+	if err != nil {
+		return err
+	}
+	all := append(append([][]byte{}, msgs...), holderAttr)
+	if err := PSBatchVerify(suite, issuerPub.Points(), all, S, opts...); err != nil {
+		return err
+	}
+	// R = g1^z / S^c
+	R := g1.Point().Mul(cred.Response, nil)
+	R.Sub(R, g1.Point().Mul(cred.Challenge, cred.Holder))
+	c, err := delegationChallenge(suite, cred.Holder, cred.Delegatee, cred.Attrs, R)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(cred.Challenge) {
+		return ErrInvalidDelegation
+	}
+	return nil
+}
+
+// MarshalBinary encodes the credential as header || sigma_1 || sigma_2 ||
+// S || D || c || z || n || attr_1 || ... || attr_n, each attribute prefixed
+// with its length.
+func (c *DelegatedCredential) MarshalBinary() ([]byte, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(c.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, c.suite.G1(), false, c.Signature.Sigma1, c.Signature.Sigma2, c.Holder, c.Delegatee); err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalars(buf, c.suite.G1(), c.Challenge, c.Response); err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(c.Attrs)); err != nil {
+		return nil, err
+	}
+	for _, attr := range c.Attrs {
+		if buf, err = appendBytes(buf, attr); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a credential, see PrivateKey.UnmarshalBinary.
+func (c *DelegatedCredential) UnmarshalBinary(data []byte) error {
+	if c == nil {
+		return ErrInvalidDelegation
+	}
+	suite, body, newer, err := readHeader(c.suite, data)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 4, false)
+	if err != nil {
+		return err
+	}
+	scalars, body, err := decodeScalars(suite.G1(), body, 2)
+	if err != nil {
+		return err
+	}
+	n, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	attrs := make([][]byte, n)
+	for i := range attrs {
+		if attrs[i], body, err = readBytes(body); err != nil {
+			return err
+		}
+	}
+	if err := checkTrailing(body, newer); err != nil {
+		return err
+	}
+	*c = DelegatedCredential{
+		suite:     suite,
+		Signature: &Signature{suite: suite, Sigma1: points[0], Sigma2: points[1]},
+		Holder:    points[2],
+		Delegatee: points[3],
+		Attrs:     attrs,
+		Challenge: scalars[0],
+		Response:  scalars[1],
+	}
+	return nil
+}
+
+// UnmarshalDelegatedCredential decodes a credential produced under suite.
+func UnmarshalDelegatedCredential(suite pairing.Suite, data []byte) (*DelegatedCredential, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	c := &DelegatedCredential{suite: suite}
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
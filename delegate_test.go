@@ -0,0 +1,75 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// issueHolder has the issuer sign msgs and the key of a fresh holder, and
+// returns the signature and the holder's secret.
+func issueHolder(t *testing.T, suite pairing.Suite, priv *PrivateKey, msgs [][]byte) (*Signature, kyber.Scalar) {
+	secret := newScalar(suite).Pick(random.New())
+	attr, err := HolderKeyAttribute(suite, suite.G1().Point().Mul(secret, nil))
+	require.Nil(t, err)
+	S, err := BatchSign(suite, priv.Scalars(), append(append([][]byte{}, msgs...), attr))
+	require.Nil(t, err)
+	sig, err := NewSignature(suite, S)
+	require.Nil(t, err)
+	return sig, secret
+}
+
+func TestDelegate(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("role=admin")}
+		priv, pub := newTestKeys(t, suite, len(msgs)+1)
+		sig, secret := issueHolder(t, suite, priv, msgs)
+		device := suite.G1().Point().Pick(random.New())
+		attrs := [][]byte{[]byte("device=laptop")}
+
+		cred, err := Delegate(suite, sig, secret, device, attrs)
+		require.Nil(t, err)
+		buf, err := cred.MarshalBinary()
+		require.Nil(t, err)
+		received, err := UnmarshalDelegatedCredential(suite, buf)
+		require.Nil(t, err)
+		require.Nil(t, VerifyDelegated(suite, pub, received, msgs))
+		require.Equal(t, attrs, received.Attrs)
+
+		// Both links are checked.
+		require.Equal(t, ErrInvalidSignature, VerifyDelegated(suite, pub, cred, [][]byte{msgs[0], []byte("role=root")}))
+		forged := *cred
+		forged.Attrs = [][]byte{[]byte("device=server")}
+		require.Equal(t, ErrInvalidDelegation, VerifyDelegated(suite, pub, &forged, msgs))
+		forged = *cred
+		forged.Delegatee = suite.G1().Point().Pick(random.New())
+		require.Equal(t, ErrInvalidDelegation, VerifyDelegated(suite, pub, &forged, msgs))
+
+		// One level only.
+		_, err = cred.Delegate(suite, newScalar(suite).Pick(random.New()), device, attrs)
+		require.Equal(t, ErrDelegationDepth, err)
+	})
+}
+
+func TestDelegateSwappedHolder(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice")}
+		priv, pub := newTestKeys(t, suite, len(msgs)+1)
+		sig, _ := issueHolder(t, suite, priv, msgs)
+		device := suite.G1().Point().Pick(random.New())
+
+		// A middle key the issuer did not sign breaks the chain, even though
+		// its own signature on the delegatee is valid.
+		mallory := newScalar(suite).Pick(random.New())
+		cred, err := Delegate(suite, sig, mallory, device, nil)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyDelegated(suite, pub, cred, msgs))
+
+		_, err = Delegate(suite, sig, mallory, suite.G1().Point().Null(), nil)
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+	})
+}
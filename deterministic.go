@@ -0,0 +1,87 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Deterministic signing derives h, the only randomness of a signature, from
+// the private key and the messages, in the spirit of RFC 6979. The stream h
+// is picked from is the output of HKDF-SHA256 (RFC 5869) with
+//
+//	IKM  = x || y_1 || ... || y_n
+//	salt = "PS-DET-" || curve || "-V1", e.g. "PS-DET-BN256-V1"
+//	info = k || m_1 || ... || m_k
+//
+// where the key scalars and the message scalars m_i, hashed under the
+// message tag, are in their canonical encoding and k is a big-endian uint16.
+// Signing the same messages with the same key and tag twice gives the same
+// signature, while any other message, key or tag gives an independent h.
+// A signature made this way is an ordinary signature: verifiers cannot tell
+// it apart, and re-randomizing or proving it works as usual.
+
+// DeterministicSign makes Sign, BatchSign and AggreSign derive the signature
+// from the key and the messages instead of the suite's random stream, so
+// that retries yield byte-identical signatures. The derivation is documented
+// above. Verification ignores the option.
+func DeterministicSign() Option {
+	return func(o *options) {
+		o.deterministic = true
+	}
+}
+
+// signingStream returns the stream to pick the signature of the message
+// scalars m from: the suite's random stream or the stream set by a
+// ConcurrentSigner, or the HKDF output for priKey and m under
+// DeterministicSign.
+func (o *options) signingStream(suite pairing.Suite, priKey []kyber.Scalar, m []kyber.Scalar) (cipher.Stream, error) {
+	if !o.deterministic {
+		if o.rand != nil {
+			return o.rand, nil
+		}
+		return suite.RandomStream(), nil
+	}
+	ikm, err := appendScalars(nil, suite.G1(), priKey...)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(ikm)
+	info, err := appendCount(nil, len(m))
+	if err != nil {
+		return nil, err
+	}
+	if info, err = appendScalars(info, suite.G1(), m...); err != nil {
+		return nil, err
+	}
+	return &readerStream{r: hkdf.New(sha256.New, ikm, protocolDST(suite, "DET"), info)}, nil
+}
+
+// withStream makes signing draw from rand instead of the suite's random
+// stream.
+func withStream(rand cipher.Stream) Option {
+	return func(o *options) {
+		o.rand = rand
+	}
+}
+
+// readerStream adapts a reader of key stream, such as HKDF output, to
+// cipher.Stream. It panics when the reader fails, which guardedStream turns
+// into ErrEntropyFailure.
+type readerStream struct {
+	r io.Reader
+}
+
+func (s *readerStream) XORKeyStream(dst, src []byte) {
+	buf := make([]byte, len(src))
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		panic(err)
+	}
+	for i := range src {
+		dst[i] = src[i] ^ buf[i]
+	}
+}
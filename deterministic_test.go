@@ -0,0 +1,61 @@
+package ps
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestDeterministicSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		first, err := BatchSign(suite, priv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		second, err := BatchSign(suite, priv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, first, second)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, first))
+
+		// Other messages, tags or keys give other signatures.
+		other, err := BatchSign(suite, priv.Scalars(), [][]byte{msgs[1], msgs[0]}, DeterministicSign())
+		require.Nil(t, err)
+		require.NotEqual(t, first[0], other[0])
+		other, err = BatchSign(suite, priv.Scalars(), msgs, DeterministicSign(), WithDST([]byte("other")))
+		require.Nil(t, err)
+		require.NotEqual(t, first[0], other[0])
+		otherPriv, _ := newTestKeys(t, suite, len(msgs))
+		other, err = BatchSign(suite, otherPriv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		require.NotEqual(t, first[0], other[0])
+
+		single, err := Sign(suite, priv.Scalars(), msgs[0], DeterministicSign())
+		require.Nil(t, err)
+		again, err := AggreSign(suite, priv.Scalars(), msgs[:1], DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, single, again)
+		require.Nil(t, Verify(suite, pub.Points(), msgs[0], single))
+		random, err := Sign(suite, priv.Scalars(), msgs[0])
+		require.Nil(t, err)
+		require.NotEqual(t, single[0], random[0])
+	})
+}
+
+// TestDeterministicSignGolden pins the derivation on BLS12-381, whose point
+// picking is implemented in this module.
+func TestDeterministicSignGolden(t *testing.T) {
+	suite := bls12381.NewSuite()
+	key := make([]kyber.Scalar, 3)
+	for i := range key {
+		key[i] = suite.G1().Scalar().SetInt64(int64(1000 + i))
+	}
+	S, err := BatchSign(suite, key, [][]byte{[]byte("attribute 1"), []byte("attribute 2")}, DeterministicSign())
+	require.Nil(t, err)
+	require.Equal(t, "9699e1a96bb4731cba881b6e44a4154808f7ab947b59d3c64c965b5cd15869d2c0de9b630c2385fd6a67b3eb47de4464", hex.EncodeToString(S[0]))
+	require.Equal(t, "ab1200280c574497d3a30fe052a2d2c62f0976b134bcf05f8cd0c4e8e51928a2b73cff08b9bcc750f4f219dfbb48c337", hex.EncodeToString(S[1]))
+}
@@ -0,0 +1,145 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A failing PSBatchVerify only says ErrInvalidSignature, whichever of the
+// messages was altered. DiagnoseBatchInputs checks what can be checked
+// without a pairing: that every message is present, that the key has a
+// well-formed point for every message and that the signature decodes.
+// DiagnoseBatchFailure adds the intermediate aggregates of a sequential
+// chain, partials[i] being the aggregate of msgs[:i+1]: a stored message
+// that differs from the signed one breaks every aggregate from its index on,
+// so a binary search over the prefixes finds it in about log2(len(msgs))
+// verifications.
+
+// ErrMissingPartial is reported when an intermediate aggregate of a
+// diagnosis is nil or incomplete.
+var ErrMissingPartial = errors.New("ps: missing intermediate aggregate")
+
+// DiagnosisIssue is a problem found by a diagnosis. Index is the message,
+// key component or aggregate it concerns, or -1 for the signature.
+type DiagnosisIssue struct {
+	Part  string
+	Index int
+	Err   error
+}
+
+func (i DiagnosisIssue) String() string {
+	if i.Index < 0 {
+		return fmt.Sprintf("%s: %v", i.Part, i.Err)
+	}
+	return fmt.Sprintf("%s %d: %v", i.Part, i.Index, i.Err)
+}
+
+// DiagnosisReport is the outcome of a diagnosis of a batch verification.
+type DiagnosisReport struct {
+	// Messages is the number of messages diagnosed.
+	Messages int
+	// Verified reports whether the signature verifies on all messages.
+	Verified bool
+	// FirstFailure is the index of the first message from which the
+	// intermediate aggregates stop verifying, or -1 if it was not located.
+	FirstFailure int
+	// Issues lists the problems found with the inputs.
+	Issues []DiagnosisIssue
+}
+
+func (r *DiagnosisReport) add(part string, index int, err error) {
+	r.Issues = append(r.Issues, DiagnosisIssue{Part: part, Index: index, Err: err})
+}
+
+// DiagnoseBatchInputs checks the inputs of PSBatchVerify without verifying
+// the signature: every message must be non-empty and have a key component,
+// every used key component must lie in the prime-order subgroup, and S must
+// decode. Verified is left false.
+func DiagnoseBatchInputs(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, S [][]byte, opts ...Option) (*DiagnosisReport, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	r := &DiagnosisReport{Messages: len(msgs), FirstFailure: -1}
+	diagnoseInputs(suite, r, pubKey, msgs, S, o)
+	return r, nil
+}
+
+func diagnoseInputs(suite pairing.Suite, r *DiagnosisReport, pubKey []kyber.Point, msgs [][]byte, S [][]byte, o *options) {
+	if len(msgs) == 0 {
+		r.add("messages", -1, ErrNoMessages)
+	}
+	for i, msg := range msgs {
+		if len(msg) == 0 {
+			r.add("message", i, ErrEmptyMessage)
+		}
+		if i+1 >= len(pubKey) {
+			r.add("message", i, fmt.Errorf("%w: no key component Y_%d", ErrKeyLengthMismatch, i+1))
+		}
+	}
+	n := len(msgs) + 1
+	if n > len(pubKey) {
+		n = len(pubKey)
+	}
+	for i, p := range pubKey[:n] {
+		if p == nil {
+			r.add("key", i, ErrNilKey)
+		} else if err := checkSubgroup(suite.G2(), p); err != nil {
+			r.add("key", i, err)
+		}
+	}
+	if _, err := parseSignature(suite, S, o.validatePoints); err != nil {
+		r.add("signature", -1, err)
+	}
+}
+
+// DiagnoseBatchFailure diagnoses a PSBatchVerify of S on msgs: it runs the
+// checks of DiagnoseBatchInputs and verifies S, and if S fails it searches
+// partials, the intermediate aggregates of the chain that produced S, for
+// the first message from which they fail. partials may be shorter than
+// msgs; with none, FirstFailure stays -1.
+func DiagnoseBatchFailure(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, S [][]byte, partials []*Signature, opts ...Option) (*DiagnosisReport, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(partials) > len(msgs) {
+		return nil, fmt.Errorf("%w: %d aggregates for %d messages", ErrBatchLengthMismatch, len(partials), len(msgs))
+	}
+	r := &DiagnosisReport{Messages: len(msgs), FirstFailure: -1}
+	diagnoseInputs(suite, r, pubKey, msgs, S, o)
+	if len(r.Issues) > 0 {
+		return r, nil
+	}
+	r.Verified = PSBatchVerify(suite, pubKey, msgs, S, opts...) == nil
+	if r.Verified {
+		return r, nil
+	}
+	for i, p := range partials {
+		if p.check() != nil {
+			r.add("aggregate", i, ErrMissingPartial)
+		}
+	}
+	if len(partials) == 0 || len(r.Issues) > 0 {
+		return r, nil
+	}
+	// The first prefix whose aggregate fails, if any, lies in [lo, hi].
+	lo, hi := 0, len(partials)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if PSBatchVerifyPoints(suite, pubKey, msgs[:mid+1], partials[mid], opts...) == nil {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	// With every aggregate verifying, only a last message outside them can
+	// be located.
+	if lo < len(partials) || lo == len(msgs)-1 {
+		r.FirstFailure = lo
+	}
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestDiagnoseBatchFailure(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := make([][]byte, 50)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		// Keep the intermediate aggregates of the chain.
+		partials := make([]*Signature, len(msgs))
+		sig, err := AggreSignPoints(suite, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		partials[0] = sig
+		for i := 1; i < len(msgs); i++ {
+			sig, err = AggregatePSSignPoints(suite, priv.Y[i], sig, msgs[i])
+			require.Nil(t, err)
+			partials[i] = sig
+		}
+		S, err := sig.Components()
+		require.Nil(t, err)
+
+		r, err := DiagnoseBatchFailure(suite, pub.Points(), msgs, S, partials)
+		require.Nil(t, err)
+		require.True(t, r.Verified)
+		require.Equal(t, -1, r.FirstFailure)
+		require.Empty(t, r.Issues)
+
+		// Message 17 was altered in storage.
+		stored := append([][]byte{}, msgs...)
+		stored[17] = []byte("attribute 71")
+		r, err = DiagnoseBatchFailure(suite, pub.Points(), stored, S, partials)
+		require.Nil(t, err)
+		require.False(t, r.Verified)
+		require.Equal(t, 17, r.FirstFailure)
+
+		// The last message, with only the aggregates before it.
+		stored = append([][]byte{}, msgs...)
+		stored[49] = []byte("attribute 94")
+		r, err = DiagnoseBatchFailure(suite, pub.Points(), stored, S, partials[:49])
+		require.Nil(t, err)
+		require.Equal(t, 49, r.FirstFailure)
+
+		// Without aggregates the failure is not located.
+		r, err = DiagnoseBatchFailure(suite, pub.Points(), stored, S, nil)
+		require.Nil(t, err)
+		require.False(t, r.Verified)
+		require.Equal(t, -1, r.FirstFailure)
+
+		partials[3] = nil
+		r, err = DiagnoseBatchFailure(suite, pub.Points(), stored, S, partials)
+		require.Nil(t, err)
+		require.Equal(t, []DiagnosisIssue{{Part: "aggregate", Index: 3, Err: ErrMissingPartial}}, r.Issues)
+	})
+}
+
+func TestDiagnoseBatchInputs(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 0"), []byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+
+		r, err := DiagnoseBatchInputs(suite, pub.Points(), msgs, S)
+		require.Nil(t, err)
+		require.Empty(t, r.Issues)
+
+		// An empty message, a key one component short, a point outside the
+		// subgroup and a truncated signature.
+		stored := [][]byte{msgs[0], nil, msgs[2]}
+		key := pub.Points()[:3]
+		key[1] = suite.G2().Point().Pick(random.New())
+		if checkSubgroup(suite.G2(), key[1]) == nil {
+			key[1] = nil
+		}
+		r, err = DiagnoseBatchInputs(suite, key, stored, [][]byte{S[0], S[1][1:]})
+		require.Nil(t, err)
+		var parts []string
+		for _, issue := range r.Issues {
+			parts = append(parts, issue.Part+" "+fmt.Sprint(issue.Index))
+		}
+		require.Equal(t, []string{"message 1", "message 2", "key 1", "signature -1"}, parts)
+		require.Equal(t, ErrEmptyMessage, r.Issues[0].Err)
+		require.True(t, errors.Is(r.Issues[1].Err, ErrKeyLengthMismatch))
+		require.True(t, errors.Is(r.Issues[3].Err, ErrMalformedSignature))
+		require.Contains(t, r.Issues[1].String(), "message 2: ")
+	})
+}
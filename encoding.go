@@ -0,0 +1,581 @@
+package ps
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Serialized keys and signatures start with a header made of the major and
+// minor format version and the one-byte SuiteID of their suite, followed by
+// the kyber encodings of their components. Keys also carry their attribute
+// count r as a big-endian uint16:
+//
+//	private key: major || minor || id || r || x || y_1 || ... || y_r || policy
+//	public key:  major || minor || id || r || X || Y_1 || ... || Y_r || sum
+//	signature:   major || minor || id || sigma_1 || sigma_2
+//
+// Since version 1.1 public keys end with sum, the SHA-256 checksum of
+// id || r || X || Y_1 || ... || Y_r with uncompressed points, so that a key
+// whose components were reordered or altered is rejected with ErrCorruptKey.
+// The same digest is the key's Fingerprint. Since version 1.2 private keys
+// end with their KeyPolicy, see appendPolicy, and since version 1.3
+// signature proofs end with the KeyID of their key.
+//
+// A reader rejects major versions it does not know. Newer minor versions may
+// only append fields, which older readers skip; for known minor versions any
+// trailing data is an error. Signatures are exempt: they never gain fields, so
+// bytes after sigma_2 are rejected under every minor version, keeping one
+// encoding per signature for systems that hash them.
+//
+// Version 0 is the legacy headerless encoding: the bare concatenation of the
+// component encodings. It can only be read through the Legacy functions, and
+// MigrateKey upgrades a v0 public key to the current version.
+
+// Format version written by this package.
+const (
+	FormatMajor = 1
+	FormatMinor = 3
+)
+
+// checksumMinor is the first minor version of major 1 carrying public key
+// checksums.
+const checksumMinor = 1
+
+// policyMinor is the first minor version of major 1 carrying private key
+// policies.
+const policyMinor = 2
+
+// keyIDMinor is the first minor version of major 1 carrying the key ID of
+// signature proofs.
+const keyIDMinor = 3
+
+// supportedMinor maps every major version that can be read to the highest
+// minor version whose fields are understood.
+var supportedMinor = map[byte]byte{
+	1: 3,
+}
+
+const headerLen = 3
+
+var (
+	// ErrSuiteMismatch is returned when a serialized artifact was produced
+	// under a different suite than the one it is loaded with.
+	ErrSuiteMismatch = errors.New("ps: suite mismatch")
+
+	// ErrUnsupportedVersion is returned for encodings with an unknown major
+	// format version.
+	ErrUnsupportedVersion = errors.New("ps: unsupported format version")
+
+	// ErrCorruptKey is returned when the checksum of a serialized public key
+	// does not match its components.
+	ErrCorruptKey = errors.New("ps: corrupt public key")
+)
+
+func writeHeader(suite pairing.Suite) ([]byte, error) {
+	id, err := SuiteIDOf(suite)
+	if err != nil {
+		return nil, err
+	}
+	return []byte{FormatMajor, FormatMinor, byte(id)}, nil
+}
+
+// checkSuite resolves the suite identified by got. A nil suite is looked up in
+// the registry, otherwise the identifier must match it.
+func checkSuite(suite pairing.Suite, got SuiteID) (pairing.Suite, error) {
+	if suite == nil {
+		return SuiteByID(got)
+	}
+	want, err := SuiteIDOf(suite)
+	if err != nil {
+		return nil, err
+	}
+	if got != want {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrSuiteMismatch, SuiteName(want), SuiteName(got))
+	}
+	return suite, nil
+}
+
+// readHeader checks the header of an encoding and returns its suite and body.
+// newer reports whether the body was written by a newer minor version and may
+// therefore carry trailing fields to skip.
+func readHeader(suite pairing.Suite, data []byte) (pairing.Suite, []byte, bool, error) {
+	if len(data) < headerLen {
+		return nil, nil, false, errors.New("ps: truncated encoding")
+	}
+	major, minor := data[0], data[1]
+	known, ok := supportedMinor[major]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("%w: %d.%d", ErrUnsupportedVersion, major, minor)
+	}
+	s, err := checkSuite(suite, SuiteID(data[2]))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return s, data[headerLen:], minor > known, nil
+}
+
+func checkTrailing(rest []byte, newer bool) error {
+	if len(rest) > 0 && !newer {
+		return fmt.Errorf("ps: %d trailing bytes after encoding", len(rest))
+	}
+	return nil
+}
+
+func appendCount(buf []byte, n int) ([]byte, error) {
+	if n > 0xffff {
+		return nil, fmt.Errorf("ps: %d attributes exceed the encoding limit", n)
+	}
+	return append(buf, byte(n>>8), byte(n)), nil
+}
+
+func readCount(data []byte) (int, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, errors.New("ps: truncated attribute count")
+	}
+	return int(binary.BigEndian.Uint16(data)), data[2:], nil
+}
+
+// appendBytes appends b prefixed with its length as a count.
+func appendBytes(buf, b []byte) ([]byte, error) {
+	if len(b) > 0xffff {
+		return nil, fmt.Errorf("ps: %d bytes exceed the encoding limit", len(b))
+	}
+	return append(append(buf, byte(len(b)>>8), byte(len(b))), b...), nil
+}
+
+// readBytes reads a byte string written by appendBytes off data.
+func readBytes(data []byte) ([]byte, []byte, error) {
+	n, data, err := readCount(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) < n {
+		return nil, nil, errors.New("ps: truncated byte string")
+	}
+	return append([]byte{}, data[:n]...), data[n:], nil
+}
+
+// decodeScalars reads n scalars off data and returns the remaining bytes. A
+// negative n consumes the whole input, as in the legacy encoding.
+func decodeScalars(g kyber.Group, data []byte, n int) ([]kyber.Scalar, []byte, error) {
+	size := g.ScalarLen()
+	if n < 0 {
+		if len(data)%size != 0 {
+			return nil, nil, fmt.Errorf("ps: invalid scalar encoding length %d", len(data))
+		}
+		n = len(data) / size
+	}
+	if len(data) < n*size {
+		return nil, nil, errors.New("ps: truncated scalar encoding")
+	}
+	scalars := make([]kyber.Scalar, n)
+	for i := range scalars {
+		scalars[i] = g.Scalar()
+		if err := scalars[i].UnmarshalBinary(data[i*size : (i+1)*size]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return scalars, data[n*size:], nil
+}
+
+// decodePoints reads n points off data, see decodeScalars. Points are read in
+// compressed form if compressed is set.
+func decodePoints(g kyber.Group, data []byte, n int, compressed bool) ([]kyber.Point, []byte, error) {
+	size := pointLen(g, compressed)
+	if n < 0 {
+		if len(data)%size != 0 {
+			return nil, nil, fmt.Errorf("ps: invalid point encoding length %d", len(data))
+		}
+		n = len(data) / size
+	}
+	if len(data) < n*size {
+		return nil, nil, errors.New("ps: truncated point encoding")
+	}
+	points := make([]kyber.Point, n)
+	for i := range points {
+		p, err := unmarshalPoint(g, data[i*size:(i+1)*size], compressed)
+		if err != nil {
+			return nil, nil, err
+		}
+		points[i] = p
+	}
+	return points, data[n*size:], nil
+}
+
+func appendPoints(buf []byte, g kyber.Group, compressed bool, points ...kyber.Point) ([]byte, error) {
+	for _, p := range points {
+		b, err := marshalPoint(g, p, compressed)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// MarshalBinary encodes the private key in the current format.
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(k.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(k.Y)); err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalars(buf, k.suite.G1(), k.Scalars()...); err != nil {
+		return nil, err
+	}
+	return appendPolicy(buf, k.policy)
+}
+
+// appendScalars encodes secret scalars into buf, which is grown once up front
+// so that no stray copies are left behind by reallocation. The temporary
+// encodings are zeroed.
+func appendScalars(buf []byte, g kyber.Group, scalars ...kyber.Scalar) ([]byte, error) {
+	out := make([]byte, len(buf), len(buf)+len(scalars)*g.ScalarLen())
+	copy(out, buf)
+	for _, sc := range scalars {
+		b, err := sc.MarshalBinary()
+		if err != nil {
+			zeroBytes(out[len(buf):])
+			return nil, err
+		}
+		out = append(out, b...)
+		zeroBytes(b)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary decodes a private key. If the key already has a suite the
+// encoding must carry its identifier, otherwise the suite is looked up in the
+// registry.
+func (k *PrivateKey) UnmarshalBinary(data []byte) error {
+	if k == nil {
+		return ErrNilKey
+	}
+	suite, body, newer, err := readHeader(k.suite, data)
+	if err != nil {
+		return err
+	}
+	r, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, r+1)
+	if err != nil {
+		return err
+	}
+	var policy *KeyPolicy
+	if data[0] == 1 && data[1] >= policyMinor {
+		if policy, rest, err = readPolicy(rest); err != nil {
+			return err
+		}
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	if err := k.set(suite, scalars); err != nil {
+		return err
+	}
+	return k.SetPolicy(policy)
+}
+
+func (k *PrivateKey) set(suite pairing.Suite, scalars []kyber.Scalar) error {
+	dec, err := NewPrivateKey(suite, scalars)
+	if err != nil {
+		return err
+	}
+	*k = *dec
+	return nil
+}
+
+// MarshalBinary encodes the public key in the current format.
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	return k.marshal(false)
+}
+
+func (k *PublicKey) marshal(compressed bool) ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(k.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(k.Y)); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, k.suite.G2(), compressed, k.Points()...); err != nil {
+		return nil, err
+	}
+	sum, err := k.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, sum...), nil
+}
+
+// canonical returns id || r || X || Y_1 || ... || Y_r with uncompressed
+// points, the input of the key checksum.
+func (k *PublicKey) canonical() ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	id, err := SuiteIDOf(k.suite)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := appendCount([]byte{byte(id)}, len(k.Y))
+	if err != nil {
+		return nil, err
+	}
+	return appendPoints(buf, k.suite.G2(), false, k.Points()...)
+}
+
+// Fingerprint returns the SHA-256 digest identifying the public key, which
+// is also the checksum stored in its encoding: the digest of
+// id || r || X || Y_1 || ... || Y_r with uncompressed points. It depends on
+// the key alone and is stable across releases.
+func (k *PublicKey) Fingerprint() ([]byte, error) {
+	buf, err := k.canonical()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(buf)
+	return sum[:], nil
+}
+
+// keyIDLen is the length of a key ID in bytes.
+const keyIDLen = 8
+
+// KeyID returns the short identifier of the key put in credential headers,
+// revocation lists and signature proofs: the first 8 bytes of its
+// Fingerprint, hex-encoded. Like the fingerprint it is stable across
+// releases.
+func (k *PublicKey) KeyID() (string, error) {
+	sum, err := k.Fingerprint()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum[:keyIDLen]), nil
+}
+
+// SelectKey returns the key of keys whose KeyID is id, such as the issuer
+// key of a SignatureProof.
+func SelectKey(keys []*PublicKey, id string) (*PublicKey, error) {
+	for _, k := range keys {
+		kid, err := k.KeyID()
+		if err != nil {
+			return nil, err
+		}
+		if kid == id {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: key ID %q", ErrKeyNotFound, id)
+}
+
+// UnmarshalBinary decodes a public key, see PrivateKey.UnmarshalBinary.
+func (k *PublicKey) UnmarshalBinary(data []byte) error {
+	return k.unmarshal(data, false)
+}
+
+func (k *PublicKey) unmarshal(data []byte, compressed bool) error {
+	if k == nil {
+		return ErrNilKey
+	}
+	suite, body, newer, err := readHeader(k.suite, data)
+	if err != nil {
+		return err
+	}
+	r, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	points, rest, err := decodePoints(suite.G2(), body, r+1, compressed)
+	if err != nil {
+		return err
+	}
+	dec := new(PublicKey)
+	if err := dec.set(suite, points); err != nil {
+		return err
+	}
+	if data[0] == 1 && data[1] >= checksumMinor {
+		if len(rest) < sha256.Size {
+			return errors.New("ps: truncated public key checksum")
+		}
+		sum, err := dec.Fingerprint()
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(sum, rest[:sha256.Size]) != 1 {
+			return ErrCorruptKey
+		}
+		rest = rest[sha256.Size:]
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	*k = *dec
+	return nil
+}
+
+func (k *PublicKey) set(suite pairing.Suite, points []kyber.Point) error {
+	dec, err := NewPublicKey(suite, points)
+	if err != nil {
+		return err
+	}
+	if err := validatePublicKey(dec, 0, false); err != nil {
+		return err
+	}
+	*k = *dec
+	return nil
+}
+
+// MarshalBinary encodes the signature in the current format.
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	return s.marshal(false)
+}
+
+func (s *Signature) marshal(compressed bool) ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(s.suite)
+	if err != nil {
+		return nil, err
+	}
+	return appendPoints(buf, s.suite.G1(), compressed, s.Sigma1, s.Sigma2)
+}
+
+// UnmarshalBinary decodes a signature, see PrivateKey.UnmarshalBinary.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	return s.unmarshal(data, false)
+}
+
+func (s *Signature) unmarshal(data []byte, compressed bool) error {
+	if s == nil {
+		return ErrNilSignature
+	}
+	suite, body, _, err := readHeader(s.suite, data)
+	if err != nil {
+		return err
+	}
+	points, rest, err := decodePoints(suite.G1(), body, 2, compressed)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d trailing bytes after sigma_2", ErrMalformedSignature, len(rest))
+	}
+	*s = Signature{suite: suite, Sigma1: points[0], Sigma2: points[1]}
+	return nil
+}
+
+// UnmarshalPrivateKey decodes a private key produced under suite.
+func UnmarshalPrivateKey(suite pairing.Suite, data []byte) (*PrivateKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	k := &PrivateKey{suite: suite}
+	if err := k.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UnmarshalPublicKey decodes a public key produced under suite.
+func UnmarshalPublicKey(suite pairing.Suite, data []byte) (*PublicKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	k := &PublicKey{suite: suite}
+	if err := k.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UnmarshalSignature decodes a signature produced under suite.
+func UnmarshalSignature(suite pairing.Suite, data []byte) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	s := &Signature{suite: suite}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalPrivateKeyLegacy decodes a v0 private key, which carries no suite
+// identifier. The caller is responsible for picking the right suite.
+func UnmarshalPrivateKeyLegacy(suite pairing.Suite, data []byte) (*PrivateKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	scalars, _, err := decodeScalars(suite.G1(), data, -1)
+	if err != nil {
+		return nil, err
+	}
+	k := new(PrivateKey)
+	if err := k.set(suite, scalars); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UnmarshalPublicKeyLegacy decodes a v0 public key.
+func UnmarshalPublicKeyLegacy(suite pairing.Suite, data []byte) (*PublicKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	points, _, err := decodePoints(suite.G2(), data, -1, false)
+	if err != nil {
+		return nil, err
+	}
+	k := new(PublicKey)
+	if err := k.set(suite, points); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UnmarshalSignatureLegacy decodes a v0 signature.
+func UnmarshalSignatureLegacy(suite pairing.Suite, data []byte) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	points, rest, err := decodePoints(suite.G1(), data, 2, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTrailing(rest, false); err != nil {
+		return nil, err
+	}
+	return &Signature{suite: suite, Sigma1: points[0], Sigma2: points[1]}, nil
+}
+
+// MigrateKey upgrades a v0 public key, produced under suite, to the current
+// format. Private keys are migrated by loading them with
+// UnmarshalPrivateKeyLegacy and marshalling the result.
+func MigrateKey(suite pairing.Suite, old []byte) ([]byte, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	k, err := UnmarshalPublicKeyLegacy(suite, old)
+	if err != nil {
+		return nil, err
+	}
+	return k.MarshalBinary()
+}
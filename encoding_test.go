@@ -0,0 +1,316 @@
+package ps
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+	"golang.org/x/crypto/hkdf"
+)
+
+// keyDraws counts the key pairs each test has drawn, so that every pair of a
+// test has its own seeds.
+var keyDraws sync.Map
+
+// newTestKeys draws the n-th key pair of t from fixtureStream seeds
+// "<test name> key <n>.<i>", i = 0..attrs, so a failing test reproduces its
+// keys with pstest.ConstantStream.
+func newTestKeys(t testing.TB, suite pairing.Suite, attrs int) (*PrivateKey, *PublicKey) {
+	t.Helper()
+	counter, loaded := keyDraws.LoadOrStore(t.Name(), new(int64))
+	if !loaded {
+		t.Cleanup(func() { keyDraws.Delete(t.Name()) })
+	}
+	n := atomic.AddInt64(counter.(*int64), 1) - 1
+	var randoms []cipher.Stream
+	for i := 0; i <= attrs; i++ {
+		randoms = append(randoms, fixtureStream(fmt.Sprintf("%s key %d.%d", t.Name(), n, i)))
+	}
+	private, public, err := NewKeyPairPoints(suite, randoms)
+	require.Nil(t, err)
+	priv, err := NewPrivateKey(suite, private)
+	require.Nil(t, err)
+	pub, err := NewPublicKey(suite, public)
+	require.Nil(t, err)
+	return priv, pub
+}
+
+// fixtureStream is pstest.ConstantStream, which the package's own tests cannot
+// import: AES-256 in counter mode, with a zero IV, keyed with SHA-256(seed).
+func fixtureStream(seed string) cipher.Stream {
+	key := sha256.Sum256([]byte(seed))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err)
+	}
+	return cipher.NewCTR(block, make([]byte, aes.BlockSize))
+}
+
+func TestFixtureStreamGolden(t *testing.T) {
+	// The key stream of pstest.ConstantStream([]byte("seed")).
+	buf := make([]byte, 16)
+	fixtureStream("seed").XORKeyStream(buf, buf)
+	require.Equal(t, "1024e03ef1672193f39622137b645616", hex.EncodeToString(buf))
+}
+
+// seededStream returns a random stream fixed by seed.
+func seededStream(seed string) cipher.Stream {
+	return &readerStream{r: hkdf.New(sha256.New, []byte(seed), nil, nil)}
+}
+
+func newTestSignature(t *testing.T, suite pairing.Suite, priv *PrivateKey, msg []byte) *Signature {
+	S, err := Sign(suite, priv.Scalars(), msg)
+	require.Nil(t, err)
+	sig, err := NewSignature(suite, S)
+	require.Nil(t, err)
+	return sig
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		sig := newTestSignature(t, suite, priv, msg)
+
+		buf, err := priv.MarshalBinary()
+		require.Nil(t, err)
+		priv2, err := UnmarshalPrivateKey(suite, buf)
+		require.Nil(t, err)
+		require.True(t, priv.X.Equal(priv2.X))
+
+		buf, err = pub.MarshalBinary()
+		require.Nil(t, err)
+		pub2, err := UnmarshalPublicKey(suite, buf)
+		require.Nil(t, err)
+
+		buf, err = sig.MarshalBinary()
+		require.Nil(t, err)
+		sig2, err := UnmarshalSignature(suite, buf)
+		require.Nil(t, err)
+
+		S, err := sig2.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub2.Points(), msg, S))
+	})
+}
+
+func TestEncodingSuiteMismatch(t *testing.T) {
+	bn := pairing.NewSuiteBn256()
+	priv, pub := newTestKeys(t, bn, 1)
+	sig := newTestSignature(t, bn, priv, []byte("Hello PS Signature"))
+
+	bls := bls12381.NewSuite()
+	buf, err := pub.MarshalBinary()
+	require.Nil(t, err)
+	_, err = UnmarshalPublicKey(bls, buf)
+	require.True(t, errors.Is(err, ErrSuiteMismatch))
+	require.Contains(t, err.Error(), "bn256")
+	require.Contains(t, err.Error(), "bls12381")
+
+	buf, err = priv.MarshalBinary()
+	require.Nil(t, err)
+	_, err = UnmarshalPrivateKey(bls, buf)
+	require.True(t, errors.Is(err, ErrSuiteMismatch))
+
+	buf, err = sig.MarshalBinary()
+	require.Nil(t, err)
+	_, err = UnmarshalSignature(bls, buf)
+	require.True(t, errors.Is(err, ErrSuiteMismatch))
+}
+
+func TestEncodingSuiteFromRegistry(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 2)
+		buf, err := pub.MarshalBinary()
+		require.Nil(t, err)
+
+		var dec PublicKey
+		require.Nil(t, dec.UnmarshalBinary(buf))
+		require.Equal(t, suite.G1().String(), dec.Suite().G1().String())
+		require.Equal(t, len(pub.Y), len(dec.Y))
+	})
+}
+
+func TestEncodingLegacy(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		sig := newTestSignature(t, suite, priv, msg)
+
+		var legacy []byte
+		for _, p := range pub.Points() {
+			b, err := p.MarshalBinary()
+			require.Nil(t, err)
+			legacy = append(legacy, b...)
+		}
+		pub2, err := UnmarshalPublicKeyLegacy(suite, legacy)
+		require.Nil(t, err)
+
+		S, err := sig.Components()
+		require.Nil(t, err)
+		sig2, err := UnmarshalSignatureLegacy(suite, append(S[0], S[1]...))
+		require.Nil(t, err)
+
+		S, err = sig2.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub2.Points(), msg, S))
+
+		// A legacy blob is not accepted on the identified path.
+		_, err = UnmarshalPublicKey(suite, legacy)
+		require.NotNil(t, err)
+	})
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.Nil(t, err)
+	buf, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	require.Nil(t, err)
+	return buf
+}
+
+func TestMigrateKeyFixture(t *testing.T) {
+	suite := bls12381.NewSuite()
+	oldPub := readFixture(t, "pubkey_v0_bls12381.hex")
+	oldPriv := readFixture(t, "privkey_v0_bls12381.hex")
+
+	buf, err := MigrateKey(suite, oldPub)
+	require.Nil(t, err)
+	require.Equal(t, byte(FormatMajor), buf[0])
+	require.Equal(t, byte(FormatMinor), buf[1])
+
+	pub, err := UnmarshalPublicKey(suite, buf)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(pub.Y))
+	priv, err := UnmarshalPrivateKeyLegacy(suite, oldPriv)
+	require.Nil(t, err)
+
+	msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+	S, err := BatchSign(suite, priv.Scalars(), msgs)
+	require.Nil(t, err)
+	require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+}
+
+func TestEncodingVersions(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 1)
+		buf, err := pub.MarshalBinary()
+		require.Nil(t, err)
+
+		unknownMajor := append([]byte{}, buf...)
+		unknownMajor[0] = FormatMajor + 1
+		_, err = UnmarshalPublicKey(suite, unknownMajor)
+		require.True(t, errors.Is(err, ErrUnsupportedVersion))
+
+		// Fields appended by a newer minor version are skipped.
+		newerMinor := append([]byte{}, buf...)
+		newerMinor[1] = FormatMinor + 1
+		newerMinor = append(newerMinor, 0xde, 0xad)
+		dec, err := UnmarshalPublicKey(suite, newerMinor)
+		require.Nil(t, err)
+		require.True(t, pub.X.Equal(dec.X))
+
+		// The same trailing bytes are rejected for the known minor version.
+		_, err = UnmarshalPublicKey(suite, append(buf, 0xde, 0xad))
+		require.NotNil(t, err)
+	})
+}
+
+func TestPublicKeyChecksum(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 2)
+		buf, err := pub.MarshalBinary()
+		require.Nil(t, err)
+		fp, err := pub.Fingerprint()
+		require.Nil(t, err)
+		require.Equal(t, fp, buf[len(buf)-len(fp):])
+
+		dec, err := UnmarshalPublicKey(suite, buf)
+		require.Nil(t, err)
+		decFP, err := dec.Fingerprint()
+		require.Nil(t, err)
+		require.Equal(t, fp, decFP)
+
+		// Swap Y_1 and Y_2 as a faulty migration would.
+		size := suite.G2().PointLen()
+		y1 := headerLen + 2 + size
+		swapped := append([]byte{}, buf...)
+		copy(swapped[y1:], buf[y1+size:y1+2*size])
+		copy(swapped[y1+size:], buf[y1:y1+size])
+		_, err = UnmarshalPublicKey(suite, swapped)
+		require.True(t, errors.Is(err, ErrCorruptKey))
+
+		_, err = UnmarshalPublicKey(suite, buf[:len(buf)-1])
+		require.NotNil(t, err)
+
+		// Version 1.0 keys carry no checksum.
+		v10 := append([]byte{}, buf[:len(buf)-len(fp)]...)
+		v10[1] = 0
+		dec, err = UnmarshalPublicKey(suite, v10)
+		require.Nil(t, err)
+		require.True(t, pub.Y[1].Equal(dec.Y[1]))
+	})
+}
+
+// TestFingerprintGolden pins the fingerprint and key ID of a fixed key, which
+// must not change across releases.
+func TestFingerprintGolden(t *testing.T) {
+	suite := bls12381.NewSuite()
+	randoms := []cipher.Stream{seededStream("fingerprint x"), seededStream("fingerprint y1"), seededStream("fingerprint y2")}
+	_, public, err := NewKeyPairPoints(suite, randoms)
+	require.Nil(t, err)
+	pub, err := NewPublicKey(suite, public)
+	require.Nil(t, err)
+	fp, err := pub.Fingerprint()
+	require.Nil(t, err)
+	require.Equal(t, "dd96ab9eefd5e423690736ab79fadb584c452bc944daef8ab25bc1713ee19529", hex.EncodeToString(fp))
+	id, err := pub.KeyID()
+	require.Nil(t, err)
+	require.Equal(t, "dd96ab9eefd5e423", id)
+
+	_, other := newTestKeys(t, suite, 2)
+	found, err := SelectKey([]*PublicKey{other, pub}, id)
+	require.Nil(t, err)
+	require.True(t, found == pub)
+	_, err = SelectKey([]*PublicKey{other}, id)
+	require.True(t, errors.Is(err, ErrKeyNotFound))
+}
+
+func TestSignatureTrailingBytes(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, _ := newTestKeys(t, suite, 1)
+		sig := newTestSignature(t, suite, priv, []byte("Hello PS Signature"))
+		buf, err := sig.MarshalBinary()
+		require.Nil(t, err)
+		extra, err := suite.G1().Point().Pick(random.New()).MarshalBinary()
+		require.Nil(t, err)
+		compressed, err := sig.MarshalCompressed()
+		require.Nil(t, err)
+
+		for name, tail := range map[string][]byte{"point": extra, "bytes": {0xde, 0xad}} {
+			_, err = UnmarshalSignature(suite, append(append([]byte{}, buf...), tail...))
+			require.True(t, errors.Is(err, ErrMalformedSignature), name)
+			_, err = UnmarshalSignatureCompressed(suite, append(append([]byte{}, compressed...), tail...))
+			require.True(t, errors.Is(err, ErrMalformedSignature), name)
+
+			// Unlike keys, signatures gain no fields in newer minor versions.
+			newerMinor := append(append([]byte{}, buf...), tail...)
+			newerMinor[1] = FormatMinor + 1
+			_, err = UnmarshalSignature(suite, newerMinor)
+			require.True(t, errors.Is(err, ErrMalformedSignature), name)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Verifiable encryption hands an auditor a signature that nobody else can
+// recover, with a proof that it is valid. The signature is first randomized
+// to (s_1, s_2) = (sigma_1^r, sigma_2^r), which leaves s_1 uniformly random
+// and independent of the original, so s_1 travels in the clear. s_2 is
+// ElGamal-encrypted to the auditor key P = g^a in G1 as
+//
+//	(C_1, C_2) = (g^k, s_2 * P^k).
+//
+// The PS verification equation e(s_2, g) = e(s_1, X * Y_1^(m_1) * ... *
+// Y_r^(m_r)) then becomes
+//
+//	e(C_2, g) / e(s_1, X * Y_1^(m_1) * ... * Y_r^(m_r)) = e(P, g)^k,
+//
+// and a Schnorr proof of knowledge of k satisfying it together with C_1 =
+// g^k, made non-interactive by Fiat-Shamir, shows that C_2 hides a valid
+// signature on the messages. Its challenge binds the public key, the
+// messages, the auditor key and the ciphertext. The auditor decrypts s_2 =
+// C_2 / C_1^a.
+
+// EncryptedSignature is a signature encrypted to an auditor: the randomized
+// first component Sigma1, the ElGamal ciphertext (C1, C2) of the second and
+// the proof (c, z) of their validity.
+type EncryptedSignature struct {
+	suite     pairing.Suite
+	Sigma1    kyber.Point
+	C1, C2    kyber.Point
+	Challenge kyber.Scalar
+	Response  kyber.Scalar
+}
+
+// check reports whether the encrypted signature is complete.
+func (e *EncryptedSignature) check() error {
+	if e == nil || e.Sigma1 == nil || e.C1 == nil || e.C2 == nil || e.Challenge == nil || e.Response == nil {
+		return ErrNilSignature
+	}
+	if e.suite == nil {
+		return ErrNilSuite
+	}
+	return nil
+}
+
+// encryptionStatement returns e(s_1, X * Y_1^(m_1) * ... * Y_r^(m_r)), the
+// right-hand side of the PS verification equation.
+func encryptionStatement(suite pairing.Suite, pub *PublicKey, m []kyber.Scalar, s1 kyber.Point) kyber.Point {
+	X := pub.X.Clone()
+	for i, mi := range m {
+		X.Add(X, suite.G2().Point().Mul(mi, pub.Y[i]))
+	}
+	return suite.Pair(s1, X)
+}
+
+// encryptionChallenge derives the Fiat-Shamir challenge from the public key,
+// the message scalars, the auditor key, the encrypted signature and the
+// commitments R_1 in G1 and R_2 in GT.
+func encryptionChallenge(suite pairing.Suite, pub *PublicKey, m []kyber.Scalar, auditor kyber.Point, e *EncryptedSignature, R1, R2 kyber.Point) (kyber.Scalar, error) {
+	buf, err := pub.canonical()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(m)); err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalars(buf, suite.G1(), m...); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.G1(), false, auditor, e.Sigma1, e.C1, e.C2, R1); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.GT(), false, R2); err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "VENC"), buf), nil
+}
+
+// checkAuditorKey rejects a missing auditor key or one that cannot hide.
+func checkAuditorKey(suite pairing.Suite, auditor kyber.Point) error {
+	if auditor == nil {
+		return ErrNilKey
+	}
+	if isIdentity(suite.G1(), auditor) {
+		return fmt.Errorf("%w: identity auditor key", ErrInvalidPoint)
+	}
+	return checkSubgroup(suite.G1(), auditor)
+}
+
+// EncryptSignature encrypts sig, a valid signature on msgs under pubKey, to
+// the auditor key auditorPub = g^a in G1, and proves that the ciphertext
+// holds a valid signature on msgs. Anyone can check the proof with
+// VerifyEncryptedSignature; only the auditor can decrypt.
+func EncryptSignature(suite pairing.Suite, auditorPub kyber.Point, sig *Signature, pubKey *PublicKey, msgs [][]byte, opts ...Option) (*EncryptedSignature, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := pubKey.check(); err != nil {
+		return nil, err
+	}
+	if err := checkAuditorKey(suite, auditorPub); err != nil {
+		return nil, err
+	}
+	S, err := sig.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := PSBatchVerify(suite, pubKey.Points(), msgs, S, opts...); err != nil {
+		return nil, err
+	}
+
+	rand := suite.RandomStream()
+	r, err := pickScalar(suite, rand)
+	if err != nil {
+		return nil, err
+	}
+	k, err := pickScalar(suite, rand)
+	if err != nil {
+		return nil, err
+	}
+	w, err := pickScalar(suite, rand)
+	if err != nil {
+		return nil, err
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+
+	g1 := suite.G1()
+	e := &EncryptedSignature{suite: suite, Sigma1: g1.Point().Mul(r, sig.Sigma1), C1: g1.Point().Mul(k, nil)}
+	e.C2 = g1.Point().Mul(r, sig.Sigma2)
+	e.C2.Add(e.C2, g1.Point().Mul(k, auditorPub))
+	// R_1 = g^w, R_2 = e(P, g)^w
+	R1 := g1.Point().Mul(w, nil)
+	R2 := suite.Pair(g1.Point().Mul(w, auditorPub), suite.G2().Point().Base())
+	if e.Challenge, err = encryptionChallenge(suite, pubKey, m, auditorPub, e, R1, R2); err != nil {
+		return nil, err
+	}
+	e.Response = newScalar(suite).Mul(e.Challenge, k)
+	e.Response.Add(e.Response, w)
+	k.Zero()
+	w.Zero()
+	return e, nil
+}
+
+// VerifyEncryptedSignature checks that enc holds a valid signature on msgs
+// under pubKey, encrypted to auditorPub. It needs no secret and returns
+// ErrInvalidSignature if the proof does not verify.
+func VerifyEncryptedSignature(suite pairing.Suite, auditorPub kyber.Point, pubKey *PublicKey, msgs [][]byte, enc *EncryptedSignature, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := pubKey.check(); err != nil {
+		return err
+	}
+	if err := checkAuditorKey(suite, auditorPub); err != nil {
+		return err
+	}
+	if err := enc.check(); err != nil {
+		return err
+	}
+	if err := checkMessageCount(len(pubKey.Y)+1, len(msgs)); err != nil {
+		return err
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return err
+	}
+	g1 := suite.G1()
+	if isIdentity(g1, enc.Sigma1) {
+		return ErrInvalidSignature
+	}
+	for _, p := range []kyber.Point{enc.Sigma1, enc.C1, enc.C2} {
+		if err := checkSubgroup(g1, p); err != nil {
+			return err
+		}
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+
+	// R_1 = g^z / C_1^c, R_2 = e(P^z / C_2^c, g) * e(s_1, X * prod Y_i^(m_i))^c
+	c, z := enc.Challenge, enc.Response
+	R1 := g1.Point().Mul(z, nil)
+	R1.Sub(R1, g1.Point().Mul(c, enc.C1))
+	base := g1.Point().Mul(z, auditorPub)
+	base.Sub(base, g1.Point().Mul(c, enc.C2))
+	R2 := suite.Pair(base, suite.G2().Point().Base())
+	R2.Add(R2, suite.GT().Point().Mul(c, encryptionStatement(suite, pubKey, m, enc.Sigma1)))
+	want, err := encryptionChallenge(suite, pubKey, m, auditorPub, enc, R1, R2)
+	if err != nil {
+		return err
+	}
+	if !want.Equal(c) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// DecryptSignature recovers the signature in enc with the auditor's secret
+// key a. The result is valid if enc passed VerifyEncryptedSignature.
+func DecryptSignature(suite pairing.Suite, auditorKey kyber.Scalar, enc *EncryptedSignature) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if auditorKey == nil {
+		return nil, ErrNilKey
+	}
+	if err := enc.check(); err != nil {
+		return nil, err
+	}
+	s2 := suite.G1().Point().Mul(auditorKey, enc.C1)
+	s2.Sub(enc.C2, s2)
+	return &Signature{suite: suite, Sigma1: enc.Sigma1.Clone(), Sigma2: s2}, nil
+}
+
+// MarshalBinary encodes the encrypted signature as header || s_1 || C_1 ||
+// C_2 || c || z.
+func (e *EncryptedSignature) MarshalBinary() ([]byte, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(e.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, e.suite.G1(), false, e.Sigma1, e.C1, e.C2); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, e.suite.G1(), e.Challenge, e.Response)
+}
+
+// UnmarshalBinary decodes an encrypted signature, see
+// PrivateKey.UnmarshalBinary.
+func (e *EncryptedSignature) UnmarshalBinary(data []byte) error {
+	if e == nil {
+		return ErrNilSignature
+	}
+	suite, body, newer, err := readHeader(e.suite, data)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 3, false)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, 2)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	*e = EncryptedSignature{suite: suite, Sigma1: points[0], C1: points[1], C2: points[2], Challenge: scalars[0], Response: scalars[1]}
+	return nil
+}
+
+// UnmarshalEncryptedSignature decodes an encrypted signature produced under
+// suite.
+func UnmarshalEncryptedSignature(suite pairing.Suite, data []byte) (*EncryptedSignature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	e := &EncryptedSignature{suite: suite}
+	if err := e.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
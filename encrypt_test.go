@@ -0,0 +1,92 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEncryptSignature(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		auditorKey := newScalar(suite).Pick(random.New())
+		auditorPub := suite.G1().Point().Mul(auditorKey, nil)
+
+		enc, err := EncryptSignature(suite, auditorPub, sig, pub, msgs)
+		require.Nil(t, err)
+		buf, err := enc.MarshalBinary()
+		require.Nil(t, err)
+		enc, err = UnmarshalEncryptedSignature(suite, buf)
+		require.Nil(t, err)
+
+		// Anyone checks the proof without the auditor key.
+		require.Nil(t, VerifyEncryptedSignature(suite, auditorPub, pub, msgs, enc))
+		require.False(t, enc.Sigma1.Equal(sig.Sigma1))
+
+		// The auditor recovers a valid signature on the messages.
+		dec, err := DecryptSignature(suite, auditorKey, enc)
+		require.Nil(t, err)
+		D, err := dec.Components()
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, D))
+		ok, err := dec.IsEquivalent(suite, sig, pub.Points(), msgs)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		// Another key decrypts to garbage.
+		wrong, err := DecryptSignature(suite, newScalar(suite).Pick(random.New()), enc)
+		require.Nil(t, err)
+		W, err := wrong.Components()
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, W))
+	})
+}
+
+func TestEncryptSignatureInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		_, otherPub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		auditorPub := suite.G1().Point().Pick(random.New())
+		otherAuditor := suite.G1().Point().Pick(random.New())
+
+		enc, err := EncryptSignature(suite, auditorPub, sig, pub, msgs)
+		require.Nil(t, err)
+
+		// The proof is bound to the messages, the signer and the auditor.
+		other := [][]byte{[]byte("name=Alice"), []byte("age=18")}
+		require.Equal(t, ErrInvalidSignature, VerifyEncryptedSignature(suite, auditorPub, pub, other, enc))
+		require.Equal(t, ErrInvalidSignature, VerifyEncryptedSignature(suite, auditorPub, otherPub, msgs, enc))
+		require.Equal(t, ErrInvalidSignature, VerifyEncryptedSignature(suite, otherAuditor, pub, msgs, enc))
+
+		// A ciphertext of anything else than the signature is caught.
+		forged := *enc
+		forged.C2 = suite.G1().Point().Add(enc.C2, suite.G1().Point().Pick(random.New()))
+		require.Equal(t, ErrInvalidSignature, VerifyEncryptedSignature(suite, auditorPub, pub, msgs, &forged))
+		forged = *enc
+		forged.Sigma1 = suite.G1().Point().Pick(random.New())
+		require.Equal(t, ErrInvalidSignature, VerifyEncryptedSignature(suite, auditorPub, pub, msgs, &forged))
+
+		// Invalid signatures and auditor keys are refused.
+		_, err = EncryptSignature(suite, auditorPub, sig, pub, other)
+		require.Equal(t, ErrInvalidSignature, err)
+		_, err = EncryptSignature(suite, suite.G1().Point().Null(), sig, pub, msgs)
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+		_, err = EncryptSignature(suite, nil, sig, pub, msgs)
+		require.Equal(t, ErrNilKey, err)
+		_, err = DecryptSignature(suite, nil, enc)
+		require.Equal(t, ErrNilKey, err)
+	})
+}
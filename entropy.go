@@ -0,0 +1,79 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Kyber's Pick methods read from a cipher.Stream and have no error result, so
+// a failing source panics inside them. All randomness is therefore consumed
+// through guardedStream, which turns a failure of the underlying stream into
+// an error reported once the pick is done. The picked value is discarded in
+// that case.
+
+// ErrEntropyFailure is returned when a randomness source, including the one
+// returned by a suite's RandomStream, is missing or fails while being read.
+var ErrEntropyFailure = errors.New("ps: randomness source failed")
+
+// guardedStream records the first failure of the wrapped stream. After it,
+// the stream outputs a fixed non-zero filler instead, so that kyber's
+// rejection sampling terminates.
+type guardedStream struct {
+	s   cipher.Stream
+	err error
+}
+
+func (g *guardedStream) XORKeyStream(dst, src []byte) {
+	if g.err == nil {
+		if g.err = g.read(dst, src); g.err == nil {
+			return
+		}
+	}
+	n := len(dst)
+	if len(src) < n {
+		n = len(src)
+	}
+	for i := 0; i < n; i++ {
+		dst[i] = 0x01
+	}
+}
+
+func (g *guardedStream) read(dst, src []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrEntropyFailure, r)
+		}
+	}()
+	g.s.XORKeyStream(dst, src)
+	return nil
+}
+
+// pickScalar picks a random scalar of suite from rand.
+func pickScalar(suite pairing.Suite, rand cipher.Stream) (kyber.Scalar, error) {
+	if rand == nil {
+		return nil, fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	guard := &guardedStream{s: rand}
+	s := newScalar(suite).Pick(guard)
+	if guard.err != nil {
+		return nil, guard.err
+	}
+	return s, nil
+}
+
+// pickPoint picks a random point of g from rand.
+func pickPoint(g kyber.Group, rand cipher.Stream) (kyber.Point, error) {
+	if rand == nil {
+		return nil, fmt.Errorf("%w: nil random stream", ErrEntropyFailure)
+	}
+	guard := &guardedStream{s: rand}
+	p := g.Point().Pick(guard)
+	if guard.err != nil {
+		return nil, guard.err
+	}
+	return p, nil
+}
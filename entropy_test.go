@@ -0,0 +1,62 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// panicStream is a randomness source in a failed state.
+type panicStream struct{}
+
+func (panicStream) XORKeyStream(dst, src []byte) {
+	panic("drbg: reseed required")
+}
+
+// brokenSuite replaces the random stream of a suite.
+type brokenSuite struct {
+	pairing.Suite
+	stream cipher.Stream
+}
+
+func (s brokenSuite) RandomStream() cipher.Stream {
+	return s.stream
+}
+
+func TestEntropyFailure(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 2)
+		S := newTestSignature(t, suite, priv, msg)
+		sig, err := S.Components()
+		require.Nil(t, err)
+
+		for name, stream := range map[string]cipher.Stream{"panic": panicStream{}, "nil": nil} {
+			broken := brokenSuite{Suite: suite, stream: stream}
+			require.NotPanics(t, func() {
+				_, err := Sign(broken, priv.Scalars(), msg)
+				require.True(t, errors.Is(err, ErrEntropyFailure), name)
+				_, err = BatchSign(broken, priv.Scalars(), [][]byte{msg, msg})
+				require.True(t, errors.Is(err, ErrEntropyFailure), name)
+				_, err = AggreSign(broken, priv.Scalars(), [][]byte{msg})
+				require.True(t, errors.Is(err, ErrEntropyFailure), name)
+				_, err = AggregatePSSign(broken, priv.Y[1], sig, msg)
+				require.True(t, errors.Is(err, ErrEntropyFailure), name)
+			}, name)
+		}
+
+		require.NotPanics(t, func() {
+			_, _, err := NewKeyPair(suite, []cipher.Stream{random.New(), panicStream{}})
+			require.True(t, errors.Is(err, ErrEntropyFailure))
+			require.Contains(t, err.Error(), "random stream 1")
+		})
+
+		// Verification consumes no randomness.
+		broken := brokenSuite{Suite: suite, stream: panicStream{}}
+		require.Nil(t, Verify(broken, pub.Points(), msg, sig))
+	})
+}
@@ -0,0 +1,269 @@
+package ps
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// An Envelope keeps the context of a signature with it: the KeyID of the
+// issuer key, the suite, the number of messages signed and, optionally, the
+// time of issuance. Its binary form is
+//
+//	major || minor || id || n || kid || k || t || [issued] || sigma_1 || sigma_2
+//
+// with the n-byte key ID kid, the attribute count k, and t = 1 followed by
+// the issuance time in Unix seconds as a big-endian int64, or t = 0 without
+// one. Its JSON form is an object with the members "kid", "suite" (the suite
+// name), "attributes", "issued_at" (RFC 3339, omitted without a time) and
+// "signature" (the hex form of the signature).
+//
+// Only the messages are signed. The metadata tells the verifier which key
+// and how many messages to expect, and a wrong key ID or count only makes
+// Open fail; in particular IssuedAt is informational and can be altered
+// freely. Issuers that need the time bound to the signature sign it as a
+// message, such as a NotAfterAttribute or EpochAttribute among msgs.
+
+// ErrInvalidEnvelope is returned for envelopes that are malformed or do not
+// match the messages they are opened with.
+var ErrInvalidEnvelope = errors.New("ps: invalid signature envelope")
+
+// Envelope is a signature with its metadata.
+type Envelope struct {
+	KeyID          string
+	SuiteID        SuiteID
+	AttributeCount int
+	// IssuedAt is the time of issuance to the second, or the zero time.
+	IssuedAt  time.Time
+	Signature *Signature
+}
+
+// Seal signs msgs with priv, as PrivateKey.BatchSign does, and returns the
+// signature in an envelope issued at now. A zero now leaves the time out.
+func Seal(priv *PrivateKey, msgs [][]byte, now time.Time, opts ...Option) (*Envelope, error) {
+	if err := priv.check(); err != nil {
+		return nil, err
+	}
+	id, err := SuiteIDOf(priv.suite)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := priv.Public().KeyID()
+	if err != nil {
+		return nil, err
+	}
+	S, err := priv.BatchSign(msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := NewSignature(priv.suite, S)
+	if err != nil {
+		return nil, err
+	}
+	e := &Envelope{KeyID: kid, SuiteID: id, AttributeCount: len(msgs), Signature: sig}
+	if !now.IsZero() {
+		e.IssuedAt = now.UTC().Truncate(time.Second)
+	}
+	return e, nil
+}
+
+// Open verifies the signature in the envelope on msgs under the key of keys
+// it names, which it returns. A key ID matching none of keys gives
+// ErrKeyNotFound, and a number of messages other than AttributeCount
+// ErrInvalidEnvelope.
+func (e *Envelope) Open(keys []*PublicKey, msgs [][]byte, opts ...Option) (*PublicKey, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	if len(msgs) != e.AttributeCount {
+		return nil, fmt.Errorf("%w: %d messages, envelope holds %d", ErrInvalidEnvelope, len(msgs), e.AttributeCount)
+	}
+	pub, err := SelectKey(keys, e.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	S, err := e.Signature.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := PSBatchVerify(e.Signature.suite, pub.Points(), msgs, S, opts...); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// OpenWith is Open with the keys of ks, verifying with the cached Verifier
+// of the key named and the options of ks.
+func (e *Envelope) OpenWith(ks *KeySet, msgs [][]byte) (*PublicKey, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	if len(msgs) != e.AttributeCount {
+		return nil, fmt.Errorf("%w: %d messages, envelope holds %d", ErrInvalidEnvelope, len(msgs), e.AttributeCount)
+	}
+	k, err := ks.lookup(e.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if k.pub.suite.G1().String() != e.Signature.suite.G1().String() {
+		return nil, ErrSuiteMismatch
+	}
+	S, err := e.Signature.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := k.verifier.BatchVerify(msgs, S); err != nil {
+		return nil, err
+	}
+	return k.pub, nil
+}
+
+// check reports whether the envelope is complete and consistent with its
+// signature.
+func (e *Envelope) check() error {
+	if e == nil {
+		return ErrInvalidEnvelope
+	}
+	if err := e.Signature.check(); err != nil {
+		return err
+	}
+	id, err := SuiteIDOf(e.Signature.suite)
+	if err != nil {
+		return err
+	}
+	if id != e.SuiteID {
+		return fmt.Errorf("%w: envelope of suite %s holds a %s signature", ErrSuiteMismatch, SuiteName(e.SuiteID), SuiteName(id))
+	}
+	if e.AttributeCount < 1 || e.AttributeCount > 0xffff {
+		return fmt.Errorf("%w: %d attributes", ErrInvalidEnvelope, e.AttributeCount)
+	}
+	if _, err := hex.DecodeString(e.KeyID); err != nil || len(e.KeyID) != 2*keyIDLen {
+		return fmt.Errorf("%w: key ID %q", ErrInvalidEnvelope, e.KeyID)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the envelope in its canonical binary form.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(e.Signature.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendKeyID(buf, e.KeyID); err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, e.AttributeCount); err != nil {
+		return nil, err
+	}
+	if e.IssuedAt.IsZero() {
+		buf = append(buf, 0)
+	} else {
+		var t [8]byte
+		binary.BigEndian.PutUint64(t[:], uint64(e.IssuedAt.Unix()))
+		buf = append(append(buf, 1), t[:]...)
+	}
+	return appendPoints(buf, e.Signature.suite.G1(), false, e.Signature.Sigma1, e.Signature.Sigma2)
+}
+
+// UnmarshalBinary decodes an envelope, selecting its suite through the
+// registry.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if e == nil {
+		return ErrInvalidEnvelope
+	}
+	suite, body, newer, err := readHeader(nil, data)
+	if err != nil {
+		return err
+	}
+	dec := &Envelope{SuiteID: SuiteID(data[2])}
+	if dec.KeyID, body, err = readKeyID(body); err != nil {
+		return err
+	}
+	if dec.AttributeCount, body, err = readCount(body); err != nil {
+		return err
+	}
+	if len(body) < 1 {
+		return fmt.Errorf("%w: truncated", ErrInvalidEnvelope)
+	}
+	switch body[0] {
+	case 0:
+		body = body[1:]
+	case 1:
+		if len(body) < 9 {
+			return fmt.Errorf("%w: truncated issuance time", ErrInvalidEnvelope)
+		}
+		dec.IssuedAt = time.Unix(int64(binary.BigEndian.Uint64(body[1:])), 0).UTC()
+		body = body[9:]
+	default:
+		return fmt.Errorf("%w: unknown time tag %d", ErrInvalidEnvelope, body[0])
+	}
+	points, rest, err := decodePoints(suite.G1(), body, 2, false)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	dec.Signature = &Signature{suite: suite, Sigma1: points[0], Sigma2: points[1]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*e = *dec
+	return nil
+}
+
+// envelopeJSON is the JSON form of an Envelope.
+type envelopeJSON struct {
+	KeyID      string     `json:"kid"`
+	Suite      string     `json:"suite"`
+	Attributes int        `json:"attributes"`
+	IssuedAt   *time.Time `json:"issued_at,omitempty"`
+	Signature  *Signature `json:"signature"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Envelope) MarshalJSON() ([]byte, error) {
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	v := envelopeJSON{KeyID: e.KeyID, Suite: SuiteName(e.SuiteID), Attributes: e.AttributeCount, Signature: e.Signature}
+	if !e.IssuedAt.IsZero() {
+		t := e.IssuedAt.UTC().Truncate(time.Second)
+		v.IssuedAt = &t
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	if e == nil {
+		return ErrInvalidEnvelope
+	}
+	var v envelopeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	suite, err := SuiteByName(v.Suite)
+	if err != nil {
+		return err
+	}
+	id, err := SuiteIDOf(suite)
+	if err != nil {
+		return err
+	}
+	dec := &Envelope{KeyID: v.KeyID, SuiteID: id, AttributeCount: v.Attributes, Signature: v.Signature}
+	if v.IssuedAt != nil {
+		dec.IssuedAt = v.IssuedAt.UTC().Truncate(time.Second)
+	}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*e = *dec
+	return nil
+}
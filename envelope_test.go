@@ -0,0 +1,110 @@
+package ps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestEnvelope(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 3)
+		_, other := newTestKeys(t, suite, 3)
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		now := time.Date(2026, 10, 16, 9, 30, 15, 500, time.FixedZone("CEST", 2*3600))
+		e, err := Seal(priv, msgs, now)
+		require.Nil(t, err)
+		id, err := pub.KeyID()
+		require.Nil(t, err)
+		require.Equal(t, id, e.KeyID)
+		require.Equal(t, 2, e.AttributeCount)
+		require.Equal(t, time.Date(2026, 10, 16, 7, 30, 15, 0, time.UTC), e.IssuedAt)
+
+		key, err := e.Open([]*PublicKey{other, pub}, msgs)
+		require.Nil(t, err)
+		require.True(t, key == pub)
+
+		_, err = e.Open([]*PublicKey{other}, msgs)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		_, err = e.Open([]*PublicKey{pub}, msgs[:1])
+		require.True(t, errors.Is(err, ErrInvalidEnvelope))
+		_, err = e.Open([]*PublicKey{pub}, [][]byte{msgs[1], msgs[0]})
+		require.Equal(t, ErrInvalidSignature, err)
+
+		// The issuance time is metadata: changing it does not affect the
+		// signature.
+		moved := *e
+		moved.IssuedAt = moved.IssuedAt.Add(-24 * time.Hour)
+		_, err = moved.Open([]*PublicKey{pub}, msgs)
+		require.Nil(t, err)
+	})
+}
+
+func TestEnvelopeEncoding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		msgs := [][]byte{[]byte("alice")}
+		for _, now := range []time.Time{{}, time.Date(2026, 10, 16, 7, 30, 15, 0, time.UTC)} {
+			e, err := Seal(priv, msgs, now)
+			require.Nil(t, err)
+
+			buf, err := e.MarshalBinary()
+			require.Nil(t, err)
+			var dec Envelope
+			require.Nil(t, dec.UnmarshalBinary(buf))
+			require.Equal(t, e.KeyID, dec.KeyID)
+			require.Equal(t, e.SuiteID, dec.SuiteID)
+			require.Equal(t, e.IssuedAt, dec.IssuedAt)
+			_, err = dec.Open([]*PublicKey{pub}, msgs)
+			require.Nil(t, err)
+			again, err := dec.MarshalBinary()
+			require.Nil(t, err)
+			require.Equal(t, buf, again)
+			_, err = (&Envelope{}).Open(nil, msgs)
+			require.NotNil(t, err)
+			require.NotNil(t, dec.UnmarshalBinary(buf[:len(buf)-1]))
+			require.NotNil(t, dec.UnmarshalBinary(append(buf, 0)))
+
+			text, err := json.Marshal(e)
+			require.Nil(t, err)
+			var fromJSON Envelope
+			require.Nil(t, json.Unmarshal(text, &fromJSON))
+			require.Equal(t, e.IssuedAt, fromJSON.IssuedAt)
+			_, err = fromJSON.Open([]*PublicKey{pub}, msgs)
+			require.Nil(t, err)
+			again, err = json.Marshal(&fromJSON)
+			require.Nil(t, err)
+			require.Equal(t, text, again)
+			require.Equal(t, !now.IsZero(), containsKey(t, text, "issued_at"))
+		}
+	})
+}
+
+func TestEnvelopeWithExpiry(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		now := time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC)
+		notAfter, err := NotAfterAttribute(now.Add(24*time.Hour), GranularityDay)
+		require.Nil(t, err)
+		msgs := [][]byte{notAfter, []byte("alice")}
+
+		// Binding the time means signing it as an attribute.
+		e, err := Seal(priv, msgs, now)
+		require.Nil(t, err)
+		_, err = e.Open([]*PublicKey{pub}, msgs)
+		require.Nil(t, err)
+		require.Nil(t, CheckNotAfter(msgs[0], now))
+		require.True(t, errors.Is(CheckNotAfter(msgs[0], now.Add(72*time.Hour)), ErrExpired))
+	})
+}
+
+func containsKey(t *testing.T, text []byte, key string) bool {
+	var m map[string]interface{}
+	require.Nil(t, json.Unmarshal(text, &m))
+	_, ok := m[key]
+	return ok
+}
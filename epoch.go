@@ -0,0 +1,86 @@
+package ps
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Epoch-tagged signatures bind a validity epoch as an implicit first
+// message, signed with y_1, ahead of the caller's messages, which move up one
+// slot. The epoch is encoded as the 16-byte message
+//
+//	"PS-EPOCH" || epoch
+//
+// with the epoch as a big-endian uint64, and hashed like any other message.
+// A signature made for epoch N does not verify for epoch N+1, so verifiers
+// reject stale credentials by checking against the current epoch, without a
+// revocation list. In signature proofs the epoch is index 0 and is revealed
+// by mapping it to EpochAttribute(epoch).
+
+// epochTag prefixes the epoch in its attribute encoding.
+const epochTag = "PS-EPOCH"
+
+// ErrInvalidEpochPeriod is returned for epoch periods that are not positive.
+var ErrInvalidEpochPeriod = errors.New("ps: invalid epoch period")
+
+// EpochAttribute returns the message that encodes epoch in the reserved
+// first slot.
+func EpochAttribute(epoch uint64) []byte {
+	buf := make([]byte, len(epochTag)+8)
+	copy(buf, epochTag)
+	binary.BigEndian.PutUint64(buf[len(epochTag):], epoch)
+	return buf
+}
+
+// EpochAt returns the number of whole periods elapsed between the Unix epoch
+// and t. Times before 1970 fall in epoch 0.
+func EpochAt(t time.Time, period time.Duration) (uint64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidEpochPeriod, period)
+	}
+	since := t.Sub(time.Unix(0, 0))
+	if since < 0 {
+		return 0, nil
+	}
+	return uint64(since / period), nil
+}
+
+// CurrentEpochAttribute returns the epoch attribute for the current time and
+// period, see EpochAt.
+func CurrentEpochAttribute(period time.Duration) ([]byte, error) {
+	epoch, err := EpochAt(time.Now(), period)
+	if err != nil {
+		return nil, err
+	}
+	return EpochAttribute(epoch), nil
+}
+
+// withEpoch prepends the epoch attribute to msgs.
+func withEpoch(epoch uint64, msgs [][]byte) [][]byte {
+	return append([][]byte{EpochAttribute(epoch)}, msgs...)
+}
+
+// SignWithEpoch signs msgs for epoch, as BatchSign does on the epoch
+// attribute followed by msgs. The key needs one Y component more than there
+// are messages.
+func SignWithEpoch(suite pairing.Suite, priKey []kyber.Scalar, epoch uint64, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	return BatchSign(suite, priKey, withEpoch(epoch, msgs), opts...)
+}
+
+// VerifyWithEpoch checks a signature made by SignWithEpoch on msgs for
+// epoch. It returns ErrInvalidSignature for a signature made for any other
+// epoch.
+func VerifyWithEpoch(suite pairing.Suite, pubKey []kyber.Point, epoch uint64, msgs [][]byte, S [][]byte, opts ...Option) error {
+	if len(msgs) == 0 {
+		return ErrNoMessages
+	}
+	return PSBatchVerify(suite, pubKey, withEpoch(epoch, msgs), S, opts...)
+}
@@ -0,0 +1,59 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestSignWithEpoch(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31")}
+		priv, pub := newTestKeys(t, suite, len(msgs)+1)
+		S, err := SignWithEpoch(suite, priv.Scalars(), 41, msgs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyWithEpoch(suite, pub.Points(), 41, msgs, S))
+		require.Equal(t, ErrInvalidSignature, VerifyWithEpoch(suite, pub.Points(), 42, msgs, S))
+		require.Equal(t, ErrInvalidSignature, VerifyWithEpoch(suite, pub.Points(), 40, msgs, S))
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), append([][]byte{EpochAttribute(41)}, msgs...), S))
+
+		// The key needs a slot for the epoch.
+		_, err = SignWithEpoch(suite, priv.Scalars(), 41, append(msgs, msgs[0]))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = SignWithEpoch(suite, priv.Scalars(), 41, nil)
+		require.Equal(t, ErrNoMessages, err)
+
+		// The epoch is revealed in a signature proof at index 0.
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+		all := append([][]byte{EpochAttribute(41)}, msgs...)
+		proof, err := ProveSignature(suite, pub, sig, all, map[int]bool{0: true}, nonce)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartial(suite, pub, map[int][]byte{0: EpochAttribute(41)}, proof, nonce))
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, map[int][]byte{0: EpochAttribute(42)}, proof, nonce))
+	})
+}
+
+func TestEpochAt(t *testing.T) {
+	day := 24 * time.Hour
+	epoch, err := EpochAt(time.Date(1970, 1, 3, 12, 0, 0, 0, time.UTC), day)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), epoch)
+	epoch, err = EpochAt(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), day)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0), epoch)
+	_, err = EpochAt(time.Now(), 0)
+	require.True(t, errors.Is(err, ErrInvalidEpochPeriod))
+
+	require.Equal(t, []byte("PS-EPOCH\x00\x00\x00\x00\x00\x00\x01\x02"), EpochAttribute(0x102))
+	attr, err := CurrentEpochAttribute(day)
+	require.Nil(t, err)
+	now, err := EpochAt(time.Now(), day)
+	require.Nil(t, err)
+	// The test may straddle midnight.
+	require.Contains(t, [][]byte{EpochAttribute(now - 1), EpochAttribute(now)}, attr)
+}
@@ -0,0 +1,64 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// ExtendSignature appends newMsg to S, a signature by priKey on
+// existingMsgs, so that the result verifies with PSBatchVerify on
+// existingMsgs followed by newMsg. Only the original signer can extend: the
+// input signature is first checked with the private key, as
+// sigma_2 = sigma_1^(x + y_1*m_1 + ... + y_k*m_k), and the new message is
+// signed with y_(k+1). Like AggregatePSSign, the result is re-randomized:
+//
+//	(sigma_1^t, (sigma_2 * sigma_1^(y_(k+1) * m'))^t).
+//
+// It returns ErrKeyLengthMismatch if priKey has no y_(k+1).
+func ExtendSignature(suite pairing.Suite, priKey []kyber.Scalar, S [][]byte, existingMsgs [][]byte, newMsg []byte, opts ...Option) ([][]byte, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessageCount(len(priKey), len(existingMsgs)+1); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	if err := checkMessages(existingMsgs...); err != nil {
+		return nil, err
+	}
+	if err := checkMessages(newMsg); err != nil {
+		return nil, err
+	}
+	if S == nil {
+		return nil, ErrNilSignature
+	}
+	sig, err := parseSignature(suite, S, o.validatePoints)
+	if err != nil {
+		return nil, err
+	}
+	g1 := suite.G1()
+	s1, s2 := sig.Sigma1, sig.Sigma2
+	if isIdentity(g1, s1) || isIdentity(g1, s2) {
+		return nil, ErrInvalidSignature
+	}
+	e := priKey[0].Clone()
+	for i, msg := range existingMsgs {
+		e.Add(e, newScalar(suite).Mul(priKey[i+1], hashToScalar(suite, o.dst, msg)))
+	}
+	if !g1.Point().Mul(e, s1).Equal(s2) {
+		return nil, fmt.Errorf("%w: not a signature on the existing messages", ErrInvalidSignature)
+	}
+	t, err := pickScalar(suite, suite.RandomStream())
+	if err != nil {
+		return nil, err
+	}
+	y := newScalar(suite).Mul(priKey[len(existingMsgs)+1], hashToScalar(suite, o.dst, newMsg))
+	s2 = g1.Point().Add(s2, g1.Point().Mul(y, s1))
+	extended := &Signature{suite: suite, Sigma1: g1.Point().Mul(t, s1), Sigma2: s2.Mul(t, s2)}
+	return extended.Components()
+}
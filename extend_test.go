@@ -0,0 +1,37 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestExtendSignature(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31"), []byte("renewed=2026")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs[:2])
+		require.Nil(t, err)
+
+		extended, err := ExtendSignature(suite, priv.Scalars(), S, msgs[:2], msgs[2])
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, extended))
+		require.NotNil(t, PSBatchVerify(suite, pub.Points(), msgs[:2], extended))
+		require.NotEqual(t, S[0], extended[0])
+
+		// The key has no slot for a fourth message.
+		_, err = ExtendSignature(suite, priv.Scalars(), extended, msgs, []byte("extra"))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+
+		// The input must be a signature by the same key on the same messages.
+		_, err = ExtendSignature(suite, priv.Scalars(), S, [][]byte{msgs[1], msgs[0]}, msgs[2])
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+		other, _ := newTestKeys(t, suite, len(msgs))
+		_, err = ExtendSignature(suite, other.Scalars(), S, msgs[:2], msgs[2])
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+		_, err = ExtendSignature(suite, priv.Scalars(), S, msgs[:2], nil)
+		require.Equal(t, ErrEmptyMessage, err)
+	})
+}
@@ -0,0 +1,61 @@
+package ps
+
+import (
+	"go.dedis.ch/kyber/v3"
+)
+
+// Fixed-base multiplication trades memory for speed when the same point is
+// multiplied by many scalars, as the Y_i of a public key are by message
+// scalars. The table of a point P holds d * 16^j * P for every 4-bit digit
+// d = 1..15 and every digit position j of a scalar, so that
+//
+//	s * P = sum_j table[j][s_j - 1]
+//
+// for the base-16 digits s_j of s costs one addition per non-zero digit and
+// no doubling.
+
+const fixedBaseWindow = 4
+
+// fixedBaseTable is the precomputed table of a point. It is read-only after
+// construction and safe for concurrent use.
+type fixedBaseTable struct {
+	g       kyber.Group
+	windows [][]kyber.Point
+}
+
+// newFixedBaseTable precomputes the table of p in g.
+func newFixedBaseTable(g kyber.Group, p kyber.Point) (*fixedBaseTable, error) {
+	t := &fixedBaseTable{g: g}
+	digits := 8 / fixedBaseWindow * g.ScalarLen()
+	t.windows = make([][]kyber.Point, digits)
+	base := p.Clone()
+	for j := range t.windows {
+		row := make([]kyber.Point, 1<<fixedBaseWindow-1)
+		row[0] = base.Clone()
+		for d := 1; d < len(row); d++ {
+			row[d] = g.Point().Add(row[d-1], base)
+		}
+		t.windows[j] = row
+		base = g.Point().Add(row[len(row)-1], base)
+	}
+	return t, nil
+}
+
+// mul returns s * P.
+func (t *fixedBaseTable) mul(s kyber.Scalar) (kyber.Point, error) {
+	le, err := littleEndianScalar(t.g, s)
+	if err != nil {
+		return nil, err
+	}
+	r := t.g.Point().Null()
+	// Byte i holds digits 2i and 2i+1.
+	for i, b := range le {
+		if lo := b & 0x0f; lo != 0 {
+			r.Add(r, t.windows[2*i][lo-1])
+		}
+		if hi := b >> 4; hi != 0 {
+			r.Add(r, t.windows[2*i+1][hi-1])
+		}
+	}
+	return r, nil
+}
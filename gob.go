@@ -0,0 +1,31 @@
+package ps
+
+import "encoding/gob"
+
+// Keys and signatures travel through encoding/gob in their canonical binary
+// format, so decoding a gob stream validates every point just like
+// UnmarshalBinary does.
+
+func init() {
+	gob.Register(&PrivateKey{})
+	gob.Register(&PublicKey{})
+	gob.Register(&Signature{})
+}
+
+// GobEncode implements gob.GobEncoder.
+func (k *PrivateKey) GobEncode() ([]byte, error) { return k.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder.
+func (k *PrivateKey) GobDecode(data []byte) error { return k.UnmarshalBinary(data) }
+
+// GobEncode implements gob.GobEncoder.
+func (k *PublicKey) GobEncode() ([]byte, error) { return k.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder.
+func (k *PublicKey) GobDecode(data []byte) error { return k.UnmarshalBinary(data) }
+
+// GobEncode implements gob.GobEncoder.
+func (s *Signature) GobEncode() ([]byte, error) { return s.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder.
+func (s *Signature) GobDecode(data []byte) error { return s.UnmarshalBinary(data) }
@@ -0,0 +1,59 @@
+package ps
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+type checkpoint struct {
+	Private   *PrivateKey
+	Public    *PublicKey
+	Signature *Signature
+	Message   []byte
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 2)
+		sig := newTestSignature(t, suite, priv, msg)
+
+		var buf bytes.Buffer
+		require.Nil(t, gob.NewEncoder(&buf).Encode(checkpoint{priv, pub, sig, msg}))
+
+		var dec checkpoint
+		require.Nil(t, gob.NewDecoder(&buf).Decode(&dec))
+		S, err := dec.Signature.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, dec.Public.Points(), dec.Message, S))
+
+		S, err = Sign(suite, dec.Private.Scalars(), []byte("restored"))
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, dec.Public.Points(), []byte("restored"), S))
+	})
+}
+
+func TestGobCorrupted(t *testing.T) {
+	suite := bls12381.NewSuite()
+	priv, pub := newTestKeys(t, suite, 1)
+	sig := newTestSignature(t, suite, priv, []byte("checkpoint"))
+
+	var buf bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&buf).Encode(checkpoint{priv, pub, sig, nil}))
+
+	// Overwrite X with bytes that are not a valid compressed point.
+	x, err := pub.X.MarshalBinary()
+	require.Nil(t, err)
+	data := buf.Bytes()
+	i := bytes.Index(data, x)
+	require.True(t, i >= 0)
+	copy(data[i:], bytes.Repeat([]byte{0xff}, len(x)))
+
+	var dec checkpoint
+	require.NotNil(t, gob.NewDecoder(bytes.NewReader(data)).Decode(&dec))
+}
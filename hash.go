@@ -0,0 +1,172 @@
+package ps
+
+import (
+	"context"
+	"crypto"
+	"crypto/cipher"
+	_ "crypto/sha256" // registers crypto.SHA256 for the expander
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudflare/circl/expander"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Messages are mapped to scalars with the hash_to_field construction of RFC
+// 9380: expand_message_xmd with SHA-256 stretches the message to
+// hashToScalarLen bytes under a domain separation tag, and the result is
+// reduced modulo the group order. The 128 extra bits over the 255-bit orders
+// of the supported suites make the scalar statistically close to uniform.
+//
+// The tag binds signatures to the protocol they were issued for: a signature
+// made under one tag does not verify under another. Unless WithDST is given,
+// the tag is DefaultDST of the suite, e.g. "PS-SIG-BN256-V1".
+
+const hashToScalarLen = 48
+
+// ErrEmptyDST is returned when an empty domain separation tag is given.
+var ErrEmptyDST = errors.New("ps: empty domain separation tag")
+
+// Option configures how messages are hashed when signing and verifying.
+type Option func(*options)
+
+type options struct {
+	dst            []byte
+	validatePoints bool
+	// indices is the aggregation header checked by PSBatchVerify, if
+	// checkIndices is set.
+	indices      []int
+	checkIndices bool
+	// proofs are the proofs of possession checked by the aggregation of
+	// keys, if checkProofs is set.
+	proofs      []*PossessionProof
+	checkProofs bool
+	// deterministic derives the randomness of Sign and BatchSign from the
+	// key and the messages, see DeterministicSign.
+	deterministic bool
+	// bisect locates the invalid signatures of a failing VerifyBatch.
+	bisect bool
+	// workers is the number of goroutines NewKeyPair and BatchSign spread
+	// their work over, see WithParallelism.
+	workers int
+	// rand, if set, replaces the suite's random stream for signing.
+	rand cipher.Stream
+	// config holds the limits on the size of inputs, see WithConfig.
+	config Config
+	// projection maps the slots of indexed messages to those of a
+	// projected key, if project is set, see WithProjection.
+	projection *KeyProjection
+	project    bool
+	// kdf holds the key file parameters, see WithKDFParams.
+	kdf *KDFParams
+	// auditCtx is the context of the audit hook, see WithAuditContext.
+	auditCtx context.Context
+	// inst observes verifications and signatures, see
+	// WithInstrumentation.
+	inst Instrumentation
+}
+
+// WithDST sets the domain separation tag mixed into message hashing. Signers
+// and verifiers must use the same tag. An empty tag is rejected with
+// ErrEmptyDST rather than silently replaced by the default.
+func WithDST(dst []byte) Option {
+	return func(o *options) {
+		o.dst = append([]byte{}, dst...)
+	}
+}
+
+// DefaultDST returns the tag used when no WithDST option is given:
+// "PS-SIG-" followed by the upper-cased curve name and "-V1". It returns nil
+// for a nil suite.
+func DefaultDST(suite pairing.Suite) []byte {
+	if suite == nil {
+		return nil
+	}
+	return protocolDST(suite, "SIG")
+}
+
+// protocolDST returns the tag "PS-" || protocol || "-" || curve || "-V1"
+// separating the hashes of the protocols built on suite.
+func protocolDST(suite pairing.Suite, protocol string) []byte {
+	curve := strings.TrimSuffix(suite.G1().String(), ".G1")
+	return []byte("PS-" + protocol + "-" + strings.ToUpper(curve) + "-V1")
+}
+
+// ValidatePoints controls whether signature points parsed by Verify,
+// VerifyScalar, PSBatchVerify and AggregatePSSign are checked to lie in the
+// prime-order subgroup of G1. It is on by default. Callers that already
+// validated the signature, e.g. by parsing it with NewSignature or
+// UnmarshalSignature, may turn it off to save a scalar multiplication per
+// point; the curve checks of the suite's own decoder still apply.
+func ValidatePoints(validate bool) Option {
+	return func(o *options) {
+		o.validatePoints = validate
+	}
+}
+
+func newOptions(suite pairing.Suite, opts []Option) (*options, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	o := &options{dst: DefaultDST(suite), validatePoints: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	return o, nil
+}
+
+// hashToScalar maps msg to a scalar of suite under the tag dst.
+func hashToScalar(suite pairing.Suite, dst, msg []byte) kyber.Scalar {
+	return hashToScalarInto(newScalar(suite), dst, msg)
+}
+
+// hashToScalarInto implements hashToScalar, setting and returning s.
+func hashToScalarInto(s kyber.Scalar, dst, msg []byte) kyber.Scalar {
+	uniform := expander.NewExpanderMD(crypto.SHA256, dst).Expand(msg, hashToScalarLen)
+	return s.SetBytes(uniform)
+}
+
+// HashMessages maps msgs to the scalars m_1,...,m_r that signatures are
+// computed on, under the tag set by opts. Protocols built on top of PS, such
+// as threshold signing, use it to sign the same scalars as BatchSign.
+func HashMessages(suite pairing.Suite, msgs [][]byte, opts ...Option) ([]kyber.Scalar, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+	return m, nil
+}
+
+// hashablePoint is a point that hashes to its group, as the G1 points of
+// kyber's bn256 and of package bls12381 do.
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// hashToPoint maps msg to a point of G1 with unknown discrete logarithm under
+// the tag dst. The suite's G1 points must be hashablePoints.
+func hashToPoint(suite pairing.Suite, dst, msg []byte) (kyber.Point, error) {
+	hashable, ok := suite.G1().Point().(hashablePoint)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s cannot hash to points", ErrIncompatibleSuite, suite.G1())
+	}
+	return hashable.Hash(append(append([]byte{}, dst...), msg...)), nil
+}
@@ -0,0 +1,82 @@
+package ps
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Child keys are derived from a master key one path index at a time, in the
+// manner of BIP 32. The child of a parent with scalars x, y_1,...,y_r at
+// index i has the scalars picked, component j = 0,...,r, from
+//
+//	HKDF-Expand(PRK, i || j),   PRK = HKDF-Extract(salt, x || y_1 || ... || y_r)
+//
+// with SHA-256, salt = "PS-HD-" || curve || "-V1", i a big-endian uint32
+// and j a big-endian uint16. A path derives a chain of children, so the key
+// at path (a, b) is the child at b of the key at (a). The child has as many
+// slots as the master and no policy.
+//
+// Derivation is hardened only: every index must be at least Hardened, and
+// the child depends on the secret scalars of its parent. Deriving child
+// public keys from the master public key is unsupported, since the additive
+// tweaks that allow it would let anyone holding a child secret and the
+// public path recover the master secret. Auditors check a child against the
+// master by deriving it again with the master secret.
+
+// Hardened is the smallest hardened path index. Index Hardened+i is written
+// i' in BIP 32 notation.
+const Hardened uint32 = 1 << 31
+
+// ErrInvalidPath is returned for derivation paths that are empty or hold
+// non-hardened indices.
+var ErrInvalidPath = errors.New("ps: invalid derivation path")
+
+// DeriveChildKey returns the key pair at path below masterPriv, see above.
+// Every index of path must be hardened.
+func DeriveChildKey(masterPriv *PrivateKey, path []uint32) (*PrivateKey, *PublicKey, error) {
+	if err := masterPriv.check(); err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+	for n, i := range path {
+		if i < Hardened {
+			return nil, nil, fmt.Errorf("%w: index %d at depth %d is not hardened", ErrInvalidPath, i, n)
+		}
+	}
+	suite := masterPriv.suite
+	salt := protocolDST(suite, "HD")
+	scalars := masterPriv.Scalars()
+	for depth, i := range path {
+		ikm, err := appendScalars(nil, suite.G1(), scalars...)
+		if err != nil {
+			return nil, nil, err
+		}
+		prk := hkdf.Extract(sha256.New, ikm, salt)
+		zeroBytes(ikm)
+		child := make([]kyber.Scalar, len(scalars))
+		for j := range child {
+			var info [6]byte
+			binary.BigEndian.PutUint32(info[:], i)
+			binary.BigEndian.PutUint16(info[4:], uint16(j))
+			if child[j], err = pickScalar(suite, &readerStream{r: hkdf.Expand(sha256.New, prk, info[:])}); err != nil {
+				return nil, nil, err
+			}
+		}
+		zeroBytes(prk)
+		if depth > 0 {
+			for _, s := range scalars {
+				s.Zero()
+			}
+		}
+		scalars = child
+	}
+	priv := &PrivateKey{suite: suite, X: scalars[0], Y: scalars[1:]}
+	return priv, priv.Public(), nil
+}
@@ -0,0 +1,80 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// TestDeriveChildKeyGolden pins the children of a fixed master key, which
+// must not change across releases.
+func TestDeriveChildKeyGolden(t *testing.T) {
+	suite := bls12381.NewSuite()
+	randoms := []cipher.Stream{seededStream("hd x"), seededStream("hd y1"), seededStream("hd y2")}
+	private, _, err := NewKeyPairPoints(suite, randoms)
+	require.Nil(t, err)
+	master, err := NewPrivateKey(suite, private)
+	require.Nil(t, err)
+	for _, c := range []struct {
+		path []uint32
+		id   string
+	}{
+		{[]uint32{Hardened}, "75e4d9710f86be92"},
+		{[]uint32{Hardened + 44, Hardened + 1}, "d7ffbd2fc9d8b3cf"},
+	} {
+		_, pub, err := DeriveChildKey(master, c.path)
+		require.Nil(t, err)
+		id, err := pub.KeyID()
+		require.Nil(t, err)
+		require.Equal(t, c.id, id, "%v", c.path)
+	}
+}
+
+func TestDeriveChildKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		master, masterPub := newTestKeys(t, suite, 2)
+		x := master.X.Clone()
+		path := []uint32{Hardened + 44, Hardened + 7}
+		priv, pub, err := DeriveChildKey(master, path)
+		require.Nil(t, err)
+		require.Equal(t, len(master.Y), len(priv.Y))
+		require.Nil(t, priv.Policy())
+		msgs := [][]byte{[]byte("region"), []byte("eu-west")}
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, masterPub.Points(), msgs, S))
+
+		// Derivation is deterministic and goes one level at a time.
+		again, _, err := DeriveChildKey(master, path)
+		require.Nil(t, err)
+		parent, _, err := DeriveChildKey(master, path[:1])
+		require.Nil(t, err)
+		stepped, _, err := DeriveChildKey(parent, path[1:])
+		require.Nil(t, err)
+		for i, s := range priv.Scalars() {
+			require.True(t, s.Equal(again.Scalars()[i]))
+			require.True(t, s.Equal(stepped.Scalars()[i]))
+		}
+		require.True(t, x.Equal(master.X), "the master key is left intact")
+
+		// Siblings share no scalar, nor a difference of scalars.
+		sibling, _, err := DeriveChildKey(master, []uint32{Hardened + 44, Hardened + 8})
+		require.Nil(t, err)
+		d := newScalar(suite).Sub(priv.X, sibling.X)
+		for i := range priv.Y {
+			require.False(t, priv.Y[i].Equal(sibling.Y[i]))
+			require.False(t, d.Equal(newScalar(suite).Sub(priv.Y[i], sibling.Y[i])))
+		}
+		require.False(t, priv.X.Equal(sibling.X))
+
+		for _, bad := range [][]uint32{nil, {44}, {Hardened, Hardened - 1}} {
+			_, _, err := DeriveChildKey(master, bad)
+			require.True(t, errors.Is(err, ErrInvalidPath), "%v", bad)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package ps
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Keys and signatures have a text form made of "0x" followed by the lowercase
+// hexadecimal encoding of their binary format. It is used through
+// encoding.TextMarshaler so the types can be embedded in configuration files.
+
+const hexPrefix = "0x"
+
+// ErrInvalidHex is returned when parsing malformed hexadecimal text.
+var ErrInvalidHex = errors.New("ps: invalid hex encoding")
+
+func encodeHex(b []byte) string {
+	return hexPrefix + hex.EncodeToString(b)
+}
+
+// decodeHex accepts hexadecimal text with or without the 0x prefix.
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, hexPrefix), "0X")
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("%w: odd length %d", ErrInvalidHex, len(s))
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHex, err)
+	}
+	return b, nil
+}
+
+// Hex returns the 0x-prefixed hexadecimal form of the public key.
+func (k *PublicKey) Hex() (string, error) {
+	b, err := k.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return encodeHex(b), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k *PublicKey) MarshalText() ([]byte, error) {
+	s, err := k.Hex()
+	return []byte(s), err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, see UnmarshalBinary for
+// how the suite is selected.
+func (k *PublicKey) UnmarshalText(text []byte) error {
+	b, err := decodeHex(string(text))
+	if err != nil {
+		return err
+	}
+	return k.UnmarshalBinary(b)
+}
+
+// PublicKeyFromHex parses a public key in hexadecimal form, selecting its
+// suite through the registry.
+func PublicKeyFromHex(s string) (*PublicKey, error) {
+	k := new(PublicKey)
+	if err := k.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Hex returns the 0x-prefixed hexadecimal form of the signature.
+func (s *Signature) Hex() (string, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return encodeHex(b), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s *Signature) MarshalText() ([]byte, error) {
+	h, err := s.Hex()
+	return []byte(h), err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Signature) UnmarshalText(text []byte) error {
+	b, err := decodeHex(string(text))
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(b)
+}
+
+// SignatureFromHex parses a signature in hexadecimal form, selecting its suite
+// through the registry.
+func SignatureFromHex(s string) (*Signature, error) {
+	sig := new(Signature)
+	if err := sig.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -0,0 +1,66 @@
+package ps
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestHexRoundTrip(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		sig := newTestSignature(t, suite, priv, msg)
+
+		h, err := pub.Hex()
+		require.Nil(t, err)
+		require.True(t, strings.HasPrefix(h, "0x"))
+		require.Equal(t, strings.ToLower(h), h)
+
+		pub2, err := PublicKeyFromHex(h)
+		require.Nil(t, err)
+		pub3, err := PublicKeyFromHex(strings.TrimPrefix(h, "0x"))
+		require.Nil(t, err)
+		require.True(t, pub2.X.Equal(pub3.X))
+
+		h, err = sig.Hex()
+		require.Nil(t, err)
+		sig2, err := SignatureFromHex(h)
+		require.Nil(t, err)
+		S, err := sig2.Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub2.Points(), msg, S))
+	})
+}
+
+func TestHexText(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		sig := newTestSignature(t, suite, priv, []byte("config"))
+
+		type config struct {
+			Issuer    *PublicKey `json:"issuer"`
+			Signature *Signature `json:"signature"`
+		}
+		buf, err := json.Marshal(config{Issuer: pub, Signature: sig})
+		require.Nil(t, err)
+
+		var dec config
+		require.Nil(t, json.Unmarshal(buf, &dec))
+		require.True(t, pub.X.Equal(dec.Issuer.X))
+		require.True(t, sig.Sigma2.Equal(dec.Signature.Sigma2))
+	})
+}
+
+func TestHexInvalid(t *testing.T) {
+	for _, s := range []string{"0x123", "abc", "0xzz", "0x12g4"} {
+		_, err := PublicKeyFromHex(s)
+		require.True(t, errors.Is(err, ErrInvalidHex), s)
+		_, err = SignatureFromHex(s)
+		require.True(t, errors.Is(err, ErrInvalidHex), s)
+	}
+}
@@ -0,0 +1,208 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A key with n attribute slots signs up to n messages, msgs[i] with y_(i+1).
+// A credential that fills only some slots would have to pass placeholder
+// messages for the others to BatchSign, and the placeholders become part of
+// the signed statement. BatchSignIndexed and BatchVerifyIndexed take only the
+// filled slots: the signature is
+//
+//	(h, h^(x + sum y_(i+1)*m_i)) over the slots i given,
+//
+// and the empty slots do not take part in it. Signing every slot from 0 to
+// n-1 gives the signature BatchSign gives on the same messages in order.
+// The slots are part of the statement: a signature on a message in slot 5
+// does not verify with the same message in slot 6.
+//
+// PSBatchVerify binds msgs[i] to Y_(i+1) by position alone, so messages that
+// travel through a map, or a protobuf whose repeated fields get reordered,
+// silently change the statement. Carrying each message with its slot and
+// calling CanonicalizeMessages, or PSBatchVerifyPositions, restores the
+// order, and a slot lost on the way fails with ErrMissingAttribute.
+
+// ErrMissingAttribute is returned when a set of messages in slots leaves out
+// a slot below the highest one given.
+var ErrMissingAttribute = errors.New("ps: missing attribute")
+
+// ErrInvalidIndex is returned when an indexed message names a slot outside
+// the key or a slot already given.
+var ErrInvalidIndex = errors.New("ps: invalid message index")
+
+// IndexedMessage is a message in slot Index of a key, counting from 0.
+type IndexedMessage struct {
+	Index int
+	Msg   []byte
+}
+
+// IndexedMessages is a set of messages in distinct slots, in any order.
+type IndexedMessages []IndexedMessage
+
+// BatchSignIndexed signs msgs in their slots of priKey, leaving the other
+// slots out of the signature. Options apply as for BatchSign, except
+// WithParallelism.
+func BatchSignIndexed(suite pairing.Suite, priKey []kyber.Scalar, msgs IndexedMessages, opts ...Option) ([][]byte, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	m, err := indexedScalars(suite, len(priKey)-1, msgs, o)
+	if err != nil {
+		return nil, err
+	}
+	sum := newScalar(suite)
+	for i, mi := range m {
+		if mi != nil {
+			sum.Add(sum, newScalar(suite).Mul(priKey[i+1], mi))
+		}
+	}
+	rand, err := o.signingStream(suite, priKey, denseScalars(suite, m))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signSum(suite, priKey[0], sum, rand)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Components()
+}
+
+// BatchVerifyIndexed checks a signature S made by BatchSignIndexed on msgs
+// under pubKey. Options apply as for PSBatchVerify, except WithIndices.
+// With WithProjection, pubKey is a projected key and msgs are in their slots
+// of the original key.
+func BatchVerifyIndexed(suite pairing.Suite, pubKey []kyber.Point, msgs IndexedMessages, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if o.project {
+		if msgs, err = o.projection.project(len(pubKey)-1, msgs); err != nil {
+			return err
+		}
+	}
+	m, err := indexedScalars(suite, len(pubKey)-1, msgs, o)
+	if err != nil {
+		return err
+	}
+	if S == nil {
+		return ErrNilSignature
+	}
+	sig, err := parseSignature(suite, S, o.validatePoints)
+	if err != nil {
+		return err
+	}
+	scalars := make([]kyber.Scalar, 0, len(msgs))
+	points := make([]kyber.Point, 0, len(msgs))
+	for i, mi := range m {
+		if mi != nil {
+			scalars = append(scalars, mi)
+			points = append(points, pubKey[i+1])
+		}
+	}
+	Y, err := multiScalarMul(suite.G2(), scalars, points)
+	if err != nil {
+		return err
+	}
+	X := suite.G2().Point().Add(Y, pubKey[0])
+	return verifySignature(suite, X, suite.G2().Point().Base(), sig)
+}
+
+// CanonicalizeMessages returns msgs ordered by slot, as PSBatchVerify and
+// BatchSign take them. The slots must be distinct and cover 0 to len(msgs)-1.
+func CanonicalizeMessages(msgs IndexedMessages) ([][]byte, error) {
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	sorted := make([]int, len(msgs))
+	for i, msg := range msgs {
+		if msg.Index < 0 {
+			return nil, itemError("check index", i, fmt.Errorf("%w: slot %d", ErrInvalidIndex, msg.Index))
+		}
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return msgs[sorted[a]].Index < msgs[sorted[b]].Index
+	})
+	canonical := make([][]byte, len(msgs))
+	for slot, i := range sorted {
+		switch index := msgs[i].Index; {
+		case index < slot:
+			return nil, itemError("check index", i, fmt.Errorf("%w: slot %d given twice", ErrInvalidIndex, index))
+		case index > slot:
+			return nil, fmt.Errorf("%w: slot %d", ErrMissingAttribute, slot)
+		}
+		canonical[slot] = msgs[i].Msg
+	}
+	return canonical, nil
+}
+
+// PSBatchVerifyPositions is PSBatchVerify on the messages of msgs ordered by
+// slot with CanonicalizeMessages, so that any order of msgs verifies alike.
+func PSBatchVerifyPositions(suite pairing.Suite, pubKey []kyber.Point, msgs IndexedMessages, S [][]byte, opts ...Option) error {
+	canonical, err := CanonicalizeMessages(msgs)
+	if err != nil {
+		return err
+	}
+	return PSBatchVerify(suite, pubKey, canonical, S, opts...)
+}
+
+// indexedScalars checks that msgs are non-empty messages in distinct slots
+// of a key with slots attributes, and returns their scalars by slot, up to
+// the highest slot given, nil for the slots left out. Failures are reported
+// as a *BatchError holding the position of the message in msgs.
+func indexedScalars(suite pairing.Suite, slots int, msgs IndexedMessages, o *options) ([]kyber.Scalar, error) {
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, err
+	}
+	last := -1
+	for i, msg := range msgs {
+		if msg.Index < 0 || msg.Index >= slots {
+			return nil, itemError("check index", i, fmt.Errorf("%w: slot %d outside 0..%d", ErrInvalidIndex, msg.Index, slots-1))
+		}
+		if len(msg.Msg) == 0 {
+			return nil, itemError("hash message", i, ErrEmptyMessage)
+		}
+		if msg.Index > last {
+			last = msg.Index
+		}
+	}
+	m := make([]kyber.Scalar, last+1)
+	for i, msg := range msgs {
+		if m[msg.Index] != nil {
+			return nil, itemError("check index", i, fmt.Errorf("%w: slot %d given twice", ErrInvalidIndex, msg.Index))
+		}
+		m[msg.Index] = hashToScalar(suite, o.dst, msg.Msg)
+	}
+	return m, nil
+}
+
+// denseScalars returns m with zero for the slots left out, which no message
+// hashes to, so that deterministic signing binds the slots as well as the
+// messages.
+func denseScalars(suite pairing.Suite, m []kyber.Scalar) []kyber.Scalar {
+	dense := make([]kyber.Scalar, len(m))
+	for i, mi := range m {
+		if mi == nil {
+			mi = newScalar(suite)
+		}
+		dense[i] = mi
+	}
+	return dense
+}
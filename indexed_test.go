@@ -0,0 +1,120 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestIndexedMessages(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 32)
+		msgs := IndexedMessages{
+			{Index: 31, Msg: []byte("expires 2027-01-01")},
+			{Index: 0, Msg: []byte("Alice")},
+			{Index: 5, Msg: []byte("Paris")},
+		}
+		S, err := BatchSignIndexed(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Nil(t, BatchVerifyIndexed(suite, pub.Points(), msgs, S))
+
+		altered := append(IndexedMessages{}, msgs...)
+		altered[2].Msg = []byte("Berlin")
+		require.Equal(t, ErrInvalidSignature, BatchVerifyIndexed(suite, pub.Points(), altered, S))
+
+		// The slot is signed along with the message.
+		moved := append(IndexedMessages{}, msgs...)
+		moved[2].Index = 6
+		require.Equal(t, ErrInvalidSignature, BatchVerifyIndexed(suite, pub.Points(), moved, S))
+		require.Equal(t, ErrInvalidSignature, BatchVerifyIndexed(suite, pub.Points(), msgs[:2], S))
+
+		dup := append(IndexedMessages{}, msgs...)
+		dup[2].Index = 0
+		_, err = BatchSignIndexed(suite, priv.Scalars(), dup)
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 2, batchErr.Index)
+		require.True(t, errors.Is(err, ErrInvalidIndex))
+		require.True(t, errors.Is(BatchVerifyIndexed(suite, pub.Points(), dup, S), ErrInvalidIndex))
+
+		for _, slot := range []int{-1, 32} {
+			out := IndexedMessages{{Index: slot, Msg: []byte("x")}}
+			_, err = BatchSignIndexed(suite, priv.Scalars(), out)
+			require.True(t, errors.Is(err, ErrInvalidIndex), "slot %d", slot)
+			require.True(t, errors.Is(BatchVerifyIndexed(suite, pub.Points(), out, S), ErrInvalidIndex), "slot %d", slot)
+		}
+		_, err = BatchSignIndexed(suite, priv.Scalars(), IndexedMessages{{Index: 1}})
+		require.True(t, errors.Is(err, ErrEmptyMessage))
+		_, err = BatchSignIndexed(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNoMessages, err)
+	})
+}
+
+func TestIndexedMatchesBatchSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 4)
+		msgs := [][]byte{[]byte("Alice"), []byte("1990-04-01"), []byte("Paris")}
+		indexed := IndexedMessages{{Index: 2, Msg: msgs[2]}, {Index: 0, Msg: msgs[0]}, {Index: 1, Msg: msgs[1]}}
+
+		S, err := BatchSign(suite, priv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		T, err := BatchSignIndexed(suite, priv.Scalars(), indexed, DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, S, T)
+		require.Nil(t, BatchVerifyIndexed(suite, pub.Points(), indexed, S))
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, T))
+
+		// Deterministic signatures on a sparse set depend on the slots.
+		U, err := BatchSignIndexed(suite, priv.Scalars(), IndexedMessages{{Index: 1, Msg: msgs[0]}}, DeterministicSign())
+		require.Nil(t, err)
+		V, err := BatchSignIndexed(suite, priv.Scalars(), IndexedMessages{{Index: 2, Msg: msgs[0]}}, DeterministicSign())
+		require.Nil(t, err)
+		require.NotEqual(t, U[0], V[0])
+	})
+}
+
+func TestPSBatchVerifyPositions(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 4)
+		msgs := [][]byte{[]byte("Alice"), []byte("1990-04-01"), []byte("Paris"), []byte("NL")}
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+
+		attrs := func(order ...int) IndexedMessages {
+			set := make(IndexedMessages, len(order))
+			for i, slot := range order {
+				set[i] = IndexedMessage{Index: slot, Msg: msgs[slot]}
+			}
+			return set
+		}
+		for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}} {
+			canonical, err := CanonicalizeMessages(attrs(order...))
+			require.Nil(t, err)
+			require.Equal(t, msgs, canonical)
+			require.Nil(t, PSBatchVerifyPositions(suite, pub.Points(), attrs(order...), S), "order %v", order)
+		}
+
+		// Positions travel with the messages: swapping two values fails.
+		swapped := attrs(2, 0, 3, 1)
+		swapped[0].Msg, swapped[1].Msg = swapped[1].Msg, swapped[0].Msg
+		require.Equal(t, ErrInvalidSignature, PSBatchVerifyPositions(suite, pub.Points(), swapped, S))
+
+		err = PSBatchVerifyPositions(suite, pub.Points(), attrs(3, 0, 1), S)
+		require.True(t, errors.Is(err, ErrMissingAttribute))
+		require.Contains(t, err.Error(), "slot 2")
+		_, err = CanonicalizeMessages(attrs(1, 2))
+		require.True(t, errors.Is(err, ErrMissingAttribute))
+
+		_, err = CanonicalizeMessages(attrs(0, 1, 1))
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 2, batchErr.Index)
+		require.True(t, errors.Is(err, ErrInvalidIndex))
+		_, err = CanonicalizeMessages(IndexedMessages{{Index: -1, Msg: msgs[0]}})
+		require.True(t, errors.Is(err, ErrInvalidIndex))
+		_, err = CanonicalizeMessages(nil)
+		require.Equal(t, ErrNoMessages, err)
+	})
+}
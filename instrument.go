@@ -0,0 +1,74 @@
+package ps
+
+import "time"
+
+// Instrumentation lets a service export the latency and failure rate of its
+// verifications and signatures, e.g. as Prometheus metrics with the psprom
+// package, without wrapping every call. It is attached with
+// WithInstrumentation to the values and bulk calls serving many requests:
+//
+//   - NewVerifier, whose Verify and BatchVerify are observed;
+//   - NewKeySet, whose verifiers and VerifyPartial are observed;
+//   - NewConcurrentSigner, whose Sign and BatchSign are observed;
+//   - VerifyAll, observed once per item, and VerifyBatch and VerifyMany,
+//     observed once per call.
+//
+// The other functions ignore it. Every observed call is reported, failed or
+// not, including calls refused for their arguments or a key policy; only
+// invalid options are not. Without instrumentation, the default, observing
+// costs a nil check and does not allocate.
+
+// Instrumentation observes verifications and signatures. Its methods get
+// the duration of the call, whether it succeeded and the number of
+// attributes, i.e. messages, per signature. They are called synchronously,
+// possibly from several goroutines at once, and must return quickly.
+type Instrumentation interface {
+	ObserveVerify(d time.Duration, ok bool, attrs int)
+	ObserveSign(d time.Duration, ok bool, attrs int)
+}
+
+// NopInstrumentation observes nothing. It may be embedded by
+// implementations interested in some of the observations only.
+type NopInstrumentation struct{}
+
+// ObserveVerify does nothing.
+func (NopInstrumentation) ObserveVerify(time.Duration, bool, int) {}
+
+// ObserveSign does nothing.
+func (NopInstrumentation) ObserveSign(time.Duration, bool, int) {}
+
+// WithInstrumentation reports the calls observed, see Instrumentation, to
+// inst. A nil inst observes nothing.
+func WithInstrumentation(inst Instrumentation) Option {
+	return func(o *options) {
+		o.inst = inst
+	}
+}
+
+// observer is the instrumentation of a value or call, which may be nil.
+type observer struct {
+	inst Instrumentation
+}
+
+// start returns the start time of an observed call, or the zero time
+// without instrumentation.
+func (ob observer) start() time.Time {
+	if ob.inst == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// verified observes a verification started at start.
+func (ob observer) verified(start time.Time, err error, attrs int) {
+	if ob.inst != nil {
+		ob.inst.ObserveVerify(time.Since(start), err == nil, attrs)
+	}
+}
+
+// signed observes a signature started at start.
+func (ob observer) signed(start time.Time, err error, attrs int) {
+	if ob.inst != nil {
+		ob.inst.ObserveSign(time.Since(start), err == nil, attrs)
+	}
+}
@@ -0,0 +1,206 @@
+package ps
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// observation is a call of a countingInstrumentation.
+type observation struct {
+	sign  bool
+	ok    bool
+	attrs int
+}
+
+// countingInstrumentation counts its observations.
+type countingInstrumentation struct {
+	mu     sync.Mutex
+	counts map[observation]int
+}
+
+func newCountingInstrumentation() *countingInstrumentation {
+	return &countingInstrumentation{counts: make(map[observation]int)}
+}
+
+func (c *countingInstrumentation) observe(sign bool, d time.Duration, ok bool, attrs int) {
+	if d < 0 {
+		panic("negative duration")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[observation{sign, ok, attrs}]++
+}
+
+func (c *countingInstrumentation) ObserveVerify(d time.Duration, ok bool, attrs int) {
+	c.observe(false, d, ok, attrs)
+}
+
+func (c *countingInstrumentation) ObserveSign(d time.Duration, ok bool, attrs int) {
+	c.observe(true, d, ok, attrs)
+}
+
+// take returns the counts observed since the last call.
+func (c *countingInstrumentation) take() map[observation]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.counts
+	c.counts = make(map[observation]int)
+	return counts
+}
+
+func TestInstrumentationVerifier(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		inst := newCountingInstrumentation()
+		v, err := NewVerifier(suite, pub, WithInstrumentation(inst))
+		require.Nil(t, err)
+
+		S, err := priv.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Nil(t, v.BatchVerify(msgs, S))
+		one, err := priv.BatchSign(msgs[:1])
+		require.Nil(t, err)
+		require.Nil(t, v.Verify(msgs[0], one))
+		require.Equal(t, map[observation]int{{false, true, 2}: 1, {false, true, 1}: 1}, inst.take())
+
+		truncated := [][]byte{S[0], S[1][:len(S[1])-1]}
+		failures := map[string]func() error{
+			"invalid signature":   func() error { return v.BatchVerify([][]byte{msgs[1], msgs[0]}, S) },
+			"malformed signature": func() error { return v.BatchVerify(msgs, truncated) },
+			"nil signature":       func() error { return v.BatchVerify(msgs, nil) },
+			"empty message":       func() error { return v.BatchVerify([][]byte{msgs[0], {}}, S) },
+			"too many messages":   func() error { return v.BatchVerify(append(msgs[:2:2], []byte("attribute 3")), S) },
+		}
+		for class, call := range failures {
+			require.NotNil(t, call(), class)
+			counts := inst.take()
+			require.Len(t, counts, 1, class)
+			for o, n := range counts {
+				require.False(t, o.sign, class)
+				require.False(t, o.ok, class)
+				require.Equal(t, 1, n, class)
+			}
+		}
+		require.NotNil(t, v.Verify(msgs[1], one))
+		require.Equal(t, map[observation]int{{false, false, 1}: 1}, inst.take())
+	})
+}
+
+func TestInstrumentationKeySet(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msgs := [][]byte{[]byte("alice"), []byte("paris")}
+	priv, pub := newTestKeys(t, suite, len(msgs))
+	inst := newCountingInstrumentation()
+	ks := NewKeySet(0, WithInstrumentation(inst))
+	id, err := ks.Add(pub)
+	require.Nil(t, err)
+
+	S, err := priv.BatchSign(msgs)
+	require.Nil(t, err)
+	v, err := ks.Resolve(id)
+	require.Nil(t, err)
+	require.Nil(t, v.BatchVerify(msgs, S))
+	require.Equal(t, map[observation]int{{false, true, 2}: 1}, inst.take())
+
+	sig, err := NewSignature(suite, S)
+	require.Nil(t, err)
+	nonce := []byte("nonce")
+	proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{0: true}, nonce)
+	require.Nil(t, err)
+	_, err = ks.VerifyPartial(map[int][]byte{0: msgs[0]}, proof, nonce)
+	require.Nil(t, err)
+	_, err = ks.VerifyPartial(map[int][]byte{0: msgs[1]}, proof, nonce)
+	require.Equal(t, ErrInvalidSignature, err)
+	proof.KeyID = "0000000000000000"
+	_, err = ks.VerifyPartial(map[int][]byte{0: msgs[0]}, proof, nonce)
+	require.True(t, errors.Is(err, ErrKeyNotFound))
+	_, err = ks.VerifyPartial(nil, nil, nonce)
+	require.True(t, errors.Is(err, ErrNilSignature))
+	require.Equal(t, map[observation]int{{false, true, 2}: 1, {false, false, 2}: 2, {false, false, 0}: 1}, inst.take())
+}
+
+func TestInstrumentationConcurrentSigner(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+	priv, _ := newTestKeys(t, suite, len(msgs))
+	require.Nil(t, priv.SetPolicy(&KeyPolicy{MaxMessages: 1, Operations: OpSign}))
+	inst := newCountingInstrumentation()
+	signer, err := NewConcurrentSigner(suite, priv, nil, WithInstrumentation(inst))
+	require.Nil(t, err)
+
+	_, err = signer.Sign(msgs[0])
+	require.Nil(t, err)
+	_, err = signer.BatchSign(msgs[:1])
+	require.Nil(t, err)
+	_, err = signer.BatchSign(msgs)
+	require.True(t, errors.Is(err, ErrPolicyViolation))
+	_, err = signer.Sign(nil)
+	require.True(t, errors.Is(err, ErrNilMessage) || errors.Is(err, ErrEmptyMessage))
+	require.Equal(t, map[observation]int{{true, true, 1}: 2, {true, false, 2}: 1, {true, false, 1}: 1}, inst.take())
+}
+
+func TestInstrumentationBulk(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+	priv, pub := newTestKeys(t, suite, 2)
+	inst := newCountingInstrumentation()
+	with := WithInstrumentation(inst)
+
+	sigs := make([]*Signature, len(msgs))
+	items := make([]VerifyItem, len(msgs))
+	for i, msg := range msgs {
+		S, err := priv.BatchSign([][]byte{msg})
+		require.Nil(t, err)
+		sigs[i], err = NewSignature(suite, S)
+		require.Nil(t, err)
+		items[i] = VerifyItem{Msgs: [][]byte{msg}, Signature: S}
+	}
+	items[2].Msgs = msgs[:1]
+
+	for _, workers := range []int{1, 4} {
+		errs, err := VerifyAll(context.Background(), suite, pub.Points(), items, workers, with)
+		require.NotNil(t, err)
+		require.Nil(t, errs[0])
+		require.Equal(t, map[observation]int{{false, true, 1}: 2, {false, false, 1}: 1}, inst.take())
+	}
+
+	require.Nil(t, VerifyBatch(suite, pub.Points(), msgs, sigs, random.New(), with))
+	require.NotNil(t, VerifyBatch(suite, pub.Points(), msgs, []*Signature{sigs[1], sigs[0], sigs[2]}, random.New(), with))
+	require.NotNil(t, VerifyBatch(suite, pub.Points(), msgs, sigs[:2], random.New(), with))
+	require.Equal(t, map[observation]int{{false, true, 1}: 1, {false, false, 1}: 2}, inst.take())
+
+	S, err := priv.BatchSign(msgs[:2])
+	require.Nil(t, err)
+	sig, err := NewSignature(suite, S)
+	require.Nil(t, err)
+	_, err = VerifyMany(suite, []*PublicKey{pub, pub}, msgs[:2], []*Signature{sig, sig}, with)
+	require.Nil(t, err)
+	_, err = VerifyMany(suite, []*PublicKey{pub, pub}, msgs[:2], []*Signature{sig, sigs[0]}, with)
+	require.NotNil(t, err)
+	require.Equal(t, map[observation]int{{false, true, 2}: 1, {false, false, 2}: 1}, inst.take())
+
+	// Without instrumentation nothing is observed, and the free functions
+	// ignore it.
+	_, err = VerifyMany(suite, []*PublicKey{pub}, msgs[:2], []*Signature{sig})
+	require.Nil(t, err)
+	require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs[:2], S, with))
+	require.Empty(t, inst.take())
+}
+
+func TestInstrumentationAllocs(t *testing.T) {
+	var ob observer
+	allocs := testing.AllocsPerRun(100, func() {
+		start := ob.start()
+		ob.verified(start, nil, 1)
+		ob.signed(start, ErrInvalidSignature, 1)
+	})
+	require.Equal(t, 0.0, allocs)
+}
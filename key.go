@@ -0,0 +1,163 @@
+package ps
+
+import (
+	"crypto"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// PrivateKey is a PS signing key holding the secret scalars x and y_1,...,y_r.
+type PrivateKey struct {
+	suite  pairing.Suite
+	X      kyber.Scalar
+	Y      []kyber.Scalar
+	wiped  bool
+	policy *KeyPolicy
+	audit  *auditor
+}
+
+// PublicKey is a PS verification key holding the points X = g^x and
+// Y_i = g^(y_i) on the curve G2.
+type PublicKey struct {
+	suite pairing.Suite
+	X     kyber.Point
+	Y     []kyber.Point
+}
+
+// NewPrivateKey wraps a private key in the layout returned by NewKeyPairPoints,
+// x followed by y_1,...,y_r.
+func NewPrivateKey(suite pairing.Suite, priKey []kyber.Scalar) (*PrivateKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	return &PrivateKey{suite: suite, X: priKey[0], Y: priKey[1:]}, nil
+}
+
+// NewPublicKey wraps a public key in the layout returned by NewKeyPairPoints,
+// X followed by Y_1,...,Y_r.
+func NewPublicKey(suite pairing.Suite, pubKey []kyber.Point) (*PublicKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return nil, err
+	}
+	return &PublicKey{suite: suite, X: pubKey[0], Y: pubKey[1:]}, nil
+}
+
+// check reports whether the key is complete: non-nil, with a suite and no
+// nil component.
+func (k *PrivateKey) check() error {
+	if k == nil || k.X == nil {
+		return ErrNilKey
+	}
+	if k.wiped {
+		return ErrKeyWiped
+	}
+	if k.suite == nil {
+		return ErrNilSuite
+	}
+	return checkPrivateKey(k.Y, 1)
+}
+
+// Wipe zeroes the secret scalars in place, so slices obtained from Scalars
+// are wiped as well. Afterwards the key is unusable: Sign and the other
+// operations taking it fail with ErrKeyWiped. Wipe may be called repeatedly.
+//
+// The key holds no encoded copies of its scalars; buffers returned by
+// MarshalBinary belong to the caller, who must clear them.
+func (k *PrivateKey) Wipe() {
+	if k == nil {
+		return
+	}
+	if k.X != nil {
+		k.X.Zero()
+	}
+	for _, y := range k.Y {
+		if y != nil {
+			y.Zero()
+		}
+	}
+	k.wiped = true
+}
+
+// Suite returns the pairing suite the key belongs to.
+func (k *PrivateKey) Suite() pairing.Suite {
+	if k == nil {
+		return nil
+	}
+	return k.suite
+}
+
+// Scalars returns x followed by y_1,...,y_r, the layout used by Sign and
+// BatchSign.
+func (k *PrivateKey) Scalars() []kyber.Scalar {
+	if k == nil {
+		return nil
+	}
+	return append([]kyber.Scalar{k.X}, k.Y...)
+}
+
+// Public derives the public key (g^x, g^(y_1),...,g^(y_r)). It returns nil
+// for an incomplete key.
+func (k *PrivateKey) Public() *PublicKey {
+	if k.check() != nil {
+		return nil
+	}
+	pub := &PublicKey{suite: k.suite, X: k.suite.G2().Point().Mul(k.X, nil)}
+	for _, y := range k.Y {
+		pub.Y = append(pub.Y, k.suite.G2().Point().Mul(y, nil))
+	}
+	return pub
+}
+
+// check reports whether the key is complete, see PrivateKey.check.
+func (k *PublicKey) check() error {
+	if k == nil || k.X == nil {
+		return ErrNilKey
+	}
+	if k.suite == nil {
+		return ErrNilSuite
+	}
+	return checkPublicKey(k.Y, 1)
+}
+
+// Suite returns the pairing suite the key belongs to.
+func (k *PublicKey) Suite() pairing.Suite {
+	if k == nil {
+		return nil
+	}
+	return k.suite
+}
+
+// Points returns X followed by Y_1,...,Y_r, the layout used by Verify and
+// PSBatchVerify.
+func (k *PublicKey) Points() []kyber.Point {
+	if k == nil {
+		return nil
+	}
+	return append([]kyber.Point{k.X}, k.Y...)
+}
+
+// Equal reports whether x is a *PublicKey of the same suite with the same
+// points, as crypto.PublicKey implementations are expected to.
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	if !ok || k.check() != nil || other.check() != nil {
+		return false
+	}
+	if k.suite.G2().String() != other.suite.G2().String() || len(k.Y) != len(other.Y) {
+		return false
+	}
+	q := other.Points()
+	for i, p := range k.Points() {
+		if !p.Equal(q[i]) {
+			return false
+		}
+	}
+	return true
+}
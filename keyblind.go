@@ -0,0 +1,214 @@
+package ps
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Key blinding gives an issuer one public key per relying party, all backed
+// by one master key. The blinded key for a context multiplies every scalar
+// of the master key by the same factor
+//
+//	r = HKDF-Expand(PRK, context),   PRK = HKDF-Extract(salt, x || y_1 || ... || y_r)
+//
+// with SHA-256 and salt = "PS-KBLIND-" || curve || "-V1", so it is the
+// ordinary key (x*r, y_1*r,...,y_r*r) with public points (X^r, Y_1^r,...,
+// Y_r^r), and signatures made with it verify under it alone. Since r depends
+// on the master secret, telling whether two keys are blinded from the same
+// master, or a key from a given master, is a decisional Diffie-Hellman
+// problem in G2, which is hard for the type-3 pairings used here.
+//
+// A KeyBlindingProof links a blinded key to its master. It is a Schnorr
+// proof that one r relates every component, with challenge
+//
+//	c = H(master || blinded || n || context || R_0 || ... || R_r),   R_i = P_i^w,
+//
+// where master and blinded are the encoded keys, n the length of context as
+// a big-endian uint16, P_i the points of the master key and H hashes to a
+// scalar under the tag protocolDST(suite, "KBLIND"). Anyone holding a proof
+// can check the link, so issuers hand proofs to designated auditors only.
+
+// ErrInvalidKeyBlinding is returned for empty blinding contexts and for
+// proofs that do not link a blinded key to its master.
+var ErrInvalidKeyBlinding = errors.New("ps: invalid key blinding")
+
+// KeyBlindingProof is a proof (c, z) that a blinded key derives from a
+// master key, see above.
+type KeyBlindingProof struct {
+	suite     pairing.Suite
+	Challenge kyber.Scalar
+	Response  kyber.Scalar
+}
+
+// check reports whether the proof is complete.
+func (p *KeyBlindingProof) check() error {
+	if p == nil || p.Challenge == nil || p.Response == nil {
+		return ErrInvalidKeyBlinding
+	}
+	if p.suite == nil {
+		return ErrNilSuite
+	}
+	return nil
+}
+
+// keyBlindingChallenge derives the challenge binding the proof to both keys,
+// the context and the commitments R.
+func keyBlindingChallenge(suite pairing.Suite, master, blinded *PublicKey, context []byte, R []kyber.Point) (kyber.Scalar, error) {
+	buf, err := master.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b, err := blinded.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendBytes(append(buf, b...), context); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.G2(), false, R...); err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "KBLIND"), buf), nil
+}
+
+// BlindKey returns the key pair of priv blinded for context, which carries
+// the policy of priv, and a proof linking it to priv. The same context always
+// gives the same key.
+func BlindKey(priv *PrivateKey, context []byte) (*PrivateKey, *PublicKey, *KeyBlindingProof, error) {
+	if err := priv.check(); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(context) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: empty context", ErrInvalidKeyBlinding)
+	}
+	suite := priv.suite
+	if err := checkScalarField(suite); err != nil {
+		return nil, nil, nil, err
+	}
+	ikm, err := appendScalars(nil, suite.G1(), priv.Scalars()...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	prk := hkdf.Extract(sha256.New, ikm, protocolDST(suite, "KBLIND"))
+	zeroBytes(ikm)
+	r, err := pickScalar(suite, &readerStream{r: hkdf.Expand(sha256.New, prk, context)})
+	zeroBytes(prk)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer r.Zero()
+
+	scalars := priv.Scalars()
+	blinded := make([]kyber.Scalar, len(scalars))
+	for i, s := range scalars {
+		blinded[i] = newScalar(suite).Mul(s, r)
+	}
+	bpriv := &PrivateKey{suite: suite, X: blinded[0], Y: blinded[1:], policy: priv.Policy()}
+	bpub := bpriv.Public()
+
+	master := priv.Public()
+	w, err := pickScalar(suite, suite.RandomStream())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer w.Zero()
+	points := master.Points()
+	R := make([]kyber.Point, len(points))
+	for i, p := range points {
+		R[i] = suite.G2().Point().Mul(w, p)
+	}
+	c, err := keyBlindingChallenge(suite, master, bpub, context, R)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	z := newScalar(suite).Mul(c, r)
+	z.Add(z, w)
+	return bpriv, bpub, &KeyBlindingProof{suite: suite, Challenge: c, Response: z}, nil
+}
+
+// VerifyKeyBlinding checks that blindedPub is masterPub blinded for context.
+// It returns ErrInvalidKeyBlinding if the proof does not verify.
+func VerifyKeyBlinding(masterPub, blindedPub *PublicKey, proof *KeyBlindingProof, context []byte) error {
+	if err := masterPub.check(); err != nil {
+		return err
+	}
+	if err := blindedPub.check(); err != nil {
+		return err
+	}
+	if err := proof.check(); err != nil {
+		return err
+	}
+	if len(context) == 0 {
+		return fmt.Errorf("%w: empty context", ErrInvalidKeyBlinding)
+	}
+	suite := masterPub.suite
+	if blindedPub.suite.G2().String() != suite.G2().String() || proof.suite.G2().String() != suite.G2().String() {
+		return ErrSuiteMismatch
+	}
+	if len(blindedPub.Y) != len(masterPub.Y) {
+		return fmt.Errorf("%w: blinded key has %d attributes, master %d", ErrInvalidKeyBlinding, len(blindedPub.Y), len(masterPub.Y))
+	}
+	points, blinded := masterPub.Points(), blindedPub.Points()
+	R := make([]kyber.Point, len(points))
+	for i, p := range points {
+		R[i] = suite.G2().Point().Mul(proof.Response, p)
+		R[i].Sub(R[i], suite.G2().Point().Mul(proof.Challenge, blinded[i]))
+	}
+	c, err := keyBlindingChallenge(suite, masterPub, blindedPub, context, R)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(proof.Challenge) {
+		return ErrInvalidKeyBlinding
+	}
+	return nil
+}
+
+// MarshalBinary encodes the proof as header || c || z.
+func (p *KeyBlindingProof) MarshalBinary() ([]byte, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(p.suite)
+	if err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, p.suite.G1(), p.Challenge, p.Response)
+}
+
+// UnmarshalBinary decodes a proof, see PrivateKey.UnmarshalBinary.
+func (p *KeyBlindingProof) UnmarshalBinary(data []byte) error {
+	if p == nil {
+		return ErrInvalidKeyBlinding
+	}
+	suite, body, newer, err := readHeader(p.suite, data)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, 2)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	*p = KeyBlindingProof{suite: suite, Challenge: scalars[0], Response: scalars[1]}
+	return nil
+}
+
+// UnmarshalKeyBlindingProof decodes a proof produced under suite.
+func UnmarshalKeyBlindingProof(suite pairing.Suite, data []byte) (*KeyBlindingProof, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	p := &KeyBlindingProof{suite: suite}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
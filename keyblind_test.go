@@ -0,0 +1,85 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestBlindKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		ctx := []byte("relying party A")
+		bpriv, bpub, proof, err := BlindKey(priv, ctx)
+		require.Nil(t, err)
+		require.Nil(t, ValidatePublicKey(suite, bpub, 2))
+		require.Nil(t, VerifyKeyBlinding(pub, bpub, proof, ctx))
+
+		msgs := [][]byte{[]byte("alice"), []byte("admin")}
+		S, err := bpriv.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, bpub.Points(), msgs, S))
+		require.NotNil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		again, againPub, _, err := BlindKey(priv, ctx)
+		require.Nil(t, err)
+		require.True(t, again.X.Equal(bpriv.X))
+		require.True(t, againPub.X.Equal(bpub.X))
+
+		_, _, _, err = BlindKey(priv, nil)
+		require.True(t, errors.Is(err, ErrInvalidKeyBlinding))
+	})
+}
+
+func TestBlindKeyUnlinkable(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		ctxA, ctxB := []byte("relying party A"), []byte("relying party B")
+		_, pubA, proofA, err := BlindKey(priv, ctxA)
+		require.Nil(t, err)
+		_, pubB, proofB, err := BlindKey(priv, ctxB)
+		require.Nil(t, err)
+
+		for i, p := range pubA.Points() {
+			require.False(t, p.Equal(pubB.Points()[i]))
+			require.False(t, p.Equal(pub.Points()[i]))
+		}
+		// The factor is not a public function of the context.
+		h := hashToScalar(suite, protocolDST(suite, "KBLIND"), ctxA)
+		require.False(t, suite.G2().Point().Mul(h, pub.X).Equal(pubA.X))
+
+		// A proof links only the key and context it was made for.
+		require.Equal(t, ErrInvalidKeyBlinding, VerifyKeyBlinding(pub, pubB, proofA, ctxA))
+		require.Equal(t, ErrInvalidKeyBlinding, VerifyKeyBlinding(pub, pubB, proofA, ctxB))
+		require.Equal(t, ErrInvalidKeyBlinding, VerifyKeyBlinding(pub, pubA, proofB, ctxA))
+		require.Nil(t, VerifyKeyBlinding(pub, pubB, proofB, ctxB))
+
+		_, other := newTestKeys(t, suite, 2)
+		require.Equal(t, ErrInvalidKeyBlinding, VerifyKeyBlinding(other, pubA, proofA, ctxA))
+		_, short := newTestKeys(t, suite, 1)
+		require.True(t, errors.Is(VerifyKeyBlinding(short, pubA, proofA, ctxA), ErrInvalidKeyBlinding))
+	})
+}
+
+func TestKeyBlindingProofEncoding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		ctx := []byte("relying party A")
+		_, bpub, proof, err := BlindKey(priv, ctx)
+		require.Nil(t, err)
+		buf, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalKeyBlindingProof(suite, buf)
+		require.Nil(t, err)
+		require.Nil(t, VerifyKeyBlinding(pub, bpub, dec, ctx))
+
+		buf[len(buf)-1] ^= 1
+		if dec, err = UnmarshalKeyBlindingProof(suite, buf); err == nil {
+			require.Equal(t, ErrInvalidKeyBlinding, VerifyKeyBlinding(pub, bpub, dec, ctx))
+		}
+		_, err = UnmarshalKeyBlindingProof(suite, buf[:len(buf)-1])
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,193 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v3/pairing"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// A key file stores a private key encrypted under a passphrase:
+//
+//	"PSKF" || version || kdf || time || memory || threads || salt || nonce || box
+//
+// version is 1 and kdf is 1 for Argon2id (RFC 9106), whose parameters
+// follow: time and memory, in KiB, as big-endian uint32s and threads as one
+// byte. The 16-byte salt feeds Argon2id, which derives a 32-byte key from
+// the passphrase, and box is the ChaCha20-Poly1305 (RFC 8439) sealing of
+// the MarshalBinary encoding of the key under the 12-byte nonce, with
+// everything before it as additional data. Altering any byte of the file,
+// including the KDF parameters, makes decryption fail.
+
+// KDFParams are the Argon2id parameters of a key file: the number of passes
+// Time, the memory Memory in KiB, and the parallelism Threads.
+type KDFParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultKDFParams are the parameters used without WithKDFParams, the
+// second recommended option of RFC 9106.
+var DefaultKDFParams = KDFParams{Time: 3, Memory: 64 << 10, Threads: 4}
+
+// MaxKDFMemory and MaxKDFTime bound the parameters accepted, so that a key
+// file cannot make its reader spend unbounded memory or time.
+const (
+	MaxKDFMemory = 4 << 20
+	MaxKDFTime   = 64
+)
+
+const (
+	keyFileMagic   = "PSKF"
+	keyFileVersion = 1
+	kdfArgon2id    = 1
+	keyFileSaltLen = 16
+	keyFileKeyLen  = chacha20poly1305.KeySize
+	// keyFileHeaderLen is the length of everything before the box.
+	keyFileHeaderLen = len(keyFileMagic) + 2 + 4 + 4 + 1 + keyFileSaltLen + chacha20poly1305.NonceSize
+)
+
+var (
+	// ErrDecryption is returned for key files that cannot be decrypted: a
+	// wrong passphrase, altered or truncated data, or an unknown format.
+	ErrDecryption = errors.New("ps: key file decryption failed")
+
+	// ErrInvalidKDFParams is returned for KDF parameters out of range.
+	ErrInvalidKDFParams = errors.New("ps: invalid KDF parameters")
+
+	// ErrEmptyPassphrase is returned when encrypting under an empty
+	// passphrase.
+	ErrEmptyPassphrase = errors.New("ps: empty passphrase")
+)
+
+// WithKDFParams sets the Argon2id parameters of EncryptPrivateKey.
+func WithKDFParams(p KDFParams) Option {
+	return func(o *options) {
+		o.kdf = &p
+	}
+}
+
+// check reports whether the parameters are within range.
+func (p KDFParams) check() error {
+	if p.Time < 1 || p.Time > MaxKDFTime {
+		return fmt.Errorf("%w: time %d outside 1..%d", ErrInvalidKDFParams, p.Time, MaxKDFTime)
+	}
+	if p.Threads < 1 {
+		return fmt.Errorf("%w: no threads", ErrInvalidKDFParams)
+	}
+	if p.Memory < 8*uint32(p.Threads) || p.Memory > MaxKDFMemory {
+		return fmt.Errorf("%w: memory %d KiB outside %d..%d", ErrInvalidKDFParams, p.Memory, 8*uint32(p.Threads), MaxKDFMemory)
+	}
+	return nil
+}
+
+// deriveKey derives the key sealing a key file from the passphrase.
+func (p KDFParams) deriveKey(passphrase, salt []byte) []byte {
+	return argon2.IDKey(passphrase, salt, p.Time, p.Memory, p.Threads, keyFileKeyLen)
+}
+
+// EncryptPrivateKey returns priv encrypted under passphrase as a key file,
+// with the KDF parameters set by WithKDFParams or DefaultKDFParams.
+func EncryptPrivateKey(priv *PrivateKey, passphrase []byte, opts ...Option) ([]byte, error) {
+	if err := priv.check(); err != nil {
+		return nil, err
+	}
+	o, err := newOptions(priv.suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	params := DefaultKDFParams
+	if o.kdf != nil {
+		params = *o.kdf
+	}
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+	plain, err := priv.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(plain)
+
+	header := make([]byte, keyFileHeaderLen)
+	n := copy(header, keyFileMagic)
+	header[n], header[n+1] = keyFileVersion, kdfArgon2id
+	binary.BigEndian.PutUint32(header[n+2:], params.Time)
+	binary.BigEndian.PutUint32(header[n+6:], params.Memory)
+	header[n+10] = params.Threads
+	if _, err := io.ReadFull(rand.Reader, header[n+11:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEntropyFailure, err)
+	}
+	salt := header[n+11 : n+11+keyFileSaltLen]
+	nonce := header[n+11+keyFileSaltLen:]
+
+	key := params.deriveKey(passphrase, salt)
+	defer zeroBytes(key)
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plain, header), nil
+}
+
+// DecryptPrivateKey decrypts a key file made by EncryptPrivateKey with
+// passphrase and decodes the key under suite. Every failure to decrypt
+// wraps ErrDecryption.
+func DecryptPrivateKey(suite pairing.Suite, data, passphrase []byte) (*PrivateKey, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	plain, err := openKeyFile(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(plain)
+	return UnmarshalPrivateKey(suite, plain)
+}
+
+// openKeyFile returns the key encoding sealed in a key file.
+func openKeyFile(data, passphrase []byte) ([]byte, error) {
+	if len(data) < keyFileHeaderLen+chacha20poly1305.Overhead {
+		return nil, fmt.Errorf("%w: truncated key file", ErrDecryption)
+	}
+	if !bytes.HasPrefix(data, []byte(keyFileMagic)) {
+		return nil, fmt.Errorf("%w: not a key file", ErrDecryption)
+	}
+	n := len(keyFileMagic)
+	if data[n] != keyFileVersion || data[n+1] != kdfArgon2id {
+		return nil, fmt.Errorf("%w: unknown version %d or KDF %d", ErrDecryption, data[n], data[n+1])
+	}
+	params := KDFParams{
+		Time:    binary.BigEndian.Uint32(data[n+2:]),
+		Memory:  binary.BigEndian.Uint32(data[n+6:]),
+		Threads: data[n+10],
+	}
+	if err := params.check(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryption, err)
+	}
+	header := data[:keyFileHeaderLen]
+	salt := header[n+11 : n+11+keyFileSaltLen]
+	nonce := header[n+11+keyFileSaltLen:]
+
+	key := params.deriveKey(passphrase, salt)
+	defer zeroBytes(key)
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, nonce, data[keyFileHeaderLen:], header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong passphrase or altered key file", ErrDecryption)
+	}
+	return plain, nil
+}
@@ -0,0 +1,109 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// testKDFParams keep the tests fast; real key files use DefaultKDFParams.
+var testKDFParams = WithKDFParams(KDFParams{Time: 1, Memory: 64, Threads: 1})
+
+func TestEncryptPrivateKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub, err := GenerateKey(suite, 2, &KeyPolicy{Operations: OpSign})
+		require.Nil(t, err)
+		passphrase := []byte("correct horse battery staple")
+		blob, err := EncryptPrivateKey(priv, passphrase, testKDFParams)
+		require.Nil(t, err)
+		dec, err := DecryptPrivateKey(suite, blob, passphrase)
+		require.Nil(t, err)
+		require.Equal(t, priv.Policy(), dec.Policy())
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		S, err := dec.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		// Each encryption draws its own salt and nonce.
+		again, err := EncryptPrivateKey(priv, passphrase, testKDFParams)
+		require.Nil(t, err)
+		require.NotEqual(t, blob, again)
+
+		_, err = DecryptPrivateKey(suite, blob, []byte("correct horse battery stable"))
+		require.True(t, errors.Is(err, ErrDecryption))
+		_, err = DecryptPrivateKey(suite, blob, nil)
+		require.True(t, errors.Is(err, ErrDecryption))
+		_, err = EncryptPrivateKey(priv, nil, testKDFParams)
+		require.True(t, errors.Is(err, ErrEmptyPassphrase))
+	})
+}
+
+func TestDecryptPrivateKeyTampered(t *testing.T) {
+	suite := bls12381.NewSuite()
+	priv, _, err := GenerateKey(suite, 1, nil)
+	require.Nil(t, err)
+	passphrase := []byte("passphrase")
+	blob, err := EncryptPrivateKey(priv, passphrase, testKDFParams)
+	require.Nil(t, err)
+
+	// Flipping any bit of the header or the box is detected, the KDF
+	// parameters included.
+	for i := range blob {
+		tampered := append([]byte{}, blob...)
+		tampered[i] ^= 1
+		_, err := DecryptPrivateKey(suite, tampered, passphrase)
+		require.True(t, errors.Is(err, ErrDecryption), "byte %d: %v", i, err)
+	}
+	for _, n := range []int{0, 4, keyFileHeaderLen, len(blob) - 1} {
+		_, err := DecryptPrivateKey(suite, blob[:n], passphrase)
+		require.True(t, errors.Is(err, ErrDecryption), "%d bytes: %v", n, err)
+	}
+	_, err = DecryptPrivateKey(suite, append(blob, 0), passphrase)
+	require.True(t, errors.Is(err, ErrDecryption))
+}
+
+func TestKDFParams(t *testing.T) {
+	suite := bls12381.NewSuite()
+	priv, _, err := GenerateKey(suite, 1, nil)
+	require.Nil(t, err)
+	for _, bad := range []KDFParams{
+		{},
+		{Time: 1, Memory: 64},
+		{Time: 1, Memory: 31, Threads: 4},
+		{Time: MaxKDFTime + 1, Memory: 64, Threads: 1},
+		{Time: 1, Memory: MaxKDFMemory + 1, Threads: 1},
+	} {
+		_, err := EncryptPrivateKey(priv, []byte("passphrase"), WithKDFParams(bad))
+		require.True(t, errors.Is(err, ErrInvalidKDFParams), "%+v", bad)
+	}
+	require.Nil(t, DefaultKDFParams.check())
+
+	// A file demanding more memory than allowed is refused before any
+	// work is done.
+	blob, err := EncryptPrivateKey(priv, []byte("passphrase"), testKDFParams)
+	require.Nil(t, err)
+	blob[len(keyFileMagic)+6] = 0xff
+	_, err = DecryptPrivateKey(suite, blob, []byte("passphrase"))
+	require.True(t, errors.Is(err, ErrDecryption))
+}
+
+func TestDecryptPrivateKeyFixture(t *testing.T) {
+	suite := bls12381.NewSuite()
+	blob := readFixture(t, "privkey_bls12381.pskf.hex")
+	want := readFixture(t, "privkey_v1_bls12381.hex")
+
+	priv, err := DecryptPrivateKey(suite, blob, []byte("correct horse battery staple"))
+	require.Nil(t, err)
+	wantPriv, err := UnmarshalPrivateKey(suite, want)
+	require.Nil(t, err)
+	require.Equal(t, len(wantPriv.Scalars()), len(priv.Scalars()))
+	for i, x := range wantPriv.Scalars() {
+		require.True(t, x.Equal(priv.Scalars()[i]))
+	}
+
+	_, err = DecryptPrivateKey(suite, blob, []byte("Correct horse battery staple"))
+	require.True(t, errors.Is(err, ErrDecryption))
+}
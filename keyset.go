@@ -0,0 +1,200 @@
+package ps
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// A KeySet holds the issuer keys of a verification service by KeyID, each
+// with its Verifier, so that a presentation naming its key costs one map
+// lookup rather than a parse and a precomputation. Lookups share a read
+// lock. With a bound on its size the set evicts the least recently used key
+// when adding another; recency is kept by a counter stamped on each entry
+// when it is resolved, so that lookups need no write lock, and eviction
+// scans the set for the oldest stamp.
+
+// keySetEntry is a key of a KeySet with its verifier. used is accessed
+// atomically and comes first for 64-bit alignment.
+type keySetEntry struct {
+	used        uint64
+	pub         *PublicKey
+	fingerprint []byte
+	verifier    *Verifier
+}
+
+// KeySet is a set of public keys with precomputed verifiers, safe for
+// concurrent use by multiple goroutines.
+type KeySet struct {
+	clock      uint64
+	mu         sync.RWMutex
+	keys       map[string]*keySetEntry
+	maxEntries int
+	opts       []Option
+	ob         observer
+}
+
+// NewKeySet returns an empty key set holding at most maxEntries keys, or
+// any number if maxEntries is 0 or less. The options, e.g. WithDST, apply to
+// every verification with the keys of the set.
+func NewKeySet(maxEntries int, opts ...Option) *KeySet {
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &KeySet{keys: make(map[string]*keySetEntry), maxEntries: maxEntries, opts: opts, ob: observer{o.inst}}
+}
+
+// Add validates pub, see ValidatePublicKey, builds its Verifier and adds it
+// to the set, returning its KeyID. Adding a key already in the set only
+// marks it as used; a different key with the same KeyID gives ErrKeyExists.
+func (ks *KeySet) Add(pub *PublicKey) (string, error) {
+	if err := pub.check(); err != nil {
+		return "", err
+	}
+	id, err := pub.KeyID()
+	if err != nil {
+		return "", err
+	}
+	fp, err := pub.Fingerprint()
+	if err != nil {
+		return "", err
+	}
+	ks.mu.RLock()
+	e, ok := ks.keys[id]
+	ks.mu.RUnlock()
+	if ok {
+		if !bytes.Equal(e.fingerprint, fp) {
+			return "", fmt.Errorf("%w: key ID %q", ErrKeyExists, id)
+		}
+		ks.touch(e)
+		return id, nil
+	}
+	v, err := NewVerifier(pub.suite, pub, ks.opts...)
+	if err != nil {
+		return "", err
+	}
+	e = &keySetEntry{pub: pub, fingerprint: fp, verifier: v}
+	ks.touch(e)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if old, ok := ks.keys[id]; ok {
+		if !bytes.Equal(old.fingerprint, fp) {
+			return "", fmt.Errorf("%w: key ID %q", ErrKeyExists, id)
+		}
+		return id, nil
+	}
+	if ks.maxEntries > 0 && len(ks.keys) >= ks.maxEntries {
+		ks.evict()
+	}
+	ks.keys[id] = e
+	return id, nil
+}
+
+// evict removes the least recently used key. The caller holds the write
+// lock.
+func (ks *KeySet) evict() {
+	var oldest string
+	min := ^uint64(0)
+	for id, e := range ks.keys {
+		if used := atomic.LoadUint64(&e.used); used <= min {
+			oldest, min = id, used
+		}
+	}
+	delete(ks.keys, oldest)
+}
+
+// touch marks e as the most recently used entry.
+func (ks *KeySet) touch(e *keySetEntry) {
+	atomic.StoreUint64(&e.used, atomic.AddUint64(&ks.clock, 1))
+}
+
+// Remove removes the key with the given ID from the set. It returns
+// ErrKeyNotFound if there is none.
+func (ks *KeySet) Remove(id string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if _, ok := ks.keys[id]; !ok {
+		return fmt.Errorf("%w: key ID %q", ErrKeyNotFound, id)
+	}
+	delete(ks.keys, id)
+	return nil
+}
+
+// Len returns the number of keys in the set.
+func (ks *KeySet) Len() int {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return len(ks.keys)
+}
+
+// lookup returns the entry of the key with the given ID and marks it as
+// used.
+func (ks *KeySet) lookup(id string) (*keySetEntry, error) {
+	ks.mu.RLock()
+	e, ok := ks.keys[id]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: key ID %q", ErrKeyNotFound, id)
+	}
+	ks.touch(e)
+	return e, nil
+}
+
+// Resolve returns the verifier of the key with the given ID, or
+// ErrKeyNotFound.
+func (ks *KeySet) Resolve(id string) (*Verifier, error) {
+	e, err := ks.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.verifier, nil
+}
+
+// PublicKey returns the key with the given ID, or ErrKeyNotFound.
+func (ks *KeySet) PublicKey(id string) (*PublicKey, error) {
+	e, err := ks.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.pub, nil
+}
+
+// VerifyPartial checks proof, as the free function VerifyPartial does, under
+// the key of the set it names, which it returns. Proofs without a key ID
+// give ErrKeyNotFound.
+func (ks *KeySet) VerifyPartial(revealed map[int][]byte, proof *SignatureProof, nonce []byte) (*PublicKey, error) {
+	start := ks.ob.start()
+	pub, err := ks.verifyPartial(revealed, proof, nonce)
+	attrs := 0
+	if proof != nil {
+		attrs = proof.Messages
+	}
+	ks.ob.verified(start, err, attrs)
+	return pub, err
+}
+
+func (ks *KeySet) verifyPartial(revealed map[int][]byte, proof *SignatureProof, nonce []byte) (*PublicKey, error) {
+	if err := proof.check(); err != nil {
+		return nil, err
+	}
+	e, err := ks.lookup(proof.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyPartial(e.pub.suite, e.pub, revealed, proof, nonce, ks.opts...); err != nil {
+		return nil, err
+	}
+	return e.pub, nil
+}
+
+// VerifySignatureProof is VerifyPartial, under the name of the show
+// protocol.
+func (ks *KeySet) VerifySignatureProof(proof *SignatureProof, disclosed map[int][]byte, nonce []byte) (*PublicKey, error) {
+	return ks.VerifyPartial(disclosed, proof, nonce)
+}
@@ -0,0 +1,168 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestKeySet(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		_, other := newTestKeys(t, suite, 2)
+		ks := NewKeySet(0)
+		id, err := ks.Add(pub)
+		require.Nil(t, err)
+		again, err := ks.Add(pub)
+		require.Nil(t, err)
+		require.Equal(t, id, again)
+		otherID, err := ks.Add(other)
+		require.Nil(t, err)
+		require.Equal(t, 2, ks.Len())
+
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		S, err := priv.BatchSign(msgs)
+		require.Nil(t, err)
+		v, err := ks.Resolve(id)
+		require.Nil(t, err)
+		require.Nil(t, v.BatchVerify(msgs, S))
+		v, err = ks.Resolve(otherID)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, v.BatchVerify(msgs, S))
+
+		require.Nil(t, ks.Remove(otherID))
+		_, err = ks.Resolve(otherID)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		require.True(t, errors.Is(ks.Remove(otherID), ErrKeyNotFound))
+
+		points := pub.Points()
+		points[1] = suite.G2().Point().Null()
+		weak, err := NewPublicKey(suite, points)
+		require.Nil(t, err)
+		_, err = ks.Add(weak)
+		require.True(t, errors.Is(err, ErrWeakPublicKey))
+	})
+}
+
+func TestKeySetEviction(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	ks := NewKeySet(2)
+	ids := make([]string, 3)
+	for i := range ids {
+		_, pub := newTestKeys(t, suite, 1)
+		var err error
+		ids[i], err = ks.Add(pub)
+		require.Nil(t, err)
+		if i == 1 {
+			// Using the first key makes the second the least recently
+			// used.
+			_, err = ks.Resolve(ids[0])
+			require.Nil(t, err)
+		}
+	}
+	require.Equal(t, 2, ks.Len())
+	_, err := ks.Resolve(ids[0])
+	require.Nil(t, err)
+	_, err = ks.Resolve(ids[1])
+	require.True(t, errors.Is(err, ErrKeyNotFound))
+	_, err = ks.Resolve(ids[2])
+	require.Nil(t, err)
+}
+
+func TestKeySetOpen(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 3)
+		msgs := [][]byte{[]byte("alice"), []byte("paris"), []byte("admin")}
+		ks := NewKeySet(0)
+
+		e, err := Seal(priv, msgs, time.Time{})
+		require.Nil(t, err)
+		_, err = e.OpenWith(ks, msgs)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		_, err = ks.Add(pub)
+		require.Nil(t, err)
+		key, err := e.OpenWith(ks, msgs)
+		require.Nil(t, err)
+		require.True(t, key == pub)
+		_, err = e.OpenWith(ks, [][]byte{msgs[1], msgs[0], msgs[2]})
+		require.Equal(t, ErrInvalidSignature, err)
+		_, err = e.OpenWith(ks, msgs[:2])
+		require.True(t, errors.Is(err, ErrInvalidEnvelope))
+
+		S, err := priv.BatchSign(msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("nonce")
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{0: true}, nonce)
+		require.Nil(t, err)
+		key, err = ks.VerifySignatureProof(proof, map[int][]byte{0: msgs[0]}, nonce)
+		require.Nil(t, err)
+		require.True(t, key == pub)
+		_, err = ks.VerifyPartial(map[int][]byte{0: msgs[1]}, proof, nonce)
+		require.Equal(t, ErrInvalidSignature, err)
+
+		proof.KeyID = ""
+		_, err = ks.VerifySignatureProof(proof, map[int][]byte{0: msgs[0]}, nonce)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+}
+
+// TestKeySetConcurrent exercises the set from many goroutines; run it with
+// -race.
+func TestKeySetConcurrent(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	const n = 6
+	pubs := make([]*PublicKey, n)
+	ids := make([]string, n)
+	for i := range pubs {
+		_, pubs[i] = newTestKeys(t, suite, 1)
+		var err error
+		ids[i], err = pubs[i].KeyID()
+		require.Nil(t, err)
+	}
+	ks := NewKeySet(n / 2)
+	var wg sync.WaitGroup
+	errs := make(chan error, 3*n)
+	for g := 0; g < n; g++ {
+		wg.Add(3)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if _, err := ks.Add(pubs[(g+i)%n]); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := ks.Resolve(ids[(g+i)%n]); err != nil && !errors.Is(err, ErrKeyNotFound) {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if err := ks.Remove(ids[(g*i)%n]); err != nil && !errors.Is(err, ErrKeyNotFound) {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+	require.True(t, ks.Len() <= n/2, fmt.Sprint(ks.Len()))
+}
@@ -0,0 +1,282 @@
+package ps
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A FileKeystore keeps private keys in a directory, one key file per key,
+// all encrypted under the passphrase of the keystore as by
+// EncryptPrivateKey. The file of a key is named
+//
+//	<name>.<id>.pskf
+//
+// where id is the KeyID of its public key, so that keys can be listed
+// without decrypting them. Files are written to a temporary file in the
+// same directory, synced and renamed into place, so that a crash leaves
+// either the complete file or none; temporary files left behind are
+// skipped by List and removed by NewFileKeystore. The name is not
+// authenticated, but the id is checked against the key on Get.
+//
+// Methods are safe for concurrent use within one process. Several
+// processes sharing a directory must coordinate themselves.
+
+const (
+	keystoreExt      = ".pskf"
+	keystoreTemp     = ".tmp-"
+	maxKeyNameLen    = 64
+	keystoreFileMode = 0600
+)
+
+var (
+	// ErrKeyNotFound is returned for names not in a keystore.
+	ErrKeyNotFound = errors.New("ps: key not found")
+
+	// ErrKeyExists is returned when storing a key under a name in use.
+	ErrKeyExists = errors.New("ps: key already exists")
+
+	// ErrInvalidKeyName is returned for key names that are empty, longer
+	// than 64 bytes or hold characters other than ASCII letters, digits,
+	// '-' and '_'.
+	ErrInvalidKeyName = errors.New("ps: invalid key name")
+)
+
+// KeyInfo describes a key held by a FileKeystore.
+type KeyInfo struct {
+	Name string
+	ID   string
+}
+
+// FileKeystore is a directory of encrypted private keys.
+type FileKeystore struct {
+	mu         sync.Mutex
+	dir        string
+	passphrase []byte
+	opts       []Option
+}
+
+// NewFileKeystore opens the keystore in dir, creating the directory if
+// needed, with the passphrase its keys are encrypted under. The options,
+// such as WithKDFParams, apply to the keys stored by Put.
+func NewFileKeystore(dir string, passphrase []byte, opts ...Option) (*FileKeystore, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	leftovers, err := filepath.Glob(filepath.Join(dir, keystoreTemp+"*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range leftovers {
+		if err := os.Remove(f); err != nil {
+			return nil, err
+		}
+	}
+	return &FileKeystore{
+		dir:        dir,
+		passphrase: append([]byte{}, passphrase...),
+		opts:       opts,
+	}, nil
+}
+
+// checkKeyName reports whether name may name a key.
+func checkKeyName(name string) error {
+	if name == "" || len(name) > maxKeyNameLen {
+		return fmt.Errorf("%w: %q", ErrInvalidKeyName, name)
+	}
+	for _, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_':
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidKeyName, name)
+		}
+	}
+	return nil
+}
+
+// Put encrypts priv and stores it under name, returning its KeyID. Put does
+// not replace keys: a name in use gives ErrKeyExists.
+func (s *FileKeystore) Put(name string, priv *PrivateKey) (string, error) {
+	if err := checkKeyName(name); err != nil {
+		return "", err
+	}
+	if err := priv.check(); err != nil {
+		return "", err
+	}
+	id, err := priv.Public().KeyID()
+	if err != nil {
+		return "", err
+	}
+	data, err := EncryptPrivateKey(priv, s.passphrase, s.opts...)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.find(name); err == nil {
+		return "", fmt.Errorf("%w: %q", ErrKeyExists, name)
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return "", err
+	}
+	if err := s.writeFile(name+"."+id+keystoreExt, data); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// writeFile writes data to the file name in the keystore atomically.
+func (s *FileKeystore) writeFile(name string, data []byte) error {
+	tmp, err := ioutil.TempFile(s.dir, keystoreTemp+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(keystoreFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// syncDir flushes the entries of dir, making a rename into it durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Get decrypts the key stored under name.
+func (s *FileKeystore) Get(name string) (*PrivateKey, error) {
+	if err := checkKeyName(name); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.find(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, info.file()))
+	if err != nil {
+		return nil, err
+	}
+	plain, err := openKeyFile(data, s.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", name, err)
+	}
+	defer zeroBytes(plain)
+	// The suite is resolved from the registry.
+	priv := new(PrivateKey)
+	if err := priv.UnmarshalBinary(plain); err != nil {
+		return nil, err
+	}
+	id, err := priv.Public().KeyID()
+	if err != nil {
+		return nil, err
+	}
+	if id != info.ID {
+		return nil, fmt.Errorf("%w: key %q has ID %s, stored as %s", ErrDecryption, name, id, info.ID)
+	}
+	return priv, nil
+}
+
+// List returns the keys of the keystore sorted by name.
+func (s *FileKeystore) List() ([]KeyInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+// Delete removes the key stored under name.
+func (s *FileKeystore) Delete(name string) error {
+	if err := checkKeyName(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.find(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, info.file())); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// file returns the name of the key file of the key.
+func (k KeyInfo) file() string {
+	return k.Name + "." + k.ID + keystoreExt
+}
+
+// find returns the key stored under name.
+func (s *FileKeystore) find(name string) (KeyInfo, error) {
+	keys, err := s.list()
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	for _, k := range keys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return KeyInfo{}, fmt.Errorf("%w: %q", ErrKeyNotFound, name)
+}
+
+// list returns the keys whose files are in the directory, skipping
+// temporary and foreign files.
+func (s *FileKeystore) list() ([]KeyInfo, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []KeyInfo
+	for _, e := range entries {
+		base := e.Name()
+		if !e.Mode().IsRegular() || !strings.HasSuffix(base, keystoreExt) {
+			continue
+		}
+		base = strings.TrimSuffix(base, keystoreExt)
+		dot := strings.LastIndexByte(base, '.')
+		if dot < 0 {
+			continue
+		}
+		k := KeyInfo{Name: base[:dot], ID: base[dot+1:]}
+		if checkKeyName(k.Name) != nil || len(k.ID) != 2*keyIDLen {
+			continue
+		}
+		if _, err := hex.DecodeString(k.ID); err != nil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
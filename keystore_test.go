@@ -0,0 +1,138 @@
+package ps
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestFileKeystore(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		dir := t.TempDir()
+		ks, err := NewFileKeystore(dir, []byte("passphrase"), testKDFParams)
+		require.Nil(t, err)
+		keys, err := ks.List()
+		require.Nil(t, err)
+		require.Empty(t, keys)
+
+		issuer, pub := newTestKeys(t, suite, 2)
+		id, err := ks.Put("issuer", issuer)
+		require.Nil(t, err)
+		want, err := pub.KeyID()
+		require.Nil(t, err)
+		require.Equal(t, want, id)
+		_, err = ks.Put("issuer", issuer)
+		require.True(t, errors.Is(err, ErrKeyExists))
+		other, _ := newTestKeys(t, suite, 1)
+		otherID, err := ks.Put("backup-2", other)
+		require.Nil(t, err)
+
+		keys, err = ks.List()
+		require.Nil(t, err)
+		require.Equal(t, []KeyInfo{{Name: "backup-2", ID: otherID}, {Name: "issuer", ID: id}}, keys)
+
+		// A reopened keystore finds the key, which signs.
+		ks, err = NewFileKeystore(dir, []byte("passphrase"))
+		require.Nil(t, err)
+		priv, err := ks.Get("issuer")
+		require.Nil(t, err)
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		S, err := priv.BatchSign(msgs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		require.Nil(t, ks.Delete("backup-2"))
+		_, err = ks.Get("backup-2")
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		require.True(t, errors.Is(ks.Delete("backup-2"), ErrKeyNotFound))
+		keys, err = ks.List()
+		require.Nil(t, err)
+		require.Equal(t, []KeyInfo{{Name: "issuer", ID: id}}, keys)
+
+		wrong, err := NewFileKeystore(dir, []byte("passphrase!"))
+		require.Nil(t, err)
+		_, err = wrong.Get("issuer")
+		require.True(t, errors.Is(err, ErrDecryption))
+
+		for _, bad := range []string{"", "../issuer", "a.b", string(make([]byte, maxKeyNameLen+1))} {
+			_, err = ks.Put(bad, issuer)
+			require.True(t, errors.Is(err, ErrInvalidKeyName), "%q", bad)
+		}
+	})
+}
+
+func TestFileKeystoreCrashSafety(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	dir := t.TempDir()
+	ks, err := NewFileKeystore(dir, []byte("passphrase"), testKDFParams)
+	require.Nil(t, err)
+	priv, _ := newTestKeys(t, suite, 1)
+	id, err := ks.Put("issuer", priv)
+	require.Nil(t, err)
+
+	// A crash during Put leaves a partial temporary file, never a partial
+	// key file.
+	partial := filepath.Join(dir, keystoreTemp+"123")
+	require.Nil(t, ioutil.WriteFile(partial, []byte("PSKF\x01"), 0600))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0600))
+	keys, err := ks.List()
+	require.Nil(t, err)
+	require.Equal(t, []KeyInfo{{Name: "issuer", ID: id}}, keys)
+	_, err = ks.Get("issuer")
+	require.Nil(t, err)
+
+	// Reopening cleans it up and leaves other files alone.
+	_, err = NewFileKeystore(dir, []byte("passphrase"))
+	require.Nil(t, err)
+	_, err = os.Stat(partial)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "README"))
+	require.Nil(t, err)
+
+	// A key file moved under another key's ID is refused.
+	other, _ := newTestKeys(t, suite, 1)
+	otherID, err := ks.Put("other", other)
+	require.Nil(t, err)
+	require.Nil(t, os.Rename(
+		filepath.Join(dir, "other."+otherID+keystoreExt),
+		filepath.Join(dir, "other."+id+keystoreExt)))
+	_, err = ks.Get("other")
+	require.True(t, errors.Is(err, ErrDecryption))
+}
+
+func TestFileKeystoreConcurrent(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	ks, err := NewFileKeystore(t.TempDir(), []byte("passphrase"), testKDFParams)
+	require.Nil(t, err)
+	priv, _ := newTestKeys(t, suite, 1)
+
+	// Of several concurrent Puts under one name exactly one succeeds.
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = ks.Put("issuer", priv)
+			if errs[i] == nil {
+				_, errs[i] = ks.Get("issuer")
+			}
+		}(i)
+	}
+	wg.Wait()
+	stored := 0
+	for _, err := range errs {
+		if err == nil {
+			stored++
+		} else {
+			require.True(t, errors.Is(err, ErrKeyExists), "%v", err)
+		}
+	}
+	require.Equal(t, 1, stored)
+}
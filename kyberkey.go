@@ -0,0 +1,124 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// PS keys are vectors while kyber's util/key package deals in single
+// key.Pair values. The adapters below let PS keys pass through tooling built
+// around that package: a PS key is exported as a slice of pairs whose first
+// element is the X component, (x, g2^x), and is what such tooling sees as
+// "the" key. The Y components ride along as extension entries
+// (y_i, g2^(y_i)) after it and are only meaningful to ImportKeyPairs; tools
+// that keep just the first pair lose them.
+
+// KeyGenerator generates the X component of a PS key through key.NewKeyPair.
+// It implements key.Suite over the group G2 and key.Generator.
+type KeyGenerator struct {
+	kyber.Group
+	suite pairing.Suite
+}
+
+// NewKeyGenerator returns a KeyGenerator for suite.
+func NewKeyGenerator(suite pairing.Suite) (*KeyGenerator, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	return &KeyGenerator{Group: suite.G2(), suite: suite}, nil
+}
+
+// RandomStream returns the random stream of the pairing suite.
+func (g *KeyGenerator) RandomStream() cipher.Stream {
+	return g.suite.RandomStream()
+}
+
+// NewKey picks a secret scalar the way NewKeyPair does.
+func (g *KeyGenerator) NewKey(random cipher.Stream) kyber.Scalar {
+	return newScalar(g.suite).Pick(random)
+}
+
+var (
+	_ key.Suite     = (*KeyGenerator)(nil)
+	_ key.Generator = (*KeyGenerator)(nil)
+)
+
+// ExportKeyPairs converts a private key to key pairs, the X pair first and
+// then one pair per Y component.
+func ExportKeyPairs(priv *PrivateKey) ([]*key.Pair, error) {
+	if err := priv.check(); err != nil {
+		return nil, err
+	}
+	pub := priv.Public()
+	scalars, points := priv.Scalars(), pub.Points()
+	pairs := make([]*key.Pair, len(scalars))
+	for i := range pairs {
+		pairs[i] = &key.Pair{Public: points[i], Private: scalars[i]}
+	}
+	return pairs, nil
+}
+
+// ExportPublicKeyPairs converts a public key to key pairs without private
+// parts, in the layout of ExportKeyPairs.
+func ExportPublicKeyPairs(pub *PublicKey) ([]*key.Pair, error) {
+	if err := pub.check(); err != nil {
+		return nil, err
+	}
+	points := pub.Points()
+	pairs := make([]*key.Pair, len(points))
+	for i := range pairs {
+		pairs[i] = &key.Pair{Public: points[i]}
+	}
+	return pairs, nil
+}
+
+// ImportKeyPairs rebuilds a PS key under suite from the output of
+// ExportKeyPairs or ExportPublicKeyPairs. The private key is nil when the
+// pairs carry no private parts; otherwise every public part must match its
+// private part.
+func ImportKeyPairs(suite pairing.Suite, pairs []*key.Pair) (*PrivateKey, *PublicKey, error) {
+	if suite == nil {
+		return nil, nil, ErrNilSuite
+	}
+	if len(pairs) < 2 {
+		return nil, nil, fmt.Errorf("ps: need at least two key pairs, got %d", len(pairs))
+	}
+	var scalars []kyber.Scalar
+	points := make([]kyber.Point, len(pairs))
+	for i, p := range pairs {
+		if p == nil || p.Public == nil {
+			return nil, nil, fmt.Errorf("ps: key pair %d has no public part", i)
+		}
+		points[i] = p.Public
+		if (p.Private == nil) != (pairs[0].Private == nil) {
+			return nil, nil, errors.New("ps: key pairs mix public and private entries")
+		}
+		if p.Private == nil {
+			continue
+		}
+		if !suite.G2().Point().Mul(p.Private, nil).Equal(p.Public) {
+			return nil, nil, fmt.Errorf("ps: key pair %d is inconsistent", i)
+		}
+		scalars = append(scalars, p.Private)
+	}
+	pub, err := NewPublicKey(suite, points)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scalars == nil {
+		return nil, pub, nil
+	}
+	priv, err := NewPrivateKey(suite, scalars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv, pub, nil
+}
@@ -0,0 +1,73 @@
+package ps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// checkKeyPair runs the checks applied to any kyber key pair: the public part
+// is the base point raised to the private part and both survive marshalling.
+func checkKeyPair(t *testing.T, suite key.Suite, p *key.Pair) {
+	require.True(t, suite.Point().Mul(p.Private, nil).Equal(p.Public))
+
+	buf, err := p.Private.MarshalBinary()
+	require.Nil(t, err)
+	s := suite.Scalar()
+	require.Nil(t, s.UnmarshalBinary(buf))
+	require.True(t, s.Equal(p.Private))
+
+	buf, err = p.Public.MarshalBinary()
+	require.Nil(t, err)
+	q := suite.Point()
+	require.Nil(t, q.UnmarshalBinary(buf))
+	require.True(t, q.Equal(p.Public))
+}
+
+func TestKeyGenerator(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		gen, err := NewKeyGenerator(suite)
+		require.Nil(t, err)
+		p1, p2 := key.NewKeyPair(gen), key.NewKeyPair(gen)
+		checkKeyPair(t, gen, p1)
+		checkKeyPair(t, gen, p2)
+		require.False(t, p1.Private.Equal(p2.Private))
+	})
+}
+
+func TestExportImportKeyPairs(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		pairs, err := ExportKeyPairs(priv)
+		require.Nil(t, err)
+		require.Equal(t, 3, len(pairs))
+		gen, err := NewKeyGenerator(suite)
+		require.Nil(t, err)
+		for _, p := range pairs {
+			checkKeyPair(t, gen, p)
+		}
+		require.True(t, pairs[0].Public.Equal(pub.X))
+
+		priv2, pub2, err := ImportKeyPairs(suite, pairs)
+		require.Nil(t, err)
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		S, err := BatchSign(suite, priv2.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub2.Points(), msgs, S))
+
+		pubPairs, err := ExportPublicKeyPairs(pub)
+		require.Nil(t, err)
+		priv3, pub3, err := ImportKeyPairs(suite, pubPairs)
+		require.Nil(t, err)
+		require.Nil(t, priv3)
+		require.Nil(t, PSBatchVerify(suite, pub3.Points(), msgs, S))
+
+		pairs[1].Public, pairs[2].Public = pairs[2].Public, pairs[1].Public
+		_, _, err = ImportKeyPairs(suite, pairs)
+		require.NotNil(t, err)
+		_, _, err = ImportKeyPairs(suite, pairs[:1])
+		require.NotNil(t, err)
+	})
+}
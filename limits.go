@@ -0,0 +1,75 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Signing and verifying cost grows linearly with the number of messages, and
+// batch verification with the number of signatures. A service that takes the
+// messages or the batch from a request would otherwise do as much work as
+// the request asks for, so both are capped: by DefaultMaxAttributes and
+// DefaultMaxBatchItems unless a Config raises or lowers the caps with
+// WithConfig. A Verifier or PairingAccumulator keeps the Config it was
+// created with.
+
+// ErrLimitExceeded is returned when an input holds more messages or batch
+// items than the configured limits allow.
+var ErrLimitExceeded = errors.New("ps: limit exceeded")
+
+const (
+	// DefaultMaxAttributes is the most messages signed or verified together
+	// unless a Config sets MaxAttributes.
+	DefaultMaxAttributes = 1024
+
+	// DefaultMaxBatchItems is the most signatures or statements checked by
+	// one bulk verification unless a Config sets MaxBatchItems.
+	DefaultMaxBatchItems = 4096
+)
+
+// Config holds the limits enforced by BatchSign, PSBatchVerify, VerifyBatch,
+// VerifyAll, VerifyMany, the Verifier and the PairingAccumulator. A zero
+// field keeps the default, and a negative one removes the limit.
+type Config struct {
+	// MaxAttributes is the most messages in one signature.
+	MaxAttributes int
+	// MaxBatchItems is the most signatures checked by one bulk
+	// verification, or statements held by a PairingAccumulator.
+	MaxBatchItems int
+}
+
+// WithConfig sets the limits applied to the call, or to the Verifier or
+// PairingAccumulator being created.
+func WithConfig(c Config) Option {
+	return func(o *options) {
+		o.config = c
+	}
+}
+
+// checkAttributes enforces MaxAttributes on n messages.
+func (o *options) checkAttributes(n int) error {
+	if max := limit(o.config.MaxAttributes, DefaultMaxAttributes); max >= 0 && n > max {
+		return fmt.Errorf("%w: %d messages, at most %d", ErrLimitExceeded, n, max)
+	}
+	return nil
+}
+
+// checkBatchItems enforces MaxBatchItems on n items.
+func (o *options) checkBatchItems(n int) error {
+	if max := limit(o.config.MaxBatchItems, DefaultMaxBatchItems); max >= 0 && n > max {
+		return fmt.Errorf("%w: %d batch items, at most %d", ErrLimitExceeded, n, max)
+	}
+	return nil
+}
+
+// limit returns the limit set in a Config field, def if it is zero, or -1 if
+// it is negative.
+func limit(set, def int) int {
+	switch {
+	case set == 0:
+		return def
+	case set < 0:
+		return -1
+	}
+	return set
+}
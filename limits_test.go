@@ -0,0 +1,100 @@
+package ps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestAttributeLimit(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := make([][]byte, DefaultMaxAttributes+1)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		// One y for every slot keeps the public key cheap to derive.
+		key := testScalarKey(t, suite, 1)
+		for len(key) <= len(msgs) {
+			key = append(key, key[1])
+		}
+		_, err := BatchSign(suite, key, msgs)
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+
+		raised := WithConfig(Config{MaxAttributes: len(msgs)})
+		S, err := BatchSign(suite, key, msgs, raised)
+		require.Nil(t, err)
+		pub := derivePublicKey(suite, key[:2], 1)
+		for len(pub) < len(key) {
+			pub = append(pub, pub[1])
+		}
+		require.True(t, errors.Is(PSBatchVerify(suite, pub, msgs, S), ErrLimitExceeded))
+		require.Nil(t, PSBatchVerify(suite, pub, msgs, S, raised))
+
+		// A lowered limit applies to a Verifier for good.
+		_, pk := newTestKeys(t, suite, 3)
+		v, err := NewVerifier(suite, pk, WithConfig(Config{MaxAttributes: 2}))
+		require.Nil(t, err)
+		err = v.BatchVerify(msgs[:3], S)
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Contains(t, err.Error(), "3 messages, at most 2")
+	})
+}
+
+func TestBatchItemLimit(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		msgs, sigs := signBatch(t, suite, priv, 9)
+
+		// The default cap is checked before any signature is.
+		many := make([]*Signature, DefaultMaxBatchItems+1)
+		manyMsgs := make([][]byte, len(many))
+		for i := range many {
+			many[i], manyMsgs[i] = sigs[0], msgs[0]
+		}
+		err := VerifyBatch(suite, pub.Points(), manyMsgs, many, random.New())
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		_, err = VerifyMany(suite, make([]*PublicKey, len(many)), msgs[:1], many)
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		_, err = VerifyAll(context.Background(), suite, pub.Points(), make([]VerifyItem, len(many)), 1)
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+
+		lowered := WithConfig(Config{MaxBatchItems: 8})
+		err = VerifyBatch(suite, pub.Points(), msgs, sigs, random.New(), lowered)
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Nil(t, VerifyBatch(suite, pub.Points(), msgs, sigs, random.New(), WithConfig(Config{MaxBatchItems: 9})))
+
+		acc, err := NewAccumulator(suite, random.New(), WithConfig(Config{MaxBatchItems: 2}))
+		require.Nil(t, err)
+		for i := 0; i < 3; i++ {
+			S, err := sigs[i].Components()
+			require.Nil(t, err)
+			err = acc.AddVerify(pub.Points(), msgs[i], S)
+			if i < 2 {
+				require.Nil(t, err)
+			} else {
+				require.True(t, errors.Is(err, ErrLimitExceeded))
+			}
+		}
+		ok, _ := acc.Flush()
+		require.True(t, ok)
+		S, err := sigs[2].Components()
+		require.Nil(t, err)
+		require.Nil(t, acc.AddVerify(pub.Points(), msgs[2], S))
+	})
+}
+
+func TestLimitDefaults(t *testing.T) {
+	o := &options{}
+	require.Nil(t, o.checkAttributes(DefaultMaxAttributes))
+	require.NotNil(t, o.checkAttributes(DefaultMaxAttributes+1))
+	require.Nil(t, o.checkBatchItems(DefaultMaxBatchItems))
+	require.NotNil(t, o.checkBatchItems(DefaultMaxBatchItems+1))
+	o.config = Config{MaxAttributes: -1, MaxBatchItems: -1}
+	require.Nil(t, o.checkAttributes(1<<30))
+	require.Nil(t, o.checkBatchItems(1<<30))
+}
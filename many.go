@@ -0,0 +1,125 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// VerifyMany checks signatures on one set of messages made by several
+// signers, such as the members of a committee: sigs[i] is checked on msgs
+// under pubKeys[i] as PSBatchVerify does. The messages are hashed once, and
+// X * Y_1^(m_1) * ... * Y_k^(m_k) is computed once per distinct *PublicKey:
+// pass the same pointer for repeated signers. When all signatures share one
+// key, they are first checked together with a random linear combination, two
+// pairings for the whole set, as VerifyBatch does.
+//
+// The first result holds the outcome of every signature by index, nil for
+// valid ones. The second reports invalid arguments other than signatures, or
+// joins a *BatchError per invalid signature, if any.
+func VerifyMany(suite pairing.Suite, pubKeys []*PublicKey, msgs [][]byte, sigs []*Signature, opts ...Option) ([]error, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	ob := observer{o.inst}
+	start := ob.start()
+	errs, err := verifyMany(suite, pubKeys, msgs, sigs, o)
+	ob.verified(start, err, len(msgs))
+	return errs, err
+}
+
+// verifyMany implements VerifyMany.
+func verifyMany(suite pairing.Suite, pubKeys []*PublicKey, msgs [][]byte, sigs []*Signature, o *options) ([]error, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if len(pubKeys) != len(sigs) {
+		return nil, fmt.Errorf("%w: %d keys for %d signatures", ErrBatchLengthMismatch, len(pubKeys), len(sigs))
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, err
+	}
+	if err := o.checkBatchItems(len(sigs)); err != nil {
+		return nil, err
+	}
+	for i, k := range pubKeys {
+		if err := k.check(); err != nil {
+			return nil, fmt.Errorf("%w: key %d", err, i)
+		}
+		if err := checkMessageCount(len(k.Y)+1, len(msgs)); err != nil {
+			return nil, fmt.Errorf("%w: key %d", err, i)
+		}
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+
+	// accumulated[i] is the index in points of the key of sigs[i].
+	var points []kyber.Point
+	seen := make(map[*PublicKey]int)
+	accumulated := make([]int, len(pubKeys))
+	for i, k := range pubKeys {
+		j, ok := seen[k]
+		if !ok {
+			Y, err := multiScalarMul(suite.G2(), m, k.Y[:len(m)])
+			if err != nil {
+				return nil, err
+			}
+			j = len(points)
+			points = append(points, suite.G2().Point().Add(Y, k.X))
+			seen[k] = j
+		}
+		accumulated[i] = j
+	}
+
+	errs := make([]error, len(sigs))
+	valid := make([]int, 0, len(sigs))
+	for i, sig := range sigs {
+		switch {
+		case sig.check() != nil:
+			errs[i] = ErrNilSignature
+		case !batchItemValid(suite, sig, o.validatePoints):
+			errs[i] = ErrInvalidSignature
+		default:
+			valid = append(valid, i)
+		}
+	}
+	g := suite.G2().Point().Base()
+	if len(points) == 1 && len(valid) > 1 {
+		ok, err := manyHold(suite, points[0], g, sigs, valid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return errs, summarize("verify signature", errs)
+		}
+	}
+	for _, i := range valid {
+		errs[i] = verifySignature(suite, points[accumulated[i]], g, sigs[i])
+	}
+	return errs, summarize("verify signature", errs)
+}
+
+// manyHold checks the signatures of sigs at indices under the accumulated key
+// X at once: e(sum r_i*sigma_(1,i), X) = e(sum r_i*sigma_(2,i), g) for fresh
+// coefficients r_i.
+func manyHold(suite pairing.Suite, X, g kyber.Point, sigs []*Signature, indices []int) (bool, error) {
+	rand := suite.RandomStream()
+	g1 := suite.G1()
+	combined := &Signature{suite: suite, Sigma1: g1.Point().Null(), Sigma2: g1.Point().Null()}
+	for _, i := range indices {
+		r, err := pickScalar(suite, rand)
+		if err != nil {
+			return false, err
+		}
+		combined.Sigma1.Add(combined.Sigma1, g1.Point().Mul(r, sigs[i].Sigma1))
+		combined.Sigma2.Add(combined.Sigma2, g1.Point().Mul(r, sigs[i].Sigma2))
+	}
+	return verifySignature(suite, X, g, combined) == nil, nil
+}
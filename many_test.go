@@ -0,0 +1,126 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// manySignatures signs msgs once under each of keys.
+func manySignatures(t testing.TB, suite pairing.Suite, keys []*PrivateKey, msgs [][]byte) []*Signature {
+	sigs := make([]*Signature, len(keys))
+	for i, k := range keys {
+		S, err := BatchSign(suite, k.Scalars(), msgs)
+		require.Nil(t, err)
+		sigs[i], err = NewSignature(suite, S)
+		require.Nil(t, err)
+	}
+	return sigs
+}
+
+func TestVerifyManySameKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("block 7"), []byte("root 0xabc")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		privs := []*PrivateKey{priv, priv, priv, priv, priv}
+		pubs := []*PublicKey{pub, pub, pub, pub, pub}
+		sigs := manySignatures(t, suite, privs, msgs)
+
+		errs, err := VerifyMany(suite, pubs, msgs, sigs)
+		require.Nil(t, err)
+		require.Equal(t, make([]error, len(sigs)), errs)
+
+		// A signature on other messages is singled out.
+		other := manySignatures(t, suite, privs[:1], [][]byte{[]byte("block 8"), msgs[1]})
+		sigs[3] = other[0]
+		errs, err = VerifyMany(suite, pubs, msgs, sigs)
+		var batchErr *BatchError
+		require.True(t, errors.As(fmt.Errorf("committee: %w", err), &batchErr))
+		require.Equal(t, []int{3}, failedIndices(t, err))
+		for i, e := range errs {
+			if i == 3 {
+				require.Equal(t, ErrInvalidSignature, e)
+			} else {
+				require.Nil(t, e, "signature %d", i)
+			}
+		}
+	})
+}
+
+func TestVerifyManySigners(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("block 7"), []byte("root 0xabc")}
+		var privs []*PrivateKey
+		var pubs []*PublicKey
+		for i := 0; i < 3; i++ {
+			priv, pub := newTestKeys(t, suite, len(msgs))
+			privs, pubs = append(privs, priv), append(pubs, pub)
+		}
+		// The first signer signs twice.
+		privs, pubs = append(privs, privs[0]), append(pubs, pubs[0])
+		sigs := manySignatures(t, suite, privs, msgs)
+
+		errs, err := VerifyMany(suite, pubs, msgs, sigs)
+		require.Nil(t, err)
+		require.Equal(t, make([]error, len(sigs)), errs)
+
+		// Swapped signers, a missing signature and an identity signature.
+		sigs[1], sigs[2] = sigs[2], sigs[1]
+		sigs[3] = nil
+		sigs = append(sigs, &Signature{suite: suite, Sigma1: suite.G1().Point().Null(), Sigma2: suite.G1().Point().Null()})
+		pubs = append(pubs, pubs[0])
+		errs, err = VerifyMany(suite, pubs, msgs, sigs)
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 1, batchErr.Index)
+		require.Equal(t, []int{1, 2, 3, 4}, failedIndices(t, err))
+		require.Equal(t, []error{nil, ErrInvalidSignature, ErrInvalidSignature, ErrNilSignature, ErrInvalidSignature}, errs)
+
+		// Fewer keys than signatures, or a key too short for the messages.
+		_, err = VerifyMany(suite, pubs[:2], msgs, sigs)
+		require.True(t, errors.Is(err, ErrBatchLengthMismatch))
+		_, short := newTestKeys(t, suite, 1)
+		_, err = VerifyMany(suite, []*PublicKey{short}, msgs, sigs[:1])
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Contains(t, err.Error(), "key 0")
+	})
+}
+
+func BenchmarkVerifyMany(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("block 7"), []byte("root 0xabc")}
+		type signers struct {
+			privs []*PrivateKey
+			pubs  []*PublicKey
+		}
+		var shared, distinct signers
+		priv, pub := newTestKeys(b, suite, len(msgs))
+		for i := 0; i < 100; i++ {
+			shared.privs, shared.pubs = append(shared.privs, priv), append(shared.pubs, pub)
+			p, q := newTestKeys(b, suite, len(msgs))
+			distinct.privs, distinct.pubs = append(distinct.privs, p), append(distinct.pubs, q)
+		}
+		for name, set := range map[string]signers{"same key": shared, "distinct keys": distinct} {
+			sigs := manySignatures(b, suite, set.privs, msgs)
+			S := make([][][]byte, len(sigs))
+			for i, sig := range sigs {
+				S[i], _ = sig.Components()
+			}
+			b.Run(fmt.Sprintf("%s/VerifyMany", name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					VerifyMany(suite, set.pubs, msgs, sigs)
+				}
+			})
+			b.Run(fmt.Sprintf("%s/PSBatchVerify", name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for j := range S {
+						PSBatchVerify(suite, set.pubs[j].Points(), msgs, S[j])
+					}
+				}
+			})
+		}
+	})
+}
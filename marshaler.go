@@ -0,0 +1,165 @@
+package ps
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Values of domain types, such as UUIDs, amounts or country codes, are
+// signed as attributes by marshaling them straight to scalars. A type opts
+// in by implementing AttributeMarshaler, usually by passing a canonical
+// encoding of itself to HashAttribute, and string, []byte, int64, int and
+// time.Time are marshaled as if they did. HashAttribute hashes
+//
+//	uint32(len(type)) || type || data
+//
+// under the tag "PS-ATTRIBUTE-<curve>-V1", with type the Go type of the
+// value, including its package path, so that the string "42" and the int64
+// 42 marshal to different scalars even where their encodings agree. Moving
+// or renaming a type therefore changes its scalars. Integers and times are
+// encoded as by AttributeFromInt64 and AttributeFromTime, at the second, and
+// an int marshals as the int64 of the same value.
+//
+// The scalars are signed with SignScalars, and the attributes are shown with
+// ProveAttributes and VerifyPartialAttributes. They are not the scalars the
+// message-based functions hash the same values to.
+
+// AttributeMarshaler is implemented by values that marshal themselves to
+// the scalar they are signed as. MarshalAttribute must be deterministic.
+type AttributeMarshaler interface {
+	MarshalAttribute(suite pairing.Suite) (kyber.Scalar, error)
+}
+
+// HashAttribute returns the scalar of the value v encoded as data, domain
+// separated by the Go type of v.
+func HashAttribute(suite pairing.Suite, v interface{}, data []byte) kyber.Scalar {
+	name := goTypeName(v)
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(name)))
+	msg := append(append(n[:], name...), data...)
+	return hashToScalar(suite, protocolDST(suite, "ATTRIBUTE"), msg)
+}
+
+// goTypeName names the type of v by its package path and name, or by its
+// literal for unnamed types.
+func goTypeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "nil"
+	}
+	if t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// MarshalAttributes returns the scalars of values, each an
+// AttributeMarshaler or one of the built-in types.
+func MarshalAttributes(suite pairing.Suite, values ...interface{}) ([]kyber.Scalar, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	m := make([]kyber.Scalar, len(values))
+	for i, v := range values {
+		s, err := marshalAttribute(suite, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: attribute %d", err, i)
+		}
+		m[i] = s
+	}
+	return m, nil
+}
+
+// marshalAttribute returns the scalar of v.
+func marshalAttribute(suite pairing.Suite, v interface{}) (kyber.Scalar, error) {
+	switch x := v.(type) {
+	case AttributeMarshaler:
+		s, err := x.MarshalAttribute(suite)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, ErrNilMessage
+		}
+		return s, nil
+	case string:
+		return HashAttribute(suite, x, []byte(x)), nil
+	case []byte:
+		return HashAttribute(suite, x, x), nil
+	case int64:
+		return HashAttribute(suite, x, AttributeFromInt64(x)), nil
+	case int:
+		return HashAttribute(suite, int64(x), AttributeFromInt64(int64(x))), nil
+	case time.Time:
+		data, err := AttributeFromTime(x, GranularitySecond)
+		if err != nil {
+			return nil, err
+		}
+		return HashAttribute(suite, x, data), nil
+	}
+	return nil, fmt.Errorf("%w: %T is not an AttributeMarshaler", ErrAttributeType, v)
+}
+
+// SignAttributes signs values, marshaled by MarshalAttributes, as
+// SignScalars does.
+func SignAttributes(suite pairing.Suite, priKey []kyber.Scalar, values []interface{}) ([][]byte, error) {
+	m, err := MarshalAttributes(suite, values...)
+	if err != nil {
+		return nil, err
+	}
+	return SignScalars(suite, priKey, m)
+}
+
+// VerifyAttributes checks a signature S made by SignAttributes on values.
+func VerifyAttributes(suite pairing.Suite, pubKey []kyber.Point, values []interface{}, S [][]byte, opts ...Option) error {
+	m, err := MarshalAttributes(suite, values...)
+	if err != nil {
+		return err
+	}
+	return VerifyScalars(suite, pubKey, m, S, opts...)
+}
+
+// ProveAttributes is ProveSignature for a signature made by SignAttributes
+// on values.
+func ProveAttributes(suite pairing.Suite, pubKey *PublicKey, sig *Signature, values []interface{}, disclose map[int]bool, nonce []byte, opts ...Option) (*SignatureProof, error) {
+	m, err := MarshalAttributes(suite, values...)
+	if err != nil {
+		return nil, err
+	}
+	if err := pubKey.check(); err != nil {
+		return nil, err
+	}
+	if len(nonce) == 0 {
+		return nil, ErrEmptyNonce
+	}
+	S, err := sig.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyScalars(suite, pubKey.Points(), m, S, opts...); err != nil {
+		return nil, err
+	}
+	return proveScalars(suite, pubKey, sig, m, disclose, nonce, nil)
+}
+
+// VerifyPartialAttributes is VerifyPartial for a proof made by
+// ProveAttributes, with the revealed values given by index.
+func VerifyPartialAttributes(suite pairing.Suite, pubKey *PublicKey, revealed map[int]interface{}, proof *SignatureProof, nonce []byte) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	m := make(map[int]kyber.Scalar, len(revealed))
+	for i, v := range revealed {
+		s, err := marshalAttribute(suite, v)
+		if err != nil {
+			return fmt.Errorf("%w: index %d", err, i)
+		}
+		m[i] = s
+	}
+	return verifyRevealed(suite, pubKey, m, proof, nonce, nil)
+}
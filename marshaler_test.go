@@ -0,0 +1,97 @@
+package ps
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// testUUID is a domain type that signs its 16 bytes.
+type testUUID [16]byte
+
+func (u testUUID) MarshalAttribute(suite pairing.Suite) (kyber.Scalar, error) {
+	return HashAttribute(suite, u, u[:]), nil
+}
+
+func parseTestUUID(t *testing.T, s string) testUUID {
+	b, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	require.Nil(t, err)
+	var u testUUID
+	copy(u[:], b)
+	return u
+}
+
+// failingAttribute refuses to marshal.
+type failingAttribute struct{}
+
+func (failingAttribute) MarshalAttribute(pairing.Suite) (kyber.Scalar, error) {
+	return nil, ErrAttributeRange
+}
+
+func TestMarshalAttributes(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		id := parseTestUUID(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		when := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		values := []interface{}{"42", int64(42), []byte("42"), when, id}
+		m, err := MarshalAttributes(suite, values...)
+		require.Nil(t, err)
+
+		// Deterministic, and no two types collide on the same encoding.
+		again, err := MarshalAttributes(suite, values...)
+		require.Nil(t, err)
+		for i := range m {
+			require.True(t, m[i].Equal(again[i]), "%d", i)
+			for j := range m[:i] {
+				require.False(t, m[i].Equal(m[j]), "%d, %d", i, j)
+			}
+		}
+		asBytes, err := MarshalAttributes(suite, id[:], 42)
+		require.Nil(t, err)
+		require.False(t, asBytes[0].Equal(m[4]))
+		require.True(t, asBytes[1].Equal(m[1]))
+
+		_, err = MarshalAttributes(suite, "ok", 4.2)
+		require.True(t, errors.Is(err, ErrAttributeType))
+		_, err = MarshalAttributes(suite, failingAttribute{})
+		require.True(t, errors.Is(err, ErrAttributeRange))
+		_, err = MarshalAttributes(suite, time.Now())
+		require.True(t, errors.Is(err, ErrNotUTC))
+	})
+}
+
+func TestSignAttributes(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 3)
+		id := parseTestUUID(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		values := []interface{}{id, "NL", int64(1250)}
+		S, err := SignAttributes(suite, priv.Scalars(), values)
+		require.Nil(t, err)
+		require.Nil(t, VerifyAttributes(suite, pub.Points(), values, S))
+		other := parseTestUUID(t, "6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+		require.Equal(t, ErrInvalidSignature, VerifyAttributes(suite, pub.Points(), []interface{}{other, "NL", int64(1250)}, S))
+
+		// Reveal the UUID only; the country and amount stay hidden.
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+		proof, err := ProveAttributes(suite, pub, sig, values, map[int]bool{0: true}, nonce)
+		require.Nil(t, err)
+		require.Equal(t, []int{1, 2}, proof.Hidden)
+		require.Nil(t, VerifyPartialAttributes(suite, pub, map[int]interface{}{0: id}, proof, nonce))
+		require.Equal(t, ErrInvalidSignature, VerifyPartialAttributes(suite, pub, map[int]interface{}{0: other}, proof, nonce))
+		require.Equal(t, ErrInvalidSignature, VerifyPartialAttributes(suite, pub, map[int]interface{}{0: id[:]}, proof, nonce))
+		err = VerifyPartialAttributes(suite, pub, map[int]interface{}{0: id, 1: "NL"}, proof, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+
+		// The message-based functions hash values differently.
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), [][]byte{id[:], []byte("NL"), AttributeFromInt64(1250)}, S))
+		_, err = ProveAttributes(suite, pub, sig, []interface{}{other, "NL", int64(1250)}, nil, nonce)
+		require.Equal(t, ErrInvalidSignature, err)
+	})
+}
@@ -0,0 +1,244 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A verifier short of memory may hold a commitment to the messages rather
+// than the messages themselves. SignWithCommitment signs a Merkle root of
+// msgs as the single, reserved attribute of the signature, in slot 1, and
+// VerifyAgainstRoot checks the signature on the root together with openings
+// of the messages the verifier cares about.
+//
+// The tree is the Merkle hash tree of RFC 6962 with SHA-256: with D the
+// messages and k the largest power of two below n = len(D),
+//
+//	MTH({d}) = SHA-256(0x00 || d)
+//	MTH(D)   = SHA-256(0x01 || MTH(D[0:k]) || MTH(D[k:n])),
+//
+// the distinct leaf and node prefixes keeping a leaf from passing for an
+// inner node. An opening of message i is its audit path in that tree. The
+// signed attribute is the hash to scalar of
+//
+//	uint64(n) || MTH(D)
+//
+// under the signing tag followed by "-MERKLE-ROOT", so that the root is not
+// confused with an ordinary message of the same bytes, and the size of the
+// tree, which the audit paths depend on, is signed too.
+
+// ErrInvalidOpening is returned when a Merkle opening does not lead to the
+// signed root.
+var ErrInvalidOpening = errors.New("ps: invalid merkle opening")
+
+// merkleLeafPrefix and merkleNodePrefix separate the hashes of leaves and
+// inner nodes, as in RFC 6962.
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// MerkleRoot is the root hash of the Merkle tree over Size messages.
+type MerkleRoot struct {
+	Size int
+	Hash [sha256.Size]byte
+}
+
+// MerkleOpening reveals message Msg at position Index of a committed message
+// vector, with the audit path from its leaf to the root.
+type MerkleOpening struct {
+	Index int
+	Msg   []byte
+	Path  [][]byte
+}
+
+// CommitMessages returns the Merkle root of msgs.
+func CommitMessages(msgs [][]byte) (MerkleRoot, error) {
+	if len(msgs) == 0 {
+		return MerkleRoot{}, ErrNoMessages
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return MerkleRoot{}, err
+	}
+	root := MerkleRoot{Size: len(msgs)}
+	copy(root.Hash[:], merkleTreeHash(msgs))
+	return root, nil
+}
+
+// OpenMessages returns the openings of msgs at indices against the root
+// CommitMessages returns for msgs.
+func OpenMessages(msgs [][]byte, indices ...int) ([]MerkleOpening, error) {
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	openings := make([]MerkleOpening, len(indices))
+	for j, i := range indices {
+		if i < 0 || i >= len(msgs) {
+			return nil, itemError("open message", j, fmt.Errorf("%w: index %d outside 0..%d", ErrInvalidIndex, i, len(msgs)-1))
+		}
+		openings[j] = MerkleOpening{Index: i, Msg: msgs[i], Path: merklePath(i, msgs)}
+	}
+	return openings, nil
+}
+
+// SignWithCommitment signs the Merkle root of msgs with x and y_1 of priKey,
+// and returns the signature with the root.
+func SignWithCommitment(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte, opts ...Option) ([][]byte, MerkleRoot, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	root, err := CommitMessages(msgs)
+	if err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	m := []kyber.Scalar{root.scalar(suite, o.dst)}
+	rand, err := o.signingStream(suite, priKey[:2], m)
+	if err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	S, err := signScalars(suite, priKey[:2], m, rand)
+	if err != nil {
+		return nil, MerkleRoot{}, err
+	}
+	return S, root, nil
+}
+
+// VerifyWithCommitment checks a signature S made by SignWithCommitment on
+// all of msgs.
+func VerifyWithCommitment(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, S [][]byte, opts ...Option) error {
+	root, err := CommitMessages(msgs)
+	if err != nil {
+		return err
+	}
+	return VerifyAgainstRoot(suite, pubKey, root, nil, S, opts...)
+}
+
+// VerifyAgainstRoot checks that S signs root, as made by SignWithCommitment,
+// and that every opening leads to root. Openings that fail are reported as a
+// *BatchError holding their position in openings.
+func VerifyAgainstRoot(suite pairing.Suite, pubKey []kyber.Point, root MerkleRoot, openings []MerkleOpening, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if root.Size <= 0 {
+		return ErrNoMessages
+	}
+	if S == nil {
+		return ErrNilSignature
+	}
+	for j, op := range openings {
+		if err := op.verify(root); err != nil {
+			return itemError("open message", j, err)
+		}
+	}
+	return verifyScalars(suite, pubKey, []kyber.Scalar{root.scalar(suite, o.dst)}, S, o.validatePoints)
+}
+
+// scalar returns the attribute signed for the root under the signing tag
+// dst.
+func (r MerkleRoot) scalar(suite pairing.Suite, dst []byte) kyber.Scalar {
+	var buf [8 + sha256.Size]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(r.Size))
+	copy(buf[8:], r.Hash[:])
+	tag := append(append([]byte{}, dst...), "-MERKLE-ROOT"...)
+	return hashToScalar(suite, tag, buf[:])
+}
+
+// verify recomputes the root from the opening's audit path, following
+// RFC 9162, section 2.1.3.2.
+func (op MerkleOpening) verify(root MerkleRoot) error {
+	if op.Index < 0 || op.Index >= root.Size {
+		return fmt.Errorf("%w: index %d outside 0..%d", ErrInvalidOpening, op.Index, root.Size-1)
+	}
+	if len(op.Msg) == 0 {
+		return ErrEmptyMessage
+	}
+	fn, sn := op.Index, root.Size-1
+	r := merkleLeafHash(op.Msg)
+	for _, p := range op.Path {
+		if len(p) != sha256.Size || sn == 0 {
+			return ErrInvalidOpening
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root.Hash[:]) {
+		return ErrInvalidOpening
+	}
+	return nil
+}
+
+// merkleTreeHash returns MTH(msgs) for at least one message.
+func merkleTreeHash(msgs [][]byte) []byte {
+	if len(msgs) == 1 {
+		return merkleLeafHash(msgs[0])
+	}
+	k := merkleSplit(len(msgs))
+	return merkleNodeHash(merkleTreeHash(msgs[:k]), merkleTreeHash(msgs[k:]))
+}
+
+// merklePath returns the audit path of leaf i of the tree over msgs, from
+// the leaf up.
+func merklePath(i int, msgs [][]byte) [][]byte {
+	if len(msgs) == 1 {
+		return nil
+	}
+	k := merkleSplit(len(msgs))
+	if i < k {
+		return append(merklePath(i, msgs[:k]), merkleTreeHash(msgs[k:]))
+	}
+	return append(merklePath(i-k, msgs[k:]), merkleTreeHash(msgs[:k]))
+}
+
+// merkleSplit returns the largest power of two below n > 1.
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func merkleLeafHash(msg []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
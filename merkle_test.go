@@ -0,0 +1,93 @@
+package ps
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// TestMerkleTreeHash checks the tree against the test vectors of the
+// certificate-transparency reference implementation of RFC 6962.
+func TestMerkleTreeHash(t *testing.T) {
+	leaves := [][]byte{}
+	for _, h := range []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"} {
+		leaf, err := hex.DecodeString(h)
+		require.Nil(t, err)
+		leaves = append(leaves, leaf)
+	}
+	require.Equal(t, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d", hex.EncodeToString(merkleTreeHash(leaves[:1])))
+	require.Equal(t, "ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c", hex.EncodeToString(merkleTreeHash(leaves[:7])))
+	require.Equal(t, "5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328", hex.EncodeToString(merkleTreeHash(leaves)))
+
+	// Every audit path of every tree size leads to the root. Leaf 0 is
+	// empty, which openings reject.
+	for n := 1; n <= len(leaves); n++ {
+		root := MerkleRoot{Size: n}
+		copy(root.Hash[:], merkleTreeHash(leaves[:n]))
+		for i := 1; i < n; i++ {
+			op := MerkleOpening{Index: i, Msg: leaves[i], Path: merklePath(i, leaves[:n])}
+			require.Nil(t, op.verify(root), "leaf %d of %d", i, n)
+		}
+	}
+}
+
+func TestSignWithCommitment(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		msgs := make([][]byte, 11)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		S, root, err := SignWithCommitment(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		committed, err := CommitMessages(msgs)
+		require.Nil(t, err)
+		require.Equal(t, committed, root)
+
+		// Full verification, and from the root with a few openings.
+		require.Nil(t, VerifyWithCommitment(suite, pub.Points(), msgs, S))
+		openings, err := OpenMessages(msgs, 0, 7, 10)
+		require.Nil(t, err)
+		require.Nil(t, VerifyAgainstRoot(suite, pub.Points(), root, openings, S))
+		require.Nil(t, VerifyAgainstRoot(suite, pub.Points(), root, nil, S))
+
+		// A forged value, a moved opening and a tampered path.
+		forged := append([]MerkleOpening{}, openings...)
+		forged[1].Msg = []byte("attribute 8")
+		err = VerifyAgainstRoot(suite, pub.Points(), root, forged, S)
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 1, batchErr.Index)
+		require.True(t, errors.Is(err, ErrInvalidOpening))
+		moved := append([]MerkleOpening{}, openings...)
+		moved[1].Index = 8
+		require.True(t, errors.Is(VerifyAgainstRoot(suite, pub.Points(), root, moved, S), ErrInvalidOpening))
+		tampered := append([]MerkleOpening{}, openings...)
+		tampered[2].Path = append([][]byte{}, openings[2].Path...)
+		tampered[2].Path[0] = openings[1].Path[0]
+		require.True(t, errors.Is(VerifyAgainstRoot(suite, pub.Points(), root, tampered, S), ErrInvalidOpening))
+		truncated := append([]MerkleOpening{}, openings...)
+		truncated[0].Path = openings[0].Path[1:]
+		require.True(t, errors.Is(VerifyAgainstRoot(suite, pub.Points(), root, truncated, S), ErrInvalidOpening))
+
+		// The root and its size are what is signed.
+		require.Equal(t, ErrInvalidSignature, VerifyWithCommitment(suite, pub.Points(), msgs[:10], S))
+		other := root
+		other.Size++
+		require.Equal(t, ErrInvalidSignature, VerifyAgainstRoot(suite, pub.Points(), other, nil, S))
+
+		// The root is not an ordinary message.
+		plain, err := Sign(suite, priv.Scalars(), root.Hash[:])
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyAgainstRoot(suite, pub.Points(), root, nil, plain))
+
+		_, err = OpenMessages(msgs, 11)
+		require.True(t, errors.Is(err, ErrInvalidIndex))
+		_, _, err = SignWithCommitment(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNoMessages, err)
+	})
+}
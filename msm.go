@@ -0,0 +1,108 @@
+package ps
+
+import (
+	"math/bits"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Multi-scalar multiplication computes s_1*P_1 + ... + s_n*P_n with the
+// bucket method of Pippenger. Scalars are cut into windows of c bits. For
+// each window, every point is added to the bucket of its digit, and the
+// buckets are summed with weights 1..2^c-1 by a running sum, for about
+// n + 2^(c+1) additions per window instead of n full multiplications. The
+// windows are combined from the most significant one, with c doublings in
+// between. Choosing c close to log2(n) balances the two terms.
+
+// msmThreshold is the number of points below which the naive sum of products
+// is faster.
+const msmThreshold = 8
+
+// littleEndianScalar returns the little-endian encoding of s, whatever the
+// byte order of its group's encoding.
+func littleEndianScalar(g kyber.Group, s kyber.Scalar) ([]byte, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	one, err := g.Scalar().One().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if one[0] == 1 {
+		return buf, nil
+	}
+	le := make([]byte, len(buf))
+	for i, b := range buf {
+		le[len(buf)-1-i] = b
+	}
+	return le, nil
+}
+
+// scalarWindow returns the c bits of the little-endian scalar le starting at
+// bit offset.
+func scalarWindow(le []byte, offset, c int) int {
+	d := 0
+	for i := 0; i < c; i++ {
+		bit := offset + i
+		if bit/8 >= len(le) {
+			break
+		}
+		d |= int(le[bit/8]>>(bit%8)&1) << i
+	}
+	return d
+}
+
+// multiScalarMul returns the sum of scalars[i] * points[i] in g. Both slices
+// must have the same length.
+func multiScalarMul(g kyber.Group, scalars []kyber.Scalar, points []kyber.Point) (kyber.Point, error) {
+	n := len(points)
+	if n < msmThreshold {
+		sum := g.Point().Null()
+		for i, p := range points {
+			sum.Add(sum, g.Point().Mul(scalars[i], p))
+		}
+		return sum, nil
+	}
+	digits := make([][]byte, n)
+	for i, s := range scalars {
+		le, err := littleEndianScalar(g, s)
+		if err != nil {
+			return nil, err
+		}
+		digits[i] = le
+	}
+	c := bits.Len(uint(n)) - 1
+	windows := (8*g.ScalarLen() + c - 1) / c
+	buckets := make([]kyber.Point, 1<<c-1)
+	result := g.Point().Null()
+	for w := windows - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			result = g.Point().Add(result, result)
+		}
+		for j := range buckets {
+			buckets[j] = nil
+		}
+		for i, p := range points {
+			d := scalarWindow(digits[i], w*c, c)
+			if d == 0 {
+				continue
+			}
+			if buckets[d-1] == nil {
+				buckets[d-1] = p.Clone()
+			} else {
+				buckets[d-1].Add(buckets[d-1], p)
+			}
+		}
+		// sum_d d * bucket_d = sum_d (bucket_d + ... + bucket_(2^c-1))
+		running, sum := g.Point().Null(), g.Point().Null()
+		for j := len(buckets) - 1; j >= 0; j-- {
+			if buckets[j] != nil {
+				running.Add(running, buckets[j])
+			}
+			sum.Add(sum, running)
+		}
+		result.Add(result, sum)
+	}
+	return result, nil
+}
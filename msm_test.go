@@ -0,0 +1,37 @@
+package ps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestMultiScalarMul(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+			for _, n := range []int{0, 1, msmThreshold - 1, msmThreshold, 33, 100} {
+				scalars := make([]kyber.Scalar, n)
+				points := make([]kyber.Point, n)
+				naive := g.Point().Null()
+				for i := range points {
+					scalars[i] = g.Scalar().Pick(random.New())
+					if i%7 == 3 {
+						scalars[i].SetInt64(-1)
+					}
+					points[i] = g.Point().Pick(random.New())
+					naive.Add(naive, g.Point().Mul(scalars[i], points[i]))
+				}
+				got, err := multiScalarMul(g, scalars, points)
+				require.Nil(t, err)
+				want, err := naive.MarshalBinary()
+				require.Nil(t, err)
+				buf, err := got.MarshalBinary()
+				require.Nil(t, err)
+				require.Equal(t, want, buf, "%s, %d points", g, n)
+			}
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A multi-signature combines the signatures of several signers on the same
+// message into one. If every signer i signs on the same base h,
+//
+//	(h, h^(x_i + y_i*m)),
+//
+// the product of the second components is h^(x + y*m) for the sums x and y
+// of the signers' secrets, a signature under the product of their public
+// keys. The base is agreed in two rounds: a coordinator derives it from the
+// message with MultiSigBase and sends both to the signers, who check it with
+// MultiSign before signing. Deriving h from the message matters: two
+// signatures by one key on the same base and different messages combine
+// into signatures on other messages.
+//
+// Aggregated keys are exposed to rogue keys: a signer announcing X' = X_a /
+// X_h for an honest X_h cancels that signer out of the aggregate. Keys must
+// therefore only be aggregated once their owners proved possession of the
+// secrets, see ProvePossession.
+
+// MultiSigBase returns the common base h of multi-signatures on msg, which
+// is hashed to G1 under the tag set by opts.
+func MultiSigBase(suite pairing.Suite, msg []byte, opts ...Option) (kyber.Point, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessages(msg); err != nil {
+		return nil, err
+	}
+	buf, err := appendScalars(nil, suite.G1(), hashToScalar(suite, o.dst, msg))
+	if err != nil {
+		return nil, err
+	}
+	return hashToPoint(suite, protocolDST(suite, "MSIG"), buf)
+}
+
+// MultiSign signs msg with the first slot of priKey on the base h sent by
+// the coordinator, after checking that h is MultiSigBase of msg.
+func MultiSign(suite pairing.Suite, priKey *PrivateKey, h kyber.Point, msg []byte, opts ...Option) (*Signature, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	if err := priKey.policy.allow(OpAggregate, 0); err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, fmt.Errorf("%w: nil base", ErrInvalidPoint)
+	}
+	want, err := MultiSigBase(suite, msg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !want.Equal(h) {
+		return nil, fmt.Errorf("%w: base does not belong to the message", ErrInvalidPoint)
+	}
+	e := newScalar(suite).Mul(priKey.Y[0], hashToScalar(suite, o.dst, msg))
+	e.Add(e, priKey.X)
+	return &Signature{suite: suite, Sigma1: want, Sigma2: suite.G1().Point().Mul(e, want)}, nil
+}
+
+// CombineSignatures multiplies the second components of signatures made by
+// MultiSign on the same base into a multi-signature, which VerifyMultiSig
+// checks under the aggregate of the signers' keys.
+func CombineSignatures(suite pairing.Suite, sigs []*Signature) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	return combineSignatures(suite, sigs, nil)
+}
+
+// combineSignatures computes (h, sigma_2,1^(a_1) * ... * sigma_2,k^(a_k)),
+// with all coefficients 1 if coeffs is nil.
+func combineSignatures(suite pairing.Suite, sigs []*Signature, coeffs []kyber.Scalar) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNilSignature
+	}
+	combined := &Signature{suite: suite, Sigma2: suite.G1().Point().Null()}
+	for i, sig := range sigs {
+		if err := sig.check(); err != nil {
+			return nil, fmt.Errorf("%w: signature %d", err, i)
+		}
+		if i == 0 {
+			combined.Sigma1 = sig.Sigma1.Clone()
+		} else if !sig.Sigma1.Equal(combined.Sigma1) {
+			return nil, fmt.Errorf("%w: signature %d uses another base", ErrInvalidSignature, i)
+		}
+		if coeffs == nil {
+			combined.Sigma2.Add(combined.Sigma2, sig.Sigma2)
+		} else {
+			combined.Sigma2.Add(combined.Sigma2, suite.G1().Point().Mul(coeffs[i], sig.Sigma2))
+		}
+	}
+	return combined, nil
+}
+
+// AggregatePublicKeys multiplies the public keys component-wise into the key
+// that verifies multi-signatures of their owners. All keys must have the
+// same length. Only keys whose owners proved possession of the secrets may
+// be aggregated, see the rogue-key note above; with WithPossessionProofs the
+// proofs are checked here.
+func AggregatePublicKeys(suite pairing.Suite, pubs []*PublicKey, opts ...Option) (*PublicKey, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	agg, err := aggregateKeys(suite, pubs, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.checkPossession(suite, pubs); err != nil {
+		return nil, err
+	}
+	return agg, nil
+}
+
+// aggregateKeys computes the component-wise product of the keys raised to
+// coeffs, with all coefficients 1 if coeffs is nil.
+func aggregateKeys(suite pairing.Suite, pubs []*PublicKey, coeffs []kyber.Scalar) (*PublicKey, error) {
+	if len(pubs) == 0 {
+		return nil, ErrNilKey
+	}
+	agg := &PublicKey{suite: suite, X: suite.G2().Point().Null()}
+	for i, pub := range pubs {
+		if err := pub.check(); err != nil {
+			return nil, fmt.Errorf("%w: key %d", err, i)
+		}
+		if i == 0 {
+			for range pub.Y {
+				agg.Y = append(agg.Y, suite.G2().Point().Null())
+			}
+		} else if len(pub.Y) != len(agg.Y) {
+			return nil, fmt.Errorf("%w: key %d has %d points, key 0 %d", ErrKeyLengthMismatch, i, len(pub.Y)+1, len(agg.Y)+1)
+		}
+		for j, p := range pub.Points() {
+			if coeffs != nil {
+				p = suite.G2().Point().Mul(coeffs[i], p)
+			}
+			if j == 0 {
+				agg.X.Add(agg.X, p)
+			} else {
+				agg.Y[j-1].Add(agg.Y[j-1], p)
+			}
+		}
+	}
+	return agg, nil
+}
+
+// VerifyMultiSig checks a multi-signature on msg under the aggregated key
+// aggPub. It is Verify on the first slot of the key.
+func VerifyMultiSig(suite pairing.Suite, aggPub *PublicKey, msg []byte, sig *Signature, opts ...Option) error {
+	if err := aggPub.check(); err != nil {
+		return err
+	}
+	S, err := sig.Components()
+	if err != nil {
+		return err
+	}
+	return Verify(suite, aggPub.Points()[:2], msg, S, opts...)
+}
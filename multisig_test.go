@@ -0,0 +1,88 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestMultiSig(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("committee decision 42")
+		var privs []*PrivateKey
+		var pubs []*PublicKey
+		for i := 0; i < 3; i++ {
+			priv, pub := newTestKeys(t, suite, 1)
+			privs, pubs = append(privs, priv), append(pubs, pub)
+		}
+
+		// The coordinator distributes the base, and the signers answer.
+		h, err := MultiSigBase(suite, msg)
+		require.Nil(t, err)
+		var sigs []*Signature
+		for _, priv := range privs {
+			sig, err := MultiSign(suite, priv, h, msg)
+			require.Nil(t, err)
+			sigs = append(sigs, sig)
+		}
+		multi, err := CombineSignatures(suite, sigs)
+		require.Nil(t, err)
+		agg, err := AggregatePublicKeys(suite, pubs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyMultiSig(suite, agg, msg, multi))
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, []byte("committee decision 43"), multi))
+
+		// Every signer counts.
+		partial, err := AggregatePublicKeys(suite, pubs[:2])
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, partial, msg, multi))
+		short, err := CombineSignatures(suite, sigs[1:])
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyMultiSig(suite, agg, msg, short))
+
+		// A single signer's multi-signature is an ordinary signature.
+		S, err := sigs[0].Components()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pubs[0].Points(), msg, S))
+	})
+}
+
+func TestMultiSigInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("committee decision 42")
+		priv, pub := newTestKeys(t, suite, 1)
+		_, long := newTestKeys(t, suite, 2)
+
+		// Signers refuse a base that does not belong to the message.
+		_, err := MultiSign(suite, priv, suite.G1().Point().Pick(random.New()), msg)
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+		other, err := MultiSigBase(suite, []byte("committee decision 43"))
+		require.Nil(t, err)
+		_, err = MultiSign(suite, priv, other, msg)
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+
+		// Signatures on different bases do not combine.
+		h, err := MultiSigBase(suite, msg)
+		require.Nil(t, err)
+		a, err := MultiSign(suite, priv, h, msg)
+		require.Nil(t, err)
+		S, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		b, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		_, err = CombineSignatures(suite, []*Signature{a, b})
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		_, err = AggregatePublicKeys(suite, []*PublicKey{pub, long})
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = AggregatePublicKeys(suite, nil)
+		require.Equal(t, ErrNilKey, err)
+		_, err = CombineSignatures(suite, nil)
+		require.Equal(t, ErrNilSignature, err)
+		_, err = MultiSigBase(suite, nil)
+		require.Equal(t, ErrEmptyMessage, err)
+	})
+}
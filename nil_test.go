@@ -0,0 +1,491 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"testing"
+	"time"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// TestNilTorture calls every exported function with each argument set to nil
+// in turn and checks that it returns an error instead of panicking.
+func TestNilTorture(t *testing.T) {
+	suite := bls12381.NewSuite()
+	msg := []byte("Hello PS Signature")
+	msgs := [][]byte{msg}
+	priv, pub := newTestKeys(t, suite, 1)
+	sig := newTestSignature(t, suite, priv, msg)
+	S, err := sig.Components()
+	require.Nil(t, err)
+	m := suite.G1().Scalar().One()
+	pubBuf, err := pub.MarshalBinary()
+	require.Nil(t, err)
+	sigBuf, err := sig.MarshalBinary()
+	require.Nil(t, err)
+	pairs, err := ExportKeyPairs(priv)
+	require.Nil(t, err)
+	ks, err := NewFileKeystore(t.TempDir(), []byte("passphrase"), testKDFParams)
+	require.Nil(t, err)
+
+	var nilSuite pairing.Suite
+	var nilPriv *PrivateKey
+	var nilPub *PublicKey
+	var nilSig *Signature
+
+	cases := map[string]func() error{
+		"NewKeyPair suite": func() error {
+			_, _, err := NewKeyPair(nilSuite, []cipher.Stream{random.New(), random.New()})
+			return err
+		},
+		"NewKeyPair randoms": func() error {
+			_, _, err := NewKeyPair(suite, nil)
+			return err
+		},
+		"NewKeyPair random": func() error {
+			_, _, err := NewKeyPair(suite, []cipher.Stream{random.New(), nil})
+			return err
+		},
+		"Sign suite": func() error { _, err := Sign(nilSuite, priv.Scalars(), msg); return err },
+		"Sign key":   func() error { _, err := Sign(suite, nil, msg); return err },
+		"Sign scalar": func() error {
+			_, err := Sign(suite, []kyber.Scalar{nil, priv.Y[0]}, msg)
+			return err
+		},
+		"SignScalar suite":   func() error { _, err := SignScalar(nilSuite, priv.Scalars(), m); return err },
+		"SignScalar key":     func() error { _, err := SignScalar(suite, nil, m); return err },
+		"SignScalar message": func() error { _, err := SignScalar(suite, priv.Scalars(), nil); return err },
+		"BatchSign suite":    func() error { _, err := BatchSign(nilSuite, priv.Scalars(), msgs); return err },
+		"BatchSign key":      func() error { _, err := BatchSign(suite, nil, msgs); return err },
+		"BatchSign messages": func() error { _, err := BatchSign(suite, priv.Scalars(), nil); return err },
+		"AggreSign suite":    func() error { _, err := AggreSign(nilSuite, priv.Scalars(), msgs); return err },
+		"AggreSign key":      func() error { _, err := AggreSign(suite, nil, msgs); return err },
+		"Verify suite":       func() error { return Verify(nilSuite, pub.Points(), msg, S) },
+		"Verify key":         func() error { return Verify(suite, nil, msg, S) },
+		"Verify point":       func() error { return Verify(suite, []kyber.Point{pub.X, nil}, msg, S) },
+		"Verify signature":   func() error { return Verify(suite, pub.Points(), msg, nil) },
+		"Verify component":   func() error { return Verify(suite, pub.Points(), msg, [][]byte{S[0], nil}) },
+		"VerifyScalar suite": func() error { return VerifyScalar(nilSuite, pub.Points(), m, S) },
+		"VerifyScalar key":   func() error { return VerifyScalar(suite, nil, m, S) },
+		"VerifyScalar message": func() error {
+			return VerifyScalar(suite, pub.Points(), nil, S)
+		},
+		"VerifyScalar signature": func() error { return VerifyScalar(suite, pub.Points(), m, nil) },
+		"ExtendSignature suite": func() error {
+			_, err := ExtendSignature(nilSuite, priv.Scalars(), S, [][]byte{msg}, msg)
+			return err
+		},
+		"ExtendSignature key":       func() error { _, err := ExtendSignature(suite, nil, S, [][]byte{msg}, msg); return err },
+		"ExtendSignature signature": func() error { _, err := ExtendSignature(suite, priv.Scalars(), nil, nil, msg); return err },
+		"SignScalars suite":         func() error { _, err := SignScalars(nilSuite, priv.Scalars(), []kyber.Scalar{m}); return err },
+		"SignScalars key":           func() error { _, err := SignScalars(suite, nil, []kyber.Scalar{m}); return err },
+		"SignScalars attribute":     func() error { _, err := SignScalars(suite, priv.Scalars(), []kyber.Scalar{nil}); return err },
+		"VerifyScalars suite":       func() error { return VerifyScalars(nilSuite, pub.Points(), []kyber.Scalar{m}, S) },
+		"VerifyScalars key":         func() error { return VerifyScalars(suite, nil, []kyber.Scalar{m}, S) },
+		"VerifyScalars signature":   func() error { return VerifyScalars(suite, pub.Points(), []kyber.Scalar{m}, nil) },
+		"BatchVerifyScalars signature": func() error {
+			return BatchVerifyScalars(suite, pub.Points(), [][]kyber.Scalar{{m}}, [][][]byte{nil})
+		},
+		"PSBatchVerify suite":    func() error { return PSBatchVerify(nilSuite, pub.Points(), msgs, S) },
+		"PSBatchVerify key":      func() error { return PSBatchVerify(suite, nil, msgs, S) },
+		"PSBatchVerify messages": func() error { return PSBatchVerify(suite, pub.Points(), nil, S) },
+		"PSBatchVerify signature": func() error {
+			return PSBatchVerify(suite, pub.Points(), msgs, nil)
+		},
+		"AggregatePSSign suite": func() error {
+			_, err := AggregatePSSign(nilSuite, priv.Y[0], S, msg)
+			return err
+		},
+		"AggregatePSSign key": func() error { _, err := AggregatePSSign(suite, nil, S, msg); return err },
+		"AggregatePSSign signature": func() error {
+			_, err := AggregatePSSign(suite, priv.Y[0], nil, msg)
+			return err
+		},
+		"AggreSignPoints key": func() error { _, err := AggreSignPoints(suite, nil, msgs); return err },
+		"AggregatePSSignPoints key": func() error {
+			_, err := AggregatePSSignPoints(suite, nil, sig, msg)
+			return err
+		},
+		"AggregatePSSignPoints signature": func() error {
+			_, err := AggregatePSSignPoints(suite, priv.Y[0], nil, msg)
+			return err
+		},
+		"AggregatePSSignPoints component": func() error {
+			_, err := AggregatePSSignPoints(suite, priv.Y[0], &Signature{suite: suite, Sigma1: sig.Sigma1}, msg)
+			return err
+		},
+		"PSBatchVerifyPoints suite": func() error { return PSBatchVerifyPoints(nilSuite, pub.Points(), msgs, sig) },
+		"PSBatchVerifyPoints signature": func() error {
+			return PSBatchVerifyPoints(suite, pub.Points(), msgs, nil)
+		},
+		"PrecomputeMessages suite":    func() error { _, err := PrecomputeMessages(nilSuite, msgs); return err },
+		"PrecomputeMessages messages": func() error { _, err := PrecomputeMessages(suite, nil); return err },
+		"SignPrecomputed key": func() error {
+			_, err := SignPrecomputed(suite, nil, []MessageScalar{{suite: suite, dst: DefaultDST(suite), Scalar: m}})
+			return err
+		},
+		"SignPrecomputed message": func() error {
+			_, err := SignPrecomputed(suite, priv.Scalars()[:2], []MessageScalar{{}})
+			return err
+		},
+		"VerifyPrecomputed signature": func() error {
+			return VerifyPrecomputed(suite, pub.Points(), []MessageScalar{{suite: suite, dst: DefaultDST(suite), Scalar: m}}, nil)
+		},
+		"MessageScalar.MarshalBinary":  func() error { _, err := MessageScalar{}.MarshalBinary(); return err },
+		"UnmarshalMessageScalar suite": func() error { _, err := UnmarshalMessageScalar(nilSuite, nil); return err },
+		"NewVerifyScratch suite":       func() error { _, err := NewVerifyScratch(nilSuite); return err },
+		"VerifyInto suite":             func() error { return VerifyInto(nilSuite, pub.Points(), msg, S, nil) },
+		"VerifyInto signature":         func() error { return VerifyInto(suite, pub.Points(), msg, nil, nil) },
+		"NewBatchSigner suite":         func() error { _, err := NewBatchSigner(nilSuite, priv.Scalars()); return err },
+		"NewBatchSigner key":           func() error { _, err := NewBatchSigner(suite, nil); return err },
+		"VerifyMany suite":             func() error { _, err := VerifyMany(nilSuite, []*PublicKey{pub}, msgs, []*Signature{sig}); return err },
+		"VerifyMany key":               func() error { _, err := VerifyMany(suite, []*PublicKey{nilPub}, msgs, []*Signature{sig}); return err },
+		"NewAccumulator suite":         func() error { _, err := NewAccumulator(nilSuite, random.New()); return err },
+		"NewConcurrentSigner suite":    func() error { _, err := NewConcurrentSigner(nilSuite, priv, nil); return err },
+		"NewConcurrentSigner key":      func() error { _, err := NewConcurrentSigner(suite, nilPriv, nil); return err },
+		"AggregateSignAll key":         func() error { _, err := AggregateSignAll(suite, nilPriv, msgs, random.New()); return err },
+		"ResumeAggregateSign signature": func() error {
+			_, err := ResumeAggregateSign(suite, priv, nil, 1, msgs, random.New())
+			return err
+		},
+		"DiagnoseBatchInputs suite": func() error { _, err := DiagnoseBatchInputs(nilSuite, pub.Points(), msgs, S); return err },
+		"DiagnoseBatchFailure suite": func() error {
+			_, err := DiagnoseBatchFailure(nilSuite, pub.Points(), msgs, S, nil)
+			return err
+		},
+		"BatchSignIndexed suite": func() error {
+			_, err := BatchSignIndexed(nilSuite, priv.Scalars(), IndexedMessages{{Index: 0, Msg: msg}})
+			return err
+		},
+		"BatchSignIndexed key": func() error {
+			_, err := BatchSignIndexed(suite, nil, IndexedMessages{{Index: 0, Msg: msg}})
+			return err
+		},
+		"BatchVerifyIndexed signature": func() error {
+			return BatchVerifyIndexed(suite, pub.Points(), IndexedMessages{{Index: 0, Msg: msg}}, nil)
+		},
+		"PSBatchVerifyPositions signature": func() error {
+			return PSBatchVerifyPositions(suite, pub.Points(), IndexedMessages{{Index: 0, Msg: msg}}, nil)
+		},
+		"BatchSignSeq messages": func() error { _, err := BatchSignSeq(suite, priv.Scalars(), nil); return err },
+		"BatchVerifySeq signature": func() error {
+			return BatchVerifySeq(suite, pub.Points(), func(yield func([]byte, error) bool) { yield(msg, nil) }, nil)
+		},
+		"VerifyPartialAggregate key":       func() error { return VerifyPartialAggregate(suite, nilPub, msgs, sig) },
+		"VerifyPartialAggregate signature": func() error { return VerifyPartialAggregate(suite, pub, msgs, nilSig) },
+		"SignWithCommitment key":           func() error { _, _, err := SignWithCommitment(suite, nil, msgs); return err },
+		"VerifyAgainstRoot signature": func() error {
+			return VerifyAgainstRoot(suite, pub.Points(), MerkleRoot{Size: 1}, nil, nil)
+		},
+		"SignSchema schema":       func() error { _, err := SignSchema(suite, priv.Scalars(), nil, nil); return err },
+		"VerifySchema schema":     func() error { return VerifySchema(suite, pub.Points(), nil, nil, S) },
+		"MarshalAttributes suite": func() error { _, err := MarshalAttributes(nilSuite, "a"); return err },
+		"MarshalAttributes value": func() error { _, err := MarshalAttributes(suite, nil); return err },
+		"SignAttributes key":      func() error { _, err := SignAttributes(suite, nil, []interface{}{"a"}); return err },
+		"VerifyAttributes signature": func() error {
+			return VerifyAttributes(suite, pub.Points(), []interface{}{"a"}, nil)
+		},
+		"ProveAttributes key": func() error {
+			_, err := ProveAttributes(suite, nilPub, sig, []interface{}{"a"}, nil, msg)
+			return err
+		},
+		"ProveAttributes signature": func() error {
+			_, err := ProveAttributes(suite, pub, nilSig, []interface{}{"a"}, nil, msg)
+			return err
+		},
+		"VerifyPartialAttributes suite": func() error {
+			return VerifyPartialAttributes(nilSuite, pub, nil, &SignatureProof{}, msg)
+		},
+		"VerifyPartialAttributes proof": func() error { return VerifyPartialAttributes(suite, pub, nil, nil, msg) },
+		"ProjectPublicKey key":          func() error { _, _, err := ProjectPublicKey(nilPub, []int{0}); return err },
+		"BatchVerifyIndexed projection": func() error {
+			return BatchVerifyIndexed(suite, pub.Points(), IndexedMessages{{Index: 0, Msg: msg}}, S, WithProjection(nil))
+		},
+		"GenerateKey suite":               func() error { _, _, err := GenerateKey(nilSuite, 1, nil); return err },
+		"PrivateKey.SetPolicy":            func() error { return nilPriv.SetPolicy(&KeyPolicy{Operations: OpSign}) },
+		"PrivateKey.BatchSign":            func() error { _, err := nilPriv.BatchSign(msgs); return err },
+		"PrivateKey.SignReserved":         func() error { _, err := nilPriv.SignReserved(nil, msgs); return err },
+		"EncryptPrivateKey key":           func() error { _, err := EncryptPrivateKey(nil, []byte("x")); return err },
+		"DecryptPrivateKey suite":         func() error { _, err := DecryptPrivateKey(nilSuite, nil, nil); return err },
+		"SignSchemaSalted schema":         func() error { _, _, err := SignSchemaSalted(suite, priv.Scalars(), nil, nil); return err },
+		"VerifySchemaSalted schema":       func() error { return VerifySchemaSalted(suite, nil, nil, nil, nil, nil) },
+		"NewFileKeystore passphrase":      func() error { _, err := NewFileKeystore(t.TempDir(), nil); return err },
+		"FileKeystore.Put key":            func() error { _, err := ks.Put("issuer", nilPriv); return err },
+		"PublicKey.KeyID":                 func() error { _, err := nilPub.KeyID(); return err },
+		"SelectKey key":                   func() error { _, err := SelectKey([]*PublicKey{nilPub}, "00"); return err },
+		"Seal key":                        func() error { _, err := Seal(nilPriv, msgs, time.Time{}); return err },
+		"Envelope.Open":                   func() error { var e *Envelope; _, err := e.Open([]*PublicKey{pub}, msgs); return err },
+		"Envelope.UnmarshalBinary":        func() error { var e *Envelope; return e.UnmarshalBinary(sigBuf) },
+		"RotateKey key":                   func() error { _, err := RotateKey(nilPriv, pub, time.Unix(1, 0)); return err },
+		"RotateKey new key":               func() error { _, err := RotateKey(priv, nilPub, time.Unix(1, 0)); return err },
+		"VerifyTransition record":         func() error { return VerifyTransition(pub, nil, pub) },
+		"VerifyTransition new key":        func() error { return VerifyTransition(pub, &TransitionRecord{Signature: sig}, nilPub) },
+		"SplitKeyAdditive key":            func() error { _, err := SplitKeyAdditive(nilPriv, 2, nil); return err },
+		"RecombineKey share":              func() error { _, err := RecombineKey([]*PrivateKey{priv, nilPriv}); return err },
+		"AdditiveBase suite":              func() error { _, err := AdditiveBase(nilSuite, msgs); return err },
+		"SignAdditiveShare share":         func() error { _, err := SignAdditiveShare(suite, nilPriv, sig.Sigma1, msgs); return err },
+		"SignAdditiveShare base":          func() error { _, err := SignAdditiveShare(suite, priv, nil, msgs); return err },
+		"SignWithShares share":            func() error { _, err := SignWithShares(suite, []*PrivateKey{priv, nilPriv}, msgs); return err },
+		"DeriveChildKey key":              func() error { _, _, err := DeriveChildKey(nilPriv, []uint32{Hardened}); return err },
+		"ValidatePublicKey suite":         func() error { return ValidatePublicKey(nilSuite, pub, 0) },
+		"ValidatePublicKey key":           func() error { return ValidatePublicKey(suite, nil, 0) },
+		"BlindKey key":                    func() error { _, _, _, err := BlindKey(nil, msg); return err },
+		"VerifyKeyBlinding proof":         func() error { return VerifyKeyBlinding(pub, pub, nil, msg) },
+		"UnmarshalKeyBlindingProof suite": func() error { _, err := UnmarshalKeyBlindingProof(nilSuite, msg); return err },
+		"KeySet Add":                      func() error { _, err := NewKeySet(0).Add(nil); return err },
+		"Envelope OpenWith":               func() error { _, err := (*Envelope)(nil).OpenWith(NewKeySet(0), msgs); return err },
+		"KeySet VerifyPartial":            func() error { _, err := NewKeySet(0).VerifyPartial(nil, nil, msg); return err },
+		"NewCryptoSigner key":             func() error { _, err := NewCryptoSigner(nil); return err },
+		"VerifyDigest key":                func() error { return VerifyDigest(nil, msg, msg, nil) },
+		"NewAggregate suite":              func() error { _, err := NewAggregate(nilSuite, priv, msgs); return err },
+		"NewAggregate key":                func() error { _, err := NewAggregate(suite, nilPriv, msgs); return err },
+		"AggregatePSSignAt key": func() error {
+			_, err := AggregatePSSignAt(suite, nilPriv, 1, &Aggregate{S: S, Indices: []int{1}}, msg)
+			return err
+		},
+		"AggregatePSSignAt signature": func() error { _, err := AggregatePSSignAt(suite, priv, 1, nil, msg); return err },
+		"Aggregate.Verify":            func() error { return (*Aggregate)(nil).Verify(suite, pub.Points(), msgs) },
+
+		"PrepareBlindSign suite": func() error {
+			_, _, err := PrepareBlindSign(nilSuite, pub, priv.BlindingKey(), msgs, random.New())
+			return err
+		},
+		"PrepareBlindSign key": func() error {
+			_, _, err := PrepareBlindSign(suite, nilPub, priv.BlindingKey(), msgs, random.New())
+			return err
+		},
+		"PrepareBlindSign blinding key": func() error {
+			_, _, err := PrepareBlindSign(suite, pub, nil, msgs, random.New())
+			return err
+		},
+		"PrepareBlindSign random": func() error {
+			_, _, err := PrepareBlindSign(suite, pub, priv.BlindingKey(), msgs, nil)
+			return err
+		},
+		"BlindSign key":     func() error { _, err := BlindSign(suite, nilPriv, nil); return err },
+		"BlindSign request": func() error { _, err := BlindSign(suite, priv, nil); return err },
+		"Unblind state":     func() error { _, err := Unblind(suite, nil, sig); return err },
+		"Unblind signature": func() error { _, err := Unblind(suite, &BlindState{}, nil); return err },
+		"CommitMessage params": func() error {
+			_, _, err := CommitMessage(suite, nil, msg, random.New())
+			return err
+		},
+		"CommitMessage random": func() error {
+			_, _, err := CommitMessage(suite, priv.BlindingKey(), msg, nil)
+			return err
+		},
+		"SignCommitment key":        func() error { _, err := SignCommitment(suite, nilPriv, nil); return err },
+		"SignCommitment commitment": func() error { _, err := SignCommitment(suite, priv, nil); return err },
+		"Finalize opening":          func() error { _, err := Finalize(suite, nil, sig); return err },
+		"Finalize signature":        func() error { _, err := Finalize(suite, &Opening{}, nil); return err },
+		"Commitment.MarshalBinary": func() error {
+			_, err := (*Commitment)(nil).MarshalBinary()
+			return err
+		},
+		"BlindingKey.MarshalBinary": func() error {
+			_, err := (*BlindingKey)(nil).MarshalBinary()
+			return err
+		},
+		"BlindSignRequest.MarshalBinary": func() error {
+			_, err := (*BlindSignRequest)(nil).MarshalBinary()
+			return err
+		},
+		"UnmarshalBlindingKey":      func() error { _, err := UnmarshalBlindingKey(nilSuite, pubBuf); return err },
+		"UnmarshalBlindSignRequest": func() error { _, err := UnmarshalBlindSignRequest(nilSuite, pubBuf); return err },
+
+		"ProveSignature suite": func() error {
+			_, err := ProveSignature(nilSuite, pub, sig, msgs, nil, msg)
+			return err
+		},
+		"ProveSignature key":       func() error { _, err := ProveSignature(suite, nilPub, sig, msgs, nil, msg); return err },
+		"ProveSignature signature": func() error { _, err := ProveSignature(suite, pub, nilSig, msgs, nil, msg); return err },
+		"VerifySignatureProof suite": func() error {
+			return VerifySignatureProof(nilSuite, pub, &SignatureProof{}, nil, msg)
+		},
+		"VerifySignatureProof key":   func() error { return VerifySignatureProof(suite, nilPub, &SignatureProof{}, nil, msg) },
+		"VerifySignatureProof proof": func() error { return VerifySignatureProof(suite, pub, nil, nil, msg) },
+		"DeriveSerial suite":         func() error { _, err := DeriveSerial(nilSuite, m, msg); return err },
+		"DeriveSerial secret":        func() error { _, err := DeriveSerial(suite, nil, msg); return err },
+		"ProveSerial suite": func() error {
+			_, err := ProveSerial(nilSuite, pub, sig, msgs, 0, nil, msg, msg)
+			return err
+		},
+		"ProveSerial signature": func() error { _, err := ProveSerial(suite, pub, nil, msgs, 0, nil, msg, msg); return err },
+		"VerifySerial proof":    func() error { return VerifySerial(suite, pub, nil, nil, msg, msg) },
+		"SerialProof.MarshalBinary": func() error {
+			_, err := (*SerialProof)(nil).MarshalBinary()
+			return err
+		},
+		"Delegate suite":      func() error { _, err := Delegate(nilSuite, sig, m, sig.Sigma1, nil); return err },
+		"Delegate signature":  func() error { _, err := Delegate(suite, nil, m, sig.Sigma1, nil); return err },
+		"Delegate secret":     func() error { _, err := Delegate(suite, sig, nil, sig.Sigma1, nil); return err },
+		"Delegate delegatee":  func() error { _, err := Delegate(suite, sig, m, nil, nil); return err },
+		"VerifyDelegated key": func() error { return VerifyDelegated(suite, nilPub, &DelegatedCredential{}, nil) },
+		"VerifyDelegated credential": func() error {
+			return VerifyDelegated(suite, pub, nil, nil)
+		},
+		"DelegatedCredential.MarshalBinary": func() error {
+			_, err := (*DelegatedCredential)(nil).MarshalBinary()
+			return err
+		},
+		"VerifyBatch suite": func() error {
+			return VerifyBatch(nilSuite, pub.Points(), [][]byte{msg}, []*Signature{sig}, random.New())
+		},
+		"VerifyBatch signature": func() error {
+			return VerifyBatch(suite, pub.Points(), [][]byte{msg}, []*Signature{nil}, random.New())
+		},
+		"VerifyPartial proof": func() error { return VerifyPartial(suite, pub, nil, nil, msg) },
+		"SignatureProof.MarshalBinary": func() error {
+			_, err := (*SignatureProof)(nil).MarshalBinary()
+			return err
+		},
+		"UnmarshalSignatureProof": func() error { _, err := UnmarshalSignatureProof(nilSuite, sigBuf); return err },
+
+		"EncryptSignature suite": func() error {
+			_, err := EncryptSignature(nilSuite, pub.X, sig, pub, msgs)
+			return err
+		},
+		"EncryptSignature auditor": func() error {
+			_, err := EncryptSignature(suite, nil, sig, pub, msgs)
+			return err
+		},
+		"EncryptSignature signature": func() error {
+			_, err := EncryptSignature(suite, sig.Sigma1, nilSig, pub, msgs)
+			return err
+		},
+		"VerifyEncryptedSignature": func() error {
+			return VerifyEncryptedSignature(suite, sig.Sigma1, pub, msgs, nil)
+		},
+		"DecryptSignature": func() error { _, err := DecryptSignature(suite, m, nil); return err },
+		"EncryptedSignature.MarshalBinary": func() error {
+			_, err := (*EncryptedSignature)(nil).MarshalBinary()
+			return err
+		},
+		"UnmarshalEncryptedSignature": func() error { _, err := UnmarshalEncryptedSignature(nilSuite, sigBuf); return err },
+
+		"MultiSigBase suite": func() error { _, err := MultiSigBase(nilSuite, msg); return err },
+		"MultiSign key":      func() error { _, err := MultiSign(suite, nilPriv, sig.Sigma1, msg); return err },
+		"MultiSign base":     func() error { _, err := MultiSign(suite, priv, nil, msg); return err },
+		"CombineSignatures":  func() error { _, err := CombineSignatures(suite, []*Signature{nilSig}); return err },
+		"AggregatePublicKeys": func() error {
+			_, err := AggregatePublicKeys(suite, []*PublicKey{nilPub})
+			return err
+		},
+		"VerifyMultiSig key":       func() error { return VerifyMultiSig(suite, nilPub, msg, sig) },
+		"VerifyMultiSig signature": func() error { return VerifyMultiSig(suite, pub, msg, nilSig) },
+
+		"AggregateSignAcross key":   func() error { _, err := AggregateSignAcross(suite, nilPriv, sig, msg); return err },
+		"AggregateSignAcross suite": func() error { _, err := AggregateSignAcross(nilSuite, priv, sig, msg); return err },
+		"AggregateVerify key":       func() error { return AggregateVerify(suite, []*PublicKey{nilPub}, msgs, sig) },
+		"AggregateVerify signature": func() error { return AggregateVerify(suite, []*PublicKey{pub}, msgs, nilSig) },
+
+		"ProvePossession suite":  func() error { _, err := ProvePossession(nilSuite, priv, random.New()); return err },
+		"ProvePossession key":    func() error { _, err := ProvePossession(suite, nilPriv, random.New()); return err },
+		"ProvePossession random": func() error { _, err := ProvePossession(suite, priv, nil); return err },
+		"VerifyPossession key":   func() error { return VerifyPossession(suite, nilPub, &PossessionProof{}) },
+		"VerifyPossession proof": func() error { return VerifyPossession(suite, pub, nil) },
+		"PossessionProof.MarshalBinary": func() error {
+			_, err := (*PossessionProof)(nil).MarshalBinary()
+			return err
+		},
+		"UnmarshalPossessionProof": func() error { _, err := UnmarshalPossessionProof(nilSuite, sigBuf); return err },
+
+		"AggregatePublicKeysSecure": func() error {
+			_, err := AggregatePublicKeysSecure(suite, []*PublicKey{nilPub})
+			return err
+		},
+		"CombineSignaturesSecure": func() error {
+			_, err := CombineSignaturesSecure(suite, []*PublicKey{pub}, []*Signature{nilSig})
+			return err
+		},
+
+		"NewPrivateKey suite": func() error { _, err := NewPrivateKey(nilSuite, priv.Scalars()); return err },
+		"NewPrivateKey key":   func() error { _, err := NewPrivateKey(suite, nil); return err },
+		"NewPublicKey suite":  func() error { _, err := NewPublicKey(nilSuite, pub.Points()); return err },
+		"NewPublicKey key":    func() error { _, err := NewPublicKey(suite, nil); return err },
+		"NewSignature suite":  func() error { _, err := NewSignature(nilSuite, S); return err },
+		"NewSignature S":      func() error { _, err := NewSignature(suite, nil); return err },
+		"Components":          func() error { _, err := nilSig.Components(); return err },
+		"IsEquivalent": func() error {
+			_, err := nilSig.IsEquivalent(suite, sig, pub.Points(), msgs)
+			return err
+		},
+		"IsEquivalent suite": func() error {
+			_, err := sig.IsEquivalent(nilSuite, sig, pub.Points(), msgs)
+			return err
+		},
+
+		"PrivateKey.MarshalBinary":    func() error { _, err := nilPriv.MarshalBinary(); return err },
+		"PrivateKey.UnmarshalBinary":  func() error { return nilPriv.UnmarshalBinary(pubBuf) },
+		"PrivateKey.GobEncode":        func() error { _, err := nilPriv.GobEncode(); return err },
+		"PublicKey.MarshalBinary":     func() error { _, err := nilPub.MarshalBinary(); return err },
+		"PublicKey.MarshalCompressed": func() error { _, err := nilPub.MarshalCompressed(); return err },
+		"PublicKey.UnmarshalBinary":   func() error { return nilPub.UnmarshalBinary(pubBuf) },
+		"PublicKey.Fingerprint":       func() error { _, err := nilPub.Fingerprint(); return err },
+		"PublicKey.Hex":               func() error { _, err := nilPub.Hex(); return err },
+		"PublicKey.UnmarshalText":     func() error { return nilPub.UnmarshalText([]byte("0x00")) },
+		"PublicKey.ToCOSEKey":         func() error { _, err := nilPub.ToCOSEKey(); return err },
+		"PublicKey.FromCOSEKey":       func() error { return nilPub.FromCOSEKey(nil, 1) },
+		"PublicKey.GobDecode":         func() error { return nilPub.GobDecode(pubBuf) },
+		"PublicKey without suite":     func() error { _, err := (&PublicKey{X: pub.X, Y: pub.Y}).MarshalBinary(); return err },
+		"Signature.MarshalBinary":     func() error { _, err := nilSig.MarshalBinary(); return err },
+		"Signature.MarshalCompressed": func() error { _, err := nilSig.MarshalCompressed(); return err },
+		"Signature.UnmarshalBinary":   func() error { return nilSig.UnmarshalBinary(sigBuf) },
+		"Signature.Hex":               func() error { _, err := nilSig.Hex(); return err },
+		"Signature components":        func() error { _, err := (&Signature{suite: suite}).MarshalBinary(); return err },
+
+		"UnmarshalPrivateKey":       func() error { _, err := UnmarshalPrivateKey(nilSuite, pubBuf); return err },
+		"UnmarshalPublicKey":        func() error { _, err := UnmarshalPublicKey(nilSuite, pubBuf); return err },
+		"UnmarshalSignature":        func() error { _, err := UnmarshalSignature(nilSuite, sigBuf); return err },
+		"UnmarshalPublicKey data":   func() error { _, err := UnmarshalPublicKey(suite, nil); return err },
+		"UnmarshalPrivateKeyLegacy": func() error { _, err := UnmarshalPrivateKeyLegacy(nilSuite, nil); return err },
+		"UnmarshalPublicKeyLegacy":  func() error { _, err := UnmarshalPublicKeyLegacy(nilSuite, nil); return err },
+		"UnmarshalSignatureLegacy":  func() error { _, err := UnmarshalSignatureLegacy(nilSuite, nil); return err },
+		"UnmarshalPublicKeyCompressed": func() error {
+			_, err := UnmarshalPublicKeyCompressed(nilSuite, pubBuf)
+			return err
+		},
+		"UnmarshalSignatureCompressed": func() error {
+			_, err := UnmarshalSignatureCompressed(nilSuite, sigBuf)
+			return err
+		},
+		"MigrateKey":       func() error { _, err := MigrateKey(nilSuite, nil); return err },
+		"PublicKeyFromHex": func() error { _, err := PublicKeyFromHex(""); return err },
+		"SignatureFromHex": func() error { _, err := SignatureFromHex(""); return err },
+
+		"RegisterSuite":   func() error { return RegisterSuite(0x7f, "nil", nilSuite) },
+		"SuiteIDOf":       func() error { _, err := SuiteIDOf(nilSuite); return err },
+		"NewKeyGenerator": func() error { _, err := NewKeyGenerator(nilSuite); return err },
+		"ExportKeyPairs":  func() error { _, err := ExportKeyPairs(nilPriv); return err },
+		"ExportPublicKeyPairs": func() error {
+			_, err := ExportPublicKeyPairs(nilPub)
+			return err
+		},
+		"ImportKeyPairs suite": func() error { _, _, err := ImportKeyPairs(nilSuite, pairs); return err },
+		"ImportKeyPairs pairs": func() error { _, _, err := ImportKeyPairs(suite, nil); return err },
+		"ImportKeyPairs pair": func() error {
+			_, _, err := ImportKeyPairs(suite, []*key.Pair{pairs[0], nil})
+			return err
+		},
+	}
+	for name, call := range cases {
+		require.NotPanics(t, func() { require.NotNil(t, call(), name) }, name)
+	}
+
+	// Accessors without an error result are nil-safe.
+	require.NotPanics(t, func() {
+		require.Nil(t, nilPriv.Suite())
+		require.Nil(t, nilPriv.Scalars())
+		require.Nil(t, nilPriv.Public())
+		require.Nil(t, nilPriv.BlindingKey())
+		require.Nil(t, nilPub.Suite())
+		require.Nil(t, nilPub.Points())
+		require.Nil(t, nilSig.Suite())
+		require.Nil(t, DefaultDST(nilSuite))
+	})
+}
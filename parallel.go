@@ -0,0 +1,176 @@
+package ps
+
+import (
+	"context"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// VerifyItem is a signature S on the messages Msgs, to be checked by
+// VerifyAll as PSBatchVerify does.
+type VerifyItem struct {
+	Msgs      [][]byte
+	Signature [][]byte
+}
+
+// VerifyAll checks items under pubKey with up to workers goroutines and
+// returns the error of every item by index, nil for valid signatures. The
+// second result is the context's error if ctx was cancelled, in which case
+// the items not verified yet report it too, and otherwise joins a
+// *BatchError per failing item, if any. With workers <= 1 the items are
+// checked sequentially in the calling goroutine. Every worker has its own
+// copy of the key, so no kyber object is shared between goroutines.
+func VerifyAll(ctx context.Context, suite pairing.Suite, pubKey []kyber.Point, items []VerifyItem, workers int, opts ...Option) ([]error, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return nil, err
+	}
+	if err := o.checkBatchItems(len(items)); err != nil {
+		return nil, err
+	}
+	ob := observer{o.inst}
+	errs := make([]error, len(items))
+	if workers <= 1 {
+		for i, it := range items {
+			if err := ctx.Err(); err != nil {
+				fillErrors(errs[i:], err)
+				return errs, err
+			}
+			start := ob.start()
+			errs[i] = PSBatchVerify(suite, pubKey, it.Msgs, it.Signature, opts...)
+			ob.verified(start, errs[i], len(it.Msgs))
+		}
+		return errs, summarize("verify item", errs)
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		key := make([]kyber.Point, len(pubKey))
+		for i, p := range pubKey {
+			key[i] = p.Clone()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				start := ob.start()
+				errs[i] = PSBatchVerify(suite, key, items[i].Msgs, items[i].Signature, opts...)
+				ob.verified(start, errs[i], len(items[i].Msgs))
+			}
+		}()
+	}
+	sent := 0
+feed:
+	for ; sent < len(items) && ctx.Err() == nil; sent++ {
+		select {
+		case next <- sent:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if sent < len(items) {
+		fillErrors(errs[sent:], ctx.Err())
+		return errs, ctx.Err()
+	}
+	return errs, summarize("verify item", errs)
+}
+
+// fillErrors sets every entry of errs to err.
+func fillErrors(errs []error, err error) {
+	for i := range errs {
+		errs[i] = err
+	}
+}
+
+// WithParallelism lets NewKeyPair and BatchSign spread their work over up to
+// workers goroutines: NewKeyPair derives the points of the public key in
+// parallel, and BatchSign hashes and weighs its messages in chunks of at
+// least parallelMinChunk. Scalars are still picked sequentially and sums are
+// reduced in order, so results do not depend on the number of workers. With
+// workers <= 1, the default, the work is done in the calling goroutine.
+func WithParallelism(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+// parallelMinChunk is the fewest messages BatchSign gives a worker. Hashing
+// and weighing fewer costs less than starting a goroutine.
+const parallelMinChunk = 64
+
+// derivePublicKey returns g^k for every scalar k of priKey, the i-th point
+// computed by worker i mod workers.
+func derivePublicKey(suite pairing.Suite, priKey []kyber.Scalar, workers int) []kyber.Point {
+	pubKey := make([]kyber.Point, len(priKey))
+	if workers <= 1 {
+		for i, k := range priKey {
+			pubKey[i] = suite.G2().Point().Mul(k, nil)
+		}
+		return pubKey
+	}
+	if workers > len(priKey) {
+		workers = len(priKey)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(priKey); i += workers {
+				pubKey[i] = suite.G2().Point().Mul(priKey[i], nil)
+			}
+		}(w)
+	}
+	wg.Wait()
+	return pubKey
+}
+
+// weighMessages hashes msgs to m_1,...,m_k under dst and returns them with
+// y_1*m_1 + ... + y_k*m_k for the y_i of priKey, over up to workers
+// goroutines. Every worker sums its chunk in its own scalar, and the partial
+// sums are added in chunk order.
+func weighMessages(suite pairing.Suite, priKey []kyber.Scalar, dst []byte, msgs [][]byte, workers int) ([]kyber.Scalar, kyber.Scalar) {
+	m := make([]kyber.Scalar, len(msgs))
+	if max := len(msgs) / parallelMinChunk; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		for i, msg := range msgs {
+			m[i] = hashToScalar(suite, dst, msg)
+		}
+		return m, weighScalars(suite, priKey, m, 0, len(m))
+	}
+	chunk := (len(msgs) + workers - 1) / workers
+	sums := make([]kyber.Scalar, workers)
+	var wg sync.WaitGroup
+	for w := range sums {
+		start, end := w*chunk, (w+1)*chunk
+		if end > len(msgs) {
+			end = len(msgs)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				m[i] = hashToScalar(suite, dst, msgs[i])
+			}
+			sums[w] = weighScalars(suite, priKey, m, start, end)
+		}(w, start, end)
+	}
+	wg.Wait()
+	sum := newScalar(suite)
+	for _, s := range sums {
+		sum.Add(sum, s)
+	}
+	return m, sum
+}
@@ -0,0 +1,204 @@
+package ps
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// verifyItems signs n two-message items with priv.
+func verifyItems(t testing.TB, suite pairing.Suite, priv *PrivateKey, n int) []VerifyItem {
+	items := make([]VerifyItem, n)
+	for i := range items {
+		msgs := [][]byte{[]byte(fmt.Sprintf("tx %d", i)), []byte("block 7")}
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		items[i] = VerifyItem{Msgs: msgs, Signature: S}
+	}
+	return items
+}
+
+func TestVerifyAll(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		items := verifyItems(t, suite, priv, 12)
+		items[3].Signature = items[4].Signature
+		items[9].Msgs = [][]byte{[]byte("tx 9")}
+		items[10].Signature = nil
+
+		for _, workers := range []int{0, 1, 4, 32} {
+			errs, err := VerifyAll(context.Background(), suite, pub.Points(), items, workers)
+			var batchErr *BatchError
+			require.True(t, errors.As(fmt.Errorf("block 7: %w", err), &batchErr))
+			require.Equal(t, 3, batchErr.Index)
+			require.Equal(t, []int{3, 9, 10}, failedIndices(t, err))
+			require.True(t, errors.Is(err, ErrInvalidSignature))
+			require.Equal(t, len(items), len(errs))
+			for i, err := range errs {
+				switch i {
+				case 3, 9:
+					require.Equal(t, ErrInvalidSignature, err, "%d workers, item %d", workers, i)
+				case 10:
+					require.Equal(t, ErrNilSignature, err, "%d workers, item %d", workers, i)
+				default:
+					require.Nil(t, err, "%d workers, item %d", workers, i)
+				}
+			}
+		}
+
+		// A cancelled context stops the verification.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, workers := range []int{1, 4} {
+			errs, err := VerifyAll(ctx, suite, pub.Points(), items, workers)
+			require.Equal(t, context.Canceled, err)
+			require.Equal(t, context.Canceled, errs[len(errs)-1])
+		}
+	})
+}
+
+func BenchmarkVerifyAll(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		priv, pub := newTestKeys(b, suite, 2)
+		items := verifyItems(b, suite, priv, 64)
+		for workers := 1; ; workers *= 2 {
+			if workers > runtime.NumCPU() {
+				workers = runtime.NumCPU()
+			}
+			b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := VerifyAll(context.Background(), suite, pub.Points(), items, workers); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			if workers == runtime.NumCPU() {
+				break
+			}
+		}
+	})
+}
+
+// seededStreams returns n random streams fixed by their index.
+func seededStreams(n int) []cipher.Stream {
+	randoms := make([]cipher.Stream, n)
+	for i := range randoms {
+		randoms[i] = seededStream(fmt.Sprintf("key %d", i))
+	}
+	return randoms
+}
+
+func TestNewKeyPairParallelism(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		var encoded [][]byte
+		for _, workers := range []int{1, 8, 100} {
+			private, public, err := NewKeyPairPoints(suite, seededStreams(21), WithParallelism(workers))
+			require.Nil(t, err)
+			priv, err := NewPrivateKey(suite, private)
+			require.Nil(t, err)
+			buf, err := priv.MarshalBinary()
+			require.Nil(t, err)
+			pub, err := NewPublicKey(suite, public)
+			require.Nil(t, err)
+			pubBuf, err := pub.MarshalBinary()
+			require.Nil(t, err)
+			encoded = append(encoded, append(buf, pubBuf...))
+		}
+		require.Equal(t, encoded[0], encoded[1])
+		require.Equal(t, encoded[0], encoded[2])
+	})
+}
+
+func BenchmarkNewKeyPairParallelism(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		randoms := make([]cipher.Stream, 501)
+		for i := range randoms {
+			randoms[i] = random.New()
+		}
+		for workers := 1; ; workers *= 2 {
+			if workers > runtime.NumCPU() {
+				workers = runtime.NumCPU()
+			}
+			b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, err := NewKeyPairPoints(suite, randoms, WithParallelism(workers)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			if workers == runtime.NumCPU() {
+				break
+			}
+		}
+	})
+}
+
+// testScalarKey picks a private key for n messages without deriving its
+// public key, for tests that only sign.
+func testScalarKey(t testing.TB, suite pairing.Suite, n int) []kyber.Scalar {
+	key := make([]kyber.Scalar, n+1)
+	for i := range key {
+		k, err := pickScalar(suite, random.New())
+		require.Nil(t, err)
+		key[i] = k
+	}
+	return key
+}
+
+func TestBatchSignParallelism(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := make([][]byte, 200)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		key := testScalarKey(t, suite, len(msgs))
+
+		var sigs [][][]byte
+		for _, workers := range []int{1, 2, 3, 8} {
+			S, err := BatchSign(seededSuite(suite, "batch sign"), key, msgs, WithParallelism(workers))
+			require.Nil(t, err)
+			sigs = append(sigs, S)
+			S, err = BatchSign(suite, key, msgs, WithParallelism(workers), DeterministicSign())
+			require.Nil(t, err)
+			sigs = append(sigs, S)
+		}
+		for i := 2; i < len(sigs); i++ {
+			require.Equal(t, sigs[i%2], sigs[i], "signature %d", i)
+		}
+		pub := derivePublicKey(suite, key, 1)
+		require.Nil(t, PSBatchVerify(suite, pub, msgs, sigs[0]))
+	})
+}
+
+func BenchmarkPSBatchSign10000(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msgs := make([][]byte, 10000)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		key := testScalarKey(b, suite, len(msgs))
+		for workers := 1; ; workers *= 2 {
+			if workers > runtime.NumCPU() {
+				workers = runtime.NumCPU()
+			}
+			b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := BatchSign(suite, key, msgs, WithParallelism(workers), WithConfig(Config{MaxAttributes: len(msgs)})); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			if workers == runtime.NumCPU() {
+				break
+			}
+		}
+	})
+}
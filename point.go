@@ -0,0 +1,34 @@
+package ps
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Decoders of some suites only check that a point lies on the curve, not that
+// it belongs to the prime-order subgroup used by the scheme: the G2 twist of
+// bn256 has a large cofactor. Points parsed from caller-supplied bytes are
+// therefore checked explicitly.
+
+// ErrInvalidPoint is returned for points outside the prime-order subgroup of
+// their group.
+var ErrInvalidPoint = errors.New("ps: point not in the prime-order subgroup")
+
+// checkSubgroup reports whether p lies in the subgroup of prime order n of g.
+// Since n itself reduces to the zero scalar, it tests (n-1)*p == -p, which is
+// equivalent to n*p being the identity.
+func checkSubgroup(g kyber.Group, p kyber.Point) error {
+	minusOne := g.Scalar().One()
+	minusOne.Neg(minusOne)
+	return checkSubgroupWith(minusOne, p, g.Point(), g.Point())
+}
+
+// checkSubgroupWith implements checkSubgroup, computing into the points mul
+// and neg of the group of p.
+func checkSubgroupWith(minusOne kyber.Scalar, p, mul, neg kyber.Point) error {
+	if !mul.Mul(minusOne, p).Equal(neg.Neg(p)) {
+		return ErrInvalidPoint
+	}
+	return nil
+}
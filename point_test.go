@@ -0,0 +1,60 @@
+package ps
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// twistPointOutsideG2 returns the encoding of a point on the bn256 twist that
+// is not in G2, found by decompressing successive x coordinates.
+func twistPointOutsideG2(t *testing.T) []byte {
+	c := make([]byte, 1+2*bn256FpLen)
+	c[0] = tagEven
+	for i := uint32(1); ; i++ {
+		binary.BigEndian.PutUint32(c[len(c)-4:], i)
+		if raw, err := decompressBN256G2(c); err == nil {
+			return raw
+		}
+	}
+}
+
+func TestSubgroupCheck(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+			require.Nil(t, checkSubgroup(g, g.Point().Pick(suite.RandomStream())))
+			require.Nil(t, checkSubgroup(g, g.Point().Null()))
+		}
+	})
+
+	suite := pairing.NewSuiteBn256()
+	raw := twistPointOutsideG2(t)
+	p := suite.G2().Point()
+	if p.UnmarshalBinary(raw) != nil {
+		t.Skip("the bn256 decoder already rejects points outside G2")
+	}
+	require.Equal(t, ErrInvalidPoint, checkSubgroup(suite.G2(), p))
+
+	_, pub := newTestKeys(t, suite, 1)
+	buf, err := pub.MarshalBinary()
+	require.Nil(t, err)
+	copy(buf[headerLen+2:], raw)
+	_, err = UnmarshalPublicKey(suite, buf)
+	require.True(t, errors.Is(err, ErrInvalidPoint))
+}
+
+func TestValidatePointsOption(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		sig, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, sig, ValidatePoints(true)))
+		require.Nil(t, Verify(suite, pub.Points(), msg, sig, ValidatePoints(false)))
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), [][]byte{msg}, sig, ValidatePoints(false)))
+	})
+}
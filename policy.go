@@ -0,0 +1,330 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A KeyPolicy restricts what a private key is used for: the slots reserved
+// by convention, such as slot 0 for a schema hash and slot 1 for an epoch,
+// which application messages must not fill, the most messages a signature
+// may cover, and the operations allowed. It travels with the *PrivateKey,
+// see GenerateKey and SetPolicy, and is serialized with it; verifiers never
+// need it.
+//
+// The policy is consulted by the operations taking a *PrivateKey:
+// PrivateKey.BatchSign and SignReserved, ConcurrentSigner, BlindSign and
+// SignCommitment, and the aggregation functions. BlindSign and
+// SignCommitment cannot see the committed messages, so only the operation
+// and the number of messages are checked there. The raw scalars returned by
+// Scalars carry no policy: the functions taking them, such as BatchSign,
+// cannot enforce it.
+
+var (
+	// ErrPolicyViolation is returned when an operation is refused by the
+	// policy of its key.
+	ErrPolicyViolation = errors.New("ps: key policy violation")
+
+	// ErrInvalidPolicy is returned for policies allowing no operation or
+	// reserving slots outside the key.
+	ErrInvalidPolicy = errors.New("ps: invalid key policy")
+)
+
+// KeyOperation is a set of operations a key may be used for.
+type KeyOperation uint8
+
+// The operations of a key policy.
+const (
+	OpSign KeyOperation = 1 << iota
+	OpBlindSign
+	OpAggregate
+
+	AllOperations = OpSign | OpBlindSign | OpAggregate
+)
+
+func (op KeyOperation) String() string {
+	switch op {
+	case OpSign:
+		return "sign"
+	case OpBlindSign:
+		return "blind-sign"
+	case OpAggregate:
+		return "aggregate"
+	}
+	return fmt.Sprintf("KeyOperation(%#x)", uint8(op))
+}
+
+// KeyPolicy is the policy of a private key. Reserved lists the slots,
+// counting from 0, that application messages may not fill; MaxMessages, if
+// not 0, bounds the number of slots a signature covers; Operations are the
+// operations allowed.
+type KeyPolicy struct {
+	Reserved    []int
+	MaxMessages int
+	Operations  KeyOperation
+}
+
+// GenerateKey returns a new key pair with attrs attribute slots and the
+// policy, which may be nil, drawing its scalars from the suite's random
+// stream.
+func GenerateKey(suite pairing.Suite, attrs int, policy *KeyPolicy, opts ...Option) (*PrivateKey, *PublicKey, error) {
+	if suite == nil {
+		return nil, nil, ErrNilSuite
+	}
+	if attrs < 1 {
+		return nil, nil, fmt.Errorf("%w: %d attributes", ErrKeyLengthMismatch, attrs)
+	}
+	randoms := make([]cipher.Stream, attrs+1)
+	for i := range randoms {
+		randoms[i] = suite.RandomStream()
+	}
+	private, public, err := NewKeyPairPoints(suite, randoms, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv := &PrivateKey{suite: suite, X: private[0], Y: private[1:]}
+	if err := priv.SetPolicy(policy); err != nil {
+		return nil, nil, err
+	}
+	return priv, &PublicKey{suite: suite, X: public[0], Y: public[1:]}, nil
+}
+
+// SetPolicy attaches a copy of p to the key, replacing its policy. A nil p
+// removes it.
+func (k *PrivateKey) SetPolicy(p *KeyPolicy) error {
+	if err := k.check(); err != nil {
+		return err
+	}
+	if p == nil {
+		k.policy = nil
+		return nil
+	}
+	c := p.clone()
+	if err := c.check(len(k.Y)); err != nil {
+		return err
+	}
+	k.policy = c
+	return nil
+}
+
+// Policy returns a copy of the policy of the key, or nil if it has none.
+func (k *PrivateKey) Policy() *KeyPolicy {
+	if k == nil || k.policy == nil {
+		return nil
+	}
+	return k.policy.clone()
+}
+
+func (p *KeyPolicy) clone() *KeyPolicy {
+	c := *p
+	c.Reserved = append([]int{}, p.Reserved...)
+	sort.Ints(c.Reserved)
+	return &c
+}
+
+// check reports whether the policy, with its reserved slots sorted, is
+// valid for a key with slots attribute slots.
+func (p *KeyPolicy) check(slots int) error {
+	if p.Operations == 0 || p.Operations&^AllOperations != 0 {
+		return fmt.Errorf("%w: operations %#x", ErrInvalidPolicy, uint8(p.Operations))
+	}
+	if p.MaxMessages < 0 || p.MaxMessages > slots {
+		return fmt.Errorf("%w: at most %d messages for %d slots", ErrInvalidPolicy, p.MaxMessages, slots)
+	}
+	for j, i := range p.Reserved {
+		if i < 0 || i >= slots {
+			return fmt.Errorf("%w: reserved slot %d outside 0..%d", ErrInvalidPolicy, i, slots-1)
+		}
+		if j > 0 && i == p.Reserved[j-1] {
+			return fmt.Errorf("%w: slot %d reserved twice", ErrInvalidPolicy, i)
+		}
+	}
+	return nil
+}
+
+// reserved reports whether slot i is reserved.
+func (p *KeyPolicy) reserved(i int) bool {
+	j := sort.SearchInts(p.Reserved, i)
+	return j < len(p.Reserved) && p.Reserved[j] == i
+}
+
+// allow checks that the policy, if not nil, permits op on messages in the
+// slots given, counting from 0.
+func (p *KeyPolicy) allow(op KeyOperation, slots ...int) error {
+	if p == nil {
+		return nil
+	}
+	if p.Operations&op == 0 {
+		return fmt.Errorf("%w: %v not allowed", ErrPolicyViolation, op)
+	}
+	for _, i := range slots {
+		if p.MaxMessages > 0 && i >= p.MaxMessages {
+			return fmt.Errorf("%w: slot %d beyond the %d messages allowed", ErrPolicyViolation, i, p.MaxMessages)
+		}
+		if p.reserved(i) {
+			return fmt.Errorf("%w: slot %d is reserved", ErrPolicyViolation, i)
+		}
+	}
+	return nil
+}
+
+// allowCount checks that the policy, if not nil, permits op on n messages
+// whose slots are not known, as in blind signing.
+func (p *KeyPolicy) allowCount(op KeyOperation, n int) error {
+	if p == nil {
+		return nil
+	}
+	if p.Operations&op == 0 {
+		return fmt.Errorf("%w: %v not allowed", ErrPolicyViolation, op)
+	}
+	if p.MaxMessages > 0 && n > p.MaxMessages {
+		return fmt.Errorf("%w: %d messages, at most %d allowed", ErrPolicyViolation, n, p.MaxMessages)
+	}
+	return nil
+}
+
+// slotRange returns the slots first to first+n-1.
+func slotRange(first, n int) []int {
+	slots := make([]int, n)
+	for i := range slots {
+		slots[i] = first + i
+	}
+	return slots
+}
+
+// BatchSign is BatchSign with the key, refusing with ErrPolicyViolation
+// messages in reserved slots or beyond those its policy allows.
+func (k *PrivateKey) BatchSign(msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	if err := k.policy.allow(OpSign, slotRange(0, len(msgs))...); err != nil {
+		return nil, err
+	}
+	S, err := BatchSign(k.suite, k.Scalars(), msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.audit.signed(auditContext(opts), msgs, S); err != nil {
+		return nil, err
+	}
+	return S, nil
+}
+
+// SignReserved signs reserved, messages in reserved slots of the key's
+// policy, and msgs in the unreserved slots from 0 upwards, in order. The
+// slots left out do not take part in the signature, as with
+// BatchSignIndexed, which it verifies with; filling every slot up to the
+// last gives the signature BatchSign gives on the messages in slot order.
+func (k *PrivateKey) SignReserved(reserved IndexedMessages, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	if err := k.policy.allowCount(OpSign, len(reserved)+len(msgs)); err != nil {
+		return nil, err
+	}
+	p := k.policy
+	if p == nil {
+		p = &KeyPolicy{}
+	}
+	all := make(IndexedMessages, 0, len(reserved)+len(msgs))
+	for n, msg := range reserved {
+		if !p.reserved(msg.Index) {
+			return nil, itemError("check index", n, fmt.Errorf("%w: slot %d is not reserved", ErrPolicyViolation, msg.Index))
+		}
+		all = append(all, msg)
+	}
+	slot := 0
+	for _, msg := range msgs {
+		for p.reserved(slot) {
+			slot++
+		}
+		all = append(all, IndexedMessage{Index: slot, Msg: msg})
+		slot++
+	}
+	for _, msg := range all {
+		if p.MaxMessages > 0 && msg.Index >= p.MaxMessages {
+			return nil, fmt.Errorf("%w: slot %d beyond the %d messages allowed", ErrPolicyViolation, msg.Index, p.MaxMessages)
+		}
+	}
+	S, err := BatchSignIndexed(k.suite, k.Scalars(), all, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if k.audit != nil {
+		sort.Slice(all, func(i, j int) bool { return all[i].Index < all[j].Index })
+		signed := make([][]byte, len(all))
+		for i, msg := range all {
+			signed[i] = msg.Msg
+		}
+		if err := k.audit.signed(auditContext(opts), signed, S); err != nil {
+			return nil, err
+		}
+	}
+	return S, nil
+}
+
+// appendPolicy encodes the policy p, which may be nil, as
+//
+//	0                                    without a policy
+//	1 || ops || max || n || r_1 || ... || r_n
+//
+// with max, n and the reserved slots r_i as counts.
+func appendPolicy(buf []byte, p *KeyPolicy) ([]byte, error) {
+	if p == nil {
+		return append(buf, 0), nil
+	}
+	buf = append(buf, 1, byte(p.Operations))
+	var err error
+	if buf, err = appendCount(buf, p.MaxMessages); err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(p.Reserved)); err != nil {
+		return nil, err
+	}
+	for _, i := range p.Reserved {
+		if buf, err = appendCount(buf, i); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// readPolicy decodes a policy encoded by appendPolicy.
+func readPolicy(data []byte) (*KeyPolicy, []byte, error) {
+	if len(data) < 1 {
+		return nil, nil, fmt.Errorf("%w: truncated policy", ErrInvalidPolicy)
+	}
+	switch data[0] {
+	case 0:
+		return nil, data[1:], nil
+	case 1:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown policy tag %d", ErrInvalidPolicy, data[0])
+	}
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("%w: truncated policy", ErrInvalidPolicy)
+	}
+	p := &KeyPolicy{Operations: KeyOperation(data[1])}
+	max, rest, err := readCount(data[2:])
+	if err != nil {
+		return nil, nil, err
+	}
+	p.MaxMessages = max
+	n, rest, err := readCount(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j := 0; j < n; j++ {
+		var i int
+		if i, rest, err = readCount(rest); err != nil {
+			return nil, nil, err
+		}
+		p.Reserved = append(p.Reserved, i)
+	}
+	return p, rest, nil
+}
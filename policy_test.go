@@ -0,0 +1,126 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestKeyPolicyReservedSlots(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		policy := &KeyPolicy{Reserved: []int{1, 0}, MaxMessages: 4, Operations: OpSign}
+		priv, pub, err := GenerateKey(suite, 5, policy)
+		require.Nil(t, err)
+		require.Equal(t, &KeyPolicy{Reserved: []int{0, 1}, MaxMessages: 4, Operations: OpSign}, priv.Policy())
+
+		// Application messages may not land in the reserved slots.
+		_, err = priv.BatchSign([][]byte{[]byte("alice")})
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+
+		schema := []byte("schema hash")
+		epoch := EpochAttribute(41)
+		reserved := IndexedMessages{{Index: 0, Msg: schema}, {Index: 1, Msg: epoch}}
+		S, err := priv.SignReserved(reserved, [][]byte{[]byte("alice"), []byte("paris")})
+		require.Nil(t, err)
+		// Verifiers need only the public key.
+		msgs := [][]byte{schema, epoch, []byte("alice"), []byte("paris")}
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		_, err = priv.SignReserved(IndexedMessages{{Index: 2, Msg: schema}}, nil)
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+		_, err = priv.SignReserved(reserved, [][]byte{[]byte("a"), []byte("b"), []byte("c")})
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+
+		// A sign-only key does not aggregate, and the policy survives a
+		// concurrent signer.
+		_, err = NewAggregate(suite, priv, msgs[:1])
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+		signer, err := NewConcurrentSigner(suite, priv, nil)
+		require.Nil(t, err)
+		_, err = signer.Sign([]byte("alice"))
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+
+		// Without a policy anything goes.
+		require.Nil(t, priv.SetPolicy(nil))
+		_, err = priv.BatchSign([][]byte{[]byte("alice")})
+		require.Nil(t, err)
+	})
+}
+
+func TestKeyPolicyOperations(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub, err := GenerateKey(suite, 2, &KeyPolicy{Operations: OpSign})
+		require.Nil(t, err)
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		req, _, err := PrepareBlindSign(suite, pub, priv.BlindingKey(), msgs, random.New())
+		require.Nil(t, err)
+		_, err = BlindSign(suite, priv, req)
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+		commitment, _, err := CommitMessage(suite, priv.BlindingKey(), msgs[0], random.New())
+		require.Nil(t, err)
+		_, err = SignCommitment(suite, priv, commitment)
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+
+		require.Nil(t, priv.SetPolicy(&KeyPolicy{Operations: OpBlindSign | OpAggregate, MaxMessages: 1}))
+		_, err = BlindSign(suite, priv, req)
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+		_, err = priv.BatchSign(msgs[:1])
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+		_, err = SignCommitment(suite, priv, commitment)
+		require.Nil(t, err)
+		_, err = AggregateSignAcross(suite, priv, nil, msgs[0])
+		require.Nil(t, err)
+
+		for _, bad := range []*KeyPolicy{
+			{},
+			{Operations: 1 << 7},
+			{Operations: OpSign, MaxMessages: 3},
+			{Operations: OpSign, Reserved: []int{2}},
+			{Operations: OpSign, Reserved: []int{0, 0}},
+		} {
+			require.True(t, errors.Is(priv.SetPolicy(bad), ErrInvalidPolicy), "%+v", bad)
+		}
+	})
+}
+
+func TestKeyPolicyEncoding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		policy := &KeyPolicy{Reserved: []int{0, 2}, MaxMessages: 3, Operations: OpSign | OpAggregate}
+		priv, pub, err := GenerateKey(suite, 3, policy)
+		require.Nil(t, err)
+		buf, err := priv.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalPrivateKey(suite, buf)
+		require.Nil(t, err)
+		require.Equal(t, policy, dec.Policy())
+		_, err = dec.BatchSign([][]byte{[]byte("alice")})
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+
+		// The public key is the same with or without a policy.
+		require.Nil(t, priv.SetPolicy(nil))
+		plain, err := priv.MarshalBinary()
+		require.Nil(t, err)
+		dec, err = UnmarshalPrivateKey(suite, plain)
+		require.Nil(t, err)
+		require.Nil(t, dec.Policy())
+		require.True(t, dec.Public().X.Equal(pub.X))
+
+		// Keys written before policies decode without one.
+		old := append([]byte{}, plain[:len(plain)-1]...)
+		old[1] = policyMinor - 1
+		dec, err = UnmarshalPrivateKey(suite, old)
+		require.Nil(t, err)
+		require.Nil(t, dec.Policy())
+
+		truncated := buf[:len(buf)-1]
+		_, err = UnmarshalPrivateKey(suite, truncated)
+		require.NotNil(t, err)
+		outside := append([]byte{}, buf...)
+		outside[len(outside)-1] = 3
+		_, err = UnmarshalPrivateKey(suite, outside)
+		require.True(t, errors.Is(err, ErrInvalidPolicy))
+	})
+}
@@ -0,0 +1,210 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A proof of possession shows that the owner of a public key (X, Y_1,...,
+// Y_r) knows its secrets x, y_1,...,y_r. It is a Schnorr proof for all of
+// them at once: the prover picks w_0,...,w_r, commits to R_i = g^(w_i) in G2,
+// and answers the challenge
+//
+//	c = H(pub || R_0 || ... || R_r)
+//
+// with z_i = w_i + c*s_i, where pub is the public key in its binary encoding
+// and H hashes to a scalar under the tag "PS-POP-<CURVE>-V1". The verifier
+// recomputes R_i = g^(z_i) / P_i^c. A rogue key built from other keys, such
+// as X_a / X_h, comes without its secrets and cannot be proven.
+
+// ErrInvalidPossessionProof is returned for public keys whose proof of
+// possession is missing or does not verify.
+var ErrInvalidPossessionProof = errors.New("ps: invalid proof of possession")
+
+// PossessionProof is a proof of possession (c, z_0,...,z_r) of the secrets
+// behind a public key, z_0 answering for x.
+type PossessionProof struct {
+	suite     pairing.Suite
+	Challenge kyber.Scalar
+	Responses []kyber.Scalar
+}
+
+// check reports whether the proof is complete.
+func (p *PossessionProof) check() error {
+	if p == nil || p.Challenge == nil || len(p.Responses) < 2 {
+		return ErrInvalidPossessionProof
+	}
+	if p.suite == nil {
+		return ErrNilSuite
+	}
+	for i, z := range p.Responses {
+		if z == nil {
+			return fmt.Errorf("%w: response %d is nil", ErrInvalidPossessionProof, i)
+		}
+	}
+	return nil
+}
+
+// possessionChallenge derives the challenge binding the proof to the
+// encoded public key and the commitments R.
+func possessionChallenge(suite pairing.Suite, pub *PublicKey, R []kyber.Point) (kyber.Scalar, error) {
+	buf, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.G2(), false, R...); err != nil {
+		return nil, err
+	}
+	return hashToScalar(suite, protocolDST(suite, "POP"), buf), nil
+}
+
+// ProvePossession proves knowledge of the secrets of priKey, reading
+// randomness from rand.
+func ProvePossession(suite pairing.Suite, priKey *PrivateKey, rand cipher.Stream) (*PossessionProof, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := priKey.check(); err != nil {
+		return nil, err
+	}
+	secrets := priKey.Scalars()
+	nonces := make([]kyber.Scalar, len(secrets))
+	R := make([]kyber.Point, len(secrets))
+	for i := range nonces {
+		var err error
+		if nonces[i], err = pickScalar(suite, rand); err != nil {
+			return nil, err
+		}
+		R[i] = suite.G2().Point().Mul(nonces[i], nil)
+	}
+	c, err := possessionChallenge(suite, priKey.Public(), R)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]kyber.Scalar, len(secrets))
+	for i, s := range secrets {
+		responses[i] = newScalar(suite).Mul(c, s)
+		responses[i].Add(responses[i], nonces[i])
+		nonces[i].Zero()
+	}
+	return &PossessionProof{suite: suite, Challenge: c, Responses: responses}, nil
+}
+
+// VerifyPossession checks a proof of possession of the secrets behind
+// pubKey. It returns ErrInvalidPossessionProof if the proof does not verify.
+func VerifyPossession(suite pairing.Suite, pubKey *PublicKey, proof *PossessionProof) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	if err := pubKey.check(); err != nil {
+		return err
+	}
+	if err := proof.check(); err != nil {
+		return err
+	}
+	points := pubKey.Points()
+	if len(proof.Responses) != len(points) {
+		return fmt.Errorf("%w: %d responses for %d key points", ErrInvalidPossessionProof, len(proof.Responses), len(points))
+	}
+	R := make([]kyber.Point, len(points))
+	for i, p := range points {
+		R[i] = suite.G2().Point().Mul(proof.Responses[i], nil)
+		R[i].Sub(R[i], suite.G2().Point().Mul(proof.Challenge, p))
+	}
+	c, err := possessionChallenge(suite, pubKey, R)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(proof.Challenge) {
+		return ErrInvalidPossessionProof
+	}
+	return nil
+}
+
+// WithPossessionProofs makes AggregatePublicKeys and AggregateVerify demand a
+// proof of possession for every key, proofs[i] belonging to the i-th key.
+func WithPossessionProofs(proofs []*PossessionProof) Option {
+	return func(o *options) {
+		o.proofs = append([]*PossessionProof{}, proofs...)
+		o.checkProofs = true
+	}
+}
+
+// checkPossession verifies the proofs of possession demanded by
+// WithPossessionProofs, if any.
+func (o *options) checkPossession(suite pairing.Suite, pubs []*PublicKey) error {
+	if !o.checkProofs {
+		return nil
+	}
+	if len(o.proofs) != len(pubs) {
+		return fmt.Errorf("%w: %d proofs for %d keys", ErrInvalidPossessionProof, len(o.proofs), len(pubs))
+	}
+	for i, pub := range pubs {
+		if err := VerifyPossession(suite, pub, o.proofs[i]); err != nil {
+			return fmt.Errorf("%w: key %d", err, i)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the proof as header || r || c || z_0 || ... || z_r.
+func (p *PossessionProof) MarshalBinary() ([]byte, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(p.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(p.Responses)-1); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, p.suite.G1(), append([]kyber.Scalar{p.Challenge}, p.Responses...)...)
+}
+
+// UnmarshalBinary decodes a proof, see PrivateKey.UnmarshalBinary.
+func (p *PossessionProof) UnmarshalBinary(data []byte) error {
+	if p == nil {
+		return ErrInvalidPossessionProof
+	}
+	suite, body, newer, err := readHeader(p.suite, data)
+	if err != nil {
+		return err
+	}
+	r, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, r+2)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	dec := &PossessionProof{suite: suite, Challenge: scalars[0], Responses: scalars[1:]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*p = *dec
+	return nil
+}
+
+// UnmarshalPossessionProof decodes a proof produced under suite.
+func UnmarshalPossessionProof(suite pairing.Suite, data []byte) (*PossessionProof, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	p := &PossessionProof{suite: suite}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,97 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestPossessionProof(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		privA, pubA := newTestKeys(t, suite, 2)
+		_, pubB := newTestKeys(t, suite, 2)
+
+		proof, err := ProvePossession(suite, privA, random.New())
+		require.Nil(t, err)
+		require.Nil(t, VerifyPossession(suite, pubA, proof))
+		require.Equal(t, ErrInvalidPossessionProof, VerifyPossession(suite, pubB, proof))
+
+		buf, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalPossessionProof(suite, buf)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPossession(suite, pubA, dec))
+		_, err = UnmarshalPossessionProof(suite, buf[:len(buf)-1])
+		require.NotNil(t, err)
+
+		forged := *proof
+		forged.Responses = append([]kyber.Scalar{}, proof.Responses...)
+		forged.Responses[2] = newScalar(suite).Pick(random.New())
+		require.Equal(t, ErrInvalidPossessionProof, VerifyPossession(suite, pubA, &forged))
+
+		// A proof for a shorter key does not cover a longer one.
+		privC, _ := newTestKeys(t, suite, 1)
+		short, err := ProvePossession(suite, privC, random.New())
+		require.Nil(t, err)
+		err = VerifyPossession(suite, pubA, short)
+		require.True(t, errors.Is(err, ErrInvalidPossessionProof))
+	})
+}
+
+func TestPossessionProofAggregation(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("committee decision 42")
+		honest, honestPub := newTestKeys(t, suite, 1)
+		attacker, attackerPub := newTestKeys(t, suite, 1)
+		honestProof, err := ProvePossession(suite, honest, random.New())
+		require.Nil(t, err)
+		attackerProof, err := ProvePossession(suite, attacker, random.New())
+		require.Nil(t, err)
+
+		pubs := []*PublicKey{honestPub, attackerPub}
+		proofs := []*PossessionProof{honestProof, attackerProof}
+		agg, err := AggregatePublicKeys(suite, pubs, WithPossessionProofs(proofs))
+		require.Nil(t, err)
+		h, err := MultiSigBase(suite, msg)
+		require.Nil(t, err)
+		var sigs []*Signature
+		for _, priv := range []*PrivateKey{honest, attacker} {
+			sig, err := MultiSign(suite, priv, h, msg)
+			require.Nil(t, err)
+			sigs = append(sigs, sig)
+		}
+		multi, err := CombineSignatures(suite, sigs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyMultiSig(suite, agg, msg, multi))
+
+		// The rogue key (X_a / X_h, Y_a / Y_h) makes the aggregate the
+		// attacker's own key, so the attacker alone signs for both. It comes
+		// without a proof, and reusing the attacker's proof fails.
+		rogue := &PublicKey{
+			suite: suite,
+			X:     suite.G2().Point().Sub(attackerPub.X, honestPub.X),
+			Y:     []kyber.Point{suite.G2().Point().Sub(attackerPub.Y[0], honestPub.Y[0])},
+		}
+		forged, err := AggregatePublicKeys(suite, []*PublicKey{honestPub, rogue})
+		require.Nil(t, err)
+		require.Nil(t, VerifyMultiSig(suite, forged, msg, sigs[1]))
+		_, err = AggregatePublicKeys(suite, []*PublicKey{honestPub, rogue}, WithPossessionProofs(proofs))
+		require.True(t, errors.Is(err, ErrInvalidPossessionProof))
+		_, err = AggregatePublicKeys(suite, pubs, WithPossessionProofs(proofs[:1]))
+		require.True(t, errors.Is(err, ErrInvalidPossessionProof))
+
+		// Aggregate verification across keys demands proofs alike.
+		across, err := AggregateSignAcross(suite, honest, nil, msg)
+		require.Nil(t, err)
+		across, err = AggregateSignAcross(suite, attacker, across, msg)
+		require.Nil(t, err)
+		msgs := [][]byte{msg, msg}
+		require.Nil(t, AggregateVerify(suite, pubs, msgs, across, WithPossessionProofs(proofs)))
+		err = AggregateVerify(suite, pubs, msgs, across, WithPossessionProofs([]*PossessionProof{attackerProof, honestProof}))
+		require.True(t, errors.Is(err, ErrInvalidPossessionProof))
+	})
+}
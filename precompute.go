@@ -0,0 +1,203 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Hashing a message to its scalar is the main cost of signing and verifying
+// short messages besides the pairings. A holder that shows the same
+// attributes many times, or a front-end that forwards them to a signing
+// backend, can hash them once with PrecomputeMessages and pass the result to
+// SignPrecomputed and VerifyPrecomputed.
+//
+// A MessageScalar is bound to the tag it was hashed under, and is encoded as
+//
+//	major || minor || id || len(dst) || dst || m
+//
+// A scalar carries no proof that it is the hash of any message. A backend
+// that signs scalars received from elsewhere signs whatever the sender chose,
+// so it must either trust the sender or re-check each scalar against its
+// message with Matches.
+
+// ErrDSTMismatch is returned when a precomputed message scalar was hashed
+// under another domain separation tag than the one in use.
+var ErrDSTMismatch = errors.New("ps: message scalar hashed under another tag")
+
+// MessageScalar is a message hashed to the scalar it is signed as.
+type MessageScalar struct {
+	suite  pairing.Suite
+	dst    []byte
+	Scalar kyber.Scalar
+}
+
+// PrecomputeMessages hashes msgs to scalars under the tag set by opts, as
+// BatchSign and PSBatchVerify do.
+func PrecomputeMessages(suite pairing.Suite, msgs [][]byte, opts ...Option) ([]MessageScalar, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	ms := make([]MessageScalar, len(msgs))
+	for i, msg := range msgs {
+		ms[i] = MessageScalar{suite: suite, dst: o.dst, Scalar: hashToScalar(suite, o.dst, msg)}
+	}
+	return ms, nil
+}
+
+// Matches reports whether m is the scalar of msg under the tag m was hashed
+// with.
+func (m MessageScalar) Matches(msg []byte) bool {
+	if m.suite == nil || m.Scalar == nil || len(msg) == 0 {
+		return false
+	}
+	want, err := hashToScalar(m.suite, m.dst, msg).MarshalBinary()
+	if err != nil {
+		return false
+	}
+	got, err := m.Scalar.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(want, got) == 1
+}
+
+// SignPrecomputed is BatchSign on messages hashed with PrecomputeMessages
+// under the same tag.
+func SignPrecomputed(suite pairing.Suite, priKey []kyber.Scalar, msgs []MessageScalar, opts ...Option) ([][]byte, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessageCount(len(priKey), len(msgs)); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	m, err := precomputedScalars(msgs, o)
+	if err != nil {
+		return nil, err
+	}
+	rand, err := o.signingStream(suite, priKey, m)
+	if err != nil {
+		return nil, err
+	}
+	return signScalars(suite, priKey, m, rand)
+}
+
+// VerifyPrecomputed is PSBatchVerify on messages hashed with
+// PrecomputeMessages under the same tag.
+func VerifyPrecomputed(suite pairing.Suite, pubKey []kyber.Point, msgs []MessageScalar, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkMessageCount(len(pubKey), len(msgs)); err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if S == nil {
+		return ErrNilSignature
+	}
+	if o.checkIndices {
+		if err := checkIndices(o.indices, len(msgs)); err != nil {
+			return err
+		}
+	}
+	m, err := precomputedScalars(msgs, o)
+	if err != nil {
+		return err
+	}
+	return verifyScalars(suite, pubKey, m, S, o.validatePoints)
+}
+
+// precomputedScalars returns the scalars of msgs, checking they were hashed
+// under the tag of o.
+func precomputedScalars(msgs []MessageScalar, o *options) ([]kyber.Scalar, error) {
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, err
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		if msg.Scalar == nil {
+			return nil, ErrNilMessage
+		}
+		if !bytes.Equal(msg.dst, o.dst) {
+			return nil, ErrDSTMismatch
+		}
+		m[i] = msg.Scalar
+	}
+	return m, nil
+}
+
+// MarshalBinary encodes the scalar together with its tag.
+func (m MessageScalar) MarshalBinary() ([]byte, error) {
+	if m.Scalar == nil {
+		return nil, ErrNilMessage
+	}
+	if m.suite == nil {
+		return nil, ErrNilSuite
+	}
+	buf, err := writeHeader(m.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendBytes(buf, m.dst); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, m.suite.G1(), m.Scalar)
+}
+
+// UnmarshalBinary decodes a scalar encoded by MarshalBinary. An empty
+// MessageScalar takes the suite from the encoding, through the registry.
+func (m *MessageScalar) UnmarshalBinary(data []byte) error {
+	if m == nil {
+		return ErrNilMessage
+	}
+	suite, body, newer, err := readHeader(m.suite, data)
+	if err != nil {
+		return err
+	}
+	dst, body, err := readBytes(body)
+	if err != nil {
+		return err
+	}
+	if len(dst) == 0 {
+		return ErrEmptyDST
+	}
+	scalars, body, err := decodeScalars(suite.G1(), body, 1)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(body, newer); err != nil {
+		return err
+	}
+	*m = MessageScalar{suite: suite, dst: dst, Scalar: scalars[0]}
+	return nil
+}
+
+// UnmarshalMessageScalar decodes a scalar of suite encoded by
+// MessageScalar.MarshalBinary.
+func UnmarshalMessageScalar(suite pairing.Suite, data []byte) (MessageScalar, error) {
+	if suite == nil {
+		return MessageScalar{}, ErrNilSuite
+	}
+	m := MessageScalar{suite: suite}
+	if err := m.UnmarshalBinary(data); err != nil {
+		return MessageScalar{}, err
+	}
+	return m, nil
+}
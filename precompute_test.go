@@ -0,0 +1,88 @@
+package ps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestPrecomputedMessages(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31"), []byte("country=FR")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		ms, err := PrecomputeMessages(suite, msgs)
+		require.Nil(t, err)
+		for i, m := range ms {
+			require.True(t, m.Matches(msgs[i]))
+			require.False(t, m.Matches([]byte("age=18")))
+		}
+
+		// The precomputed and direct paths accept each other's signatures.
+		S, err := SignPrecomputed(suite, priv.Scalars(), ms)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+		S, err = BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPrecomputed(suite, pub.Points(), ms, S))
+		require.Equal(t, ErrInvalidSignature, VerifyPrecomputed(suite, pub.Points(), []MessageScalar{ms[1], ms[0], ms[2]}, S))
+
+		// With deterministic signing both paths produce the same signature.
+		direct, err := BatchSign(suite, priv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		pre, err := SignPrecomputed(suite, priv.Scalars(), ms, DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, direct, pre)
+	})
+}
+
+func TestPrecomputedMessagesTag(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("name=Alice"), []byte("age=31")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		dst := WithDST([]byte("EXAMPLE-APP-V1"))
+
+		ms, err := PrecomputeMessages(suite, msgs, dst)
+		require.Nil(t, err)
+		S, err := SignPrecomputed(suite, priv.Scalars(), ms, dst)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S, dst))
+		require.Nil(t, VerifyPrecomputed(suite, pub.Points(), ms, S, dst))
+
+		_, err = SignPrecomputed(suite, priv.Scalars(), ms)
+		require.Equal(t, ErrDSTMismatch, err)
+		require.Equal(t, ErrDSTMismatch, VerifyPrecomputed(suite, pub.Points(), ms, S))
+	})
+}
+
+func TestMessageScalarEncoding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("name=Alice")
+		dst := WithDST([]byte("EXAMPLE-APP-V1"))
+		ms, err := PrecomputeMessages(suite, [][]byte{msg}, dst)
+		require.Nil(t, err)
+
+		buf, err := ms[0].MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalMessageScalar(suite, buf)
+		require.Nil(t, err)
+		require.True(t, dec.Scalar.Equal(ms[0].Scalar))
+		require.True(t, dec.Matches(msg))
+
+		// The tag travels with the scalar.
+		priv, pub := newTestKeys(t, suite, 1)
+		S, err := SignPrecomputed(suite, priv.Scalars(), []MessageScalar{dec}, dst)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, S, dst))
+
+		var reg MessageScalar
+		require.Nil(t, reg.UnmarshalBinary(buf))
+		require.True(t, reg.Scalar.Equal(ms[0].Scalar))
+
+		_, err = UnmarshalMessageScalar(suite, buf[:len(buf)-1])
+		require.NotNil(t, err)
+		_, err = UnmarshalMessageScalar(suite, append(buf, 0))
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package ps
+
+import (
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// A verifier that only ever checks a few slots of a wide issuer key need not
+// keep the whole key. ProjectPublicKey keeps X and the Y components of the
+// slots given, and describes the projection with a KeyProjection that maps
+// the slots of the issuer key to those of the projected one. Verifying with
+// the projected key and WithProjection, the messages keep their slots in the
+// issuer key, so that a message checked in slot 3 is bound to Y_4 of the
+// issuer as it would be with the full key.
+//
+// A projected key verifies signatures on the kept slots only, such as those
+// made by BatchSignIndexed; a signature that also covers other slots needs
+// their Y components.
+
+// KeyProjection describes a key projected from one with Attributes slots to
+// the slots Indices, in increasing order: slot Indices[j] of the original
+// key is slot j of the projected key.
+type KeyProjection struct {
+	Attributes int
+	Indices    []int
+}
+
+// ProjectPublicKey returns the key keeping X and the Y components of the
+// slots indices of pub, counting from 0, and its projection. Indices must be
+// distinct slots of pub, in any order.
+func ProjectPublicKey(pub *PublicKey, indices []int) (*PublicKey, *KeyProjection, error) {
+	if err := pub.check(); err != nil {
+		return nil, nil, err
+	}
+	p := &KeyProjection{Attributes: len(pub.Y), Indices: append([]int{}, indices...)}
+	sort.Ints(p.Indices)
+	if err := p.check(); err != nil {
+		return nil, nil, err
+	}
+	Y := make([]kyber.Point, len(p.Indices))
+	for j, i := range p.Indices {
+		Y[j] = pub.Y[i]
+	}
+	return &PublicKey{suite: pub.suite, X: pub.X, Y: Y}, p, nil
+}
+
+// check reports whether the projection keeps at least one slot, in
+// increasing order and within the original key.
+func (p *KeyProjection) check() error {
+	if p == nil || len(p.Indices) == 0 {
+		return fmt.Errorf("%w: empty projection", ErrInvalidIndex)
+	}
+	for j, i := range p.Indices {
+		if i < 0 || i >= p.Attributes {
+			return fmt.Errorf("%w: slot %d outside 0..%d", ErrInvalidIndex, i, p.Attributes-1)
+		}
+		if j > 0 && i <= p.Indices[j-1] {
+			return fmt.Errorf("%w: slot %d given twice or out of order", ErrInvalidIndex, i)
+		}
+	}
+	return nil
+}
+
+// project moves msgs from their slots in the original key to those of the
+// projected key, which has slots attribute slots.
+func (p *KeyProjection) project(slots int, msgs IndexedMessages) (IndexedMessages, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	if len(p.Indices) != slots {
+		return nil, fmt.Errorf("%w: projection keeps %d slots, key has %d", ErrKeyLengthMismatch, len(p.Indices), slots)
+	}
+	projected := make(IndexedMessages, len(msgs))
+	for n, msg := range msgs {
+		j := sort.SearchInts(p.Indices, msg.Index)
+		if j == len(p.Indices) || p.Indices[j] != msg.Index {
+			return nil, itemError("check index", n, fmt.Errorf("%w: slot %d not in the projection", ErrInvalidIndex, msg.Index))
+		}
+		projected[n] = IndexedMessage{Index: j, Msg: msg.Msg}
+	}
+	return projected, nil
+}
+
+// WithProjection makes BatchVerifyIndexed take a key projected by
+// ProjectPublicKey, with the messages in their slots of the original key.
+func WithProjection(p *KeyProjection) Option {
+	return func(o *options) {
+		o.projection = p
+		o.project = true
+	}
+}
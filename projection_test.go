@@ -0,0 +1,53 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestProjectPublicKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 40)
+		msgs := IndexedMessages{
+			{Index: 3, Msg: []byte("over 18")},
+			{Index: 0, Msg: []byte("Alice")},
+		}
+		S, err := BatchSignIndexed(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+
+		projected, p, err := ProjectPublicKey(pub, []int{3, 0})
+		require.Nil(t, err)
+		require.Equal(t, &KeyProjection{Attributes: 40, Indices: []int{0, 3}}, p)
+		require.Equal(t, 2, len(projected.Y))
+		require.True(t, projected.X.Equal(pub.X))
+		require.True(t, projected.Y[1].Equal(pub.Y[3]))
+
+		// The projected key verifies the disclosure of slots 0 and 3 alone.
+		pts := projected.Points()
+		require.Nil(t, BatchVerifyIndexed(suite, pts, msgs, S, WithProjection(p)))
+		require.Equal(t, ErrInvalidSignature, BatchVerifyIndexed(suite, pts, msgs[:1], S, WithProjection(p)))
+
+		// The slots stay those of the issuer key.
+		swapped := IndexedMessages{{Index: 0, Msg: msgs[0].Msg}, {Index: 3, Msg: msgs[1].Msg}}
+		require.Equal(t, ErrInvalidSignature, BatchVerifyIndexed(suite, pts, swapped, S, WithProjection(p)))
+		err = BatchVerifyIndexed(suite, pts, append(msgs, IndexedMessage{Index: 5, Msg: []byte("Paris")}), S, WithProjection(p))
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 2, batchErr.Index)
+		require.True(t, errors.Is(err, ErrInvalidIndex))
+		require.True(t, errors.Is(BatchVerifyIndexed(suite, pts, msgs, S), ErrInvalidIndex))
+		require.True(t, errors.Is(BatchVerifyIndexed(suite, pub.Points(), msgs, S, WithProjection(p)), ErrKeyLengthMismatch))
+
+		for _, indices := range [][]int{nil, {0, 3, 0}, {40}, {-1}} {
+			_, _, err := ProjectPublicKey(pub, indices)
+			require.True(t, errors.Is(err, ErrInvalidIndex), "%v", indices)
+		}
+		bad := &KeyProjection{Attributes: 40, Indices: []int{3, 0}}
+		require.True(t, errors.Is(BatchVerifyIndexed(suite, pts, msgs, S, WithProjection(bad)), ErrInvalidIndex))
+		_, _, err = ProjectPublicKey(nil, []int{0})
+		require.Equal(t, ErrNilKey, err)
+	})
+}
@@ -7,140 +7,523 @@ import (
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing"
-	"go.dedis.ch/kyber/v3/util/random"
 )
 
+var (
+	// ErrInvalidSignature is returned when a signature does not verify.
+	ErrInvalidSignature = errors.New("ps: invalid signature")
+
+	// ErrKeyTooShort is returned when a key has fewer components than the
+	// operation needs.
+	ErrKeyTooShort = errors.New("ps: key too short")
+
+	// ErrNilSuite is returned when a nil pairing suite is given.
+	ErrNilSuite = errors.New("ps: nil suite")
+
+	// ErrNilKey is returned when a key or one of its components is nil.
+	ErrNilKey = errors.New("ps: nil key")
+
+	// ErrNilSignature is returned when a signature or one of its components
+	// is nil.
+	ErrNilSignature = errors.New("ps: nil signature")
+
+	// ErrNilMessage is returned when a scalar message is nil.
+	ErrNilMessage = errors.New("ps: nil message")
+
+	// ErrKeyLengthMismatch is returned when a key has fewer Y components
+	// than there are messages.
+	ErrKeyLengthMismatch = errors.New("ps: key length does not match messages")
+
+	// ErrNoMessages is returned by batch operations given no messages.
+	ErrNoMessages = errors.New("ps: no messages")
+
+	// ErrDuplicateKeyMaterial is returned when key generation draws the same
+	// secret scalar twice, which points at a broken randomness source.
+	ErrDuplicateKeyMaterial = errors.New("ps: duplicate key material")
+
+	// ErrKeyWiped is returned when using a private key after Wipe.
+	ErrKeyWiped = errors.New("ps: private key wiped")
+
+	// ErrEmptyMessage is returned when signing or verifying an empty
+	// message. Empty messages usually stem from a missing field, so they are
+	// refused rather than signed.
+	ErrEmptyMessage = errors.New("ps: empty message")
+
+	// ErrZeroAttribute is returned when signing or verifying a zero scalar
+	// attribute. Its term vanishes from the verification equation, so the
+	// signature would hold for any key component Y_i.
+	ErrZeroAttribute = errors.New("ps: zero attribute")
+
+	// ErrBatchLengthMismatch is returned when a batch holds a different
+	// number of signatures and message sets.
+	ErrBatchLengthMismatch = errors.New("ps: batch length mismatch")
+)
+
+// checkMessages rejects empty messages, naming the first offending index.
+// Messages of any other length are accepted since they are hashed to scalars.
+func checkMessages(msgs ...[]byte) error {
+	for i, msg := range msgs {
+		if len(msg) == 0 {
+			if len(msgs) == 1 {
+				return ErrEmptyMessage
+			}
+			return fmt.Errorf("%w: message %d", ErrEmptyMessage, i)
+		}
+	}
+	return nil
+}
+
+// checkScalars rejects nil and zero attribute scalars, naming the first
+// offending index.
+func checkScalars(attrs []kyber.Scalar) error {
+	for i, m := range attrs {
+		if m == nil {
+			return fmt.Errorf("%w: attribute %d", ErrNilMessage, i)
+		}
+		if m.Equal(m.Clone().Zero()) {
+			return fmt.Errorf("%w: attribute %d", ErrZeroAttribute, i)
+		}
+	}
+	return nil
+}
+
+// checkMessageCount ensures a key of keyLen components covers n messages, one
+// per Y component.
+func checkMessageCount(keyLen, n int) error {
+	if n == 0 {
+		return ErrNoMessages
+	}
+	if keyLen < n+1 {
+		return fmt.Errorf("%w: %d messages need %d key components, got %d", ErrKeyLengthMismatch, n, n+1, keyLen)
+	}
+	return nil
+}
+
+// checkPrivateKey ensures priKey holds at least n scalars, none of them nil,
+// and that the key has not been wiped.
+func checkPrivateKey(priKey []kyber.Scalar, n int) error {
+	if len(priKey) < n {
+		return fmt.Errorf("%w: got %d scalars, need at least %d", ErrKeyTooShort, len(priKey), n)
+	}
+	wiped := len(priKey) > 0
+	for i, s := range priKey {
+		if s == nil {
+			return fmt.Errorf("%w: scalar %d", ErrNilKey, i)
+		}
+		wiped = wiped && s.Equal(s.Clone().Zero())
+	}
+	if wiped {
+		return ErrKeyWiped
+	}
+	return nil
+}
+
+// zeroBytes overwrites b, which held secret material, with zeros.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// checkPublicKey ensures pubKey holds at least n points, none of them nil.
+func checkPublicKey(pubKey []kyber.Point, n int) error {
+	if len(pubKey) < n {
+		return fmt.Errorf("%w: got %d points, need at least %d", ErrKeyTooShort, len(pubKey), n)
+	}
+	for i, p := range pubKey {
+		if p == nil {
+			return fmt.Errorf("%w: point %d", ErrNilKey, i)
+		}
+	}
+	return nil
+}
+
+// isIdentity reports whether p is the neutral element of g. A signature with
+// sigma_1 = sigma_2 = 1 satisfies the verification equation for any message
+// and key, so verification rejects identity components.
+func isIdentity(g kyber.Group, p kyber.Point) bool {
+	return p.Equal(g.Point().Null())
+}
+
+// maxZeroPicks bounds how often NewKeyPairPoints picks a scalar again after
+// drawing zero. A working source draws zero with negligible probability.
+const maxZeroPicks = 8
+
 // NewKeyPair creates a new PS signature signing key pair with private keys(x, y)
 // which is scalar and public key (X, Y) which is a point on the curve G2.
-func NewKeyPair(suite pairing.Suite, randoms []cipher.Stream) ([][]byte, [][]byte, error) {
-	var PriKey [][]byte
-	var PubKey [][]byte
+// The keys are returned marshalled; NewKeyPairPoints returns them as scalars
+// and points, as Sign and Verify take them.
+func NewKeyPair(suite pairing.Suite, randoms []cipher.Stream, opts ...Option) ([][]byte, [][]byte, error) {
+	priKey, pubKey, err := NewKeyPairPoints(suite, randoms, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	PriKey := make([][]byte, len(priKey))
+	PubKey := make([][]byte, len(pubKey))
+	for i := range priKey {
+		if PriKey[i], err = priKey[i].MarshalBinary(); err != nil {
+			return nil, nil, err
+		}
+		if PubKey[i], err = pubKey[i].MarshalBinary(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return PriKey, PubKey, nil
+}
+
+// NewKeyPairPoints is NewKeyPair returning the scalars and points of the key.
+// Zero scalars are drawn again, and ErrDuplicateKeyMaterial is returned if two
+// scalars are equal. The scalars are picked in order, from randoms[i] for the
+// i-th; the WithParallelism option derives the points in parallel.
+func NewKeyPairPoints(suite pairing.Suite, randoms []cipher.Stream, opts ...Option) ([]kyber.Scalar, []kyber.Point, error) {
+	var PriKey []kyber.Scalar
 
+	if suite == nil {
+		return nil, nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, nil, err
+	}
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 	if len(randoms) < 2 {
 		return nil, nil, fmt.Errorf("need minimum two random numbers")
 	}
-
-	for i := range randoms {
-		Pkey, err := suite.G2().Scalar().Pick(randoms[i]).MarshalBinary()
-		if err != nil {
-			return nil, nil, err
+	for i, r := range randoms {
+		if r == nil {
+			return nil, nil, fmt.Errorf("ps: random stream %d is nil", i)
 		}
-		PriKey = append(PriKey, Pkey)
 	}
 
-	for i := range PriKey {
-		Pkey := suite.G1().Scalar()
-		if err := Pkey.UnmarshalBinary(PriKey[i]); err != nil {
-			return nil, nil, err
+	for i := range randoms {
+		var Pkey kyber.Scalar
+		for attempt := 0; ; attempt++ {
+			if attempt == maxZeroPicks {
+				return nil, nil, fmt.Errorf("ps: random stream %d only yields zero scalars", i)
+			}
+			picked, err := pickScalar(suite, randoms[i])
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: random stream %d", err, i)
+			}
+			if !picked.Equal(newScalar(suite).Zero()) {
+				Pkey = picked
+				break
+			}
 		}
-		binPub, err := suite.G2().Point().Mul(Pkey, nil).MarshalBinary()
-		if err != nil {
-			return nil, nil, err
+		for j, prev := range PriKey {
+			if prev.Equal(Pkey) {
+				return nil, nil, fmt.Errorf("%w: scalars %d and %d are equal", ErrDuplicateKeyMaterial, j, i)
+			}
 		}
-		PubKey = append(PubKey, binPub)
+		PriKey = append(PriKey, Pkey)
 	}
 
-	return PriKey, PubKey, nil
+	return PriKey, derivePublicKey(suite, PriKey, o.workers), nil
 }
 
 // Sign creates a PS signature (h, h = h^(x+y*m)) on a given message msg using
 // the private key priKey (x, y). The signature S is a pair of points on curve G1.
-func Sign(suite pairing.Suite, priKey []kyber.Scalar, msg []byte) ([][]byte, error) {
-	var S [][]byte
-	h := suite.G1().Point().Pick(suite.RandomStream())
-	binH, err := h.MarshalBinary()
+// The message is hashed to the scalar m, so it may be of any length, but it
+// must not be empty.
+func Sign(suite pairing.Suite, priKey []kyber.Scalar, msg []byte, opts ...Option) ([][]byte, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessages(msg); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	m := []kyber.Scalar{hashToScalar(suite, o.dst, msg)}
+	rand, err := o.signingStream(suite, priKey, m)
 	if err != nil {
 		return nil, err
 	}
-	S = append(S, binH)
+	return signScalars(suite, priKey, m, rand)
+}
 
-	y := suite.G1().Scalar().Mul(priKey[1], suite.G2().Scalar().SetBytes(msg))
-	x := suite.G1().Scalar().Add(priKey[0], y)
+// SignScalar creates a PS signature on the scalar m, for protocols that map
+// their messages to scalars themselves. It is verified with VerifyScalar.
+func SignScalar(suite pairing.Suite, priKey []kyber.Scalar, m kyber.Scalar) ([][]byte, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrNilMessage
+	}
+	return signScalars(suite, priKey, []kyber.Scalar{m}, suite.RandomStream())
+}
 
-	hX := suite.G1().Point().Mul(x, h)
-	binHx, err := hX.MarshalBinary()
+// SignScalars creates a PS signature on the attributes m_1,...,m_k, given as
+// scalars by protocols that encode them without hashing, such as serial
+// numbers or openings of commitments. It is the scalar counterpart of
+// BatchSign and is verified with VerifyScalars. Nil and zero attributes are
+// rejected.
+func SignScalars(suite pairing.Suite, priKey []kyber.Scalar, attrs []kyber.Scalar) ([][]byte, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := checkMessageCount(len(priKey), len(attrs)); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	if err := checkScalars(attrs); err != nil {
+		return nil, err
+	}
+	return signScalars(suite, priKey, attrs, suite.RandomStream())
+}
+
+// signScalars computes (h, h^(x + y_1*m_1 + ... + y_k*m_k)) for h picked from
+// rand. It is the signing step shared by the byte and scalar APIs, which
+// validate their arguments first.
+func signScalars(suite pairing.Suite, priKey []kyber.Scalar, m []kyber.Scalar, rand cipher.Stream) ([][]byte, error) {
+	sig, err := signPoints(suite, priKey, m, rand)
 	if err != nil {
 		return nil, err
 	}
-	S = append(S, binHx)
+	return sig.Components()
+}
 
-	return S, nil
+// signPoints implements signScalars, returning the signature as points.
+func signPoints(suite pairing.Suite, priKey []kyber.Scalar, m []kyber.Scalar, rand cipher.Stream) (*Signature, error) {
+	return signSum(suite, priKey[0], weighScalars(suite, priKey, m, 0, len(m)), rand)
+}
+
+// weighScalars returns y_(start+1)*m_(start+1) + ... + y_end*m_end for the
+// y_i of priKey.
+func weighScalars(suite pairing.Suite, priKey []kyber.Scalar, m []kyber.Scalar, start, end int) kyber.Scalar {
+	y := newScalar(suite)
+	for i := start; i < end; i++ {
+		y.Add(y, newScalar(suite).Mul(priKey[i+1], m[i]))
+	}
+	return y
+}
+
+// signSum computes (h, h^(x + sum)) for h picked from rand, where sum is
+// y_1*m_1 + ... + y_k*m_k.
+func signSum(suite pairing.Suite, x, sum kyber.Scalar, rand cipher.Stream) (*Signature, error) {
+	h, err := pickPoint(suite.G1(), rand)
+	if err != nil {
+		return nil, err
+	}
+	hX := suite.G1().Point().Mul(newScalar(suite).Add(x, sum), h)
+
+	return &Signature{suite: suite, Sigma1: h, Sigma2: hX}, nil
 }
 
 // BatchSign creates a PS signature (h, h = h^(x + \Sigma_{i=1}^{r} y^m_r)) on a
 // given set of messages using the private key priKey (x, y_1,...y_r). The
 // signature S is a pair of points on the curve G1.
-func BatchSign(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte) ([][]byte, error) {
-	var S [][]byte
-	h := suite.G1().Point().Pick(suite.RandomStream())
-	binH, err := h.MarshalBinary()
+func BatchSign(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	sig, err := batchSign(suite, priKey, msgs, opts)
 	if err != nil {
 		return nil, err
 	}
-	S = append(S, binH)
-	y := suite.G1().Scalar()
+	return sig.Components()
+}
 
-	for i, msg := range msgs {
-		msgScalar := suite.G2().Scalar().SetBytes(msg)
-		y.Add(y, suite.G1().Scalar().Mul(priKey[i+1], msgScalar))
+// batchSign implements BatchSign, returning the signature as points.
+func batchSign(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte, opts []Option) (*Signature, error) {
+	if err := checkMessageCount(len(priKey), len(msgs)); err != nil {
+		return nil, err
 	}
-	x := suite.G1().Scalar().Add(priKey[0], y)
-	hX := suite.G1().Point().Mul(x, h)
-	binHx, err := hX.MarshalBinary()
+	if err := checkMessages(msgs...); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	o, err := newOptions(suite, opts)
 	if err != nil {
 		return nil, err
 	}
-	S = append(S, binHx)
-
-	return S, nil
-}
-
-// AggreSign implements sequential aggregration of PS signatures
-func AggreSign(suite pairing.Suite, priKey []kyber.Scalar, msg []byte) ([][]byte, error) {
-	var S [][]byte
-	t := suite.G1().Scalar().Pick(random.New())
-	sigma1 := suite.G1().Point().Mul(t, nil)
-	binSigma1, err := sigma1.MarshalBinary()
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, err
+	}
+	m, sum := weighMessages(suite, priKey, o.dst, msgs, o.workers)
+	rand, err := o.signingStream(suite, priKey, m)
 	if err != nil {
 		return nil, err
 	}
-	S = append(S, binSigma1)
+	return signSum(suite, priKey[0], sum, rand)
+}
 
-	msgScalar := suite.G2().Scalar().SetBytes(msg)
-	y := suite.G1().Scalar().Mul(priKey[1], msgScalar)
-	x := suite.G1().Scalar().Add(priKey[0], y)
-	v := suite.G1().Scalar().Mul(x, t)
-	sigma2 := suite.G1().Point().Mul(v, nil)
-	binSigma2, err := sigma2.MarshalBinary()
-	if err != nil {
+// AggreSign starts a sequential aggregate signature on the initial messages
+// m_1,...,m_k, signed with x and y_1,...,y_k of priKey. It picks a random h
+// in G1, like Sign, and returns
+//
+//	(sigma_1, sigma_2) = (h, h^(x + y_1*m_1 + ... + y_k*m_k)),
+//
+// which is the signature BatchSign produces on the same messages. Further
+// messages m_(k+1),... are appended with AggregatePSSign using y_(k+1),...,
+// and the result verifies with PSBatchVerify on all messages in order. A
+// single initial message yields a plain signature that Verify accepts under
+// the key (X, Y_1).
+func AggreSign(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if err := checkPrivateKey(priKey, 2); err != nil {
 		return nil, err
 	}
-	S = append(S, binSigma2)
+	return BatchSign(suite, priKey, msgs, opts...)
+}
 
-	return S, nil
+// AggreSignPoints is AggreSign returning the signature as points. Together
+// with AggregatePSSignPoints and PSBatchVerifyPoints it keeps a chain of
+// aggregation steps in points, leaving serialization to the caller, e.g.
+// with Signature.MarshalBinary once the aggregate is complete.
+func AggreSignPoints(suite pairing.Suite, priKey []kyber.Scalar, msgs [][]byte, opts ...Option) (*Signature, error) {
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	return batchSign(suite, priKey, msgs, opts)
 }
 
 // Verify checks the given PS signature S on the message msg using the public
 // key pubKey by verifying the equality e($\sigma_1$, X.Y^msg) == e($\sigma_2$, g)
-func Verify(suite pairing.Suite, pubKey []kyber.Point, msg []byte, S [][]byte) error {
-	msgScalar := suite.G2().Scalar().SetBytes(msg)
+// It allocates a VerifyScratch for the call, see VerifyInto.
+func Verify(suite pairing.Suite, pubKey []kyber.Point, msg []byte, S [][]byte, opts ...Option) error {
+	return VerifyInto(suite, pubKey, msg, S, nil, opts...)
+}
 
-	Y := suite.G2().Point().Mul(msgScalar, pubKey[1])
-	X := suite.G2().Point().Add(Y, pubKey[0])
+// VerifyScalar checks a PS signature created by SignScalar on the scalar m.
+// Only the ValidatePoints option applies.
+func VerifyScalar(suite pairing.Suite, pubKey []kyber.Point, m kyber.Scalar, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrNilMessage
+	}
+	if S == nil {
+		return ErrNilSignature
+	}
+	return verifyScalars(suite, pubKey, []kyber.Scalar{m}, S, o.validatePoints)
+}
 
-	s1 := suite.G1().Point()
-	if err := s1.UnmarshalBinary(S[0]); err != nil {
+// VerifyScalars checks a PS signature created by SignScalars on the
+// attributes attrs. It is the scalar counterpart of PSBatchVerify; only the
+// ValidatePoints option applies. Nil and zero attributes are rejected.
+func VerifyScalars(suite pairing.Suite, pubKey []kyber.Point, attrs []kyber.Scalar, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
 		return err
 	}
-	left := suite.Pair(s1, X)
-	s2 := suite.G1().Point()
-	if err := s2.UnmarshalBinary(S[1]); err != nil {
+	if err := checkMessageCount(len(pubKey), len(attrs)); err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if err := checkScalars(attrs); err != nil {
 		return err
 	}
-	right := suite.Pair(s2, suite.G2().Point().Base())
+	if S == nil {
+		return ErrNilSignature
+	}
+	return verifyScalars(suite, pubKey, attrs, S, o.validatePoints)
+}
+
+// BatchVerifyScalars checks the signatures sigs under pubKey, the i-th on the
+// attributes attrs[i], and returns the first failure wrapped with the index
+// of its signature. Only the ValidatePoints option applies.
+func BatchVerifyScalars(suite pairing.Suite, pubKey []kyber.Point, attrs [][]kyber.Scalar, sigs [][][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if len(sigs) == 0 {
+		return ErrNoMessages
+	}
+	if len(attrs) != len(sigs) {
+		return fmt.Errorf("%w: %d attribute sets for %d signatures", ErrBatchLengthMismatch, len(attrs), len(sigs))
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	for i, S := range sigs {
+		if err := checkMessageCount(len(pubKey), len(attrs[i])); err != nil {
+			return fmt.Errorf("%w: signature %d", err, i)
+		}
+		if err := checkScalars(attrs[i]); err != nil {
+			return fmt.Errorf("%w: signature %d", err, i)
+		}
+		if S == nil {
+			return fmt.Errorf("%w: signature %d", ErrNilSignature, i)
+		}
+		if err := verifyScalars(suite, pubKey, attrs[i], S, o.validatePoints); err != nil {
+			return fmt.Errorf("%w: signature %d", err, i)
+		}
+	}
+	return nil
+}
+
+// verifyScalars checks e(sigma_1, X * Y_1^(m_1) * ... * Y_k^(m_k)) =
+// e(sigma_2, g) for the parsed signature S. It is the verification step
+// shared by the byte and scalar APIs, which validate their arguments first.
+func verifyScalars(suite pairing.Suite, pubKey []kyber.Point, m []kyber.Scalar, S [][]byte, validate bool) error {
+	Y, err := multiScalarMul(suite.G2(), m, pubKey[1:len(m)+1])
+	if err != nil {
+		return err
+	}
+	X := suite.G2().Point().Add(Y, pubKey[0])
+	return verifyAgainst(suite, X, suite.G2().Point().Base(), S, validate)
+}
+
+// PairingChecker is implemented by suites that check a product of pairings
+// e(p1[0], p2[0]) * ... * e(p1[n-1], p2[n-1]) against the identity of GT
+// faster than by computing each pairing, e.g. with a single final
+// exponentiation. Verification uses it when the suite provides it, as the
+// bls12381 suite does, and compares two pairings otherwise.
+type PairingChecker interface {
+	PairingCheck(p1, p2 []kyber.Point) bool
+}
+
+// verifyAgainst checks e(sigma_1, X) = e(sigma_2, g) for the parsed signature
+// S, where X already accumulates the messages and g is the base of G2.
+func verifyAgainst(suite pairing.Suite, X, g kyber.Point, S [][]byte, validate bool) error {
+	sig, err := parseSignature(suite, S, validate)
+	if err != nil {
+		return err
+	}
+	return verifySignature(suite, X, g, sig)
+}
+
+// verifySignature implements verifyAgainst on a signature already parsed.
+func verifySignature(suite pairing.Suite, X, g kyber.Point, sig *Signature) error {
+	s1, s2 := sig.Sigma1, sig.Sigma2
+	if isIdentity(suite.G1(), s1) || isIdentity(suite.G1(), s2) {
+		return ErrInvalidSignature
+	}
+	if pc, ok := suite.(PairingChecker); ok {
+		// e(sigma_1, X) * e(-sigma_2, g) = 1
+		if !pc.PairingCheck([]kyber.Point{s1, suite.G1().Point().Neg(s2)}, []kyber.Point{X, g}) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	left := suite.Pair(s1, X)
+	right := suite.Pair(s2, g)
 
 	if !left.Equal(right) {
-		return errors.New("ps: invalid signature")
+		return ErrInvalidSignature
 	}
 
 	return nil
@@ -148,70 +531,160 @@ func Verify(suite pairing.Suite, pubKey []kyber.Point, msg []byte, S [][]byte) e
 
 // PSBatchVerify checks the given PS signature S on a set of messages using the public
 // pubKey by verifying the equality e($\sigma_1$, X.\Sigma_{i=1}^r Y^m_i) == e($\sigma_2$, g)
-func PSBatchVerify(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, S [][]byte) error {
-	Y := suite.G2().Point()
-
-	for i, msg := range msgs {
-		msgScalar := suite.G2().Scalar().SetBytes(msg)
-		Y.Add(Y, suite.G2().Point().Mul(msgScalar, pubKey[i+1]))
+// With the WithIndices option it first checks the header of a sequential
+// aggregate against the messages, see Aggregate.
+//
+// Failures of a single message or signature component are reported as a
+// *BatchError holding its index.
+func PSBatchVerify(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, S [][]byte, opts ...Option) error {
+	m, o, err := batchVerifyScalars(suite, pubKey, msgs, S == nil, opts)
+	if err != nil {
+		return err
 	}
-	X := suite.G2().Point().Add(Y, pubKey[0])
-
-	s1 := suite.G1().Point()
-	if err := s1.UnmarshalBinary(S[0]); err != nil {
+	sig, i, err := parseComponents(suite, S, o.validatePoints)
+	if i >= 0 {
+		return itemError("unmarshal component", i, err)
+	}
+	if err != nil {
 		return err
 	}
-	left := suite.Pair(s1, X)
+	return verifyPoints(suite, pubKey, m, sig)
+}
 
-	s2 := suite.G1().Point()
-	if err := s2.UnmarshalBinary(S[1]); err != nil {
+// PSBatchVerifyPoints is PSBatchVerify on a signature given as points. The
+// ValidatePoints option checks the subgroup membership of its points, which
+// callers that obtained sig from NewSignature or UnmarshalSignature may skip.
+func PSBatchVerifyPoints(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, sig *Signature, opts ...Option) error {
+	m, o, err := batchVerifyScalars(suite, pubKey, msgs, sig.check() != nil, opts)
+	if err != nil {
+		return err
+	}
+	if err := sig.checkPoints(o.validatePoints); err != nil {
 		return err
 	}
-	right := suite.Pair(s2, suite.G2().Point().Base())
+	return verifyPoints(suite, pubKey, m, sig)
+}
 
-	if !left.Equal(right) {
-		return errors.New("ps: invalid signature")
+// verifyPoints implements verifyScalars on a signature already parsed.
+func verifyPoints(suite pairing.Suite, pubKey []kyber.Point, m []kyber.Scalar, sig *Signature) error {
+	Y, err := multiScalarMul(suite.G2(), m, pubKey[1:len(m)+1])
+	if err != nil {
+		return err
 	}
+	X := suite.G2().Point().Add(Y, pubKey[0])
+	return verifySignature(suite, X, suite.G2().Point().Base(), sig)
+}
 
-	return nil
+// batchVerifyScalars validates the arguments of PSBatchVerify, including the
+// aggregation header, and hashes msgs. noSig reports a missing signature. An
+// empty message is reported as a *BatchError holding its index.
+func batchVerifyScalars(suite pairing.Suite, pubKey []kyber.Point, msgs [][]byte, noSig bool, opts []Option) ([]kyber.Scalar, *options, error) {
+	if err := checkMessageCount(len(pubKey), len(msgs)); err != nil {
+		return nil, nil, err
+	}
+	for i, msg := range msgs {
+		if len(msg) == 0 {
+			return nil, nil, itemError("hash message", i, ErrEmptyMessage)
+		}
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return nil, nil, err
+	}
+	if noSig {
+		return nil, nil, ErrNilSignature
+	}
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := o.checkAttributes(len(msgs)); err != nil {
+		return nil, nil, err
+	}
+	if o.checkIndices {
+		if err := checkIndices(o.indices, len(msgs)); err != nil {
+			return nil, nil, err
+		}
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+	return m, o, nil
 }
 
 // Sequential aggregation where a signature S on a set of messages m_1,
 // m_2,....,m_r, the Signature on message m_n can be sequentially aggregated
 // S = (\sigma_1^t, (sigma_2 * sigma_1^(y * m)^t))
-func AggregatePSSign(suite pairing.Suite, priKey kyber.Scalar, S [][]byte, msg []byte) ([][]byte, error) {
-	var aggregateSign [][]byte
+func AggregatePSSign(suite pairing.Suite, priKey kyber.Scalar, S [][]byte, msg []byte, opts ...Option) ([][]byte, error) {
+	o, err := aggregateOptions(suite, priKey, S == nil, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := parseSignature(suite, S, o.validatePoints)
+	if err != nil {
+		return nil, err
+	}
+	agg, err := aggregateSign(suite, priKey, sig, msg, suite.RandomStream(), o)
+	if err != nil {
+		return nil, err
+	}
+	return agg.Components()
+}
 
-	t := suite.G1().Scalar().Pick(random.New())
+// AggregatePSSignPoints is AggregatePSSign on a signature given as points,
+// returning the aggregate as points. The ValidatePoints option applies as for
+// PSBatchVerifyPoints.
+func AggregatePSSignPoints(suite pairing.Suite, priKey kyber.Scalar, sig *Signature, msg []byte, opts ...Option) (*Signature, error) {
+	o, err := aggregateOptions(suite, priKey, sig.check() != nil, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := sig.checkPoints(o.validatePoints); err != nil {
+		return nil, err
+	}
+	return aggregateSign(suite, priKey, sig, msg, suite.RandomStream(), o)
+}
 
-	s1 := suite.G1().Point()
-	if err := s1.UnmarshalBinary(S[0]); err != nil {
+// aggregateOptions validates the arguments of AggregatePSSign. noSig reports
+// a missing signature.
+func aggregateOptions(suite pairing.Suite, priKey kyber.Scalar, noSig bool, msg []byte, opts []Option) (*options, error) {
+	if priKey == nil {
+		return nil, ErrNilKey
+	}
+	if noSig {
+		return nil, ErrNilSignature
+	}
+	if err := checkMessages(msg); err != nil {
 		return nil, err
 	}
-	// sigma_1^t
-	binSigma1, err := suite.G1().Point().Mul(t, s1).MarshalBinary()
+	return newOptions(suite, opts)
+}
+
+// aggregateSign computes (sigma_1^t, (sigma_2 * sigma_1^(y * m))^t) for t
+// picked from rand. It is the aggregation step shared by the byte and point
+// APIs, which validate their arguments first.
+func aggregateSign(suite pairing.Suite, priKey kyber.Scalar, sig *Signature, msg []byte, rand cipher.Stream, o *options) (*Signature, error) {
+	t, err := pickScalar(suite, rand)
 	if err != nil {
 		return nil, err
 	}
-	aggregateSign = append(aggregateSign, binSigma1)
+	s1 := sig.Sigma1
+	if isIdentity(suite.G1(), s1) {
+		return nil, ErrInvalidSignature
+	}
 
-	msgScalar := suite.G2().Scalar().SetBytes(msg)
+	msgScalar := hashToScalar(suite, o.dst, msg)
 	// y * m
-	y := suite.G1().Scalar().Mul(priKey, msgScalar)
+	y := newScalar(suite).Mul(priKey, msgScalar)
 	// sigma_1^(y * m)
 	sigma_1 := suite.G1().Point().Mul(y, s1)
 	// sigma_2 * sigma_1^(y * m)
-	s2 := suite.G1().Point()
-	if err := s2.UnmarshalBinary(S[1]); err != nil {
-		return nil, err
-	}
 	sigma_2 := suite.G1().Point()
-	sigma_2.Add(sigma_1, s2)
-	binSigma2, err := suite.G1().Point().Mul(t, sigma_2).MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	aggregateSign = append(aggregateSign, binSigma2)
+	sigma_2.Add(sigma_1, sig.Sigma2)
 
-	return aggregateSign, nil
+	return &Signature{
+		suite:  suite,
+		Sigma1: suite.G1().Point().Mul(t, s1),
+		Sigma2: suite.G1().Point().Mul(t, sigma_2),
+	}, nil
 }
@@ -1,315 +1,739 @@
 package ps
 
 import (
+	"bytes"
 	"crypto/cipher"
+	"errors"
+	"math/big"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/bithinalangot/ps/bls12381"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
-func TestPS(t *testing.T) {
-	var randoms []cipher.Stream
-	msg := []byte("Hello PS Signature")
-	suite := pairing.NewSuiteBn256()
-	r := 2
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	private, public, err := NewKeyPair(suite, randoms)
-	sig, err := Sign(suite, private, msg)
-	require.Nil(t, err)
-	err = Verify(suite, public, msg, sig)
-	require.Nil(t, err)
+var testSuites = []struct {
+	name  string
+	suite pairing.Suite
+}{
+	{"bn256", pairing.NewSuiteBn256()},
+	{"bls12381", bls12381.NewSuite()},
+	// The same suite without its PairingChecker, to cover the generic
+	// verification path.
+	{"bls12381-generic", genericSuite{bls12381.NewSuite()}},
 }
 
-func TestPSFailSig(t *testing.T) {
-	var randoms []cipher.Stream
-	msg := []byte("Hello PS Signature")
-	suite := pairing.NewSuiteBn256()
-	r := 2
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	private, public, err := NewKeyPair(suite, randoms)
-	sig, err := Sign(suite, private, msg)
-	require.Nil(t, err)
-	sig[0][0] ^= 0x01
-	if Verify(suite, public, msg, sig) == nil {
-		t.Fatal("ps: verification succeeded unexpectedly")
-	}
+// genericSuite hides the optional interfaces of the suite it wraps.
+type genericSuite struct {
+	pairing.Suite
 }
 
-func TestBatchPSSig(t *testing.T) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms2 []cipher.Stream
-	var msgs [][]byte
-
-	for i := 0; i < r; i++ {
-		randoms2 = append(randoms2, random.New())
-	}
-	BpriKey, BpubKey, err := NewKeyPair(suite, randoms2)
-
-	if err != nil {
-		t.Fatal("Key generation not successful!")
+func forEachSuite(t *testing.T, f func(t *testing.T, suite pairing.Suite)) {
+	for _, ts := range testSuites {
+		suite := ts.suite
+		t.Run(ts.name, func(t *testing.T) { f(t, suite) })
 	}
+}
 
-	for j := 1; j < r-1; j++ {
-		msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
+func benchEachSuite(b *testing.B, f func(b *testing.B, suite pairing.Suite)) {
+	for _, ts := range testSuites {
+		suite := ts.suite
+		b.Run(ts.name, func(b *testing.B) { f(b, suite) })
 	}
+}
 
-	sig, err := BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
-	require.Nil(t, err)
-	err = PSBatchVerify(suite, BpubKey, msgs, sig)
-	require.Nil(t, err)
+func TestAggregatePSSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		r := 4
+		var randoms []cipher.Stream
+		var aggreMsg [][]byte
+
+		msg1 := []byte("PS Aggregate verify 1")
+		msg2 := []byte("PS Aggregate verify 2")
+		aggreMsg = append(aggreMsg, msg1)
+		aggreMsg = append(aggreMsg, msg2)
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		AggrpriKey, AggrpubKey, err := NewKeyPairPoints(suite, randoms)
+
+		if err != nil {
+			t.Fatal("Key generation not successful!")
+		}
+
+		AS, err := AggreSign(suite, AggrpriKey, aggreMsg[:1])
+		require.Nil(t, err)
+
+		msg3 := []byte("PS Aggregate verify 3")
+		aggreMsg = append(aggreMsg, msg3)
+
+		AS1, err := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
+		require.Nil(t, err)
+		AS2, err := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
+		require.Nil(t, err)
+
+		err = PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2)
+		require.Nil(t, err)
+	})
 }
 
-func TestBatchPSFailSig(t *testing.T) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms2 []cipher.Stream
-	var msgs [][]byte
+func TestAggregatePSFailSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		r := 4
+		var randoms []cipher.Stream
+		var aggreMsg [][]byte
+
+		msg1 := []byte("PS Aggregate verify 1")
+		msg2 := []byte("PS Aggregate verify 2")
+		aggreMsg = append(aggreMsg, msg1)
+		aggreMsg = append(aggreMsg, msg2)
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		AggrpriKey, AggrpubKey, err := NewKeyPairPoints(suite, randoms)
+
+		if err != nil {
+			t.Fatal("Key generation not successful!")
+		}
+
+		AS, err := AggreSign(suite, AggrpriKey, aggreMsg[:1])
+		require.Nil(t, err)
+
+		msg3 := []byte("PS Aggregate verify 3")
+		aggreMsg = append(aggreMsg, msg3)
+
+		AS1, err := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
+		require.Nil(t, err)
+		AS2, err := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
+		require.Nil(t, err)
+
+		AS2[0][1] ^= 0x01
+
+		if PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2) == nil {
+			t.Fatal("ps: aggregate verification succeeded unexpectedly")
+		}
+	})
+}
 
-	for i := 0; i < r; i++ {
-		randoms2 = append(randoms2, random.New())
-	}
-	BpriKey, BpubKey, err := NewKeyPair(suite, randoms2)
+func TestAggreSignInitialMessages(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3"), []byte("attribute 4")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+
+		// A single initial message gives a plain signature.
+		S, err := AggreSign(suite, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points()[:2], msgs[0], S))
+
+		// Several initial messages, then the rest appended in order.
+		S, err = AggreSign(suite, priv.Scalars(), msgs[:3])
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs[:3], S))
+		S, err = AggregatePSSign(suite, priv.Y[3], S, msgs[3])
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		_, err = AggreSign(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNoMessages, err)
+		_, err = AggreSign(suite, priv.Scalars()[:3], msgs[:3])
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+	})
+}
 
-	if err != nil {
-		t.Fatal("Key generation not successful!")
-	}
+func BenchmarkPSKeyCreation(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		var randoms []cipher.Stream
+		r := 2
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			NewKeyPairPoints(suite, randoms)
+		}
+	})
+}
 
-	for j := 1; j < r-1; j++ {
-		msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
-	}
+func BenchmarkPSKeyCreation1000(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		randoms := make([]cipher.Stream, 1001)
+		for i := range randoms {
+			randoms[i] = random.New()
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			NewKeyPairPoints(suite, randoms)
+		}
+	})
+}
 
-	sig, err := BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
-	require.Nil(t, err)
-	sig[0][0] ^= 0x01
-	if PSBatchVerify(suite, BpubKey, msgs, sig) == nil {
-		t.Fatal("ps: batch verification succeeded unexpectedly")
-	}
+func BenchmarkPSSign(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		var randoms []cipher.Stream
+		r := 2
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		private, _, _ := NewKeyPairPoints(suite, randoms)
+		msg := []byte("Hello PS Signature")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Sign(suite, private, msg)
+		}
+	})
 }
 
-func TestAggregatePSSign(t *testing.T) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms []cipher.Stream
-	var aggreMsg [][]byte
-
-	msg1 := []byte("PS Aggregate verify 1")
-	msg2 := []byte("PS Aggregate verify 2")
-	aggreMsg = append(aggreMsg, msg1)
-	aggreMsg = append(aggreMsg, msg2)
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	AggrpriKey, AggrpubKey, err := NewKeyPair(suite, randoms)
+func BenchmarkPSVerify(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		var randoms []cipher.Stream
+		msg := []byte("Hello PS Signature")
+		r := 2
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		private, public, _ := NewKeyPairPoints(suite, randoms)
+		sig, _ := Sign(suite, private, msg)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Verify(suite, public, msg, sig)
+		}
+	})
+}
 
-	if err != nil {
-		t.Fatal("Key generation not successful!")
-	}
+func BenchmarkPSVerifyNoPointValidation(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		var randoms []cipher.Stream
+		msg := []byte("Hello PS Signature")
+		r := 2
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		private, public, _ := NewKeyPairPoints(suite, randoms)
+		sig, _ := Sign(suite, private, msg)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Verify(suite, public, msg, sig, ValidatePoints(false))
+		}
+	})
+}
 
-	AS, err := AggreSign(suite, AggrpriKey, aggreMsg[0])
-	require.Nil(t, err)
+func BenchmarkPSBatchSign(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		r := 101
+		var randoms2 []cipher.Stream
+		var msgs [][]byte
+
+		for i := 0; i < r; i++ {
+			randoms2 = append(randoms2, random.New())
+		}
+		BpriKey, _, _ := NewKeyPairPoints(suite, randoms2)
+
+		for j := 1; j < r-1; j++ {
+			msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
+		}
+	})
+}
 
-	msg3 := []byte("PS Aggregate verify 3")
-	aggreMsg = append(aggreMsg, msg3)
+func BenchmarkPSBatchVerify(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		r := 3
+		var randoms2 []cipher.Stream
+		var msgs [][]byte
+
+		for i := 0; i < r; i++ {
+			randoms2 = append(randoms2, random.New())
+		}
+		BpriKey, BpubKey, _ := NewKeyPairPoints(suite, randoms2)
+		for j := 1; j < r-1; j++ {
+			msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
+		}
+
+		sig, _ := BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			PSBatchVerify(suite, BpubKey, msgs, sig)
+		}
+	})
+}
 
-	AS1, err := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
-	require.Nil(t, err)
-	AS2, err := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
-	require.Nil(t, err)
+func BenchmarkPSBatchVerify100(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msgs := make([][]byte, 100)
+		for j := range msgs {
+			msgs[j] = []byte("PS Batch Verify " + strconv.Itoa(j))
+		}
+		priv, pub := newTestKeys(b, suite, len(msgs))
+		sig, _ := BatchSign(suite, priv.Scalars(), msgs)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			PSBatchVerify(suite, pub.Points(), msgs, sig)
+		}
+	})
+}
 
-	err = PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2)
-	require.Nil(t, err)
+func BenchmarkAggregatePSSign(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		r := 4
+		var randoms []cipher.Stream
+		var aggreMsg [][]byte
+
+		msg1 := []byte("PS Aggregate verify 1")
+		msg2 := []byte("PS Aggregate verify 2")
+		msg3 := []byte("PS Aggregate verify 3")
+		aggreMsg = append(aggreMsg, msg1)
+		aggreMsg = append(aggreMsg, msg2)
+		aggreMsg = append(aggreMsg, msg3)
+
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		AggrpriKey, _, _ := NewKeyPairPoints(suite, randoms)
+		AS, _ := AggreSign(suite, AggrpriKey, aggreMsg[:1])
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			AS1, _ := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
+			_, _ = AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
+		}
+	})
 }
 
-func TestAggregatePSFailSign(t *testing.T) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms []cipher.Stream
-	var aggreMsg [][]byte
-
-	msg1 := []byte("PS Aggregate verify 1")
-	msg2 := []byte("PS Aggregate verify 2")
-	aggreMsg = append(aggreMsg, msg1)
-	aggreMsg = append(aggreMsg, msg2)
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	AggrpriKey, AggrpubKey, err := NewKeyPair(suite, randoms)
+func BenchmarkAggregatePSSignPoints(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("PS Aggregate verify 1"), []byte("PS Aggregate verify 2"), []byte("PS Aggregate verify 3")}
+		priv, _ := newTestKeys(b, suite, len(msgs))
+		sig, _ := AggreSignPoints(suite, priv.Scalars(), msgs[:1])
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sig1, _ := AggregatePSSignPoints(suite, priv.Y[1], sig, msgs[1], ValidatePoints(false))
+			_, _ = AggregatePSSignPoints(suite, priv.Y[2], sig1, msgs[2], ValidatePoints(false))
+		}
+	})
+}
 
-	if err != nil {
-		t.Fatal("Key generation not successful!")
-	}
+func BenchmarkAggregatePSVerify(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		r := 4
+		var randoms []cipher.Stream
+		var aggreMsg [][]byte
 
-	AS, err := AggreSign(suite, AggrpriKey, aggreMsg[0])
-	require.Nil(t, err)
+		msg1 := []byte("PS Aggregate verify 1")
+		msg2 := []byte("PS Aggregate verify 2")
+		msg3 := []byte("PS Aggregate verify 3")
+		aggreMsg = append(aggreMsg, msg1)
+		aggreMsg = append(aggreMsg, msg2)
+		aggreMsg = append(aggreMsg, msg3)
 
-	msg3 := []byte("PS Aggregate verify 3")
-	aggreMsg = append(aggreMsg, msg3)
+		for i := 0; i < r; i++ {
+			randoms = append(randoms, random.New())
+		}
+		AggrpriKey, AggrpubKey, _ := NewKeyPairPoints(suite, randoms)
 
-	AS1, err := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
-	require.Nil(t, err)
-	AS2, err := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
-	require.Nil(t, err)
+		AS, _ := AggreSign(suite, AggrpriKey, aggreMsg[:1])
 
-	AS2[0][1] ^= 0x01
+		AS1, _ := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
+		AS2, _ := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
 
-	if PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2) == nil {
-		t.Fatal("ps: aggregate verification succeeded unexpectedly")
-	}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2)
+		}
+	})
 }
 
-func BenchmarkPSKeyCreation(b *testing.B) {
-	var randoms []cipher.Stream
-	suite := pairing.NewSuiteBn256()
-	r := 2
+// testOrders holds the group order of each test suite.
+var testOrders = map[string]string{
+	"bn256.G1":    "65000549695646603732796438742359905742570406053903786389881062969044166799969",
+	"bls12381.G1": "52435875175126190479447740508185965837690552500527637822603658699938581184513",
+}
 
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		NewKeyPair(suite, randoms)
-	}
+func TestPSCongruentMessages(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		order, _ := new(big.Int).SetString(testOrders[suite.G1().String()], 10)
+		m1 := make([]byte, 64)
+		copy(m1, "congruent message")
+		m1[0] = 0x01
+		v := new(big.Int).Add(new(big.Int).SetBytes(m1), order)
+		m2 := v.FillBytes(make([]byte, 64))
+
+		// Both messages used to map to the same scalar.
+		require.True(t, suite.G1().Scalar().SetBytes(m1).Equal(suite.G1().Scalar().SetBytes(m2)))
+
+		priv, pub := newTestKeys(t, suite, 1)
+		sig, err := Sign(suite, priv.Scalars(), m1)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), m1, sig))
+		require.NotNil(t, Verify(suite, pub.Points(), m2, sig))
+
+		sigs, err := BatchSign(suite, priv.Scalars(), [][]byte{m1})
+		require.Nil(t, err)
+		require.NotNil(t, PSBatchVerify(suite, pub.Points(), [][]byte{m2}, sigs))
+	})
 }
 
-func BenchmarkPSSign(b *testing.B) {
-	var randoms []cipher.Stream
-	suite := pairing.NewSuiteBn256()
-	r := 2
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	private, _, _ := NewKeyPair(suite, randoms)
-	msg := []byte("Hello PS Signature")
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		Sign(suite, private, msg)
-	}
+func TestPSSignScalar(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		m := suite.G1().Scalar().Pick(random.New())
+		sig, err := SignScalar(suite, priv.Scalars(), m)
+		require.Nil(t, err)
+		require.Nil(t, VerifyScalar(suite, pub.Points(), m, sig))
+		require.NotNil(t, VerifyScalar(suite, pub.Points(), suite.G1().Scalar().One(), sig))
+	})
 }
 
-func BenchmarkPSVerify(b *testing.B) {
-	var randoms []cipher.Stream
-	msg := []byte("Hello PS Signature")
-	suite := pairing.NewSuiteBn256()
-	r := 2
+func TestPSSignScalars(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		attrs, err := HashMessages(suite, msgs)
+		require.Nil(t, err)
+
+		// The byte and scalar paths agree on hashed messages.
+		sig, err := SignScalars(suite, priv.Scalars(), attrs)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, sig))
+		sig, err = BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		require.Nil(t, VerifyScalars(suite, pub.Points(), attrs, sig))
+
+		wrong := []kyber.Scalar{attrs[1], attrs[0], attrs[2]}
+		require.Equal(t, ErrInvalidSignature, VerifyScalars(suite, pub.Points(), wrong, sig))
+
+		other, err := SignScalars(suite, priv.Scalars(), wrong)
+		require.Nil(t, err)
+		require.Nil(t, BatchVerifyScalars(suite, pub.Points(), [][]kyber.Scalar{attrs, wrong}, [][][]byte{sig, other}))
+		err = BatchVerifyScalars(suite, pub.Points(), [][]kyber.Scalar{attrs, attrs}, [][][]byte{sig, other})
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+		err = BatchVerifyScalars(suite, pub.Points(), [][]kyber.Scalar{attrs}, [][][]byte{sig, other})
+		require.True(t, errors.Is(err, ErrBatchLengthMismatch))
+		require.Equal(t, ErrNoMessages, BatchVerifyScalars(suite, pub.Points(), nil, nil))
+
+		// Nil and zero attributes are refused.
+		zero := []kyber.Scalar{attrs[0], newScalar(suite).Zero()}
+		_, err = SignScalars(suite, priv.Scalars(), zero)
+		require.True(t, errors.Is(err, ErrZeroAttribute))
+		err = VerifyScalars(suite, pub.Points(), zero, sig)
+		require.True(t, errors.Is(err, ErrZeroAttribute))
+		_, err = SignScalars(suite, priv.Scalars(), []kyber.Scalar{nil})
+		require.True(t, errors.Is(err, ErrNilMessage))
+		_, err = SignScalars(suite, priv.Scalars(), append(attrs, attrs[0]))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = SignScalars(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNoMessages, err)
+	})
+}
 
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	private, public, _ := NewKeyPair(suite, randoms)
-	sig, _ := Sign(suite, private, msg)
+func TestHashMessages(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 1)
+		msg := []byte("Hello PS Signature")
+		m, err := HashMessages(suite, [][]byte{msg})
+		require.Nil(t, err)
+		sig, err := SignScalar(suite, priv.Scalars(), m[0])
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, sig))
+		require.NotNil(t, Verify(suite, pub.Points(), msg, sig, WithDST([]byte("other"))))
+
+		_, err = HashMessages(suite, nil)
+		require.Equal(t, ErrNoMessages, err)
+		_, err = HashMessages(suite, [][]byte{msg, nil})
+		require.True(t, errors.Is(err, ErrEmptyMessage))
+	})
+}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		Verify(suite, public, msg, sig)
-	}
+func TestPSDomainSeparation(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		protoA, protoB := WithDST([]byte("PROTOCOL-A")), WithDST([]byte("PROTOCOL-B"))
+
+		sig, err := Sign(suite, priv.Scalars(), msg, protoA)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, sig, protoA))
+		require.NotNil(t, Verify(suite, pub.Points(), msg, sig, protoB))
+		require.NotNil(t, Verify(suite, pub.Points(), msg, sig))
+
+		sigs, err := BatchSign(suite, priv.Scalars(), [][]byte{msg}, protoA)
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), [][]byte{msg}, sigs, protoA))
+		require.NotNil(t, PSBatchVerify(suite, pub.Points(), [][]byte{msg}, sigs, protoB))
+
+		// The default tag is the one named by DefaultDST.
+		sig, err = Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), msg, sig, WithDST(DefaultDST(suite))))
+
+		_, err = Sign(suite, priv.Scalars(), msg, WithDST(nil))
+		require.Equal(t, ErrEmptyDST, err)
+		require.Equal(t, ErrEmptyDST, Verify(suite, pub.Points(), msg, sig, WithDST([]byte{})))
+	})
+	require.Equal(t, "PS-SIG-BN256-V1", string(DefaultDST(pairing.NewSuiteBn256())))
+	require.Equal(t, "PS-SIG-BLS12381-V1", string(DefaultDST(bls12381.NewSuite())))
 }
 
-func BenchmarkPSBatchSign(b *testing.B) {
-	suite := pairing.NewSuiteBn256()
-	r := 101
-	var randoms2 []cipher.Stream
-	var msgs [][]byte
+func TestPSIdentityForgery(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, 2)
+		null, err := suite.G1().Point().Null().MarshalBinary()
+		require.Nil(t, err)
+		forged := [][]byte{null, null}
 
-	for i := 0; i < r; i++ {
-		randoms2 = append(randoms2, random.New())
-	}
-	BpriKey, _, _ := NewKeyPair(suite, randoms2)
+		require.Equal(t, ErrInvalidSignature, Verify(suite, pub.Points(), msgs[0], forged))
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, forged))
 
-	for j := 1; j < r-1; j++ {
-		msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
-	}
+		sig, err := Sign(suite, priv.Scalars(), msgs[0])
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, Verify(suite, pub.Points(), msgs[0], [][]byte{sig[0], null}))
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
-	}
+		_, err = AggregatePSSign(suite, priv.Y[1], forged, msgs[1])
+		require.Equal(t, ErrInvalidSignature, err)
+	})
 }
 
-func BenchmarkPSBatchVerify(b *testing.B) {
-	suite := pairing.NewSuiteBn256()
-	r := 3
-	var randoms2 []cipher.Stream
-	var msgs [][]byte
+func TestPSShortKeys(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, _ := newTestKeys(t, suite, 1)
+
+		for _, key := range [][]kyber.Scalar{nil, priv.Scalars()[:1]} {
+			_, err := Sign(suite, key, msg)
+			require.True(t, errors.Is(err, ErrKeyTooShort))
+			_, err = AggreSign(suite, key, [][]byte{msg})
+			require.True(t, errors.Is(err, ErrKeyTooShort))
+		}
+
+		withNil := []kyber.Scalar{priv.X, nil}
+		_, err := Sign(suite, withNil, msg)
+		require.True(t, errors.Is(err, ErrNilKey))
+		_, err = AggreSign(suite, withNil, [][]byte{msg})
+		require.True(t, errors.Is(err, ErrNilKey))
+
+		sig, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		_, err = AggregatePSSign(suite, nil, sig, msg)
+		require.Equal(t, ErrNilKey, err)
+	})
+}
 
-	for i := 0; i < r; i++ {
-		randoms2 = append(randoms2, random.New())
-	}
-	BpriKey, BpubKey, _ := NewKeyPair(suite, randoms2)
-	for j := 1; j < r-1; j++ {
-		msgs = append(msgs, []byte("PS Batch Verify "+strconv.Itoa(j)))
-	}
+func TestPSBatchLengths(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+
+		_, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		_, err = BatchSign(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNoMessages, err)
+
+		sig, err := BatchSign(suite, priv.Scalars(), msgs[:2])
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs[:2], sig))
+		require.True(t, errors.Is(PSBatchVerify(suite, pub.Points(), msgs, sig), ErrKeyLengthMismatch))
+		require.Equal(t, ErrNoMessages, PSBatchVerify(suite, pub.Points(), [][]byte{}, sig))
+	})
+}
 
-	sig, _ := BatchSign(suite, BpriKey[:len(BpriKey)-1], msgs)
+func TestPSMalformedSignature(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		sig, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		extra, err := suite.G1().Point().Pick(random.New()).MarshalBinary()
+		require.Nil(t, err)
+		junk := random.Bits(64, false, random.New())
+
+		shapes := map[string][][]byte{
+			"one":          sig[:1],
+			"three":        {sig[0], sig[1], sig[1]},
+			"extra point":  {sig[0], sig[1], extra},
+			"extra bytes":  {sig[0], sig[1], junk},
+			"nil third":    {sig[0], sig[1], nil},
+			"ten":          {sig[0], sig[1], sig[0], sig[1], sig[0], sig[1], sig[0], sig[1], sig[0], sig[1]},
+			"short":        {sig[0], sig[1][1:]},
+			"long":         {append(append([]byte{}, sig[0]...), 0), sig[1]},
+			"empty points": {{}, {}},
+		}
+		for name, S := range shapes {
+			require.True(t, errors.Is(Verify(suite, pub.Points(), msg, S), ErrMalformedSignature), name)
+			require.True(t, errors.Is(PSBatchVerify(suite, pub.Points(), [][]byte{msg}, S), ErrMalformedSignature), name)
+			require.True(t, errors.Is(VerifyScalar(suite, pub.Points(), suite.G1().Scalar().One(), S), ErrMalformedSignature), name)
+			_, err := AggregatePSSign(suite, priv.Y[0], S, msg)
+			require.True(t, errors.Is(err, ErrMalformedSignature), name)
+			_, err = NewSignature(suite, S)
+			require.True(t, errors.Is(err, ErrMalformedSignature), name)
+		}
+	})
+}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		PSBatchVerify(suite, BpubKey, msgs, sig)
-	}
+func TestPSMessageLengths(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		msg := []byte("Hello PS Signature")
+		sig, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+
+		for _, empty := range [][]byte{nil, {}} {
+			_, err := Sign(suite, priv.Scalars(), empty)
+			require.Equal(t, ErrEmptyMessage, err)
+			_, err = AggreSign(suite, priv.Scalars(), [][]byte{empty})
+			require.Equal(t, ErrEmptyMessage, err)
+			_, err = AggregatePSSign(suite, priv.Y[0], sig, empty)
+			require.Equal(t, ErrEmptyMessage, err)
+			require.Equal(t, ErrEmptyMessage, Verify(suite, pub.Points(), empty, sig))
+		}
+
+		_, err = BatchSign(suite, priv.Scalars(), [][]byte{msg, {}})
+		require.True(t, errors.Is(err, ErrEmptyMessage))
+		require.Contains(t, err.Error(), "message 1")
+		err = PSBatchVerify(suite, pub.Points(), [][]byte{msg, {}}, sig)
+		require.True(t, errors.Is(err, ErrEmptyMessage))
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 1, batchErr.Index)
+		require.Contains(t, err.Error(), "index 1")
+
+		// Messages longer than a scalar are hashed in full, not truncated.
+		long := make([]byte, 1<<20)
+		long[len(long)-1] = 1
+		sig, err = Sign(suite, priv.Scalars(), long)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, pub.Points(), long, sig))
+		require.NotNil(t, Verify(suite, pub.Points(), long[:len(long)-1], sig))
+		require.NotNil(t, Verify(suite, pub.Points(), long[len(long)-suite.G1().ScalarLen():], sig))
+
+		// Messages around the scalar width need no length limit: each one is
+		// accepted and verifies only under itself.
+		width := suite.G1().ScalarLen()
+		at := []byte(strings.Repeat("A", width))
+		over := []byte(strings.Repeat("A", width+1))
+		other := append([]byte{'B'}, at...)
+		for _, m := range [][]byte{at, over, other} {
+			sig, err := Sign(suite, priv.Scalars(), m)
+			require.Nil(t, err)
+			require.Nil(t, Verify(suite, pub.Points(), m, sig))
+			for _, m2 := range [][]byte{at, over, other} {
+				if !bytes.Equal(m, m2) {
+					require.NotNil(t, Verify(suite, pub.Points(), m2, sig))
+				}
+			}
+		}
+	})
 }
 
-func BenchmarkAggregatePSSign(b *testing.B) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms []cipher.Stream
-	var aggreMsg [][]byte
-
-	msg1 := []byte("PS Aggregate verify 1")
-	msg2 := []byte("PS Aggregate verify 2")
-	msg3 := []byte("PS Aggregate verify 3")
-	aggreMsg = append(aggreMsg, msg1)
-	aggreMsg = append(aggreMsg, msg2)
-	aggreMsg = append(aggreMsg, msg3)
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	AggrpriKey, _, _ := NewKeyPair(suite, randoms)
-	AS, _ := AggreSign(suite, AggrpriKey, aggreMsg[0])
+// constantStream is a broken randomness source producing a fixed byte.
+type constantStream byte
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		AS1, _ := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
-		_, _ = AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
+func (c constantStream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		dst[i] = src[i] ^ byte(c)
 	}
 }
 
-func BenchmarkAggregatePSVerify(b *testing.B) {
-	suite := pairing.NewSuiteBn256()
-	r := 4
-	var randoms []cipher.Stream
-	var aggreMsg [][]byte
-
-	msg1 := []byte("PS Aggregate verify 1")
-	msg2 := []byte("PS Aggregate verify 2")
-	msg3 := []byte("PS Aggregate verify 3")
-	aggreMsg = append(aggreMsg, msg1)
-	aggreMsg = append(aggreMsg, msg2)
-	aggreMsg = append(aggreMsg, msg3)
-
-	for i := 0; i < r; i++ {
-		randoms = append(randoms, random.New())
-	}
-	AggrpriKey, AggrpubKey, _ := NewKeyPair(suite, randoms)
+func TestNewKeyPairBrokenRandomness(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, _, err := NewKeyPair(suite, []cipher.Stream{random.New(), constantStream(0x01), constantStream(0x01)})
+		require.True(t, errors.Is(err, ErrDuplicateKeyMaterial))
+		require.Contains(t, err.Error(), "scalars 1 and 2")
 
-	AS, _ := AggreSign(suite, AggrpriKey, aggreMsg[0])
+		private, _, err := NewKeyPairPoints(suite, []cipher.Stream{constantStream(0x01), constantStream(0x02)})
+		require.Nil(t, err)
+		require.False(t, private[0].Equal(private[1]))
+	})
+}
 
-	AS1, _ := AggregatePSSign(suite, AggrpriKey[2], AS, aggreMsg[1])
-	AS2, _ := AggregatePSSign(suite, AggrpriKey[3], AS1, aggreMsg[2])
+func TestNewKeyPairFixedStreams(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		randoms := make([]cipher.Stream, 5)
+		for i := range randoms {
+			randoms[i] = seededStream("key " + strconv.Itoa(i))
+		}
+		private, public, err := NewKeyPairPoints(suite, randoms)
+		require.Nil(t, err)
+
+		// Each scalar is the one picked from its stream, and each point its
+		// multiple of the base of G2.
+		for i := range randoms {
+			want, err := pickScalar(suite, seededStream("key "+strconv.Itoa(i)))
+			require.Nil(t, err)
+			require.True(t, want.Equal(private[i]), "scalar %d", i)
+			require.True(t, suite.G2().Point().Mul(want, nil).Equal(public[i]), "point %d", i)
+		}
+	})
+}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		PSBatchVerify(suite, AggrpubKey, aggreMsg, AS2)
-	}
+func TestPrivateKeyWipe(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, _ := newTestKeys(t, suite, 2)
+		scalars := priv.Scalars()
+
+		priv.Wipe()
+		for _, s := range scalars {
+			require.True(t, s.Equal(suite.G1().Scalar().Zero()))
+		}
+		_, err := Sign(suite, scalars, msg)
+		require.True(t, errors.Is(err, ErrKeyWiped))
+		_, err = Sign(suite, priv.Scalars(), msg)
+		require.True(t, errors.Is(err, ErrKeyWiped))
+		_, err = BatchSign(suite, priv.Scalars(), [][]byte{msg, msg})
+		require.True(t, errors.Is(err, ErrKeyWiped))
+		_, err = priv.MarshalBinary()
+		require.True(t, errors.Is(err, ErrKeyWiped))
+		require.Nil(t, priv.Public())
+
+		require.NotPanics(t, priv.Wipe)
+		_, err = Sign(suite, priv.Scalars(), msg)
+		require.True(t, errors.Is(err, ErrKeyWiped))
+		require.NotPanics(t, (*PrivateKey)(nil).Wipe)
+	})
+}
+
+func TestNewKeyPairMarshalled(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		private, public, err := NewKeyPair(suite, []cipher.Stream{random.New(), random.New()})
+		require.Nil(t, err)
+		require.Len(t, private, 2)
+		require.Len(t, public, 2)
+		scalars := make([]kyber.Scalar, len(private))
+		points := make([]kyber.Point, len(public))
+		for i := range private {
+			scalars[i] = suite.G1().Scalar()
+			require.Nil(t, scalars[i].UnmarshalBinary(private[i]))
+			points[i] = suite.G2().Point()
+			require.Nil(t, points[i].UnmarshalBinary(public[i]))
+			require.True(t, suite.G2().Point().Mul(scalars[i], nil).Equal(points[i]))
+		}
+		sig, err := Sign(suite, scalars, msg)
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, points, msg, sig))
+
+		_, _, err = NewKeyPair(suite, []cipher.Stream{random.New()})
+		require.NotNil(t, err)
+	})
 }
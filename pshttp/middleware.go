@@ -0,0 +1,50 @@
+package pshttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/bithinalangot/ps"
+)
+
+// The headers read by VerifyBody.
+const (
+	// HeaderKeyID names the signing key by its KeyID.
+	HeaderKeyID = "PS-Key-Id"
+	// HeaderSignature carries the signature on the body, the standard
+	// base64 encoding of ps.Signature.MarshalBinary.
+	HeaderSignature = "PS-Signature"
+)
+
+// VerifyBody returns middleware passing on only requests whose body is
+// signed, as a single message, under a key of ks. The body is read up to
+// the size limit and replayed to the next handler. Requests with an empty
+// body cannot be signed and are refused with invalid_request.
+func VerifyBody(ks *ps.KeySet, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sig, err := base64.StdEncoding.DecodeString(r.Header.Get(HeaderSignature))
+			if err != nil || len(sig) == 0 {
+				writeResponse(w, http.StatusBadRequest, CodeMalformedRequest)
+				return
+			}
+			body, err := readBody(w, r, o)
+			if err == nil {
+				err = verifySignature(ks, r.Header.Get(HeaderKeyID), [][]byte{body}, sig)
+			}
+			if err != nil {
+				f := classify(err)
+				if f.status == http.StatusUnprocessableEntity {
+					f.status = http.StatusUnauthorized
+				}
+				writeResponse(w, f.status, f.code)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
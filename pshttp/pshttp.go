@@ -0,0 +1,249 @@
+// Package pshttp verifies PS signatures and selective-disclosure proofs over
+// HTTP, for relying parties that do not link the ps package.
+//
+// NewVerifyHandler and NewProofHandler take a POST with a JSON body naming a
+// key of a ps.KeySet, and VerifyBody is middleware checking a signature
+// carried in request headers over the request body. Binary fields are
+// base64 encoded as encoding/json does for []byte: messages are the raw
+// messages, signatures the encoding of ps.Signature.MarshalBinary and proofs
+// that of ps.SignatureProof.MarshalBinary.
+//
+// Every response has the same shape, {"valid": ..., "error": ...}, where
+// error is one of the codes below or empty, so that a client learns nothing
+// beyond the code: in particular, every failed check of a well-formed
+// signature or proof gives "invalid_signature" and no message.
+//
+//	200  valid
+//	400  request_too_large, malformed_request, malformed_signature,
+//	     invalid_request, limit_exceeded
+//	405  method_not_allowed
+//	422  unknown_key, invalid_signature
+//
+// VerifyBody answers 401 instead of 422.
+package pshttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bithinalangot/ps"
+)
+
+// DefaultMaxBodySize is the largest request body read unless WithMaxBodySize
+// sets another limit.
+const DefaultMaxBodySize = 64 << 10
+
+// The error codes of responses.
+const (
+	CodeRequestTooLarge    = "request_too_large"
+	CodeMalformedRequest   = "malformed_request"
+	CodeMalformedSignature = "malformed_signature"
+	CodeInvalidRequest     = "invalid_request"
+	CodeLimitExceeded      = "limit_exceeded"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeUnknownKey         = "unknown_key"
+	CodeInvalidSignature   = "invalid_signature"
+)
+
+// Response is the body of every response.
+type Response struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error"`
+}
+
+// VerifyRequest asks to verify Signature on Messages, signed in slots 1 to
+// len(Messages) under the key KeyID.
+type VerifyRequest struct {
+	KeyID     string   `json:"keyId"`
+	Messages  [][]byte `json:"messages"`
+	Signature []byte   `json:"signature"`
+}
+
+// ProofRequest asks to verify a selective-disclosure Proof under the key
+// KeyID, with the Disclosed messages by index, counting from 0, and the
+// Nonce the verifier chose. Checking that the nonce is fresh is up to the
+// caller.
+type ProofRequest struct {
+	KeyID     string         `json:"keyId"`
+	Proof     []byte         `json:"proof"`
+	Disclosed map[int][]byte `json:"disclosed"`
+	Nonce     []byte         `json:"nonce"`
+}
+
+// Option configures a handler or middleware.
+type Option func(*options)
+
+type options struct {
+	maxBodySize int64
+}
+
+// WithMaxBodySize sets the largest request body read to n bytes.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+var (
+	// errMalformedRequest marks requests that cannot be read or decoded.
+	errMalformedRequest = errors.New("pshttp: malformed request")
+
+	// errMalformed marks errors decoding a signature or proof.
+	errMalformed = errors.New("pshttp: malformed signature")
+)
+
+// failure is an error response, before the choice of status for an invalid
+// signature.
+type failure struct {
+	code   string
+	status int
+}
+
+// classify maps an error of reading or verifying a request to its response.
+func classify(err error) failure {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return failure{CodeRequestTooLarge, http.StatusBadRequest}
+	case errors.Is(err, errMalformedRequest):
+		return failure{CodeMalformedRequest, http.StatusBadRequest}
+	case errors.Is(err, errMalformed):
+		return failure{CodeMalformedSignature, http.StatusBadRequest}
+	case errors.Is(err, ps.ErrLimitExceeded):
+		return failure{CodeLimitExceeded, http.StatusBadRequest}
+	case errors.Is(err, ps.ErrNoMessages), errors.Is(err, ps.ErrEmptyMessage),
+		errors.Is(err, ps.ErrKeyLengthMismatch), errors.Is(err, ps.ErrInvalidDisclosure),
+		errors.Is(err, ps.ErrEmptyNonce):
+		return failure{CodeInvalidRequest, http.StatusBadRequest}
+	case errors.Is(err, ps.ErrKeyNotFound):
+		return failure{CodeUnknownKey, http.StatusUnprocessableEntity}
+	}
+	return failure{CodeInvalidSignature, http.StatusUnprocessableEntity}
+}
+
+func writeResponse(w http.ResponseWriter, status int, code string) {
+	h := w.Header()
+	h.Set("Content-Type", "application/json")
+	h.Set("Cache-Control", "no-store")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Response{Valid: code == "", Error: code})
+}
+
+// readBody reads the body of r up to the size limit.
+func readBody(w http.ResponseWriter, r *http.Request, o *options) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, o.maxBodySize))
+	var tooLarge *http.MaxBytesError
+	if err != nil && !errors.As(err, &tooLarge) {
+		return nil, errMalformedRequest
+	}
+	return body, err
+}
+
+// jsonHandler decodes the POSTed JSON into the request made by newReq and
+// answers with the result of verify.
+type jsonHandler struct {
+	o      *options
+	newReq func() interface{}
+	verify func(req interface{}) error
+}
+
+func (h *jsonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, CodeMethodNotAllowed)
+		return
+	}
+	body, err := readBody(w, r, h.o)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, classify(err).code)
+		return
+	}
+	req := h.newReq()
+	if err := json.Unmarshal(body, req); err != nil {
+		writeResponse(w, http.StatusBadRequest, CodeMalformedRequest)
+		return
+	}
+	if err := h.verify(req); err != nil {
+		f := classify(err)
+		writeResponse(w, f.status, f.code)
+		return
+	}
+	writeResponse(w, http.StatusOK, "")
+}
+
+// NewVerifyHandler returns a handler verifying VerifyRequests against the
+// keys of ks.
+func NewVerifyHandler(ks *ps.KeySet, opts ...Option) http.Handler {
+	return &jsonHandler{
+		o:      newOptions(opts),
+		newReq: func() interface{} { return new(VerifyRequest) },
+		verify: func(req interface{}) error {
+			r := req.(*VerifyRequest)
+			return verifySignature(ks, r.KeyID, r.Messages, r.Signature)
+		},
+	}
+}
+
+// NewProofHandler returns a handler verifying ProofRequests against the keys
+// of ks.
+func NewProofHandler(ks *ps.KeySet, opts ...Option) http.Handler {
+	return &jsonHandler{
+		o:      newOptions(opts),
+		newReq: func() interface{} { return new(ProofRequest) },
+		verify: func(req interface{}) error { return verifyProof(ks, req.(*ProofRequest)) },
+	}
+}
+
+// verifySignature checks the encoded signature sig on msgs under the key id
+// of ks.
+func verifySignature(ks *ps.KeySet, id string, msgs [][]byte, sig []byte) error {
+	pub, err := ks.PublicKey(id)
+	if err != nil {
+		return err
+	}
+	v, err := ks.Resolve(id)
+	if err != nil {
+		return err
+	}
+	dec, err := ps.UnmarshalSignature(pub.Suite(), sig)
+	if err != nil {
+		return errMalformed
+	}
+	S, err := dec.Components()
+	if err != nil {
+		return errMalformed
+	}
+	return v.BatchVerify(msgs, S)
+}
+
+// verifyProof checks the proof of req under the key of ks it names. Proofs
+// naming another key than req are invalid.
+func verifyProof(ks *ps.KeySet, req *ProofRequest) error {
+	pub, err := ks.PublicKey(req.KeyID)
+	if err != nil {
+		return err
+	}
+	proof, err := ps.UnmarshalSignatureProof(pub.Suite(), req.Proof)
+	if err != nil {
+		return errMalformed
+	}
+	if proof.KeyID == "" {
+		proof.KeyID = req.KeyID
+	}
+	if proof.KeyID != req.KeyID {
+		return ps.ErrInvalidSignature
+	}
+	_, err = ks.VerifySignatureProof(proof, req.Disclosed, req.Nonce)
+	return err
+}
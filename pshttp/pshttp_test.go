@@ -0,0 +1,221 @@
+package pshttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/pstest"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// newFixture returns the fixture of bn256 with three messages, in a key set
+// under the returned ID.
+func newFixture(t *testing.T) (*pstest.KeyFixture, *ps.KeySet, string) {
+	f := pstest.Fixture(t, pairing.NewSuiteBn256(), 3)
+	ks := ps.NewKeySet(0)
+	id, err := ks.Add(f.Public)
+	require.Nil(t, err)
+	return f, ks, id
+}
+
+// post sends body to h and returns the status and decoded response.
+func post(t *testing.T, h http.Handler, body []byte) (int, Response, []byte) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/verify", bytes.NewReader(body)))
+	raw := rec.Body.Bytes()
+	var resp Response
+	require.Nil(t, json.Unmarshal(raw, &resp))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	return rec.Code, resp, raw
+}
+
+func encodeSignature(t *testing.T, S [][]byte) []byte {
+	sig, err := ps.NewSignature(pairing.NewSuiteBn256(), S)
+	require.Nil(t, err)
+	buf, err := sig.MarshalBinary()
+	require.Nil(t, err)
+	return buf
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	buf, err := json.Marshal(v)
+	require.Nil(t, err)
+	return buf
+}
+
+func TestVerifyHandler(t *testing.T) {
+	f, ks, id := newFixture(t)
+	sig, err := f.Signature.MarshalBinary()
+	require.Nil(t, err)
+	h := NewVerifyHandler(ks)
+
+	code, resp, _ := post(t, h, mustJSON(t, VerifyRequest{KeyID: id, Messages: f.Messages, Signature: sig}))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, Response{Valid: true}, resp)
+
+	wrong := [][]byte{f.Messages[0], f.Messages[1], []byte("other")}
+	// A signature on the same messages under another key.
+	otherPriv, _, err := ps.GenerateKey(pairing.NewSuiteBn256(), 3, nil)
+	require.Nil(t, err)
+	S, err := otherPriv.BatchSign(f.Messages)
+	require.Nil(t, err)
+	otherSig := encodeSignature(t, S)
+	S, err = f.Signature.Components()
+	require.Nil(t, err)
+	swapped := encodeSignature(t, [][]byte{S[1], S[0]})
+	var first []byte
+	for _, tc := range []struct {
+		name string
+		req  VerifyRequest
+	}{
+		{"wrong message", VerifyRequest{KeyID: id, Messages: wrong, Signature: sig}},
+		{"fewer messages", VerifyRequest{KeyID: id, Messages: f.Messages[:2], Signature: sig}},
+		{"other key", VerifyRequest{KeyID: id, Messages: f.Messages, Signature: otherSig}},
+		{"swapped components", VerifyRequest{KeyID: id, Messages: f.Messages, Signature: swapped}},
+	} {
+		code, resp, raw := post(t, h, mustJSON(t, tc.req))
+		require.Equal(t, http.StatusUnprocessableEntity, code, tc.name)
+		require.Equal(t, Response{Error: CodeInvalidSignature}, resp, tc.name)
+		// Failed checks are indistinguishable.
+		if first == nil {
+			first = raw
+		}
+		require.Equal(t, first, raw, tc.name)
+	}
+
+	code, resp, _ = post(t, h, mustJSON(t, VerifyRequest{KeyID: "0011223344556677", Messages: f.Messages, Signature: sig}))
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Equal(t, CodeUnknownKey, resp.Error)
+}
+
+func TestVerifyHandlerMalformed(t *testing.T) {
+	f, ks, id := newFixture(t)
+	sig, err := f.Signature.MarshalBinary()
+	require.Nil(t, err)
+	h := NewVerifyHandler(ks, WithMaxBodySize(1024))
+	corrupt := append([]byte{}, sig...)
+	corrupt[len(corrupt)-1] ^= 1
+
+	for _, tc := range []struct {
+		name string
+		body []byte
+		code string
+	}{
+		{"not JSON", []byte("{"), CodeMalformedRequest},
+		{"bad base64", []byte(`{"keyId":"` + id + `","messages":["!!"],"signature":""}`), CodeMalformedRequest},
+		{"too large", []byte(`{"keyId":"` + strings.Repeat("a", 2048) + `"}`), CodeRequestTooLarge},
+		{"corrupted signature", mustJSON(t, VerifyRequest{KeyID: id, Messages: f.Messages, Signature: corrupt}), CodeMalformedSignature},
+		{"truncated signature", mustJSON(t, VerifyRequest{KeyID: id, Messages: f.Messages, Signature: sig[:len(sig)-1]}), CodeMalformedSignature},
+		{"no messages", mustJSON(t, VerifyRequest{KeyID: id, Signature: sig}), CodeInvalidRequest},
+		{"empty message", mustJSON(t, VerifyRequest{KeyID: id, Messages: [][]byte{{}}, Signature: sig}), CodeInvalidRequest},
+		{"too many messages", mustJSON(t, VerifyRequest{KeyID: id, Messages: append(f.Messages, []byte("4")), Signature: sig}), CodeInvalidRequest},
+	} {
+		code, resp, _ := post(t, h, tc.body)
+		require.Equal(t, http.StatusBadRequest, code, tc.name)
+		require.Equal(t, Response{Error: tc.code}, resp, tc.name)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/verify", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+	// The attribute limit of the key set applies.
+	limited := ps.NewKeySet(0, ps.WithConfig(ps.Config{MaxAttributes: 2}))
+	_, err = limited.Add(f.Public)
+	require.Nil(t, err)
+	code, resp, _ := post(t, NewVerifyHandler(limited), mustJSON(t, VerifyRequest{KeyID: id, Messages: f.Messages, Signature: sig}))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, CodeLimitExceeded, resp.Error)
+}
+
+func TestProofHandler(t *testing.T) {
+	f, ks, id := newFixture(t)
+	suite := f.Public.Suite()
+	nonce := []byte("session 7")
+	proof, err := ps.ProveSignature(suite, f.Public, f.Signature, f.Messages, map[int]bool{1: true}, nonce)
+	require.Nil(t, err)
+	buf, err := proof.MarshalBinary()
+	require.Nil(t, err)
+	h := NewProofHandler(ks)
+	disclosed := map[int][]byte{1: f.Messages[1]}
+
+	code, resp, _ := post(t, h, mustJSON(t, ProofRequest{KeyID: id, Proof: buf, Disclosed: disclosed, Nonce: nonce}))
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Valid)
+
+	code, resp, _ = post(t, h, mustJSON(t, ProofRequest{KeyID: id, Proof: buf, Disclosed: disclosed, Nonce: []byte("session 8")}))
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Equal(t, Response{Error: CodeInvalidSignature}, resp)
+	code, resp, _ = post(t, h, mustJSON(t, ProofRequest{KeyID: id, Proof: buf, Disclosed: map[int][]byte{1: f.Messages[0]}, Nonce: nonce}))
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Equal(t, Response{Error: CodeInvalidSignature}, resp)
+
+	code, resp, _ = post(t, h, mustJSON(t, ProofRequest{KeyID: id, Proof: buf[:10], Disclosed: disclosed, Nonce: nonce}))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, CodeMalformedSignature, resp.Error)
+	code, resp, _ = post(t, h, mustJSON(t, ProofRequest{KeyID: id, Proof: buf, Disclosed: disclosed}))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, CodeInvalidRequest, resp.Error)
+
+	// A proof names its key; it does not verify under another.
+	other := pstest.Fixture(t, pairing.NewSuiteBn256(), 4)
+	otherID, err := ks.Add(other.Public)
+	require.Nil(t, err)
+	code, resp, _ = post(t, h, mustJSON(t, ProofRequest{KeyID: otherID, Proof: buf, Disclosed: disclosed, Nonce: nonce}))
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Equal(t, CodeInvalidSignature, resp.Error)
+}
+
+func TestVerifyBody(t *testing.T) {
+	f, ks, id := newFixture(t)
+	body := f.Messages[0]
+	S, err := f.Private.BatchSign([][]byte{body})
+	require.Nil(t, err)
+	sig, err := ps.NewSignature(f.Public.Suite(), S)
+	require.Nil(t, err)
+	buf, err := sig.MarshalBinary()
+	require.Nil(t, err)
+
+	var got []byte
+	h := VerifyBody(ks)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, err = io.ReadAll(r.Body)
+		require.Nil(t, err)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	send := func(body []byte, id, sig string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+		r.Header.Set(HeaderKeyID, id)
+		r.Header.Set(HeaderSignature, sig)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := send(body, id, base64.StdEncoding.EncodeToString(buf))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, body, got)
+
+	got = nil
+	rec = send([]byte("tampered body"), id, base64.StdEncoding.EncodeToString(buf))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Nil(t, got)
+	rec = send(body, id, "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = send(body, id, "not base64")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = send(nil, id, base64.StdEncoding.EncodeToString(buf))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = send(body, "", base64.StdEncoding.EncodeToString(buf))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), CodeUnknownKey)
+	require.Nil(t, got)
+}
@@ -0,0 +1,124 @@
+// Package psjws adapts PS signatures to JSON Web Signatures through the
+// golang-jwt SigningMethod interface.
+//
+// The signature of a token is a single-message PS signature over the ASCII
+// JWS signing input, encoded as base64url(sigma_1 || sigma_2) without padding.
+// Relying parties can later re-randomize it without invalidating the token.
+// Tokens made by NewWithClaims name their key by its KeyID in the "kid"
+// header, which Keyfunc resolves against a set of keys.
+package psjws
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/golang-jwt/jwt/v4"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// SigningMethodPS signs tokens with PS signatures under a fixed suite. Keys
+// are *ps.PrivateKey for signing and *ps.PublicKey for verification; only the
+// first attribute slot is used.
+type SigningMethodPS struct {
+	Name  string
+	Suite pairing.Suite
+}
+
+// Signing methods for the suites shipped with the ps package.
+var (
+	SigningMethodBN256    = &SigningMethodPS{Name: "PS-BN256", Suite: pairing.NewSuiteBn256()}
+	SigningMethodBLS12381 = &SigningMethodPS{Name: "PS-BLS12381", Suite: bls12381.NewSuite()}
+)
+
+// ErrInvalidSignatureEncoding is returned when the signature segment of a
+// token is not a well-formed PS signature.
+var ErrInvalidSignatureEncoding = errors.New("psjws: invalid signature encoding")
+
+var encoding = base64.RawURLEncoding.Strict()
+
+func init() {
+	for _, m := range []*SigningMethodPS{SigningMethodBN256, SigningMethodBLS12381} {
+		m := m
+		jwt.RegisterSigningMethod(m.Alg(), func() jwt.SigningMethod { return m })
+	}
+}
+
+// Alg returns the JWS "alg" header value.
+func (m *SigningMethodPS) Alg() string { return m.Name }
+
+// Sign signs the signing input with a *ps.PrivateKey and returns the encoded
+// signature segment.
+func (m *SigningMethodPS) Sign(signingString string, key interface{}) (string, error) {
+	priv, ok := key.(*ps.PrivateKey)
+	if !ok || priv == nil {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if err := m.checkSuite(priv.Suite()); err != nil {
+		return "", err
+	}
+	S, err := ps.Sign(m.Suite, priv.Scalars(), []byte(signingString))
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(append(S[0], S[1]...)), nil
+}
+
+// Verify checks the encoded signature segment against the signing input with
+// a *ps.PublicKey. The segment must be unpadded base64url holding exactly two
+// marshalled G1 points.
+func (m *SigningMethodPS) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(*ps.PublicKey)
+	if !ok || pub == nil {
+		return jwt.ErrInvalidKeyType
+	}
+	if err := m.checkSuite(pub.Suite()); err != nil {
+		return err
+	}
+	raw, err := encoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	size := m.Suite.G1().PointLen()
+	if len(raw) != 2*size {
+		return fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidSignatureEncoding, len(raw), 2*size)
+	}
+	S := [][]byte{raw[:size], raw[size:]}
+	if _, err := ps.NewSignature(m.Suite, S); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	return ps.Verify(m.Suite, pub.Points(), []byte(signingString), S)
+}
+
+// NewWithClaims returns a token with claims to be signed by m with the
+// private key of pub, whose KeyID it puts in the "kid" header.
+func NewWithClaims(m *SigningMethodPS, claims jwt.Claims, pub *ps.PublicKey) (*jwt.Token, error) {
+	id, err := pub.KeyID()
+	if err != nil {
+		return nil, err
+	}
+	token := jwt.NewWithClaims(m, claims)
+	token.Header["kid"] = id
+	return token, nil
+}
+
+// Keyfunc returns a jwt.Keyfunc picking from keys the one named by the
+// "kid" header of the token, as set by NewWithClaims.
+func Keyfunc(keys ...*ps.PublicKey) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		id, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: no kid header", ps.ErrKeyNotFound)
+		}
+		return ps.SelectKey(keys, id)
+	}
+}
+
+func (m *SigningMethodPS) checkSuite(suite pairing.Suite) error {
+	if suite == nil || m.Suite == nil || suite.G1().String() != m.Suite.G1().String() {
+		return jwt.ErrInvalidKeyType
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package psjws
+
+import (
+	"crypto/cipher"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func newKeys(t *testing.T, m *SigningMethodPS) (*ps.PrivateKey, *ps.PublicKey) {
+	private, public, err := ps.NewKeyPairPoints(m.Suite, []cipher.Stream{random.New(), random.New()})
+	require.Nil(t, err)
+	priv, err := ps.NewPrivateKey(m.Suite, private)
+	require.Nil(t, err)
+	pub, err := ps.NewPublicKey(m.Suite, public)
+	require.Nil(t, err)
+	return priv, pub
+}
+
+func mint(t *testing.T, m *SigningMethodPS, priv *ps.PrivateKey) string {
+	token := jwt.NewWithClaims(m, jwt.MapClaims{"sub": "alice", "role": "member"})
+	signed, err := token.SignedString(priv)
+	require.Nil(t, err)
+	return signed
+}
+
+func TestMintAndVerify(t *testing.T) {
+	for _, m := range []*SigningMethodPS{SigningMethodBN256, SigningMethodBLS12381} {
+		t.Run(m.Alg(), func(t *testing.T) {
+			priv, pub := newKeys(t, m)
+			signed := mint(t, m, priv)
+
+			token, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+				require.Equal(t, m.Alg(), token.Header["alg"])
+				return pub, nil
+			})
+			require.Nil(t, err)
+			require.True(t, token.Valid)
+			require.Equal(t, "alice", token.Claims.(jwt.MapClaims)["sub"])
+		})
+	}
+}
+
+func TestKeyID(t *testing.T) {
+	m := SigningMethodBLS12381
+	priv, pub := newKeys(t, m)
+	_, other := newKeys(t, m)
+	token, err := NewWithClaims(m, jwt.MapClaims{"sub": "alice"}, pub)
+	require.Nil(t, err)
+	signed, err := token.SignedString(priv)
+	require.Nil(t, err)
+
+	parsed, err := jwt.Parse(signed, Keyfunc(other, pub))
+	require.Nil(t, err)
+	require.True(t, parsed.Valid)
+	id, err := pub.KeyID()
+	require.Nil(t, err)
+	require.Equal(t, id, parsed.Header["kid"])
+
+	_, err = jwt.Parse(signed, Keyfunc(other))
+	require.True(t, errors.Is(err, ps.ErrKeyNotFound))
+	_, err = jwt.Parse(mint(t, m, priv), Keyfunc(pub))
+	require.True(t, errors.Is(err, ps.ErrKeyNotFound))
+}
+
+func TestTamperedPayload(t *testing.T) {
+	for _, m := range []*SigningMethodPS{SigningMethodBN256, SigningMethodBLS12381} {
+		t.Run(m.Alg(), func(t *testing.T) {
+			priv, pub := newKeys(t, m)
+			parts := strings.Split(mint(t, m, priv), ".")
+			forged := jwt.NewWithClaims(m, jwt.MapClaims{"sub": "alice", "role": "admin"})
+			forgedString, err := forged.SigningString()
+			require.Nil(t, err)
+
+			tampered := forgedString + "." + parts[2]
+			_, err = jwt.Parse(tampered, func(*jwt.Token) (interface{}, error) { return pub, nil })
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestStrictSignatureParsing(t *testing.T) {
+	m := SigningMethodBN256
+	priv, pub := newKeys(t, m)
+	parts := strings.Split(mint(t, m, priv), ".")
+	signingString := parts[0] + "." + parts[1]
+
+	require.Nil(t, m.Verify(signingString, parts[2], pub))
+	require.NotNil(t, m.Verify(signingString, parts[2]+"=", pub))
+	require.NotNil(t, m.Verify(signingString, parts[2][:len(parts[2])-4], pub))
+	require.NotNil(t, m.Verify(signingString, parts[2]+"AAAA", pub))
+	require.Equal(t, jwt.ErrInvalidKeyType, m.Verify(signingString, parts[2], priv))
+
+	_, otherPub := newKeys(t, SigningMethodBLS12381)
+	require.Equal(t, jwt.ErrInvalidKeyType, m.Verify(signingString, parts[2], otherPub))
+
+	require.Equal(t, jwt.ErrInvalidKeyType, m.Verify(signingString, parts[2], (*ps.PublicKey)(nil)))
+	_, err := m.Sign(signingString, (*ps.PrivateKey)(nil))
+	require.Equal(t, jwt.ErrInvalidKeyType, err)
+}
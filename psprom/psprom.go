@@ -0,0 +1,143 @@
+// Package psprom exports the observations of ps.Instrumentation as
+// Prometheus metrics. A Collector is both the ps.Instrumentation to pass to
+// ps.WithInstrumentation and the prometheus.Collector to register:
+//
+//	reg.MustRegister(c)
+//	ks := ps.NewKeySet(0, ps.WithInstrumentation(c))
+//
+// It exports, under the namespace given to NewCollector:
+//
+//	verifications_total{result}             counter
+//	verification_duration_seconds{result}   histogram
+//	verification_attributes                 histogram
+//	signatures_total{result}                counter
+//	signature_duration_seconds{result}      histogram
+//	signature_attributes                    histogram
+//
+// where result is "ok" or "failed". The labelled series are resolved when
+// the Collector is made, so that observing takes no lookup and no
+// allocation.
+package psprom
+
+import (
+	"time"
+
+	"github.com/bithinalangot/ps"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The values of the result label.
+const (
+	ResultOK     = "ok"
+	ResultFailed = "failed"
+)
+
+// durationBuckets cover 0.25ms to about 4s: a verification takes a few
+// milliseconds, a bulk call or a loaded host more.
+var durationBuckets = prometheus.ExponentialBuckets(0.00025, 2, 15)
+
+// attributeBuckets cover 1 to 64 attributes.
+var attributeBuckets = prometheus.ExponentialBuckets(1, 2, 7)
+
+// Collector collects the observations of the ps values and calls it is
+// attached to. It is safe for concurrent use.
+type Collector struct {
+	verify, sign *metrics
+}
+
+// metrics are the series of one kind of observation.
+type metrics struct {
+	total    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	attrs    prometheus.Histogram
+
+	okTotal, failedTotal       prometheus.Counter
+	okDuration, failedDuration prometheus.Observer
+}
+
+func newMetrics(namespace, noun, plural string) *metrics {
+	m := &metrics{
+		total: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      plural + "_total",
+			Help:      "Number of " + plural + " by result.",
+		}, []string{"result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      noun + "_duration_seconds",
+			Help:      "Duration of " + plural + " by result.",
+			Buckets:   durationBuckets,
+		}, []string{"result"}),
+		attrs: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      noun + "_attributes",
+			Help:      "Number of attributes per signature of " + plural + ".",
+			Buckets:   attributeBuckets,
+		}),
+	}
+	m.okTotal = m.total.WithLabelValues(ResultOK)
+	m.failedTotal = m.total.WithLabelValues(ResultFailed)
+	m.okDuration = m.duration.WithLabelValues(ResultOK)
+	m.failedDuration = m.duration.WithLabelValues(ResultFailed)
+	return m
+}
+
+func (m *metrics) observe(d time.Duration, ok bool, attrs int) {
+	if ok {
+		m.okTotal.Inc()
+		m.okDuration.Observe(d.Seconds())
+	} else {
+		m.failedTotal.Inc()
+		m.failedDuration.Observe(d.Seconds())
+	}
+	m.attrs.Observe(float64(attrs))
+}
+
+func (m *metrics) describe(ch chan<- *prometheus.Desc) {
+	m.total.Describe(ch)
+	m.duration.Describe(ch)
+	m.attrs.Describe(ch)
+}
+
+func (m *metrics) collect(ch chan<- prometheus.Metric) {
+	m.total.Collect(ch)
+	m.duration.Collect(ch)
+	m.attrs.Collect(ch)
+}
+
+// NewCollector returns a Collector whose metrics are named under namespace,
+// e.g. "ps" for ps_verifications_total. An empty namespace leaves the names
+// bare.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		verify: newMetrics(namespace, "verification", "verifications"),
+		sign:   newMetrics(namespace, "signature", "signatures"),
+	}
+}
+
+var (
+	_ ps.Instrumentation   = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// ObserveVerify records a verification.
+func (c *Collector) ObserveVerify(d time.Duration, ok bool, attrs int) {
+	c.verify.observe(d, ok, attrs)
+}
+
+// ObserveSign records a signature.
+func (c *Collector) ObserveSign(d time.Duration, ok bool, attrs int) {
+	c.sign.observe(d, ok, attrs)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.verify.describe(ch)
+	c.sign.describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.verify.collect(ch)
+	c.sign.collect(ch)
+}
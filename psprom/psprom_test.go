@@ -0,0 +1,87 @@
+package psprom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/pstest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestCollector(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	f := pstest.Fixture(t, suite, 2)
+	c := NewCollector("ps")
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	ks := ps.NewKeySet(0, ps.WithInstrumentation(c))
+	id, err := ks.Add(f.Public)
+	require.NoError(t, err)
+	v, err := ks.Resolve(id)
+	require.NoError(t, err)
+	S, err := f.Signature.Components()
+	require.NoError(t, err)
+
+	verified := testutil.ToFloat64(c.verify.okTotal)
+	require.NoError(t, v.BatchVerify(f.Messages, S))
+	require.Equal(t, verified+1, testutil.ToFloat64(c.verify.okTotal))
+
+	// Every class of failure counts as failed.
+	truncated := [][]byte{S[0], S[1][:len(S[1])-1]}
+	failures := map[string]func() error{
+		"invalid signature":   func() error { return v.BatchVerify([][]byte{f.Messages[1], f.Messages[0]}, S) },
+		"malformed signature": func() error { return v.BatchVerify(f.Messages, truncated) },
+		"nil signature":       func() error { return v.BatchVerify(f.Messages, nil) },
+		"too many messages":   func() error { return v.BatchVerify(append(f.Messages[:2:2], []byte("m")), S) },
+		"unknown key": func() error {
+			_, err := ks.VerifyPartial(nil, &ps.SignatureProof{KeyID: "0000000000000000"}, nil)
+			return err
+		},
+	}
+	for class, call := range failures {
+		failed := testutil.ToFloat64(c.verify.failedTotal)
+		require.Error(t, call(), class)
+		require.Equal(t, failed+1, testutil.ToFloat64(c.verify.failedTotal), class)
+	}
+	require.Equal(t, verified+1, testutil.ToFloat64(c.verify.okTotal))
+
+	signer, err := ps.NewConcurrentSigner(suite, f.Private, nil, ps.WithInstrumentation(c))
+	require.NoError(t, err)
+	_, err = signer.BatchSign(f.Messages)
+	require.NoError(t, err)
+	_, err = signer.BatchSign(append(f.Messages[:2:2], []byte("m")))
+	require.True(t, errors.Is(err, ps.ErrKeyLengthMismatch))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.sign.okTotal))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.sign.failedTotal))
+
+	expected := `
+# HELP ps_signatures_total Number of signatures by result.
+# TYPE ps_signatures_total counter
+ps_signatures_total{result="failed"} 1
+ps_signatures_total{result="ok"} 1
+# HELP ps_verifications_total Number of verifications by result.
+# TYPE ps_verifications_total counter
+ps_verifications_total{result="failed"} 5
+ps_verifications_total{result="ok"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "ps_verifications_total", "ps_signatures_total"))
+	n, err := testutil.GatherAndCount(reg)
+	require.NoError(t, err)
+	require.Equal(t, 10, n)
+}
+
+func TestCollectorAllocs(t *testing.T) {
+	c := NewCollector("ps")
+	allocs := testing.AllocsPerRun(100, func() {
+		c.ObserveVerify(1000, true, 2)
+		c.ObserveVerify(1000, false, 2)
+		c.ObserveSign(1000, true, 2)
+	})
+	require.Equal(t, 0.0, allocs)
+}
@@ -0,0 +1,104 @@
+package psrpc
+
+import (
+	"context"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3/pairing"
+	"google.golang.org/grpc"
+)
+
+// Client signs through a Server. It implements ps.Signer, where calls run
+// under context.Background; the methods taking a context bound them.
+type Client struct {
+	rpc   SignerClient
+	suite pairing.Suite
+	pub   *ps.PublicKey
+	bk    *ps.BlindingKey
+}
+
+var _ ps.Signer = (*Client)(nil)
+
+// NewClient returns a client of the server at the other end of conn, whose
+// keys belong to suite. It fetches the public key and blinding key of the
+// server.
+func NewClient(ctx context.Context, conn grpc.ClientConnInterface, suite pairing.Suite) (*Client, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	rpc := NewSignerClient(conn)
+	resp, err := rpc.PublicKey(ctx, &PublicKeyRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	pub, err := ps.UnmarshalPublicKey(suite, resp.GetPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	bk, err := ps.UnmarshalBlindingKey(suite, resp.GetBlindingKey())
+	if err != nil {
+		return nil, err
+	}
+	return &Client{rpc: rpc, suite: suite, pub: pub, bk: bk}, nil
+}
+
+// PublicKey returns the public key of the server.
+func (c *Client) PublicKey() *ps.PublicKey { return c.pub }
+
+// BlindingKey returns the blinding key of the server, for
+// ps.PrepareBlindSign.
+func (c *Client) BlindingKey() *ps.BlindingKey { return c.bk }
+
+// Sign signs msgs, see SignBatch.
+func (c *Client) Sign(msgs [][]byte) ([][]byte, error) {
+	sig, err := c.SignBatch(context.Background(), msgs)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Components()
+}
+
+// SignAcross adds msg to the aggregate S, see AggregateSign.
+func (c *Client) SignAcross(S *ps.Signature, msg []byte) (*ps.Signature, error) {
+	return c.AggregateSign(context.Background(), S, msg)
+}
+
+// SignBatch has the server sign msgs in slots 1 to len(msgs).
+func (c *Client) SignBatch(ctx context.Context, msgs [][]byte) (*ps.Signature, error) {
+	resp, err := c.rpc.SignBatch(ctx, &SignBatchRequest{Messages: msgs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return ps.UnmarshalSignature(c.suite, resp.GetSignature())
+}
+
+// BlindSign has the server sign the commitment of req. The result is
+// unblinded with ps.Unblind.
+func (c *Client) BlindSign(ctx context.Context, req *ps.BlindSignRequest) (*ps.Signature, error) {
+	data, err := req.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.rpc.BlindSign(ctx, &BlindSignRequest{Request: data})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return ps.UnmarshalSignature(c.suite, resp.GetSignature())
+}
+
+// AggregateSign has the server add msg to the aggregate S of other
+// signers, or start one if S is nil.
+func (c *Client) AggregateSign(ctx context.Context, S *ps.Signature, msg []byte) (*ps.Signature, error) {
+	var data []byte
+	if S != nil {
+		var err error
+		if data, err = S.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.rpc.AggregateSign(ctx, &AggregateSignRequest{Signature: data, Message: msg})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return ps.UnmarshalSignature(c.suite, resp.GetSignature())
+}
@@ -0,0 +1,21 @@
+// Package psrpc signs PS signatures remotely over gRPC, so that issuance keys
+// stay on a separate host. The Server wraps a *ps.PrivateKey and serves the
+// Signer service of psrpc.proto; the Client implements ps.Signer on top of a
+// connection to it, so that code issuing signatures does not depend on where
+// the key lives.
+//
+// Each RPC is refused unless the key's policy allows its operation:
+// SignBatch needs ps.OpSign, BlindSign ps.OpBlindSign and AggregateSign
+// ps.OpAggregate. Requests are held to the attribute limit of the server's
+// ps.Config before any work is done on them. Only the public key and the
+// blinding key leave the server; no RPC returns private key material.
+//
+// Errors of the ps package, and ErrInvalidRequest for requests that do not
+// decode, cross the wire as a gRPC status carrying an ErrorInfo detail in
+// the domain "ps" whose reason names the error, e.g. POLICY_VIOLATION. The
+// Client turns them back into errors matching the sentinels with errors.Is,
+// with the server's message. Other server errors are reported as
+// codes.Internal without their message.
+package psrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative psrpc.proto
@@ -0,0 +1,108 @@
+package psrpc
+
+import (
+	"errors"
+
+	"github.com/bithinalangot/ps"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidRequest is returned for requests whose keys, signatures or blind
+// sign requests do not decode.
+var ErrInvalidRequest = errors.New("psrpc: invalid request")
+
+// errorDomain is the ErrorInfo domain of errors of the ps package.
+const errorDomain = "ps"
+
+// wireErrors are the errors that cross the wire with their identity, by
+// ErrorInfo reason, and the status code they are reported with. The first
+// match wins, so errors wrapping several sentinels keep the most specific.
+var wireErrors = []struct {
+	reason string
+	code   codes.Code
+	err    error
+}{
+	{"POLICY_VIOLATION", codes.PermissionDenied, ps.ErrPolicyViolation},
+	{"LIMIT_EXCEEDED", codes.ResourceExhausted, ps.ErrLimitExceeded},
+	{"KEY_WIPED", codes.FailedPrecondition, ps.ErrKeyWiped},
+	{"INVALID_BLIND_REQUEST", codes.InvalidArgument, ps.ErrInvalidBlindRequest},
+	{"INVALID_SIGNATURE", codes.InvalidArgument, ps.ErrInvalidSignature},
+	{"SUITE_MISMATCH", codes.InvalidArgument, ps.ErrSuiteMismatch},
+	{"UNSUPPORTED_VERSION", codes.InvalidArgument, ps.ErrUnsupportedVersion},
+	{"KEY_LENGTH_MISMATCH", codes.InvalidArgument, ps.ErrKeyLengthMismatch},
+	{"NO_MESSAGES", codes.InvalidArgument, ps.ErrNoMessages},
+	{"NIL_MESSAGE", codes.InvalidArgument, ps.ErrNilMessage},
+	{"EMPTY_MESSAGE", codes.InvalidArgument, ps.ErrEmptyMessage},
+	{"ZERO_ATTRIBUTE", codes.InvalidArgument, ps.ErrZeroAttribute},
+	{"INVALID_REQUEST", codes.InvalidArgument, ErrInvalidRequest},
+}
+
+// requestError marks err, an error decoding a request, as ErrInvalidRequest
+// while keeping the ps error it wraps.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+
+func (e *requestError) Unwrap() error { return e.err }
+
+func (e *requestError) Is(target error) bool { return target == ErrInvalidRequest }
+
+// toStatus converts an error of the server to the status sent to the
+// client. Errors unknown to wireErrors lose their message, which might
+// describe the server's state.
+func toStatus(err error) error {
+	for _, w := range wireErrors {
+		if !errors.Is(err, w.err) {
+			continue
+		}
+		st, derr := status.New(w.code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason: w.reason,
+			Domain: errorDomain,
+		})
+		if derr != nil {
+			return status.Error(w.code, err.Error())
+		}
+		return st.Err()
+	}
+	return status.Error(codes.Internal, "psrpc: internal error")
+}
+
+// remoteError is an error of the server, matching the ps sentinel it was
+// reported as.
+type remoteError struct {
+	st  *status.Status
+	err error
+}
+
+func (e *remoteError) Error() string { return e.st.Message() }
+
+func (e *remoteError) Unwrap() error { return e.err }
+
+// GRPCStatus returns the status the server sent, for status.Code and
+// status.FromError.
+func (e *remoteError) GRPCStatus() *status.Status { return e.st }
+
+// fromStatus converts an error of an RPC back to the ps error it carries, or
+// returns it unchanged.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != errorDomain {
+			continue
+		}
+		for _, w := range wireErrors {
+			if w.reason == info.GetReason() {
+				return &remoteError{st: st, err: w.err}
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,251 @@
+// Remote issuance of PS signatures, so that the private key stays on the
+// signing host. Keys, signatures and blind sign requests travel in the
+// binary encodings of the ps package.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: psrpc.proto
+
+package psrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Signer_PublicKey_FullMethodName     = "/psrpc.v1.Signer/PublicKey"
+	Signer_SignBatch_FullMethodName     = "/psrpc.v1.Signer/SignBatch"
+	Signer_BlindSign_FullMethodName     = "/psrpc.v1.Signer/BlindSign"
+	Signer_AggregateSign_FullMethodName = "/psrpc.v1.Signer/AggregateSign"
+)
+
+// SignerClient is the client API for Signer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Signer signs with one PS private key held by the server.
+type SignerClient interface {
+	// PublicKey returns the public key and blinding key of the signer.
+	PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error)
+	// SignBatch signs messages in slots 1 to len(messages).
+	SignBatch(ctx context.Context, in *SignBatchRequest, opts ...grpc.CallOption) (*SignBatchResponse, error)
+	// BlindSign signs the commitment of a blind sign request.
+	BlindSign(ctx context.Context, in *BlindSignRequest, opts ...grpc.CallOption) (*BlindSignResponse, error)
+	// AggregateSign adds a message to an aggregate of other signers.
+	AggregateSign(ctx context.Context, in *AggregateSignRequest, opts ...grpc.CallOption) (*AggregateSignResponse, error)
+}
+
+type signerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerClient(cc grpc.ClientConnInterface) SignerClient {
+	return &signerClient{cc}
+}
+
+func (c *signerClient) PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublicKeyResponse)
+	err := c.cc.Invoke(ctx, Signer_PublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignBatch(ctx context.Context, in *SignBatchRequest, opts ...grpc.CallOption) (*SignBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignBatchResponse)
+	err := c.cc.Invoke(ctx, Signer_SignBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) BlindSign(ctx context.Context, in *BlindSignRequest, opts ...grpc.CallOption) (*BlindSignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlindSignResponse)
+	err := c.cc.Invoke(ctx, Signer_BlindSign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) AggregateSign(ctx context.Context, in *AggregateSignRequest, opts ...grpc.CallOption) (*AggregateSignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregateSignResponse)
+	err := c.cc.Invoke(ctx, Signer_AggregateSign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServer is the server API for Signer service.
+// All implementations must embed UnimplementedSignerServer
+// for forward compatibility.
+//
+// Signer signs with one PS private key held by the server.
+type SignerServer interface {
+	// PublicKey returns the public key and blinding key of the signer.
+	PublicKey(context.Context, *PublicKeyRequest) (*PublicKeyResponse, error)
+	// SignBatch signs messages in slots 1 to len(messages).
+	SignBatch(context.Context, *SignBatchRequest) (*SignBatchResponse, error)
+	// BlindSign signs the commitment of a blind sign request.
+	BlindSign(context.Context, *BlindSignRequest) (*BlindSignResponse, error)
+	// AggregateSign adds a message to an aggregate of other signers.
+	AggregateSign(context.Context, *AggregateSignRequest) (*AggregateSignResponse, error)
+	mustEmbedUnimplementedSignerServer()
+}
+
+// UnimplementedSignerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignerServer struct{}
+
+func (UnimplementedSignerServer) PublicKey(context.Context, *PublicKeyRequest) (*PublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublicKey not implemented")
+}
+func (UnimplementedSignerServer) SignBatch(context.Context, *SignBatchRequest) (*SignBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignBatch not implemented")
+}
+func (UnimplementedSignerServer) BlindSign(context.Context, *BlindSignRequest) (*BlindSignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlindSign not implemented")
+}
+func (UnimplementedSignerServer) AggregateSign(context.Context, *AggregateSignRequest) (*AggregateSignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateSign not implemented")
+}
+func (UnimplementedSignerServer) mustEmbedUnimplementedSignerServer() {}
+func (UnimplementedSignerServer) testEmbeddedByValue()                {}
+
+// UnsafeSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignerServer will
+// result in compilation errors.
+type UnsafeSignerServer interface {
+	mustEmbedUnimplementedSignerServer()
+}
+
+func RegisterSignerServer(s grpc.ServiceRegistrar, srv SignerServer) {
+	// If the following call pancis, it indicates UnimplementedSignerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Signer_ServiceDesc, srv)
+}
+
+func _Signer_PublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).PublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_PublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).PublicKey(ctx, req.(*PublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_SignBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignBatch(ctx, req.(*SignBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_BlindSign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlindSignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).BlindSign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_BlindSign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).BlindSign(ctx, req.(*BlindSignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_AggregateSign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateSignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).AggregateSign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_AggregateSign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).AggregateSign(ctx, req.(*AggregateSignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Signer_ServiceDesc is the grpc.ServiceDesc for Signer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Signer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "psrpc.v1.Signer",
+	HandlerType: (*SignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublicKey",
+			Handler:    _Signer_PublicKey_Handler,
+		},
+		{
+			MethodName: "SignBatch",
+			Handler:    _Signer_SignBatch_Handler,
+		},
+		{
+			MethodName: "BlindSign",
+			Handler:    _Signer_BlindSign_Handler,
+		},
+		{
+			MethodName: "AggregateSign",
+			Handler:    _Signer_AggregateSign_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "psrpc.proto",
+}
@@ -0,0 +1,168 @@
+package psrpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/bithinalangot/ps/pstest"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testSuites = []struct {
+	name  string
+	suite pairing.Suite
+}{
+	{"bn256", pairing.NewSuiteBn256()},
+	{"bls12381", bls12381.NewSuite()},
+}
+
+func forEachSuite(t *testing.T, f func(t *testing.T, suite pairing.Suite)) {
+	for _, ts := range testSuites {
+		suite := ts.suite
+		t.Run(ts.name, func(t *testing.T) { f(t, suite) })
+	}
+}
+
+// dial serves priv on an in-memory connection and returns a client of it.
+func dial(t *testing.T, priv *ps.PrivateKey, config ps.Config) (*Client, *grpc.ClientConn) {
+	srv, err := NewServer(priv, config)
+	require.Nil(t, err)
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterSignerServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	c, err := NewClient(context.Background(), conn, priv.Suite())
+	require.Nil(t, err)
+	return c, conn
+}
+
+func TestConformance(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		pstest.RunSchemeTests(t, suite, func(suite pairing.Suite, slots int) (ps.Signer, error) {
+			priv, _, err := ps.GenerateKey(suite, slots, nil)
+			if err != nil {
+				return nil, err
+			}
+			c, _ := dial(t, priv, ps.Config{})
+			return c, nil
+		})
+	})
+}
+
+func TestBlindSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub, err := ps.GenerateKey(suite, 3, nil)
+		require.Nil(t, err)
+		c, _ := dial(t, priv, ps.Config{})
+		require.True(t, pub.Equal(c.PublicKey()))
+
+		msgs := [][]byte{[]byte("alice"), []byte("1990-01-01")}
+		req, state, err := ps.PrepareBlindSign(suite, c.PublicKey(), c.BlindingKey(), msgs, random.New())
+		require.Nil(t, err)
+		blind, err := c.BlindSign(context.Background(), req)
+		require.Nil(t, err)
+		sig, err := ps.Unblind(suite, state, blind)
+		require.Nil(t, err)
+		S, err := sig.Components()
+		require.Nil(t, err)
+		require.Nil(t, ps.PSBatchVerify(suite, pub.Points(), msgs, S))
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, _, err := ps.GenerateKey(suite, 2, &ps.KeyPolicy{Operations: ps.OpBlindSign})
+	require.Nil(t, err)
+	c, _ := dial(t, priv, ps.Config{})
+	ctx := context.Background()
+
+	_, err = c.SignBatch(ctx, [][]byte{[]byte("msg")})
+	require.True(t, errors.Is(err, ps.ErrPolicyViolation))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Contains(t, err.Error(), "sign not allowed")
+	_, err = c.AggregateSign(ctx, nil, []byte("msg"))
+	require.True(t, errors.Is(err, ps.ErrPolicyViolation))
+
+	// Slot policies are enforced by the key itself.
+	priv, _, err = ps.GenerateKey(suite, 2, &ps.KeyPolicy{Reserved: []int{0}, Operations: ps.AllOperations})
+	require.Nil(t, err)
+	c, _ = dial(t, priv, ps.Config{})
+	_, err = c.SignBatch(ctx, [][]byte{[]byte("msg")})
+	require.True(t, errors.Is(err, ps.ErrPolicyViolation))
+}
+
+func TestLimits(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, _, err := ps.GenerateKey(suite, 4, nil)
+	require.Nil(t, err)
+	c, _ := dial(t, priv, ps.Config{MaxAttributes: 2})
+	ctx := context.Background()
+	msgs := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	_, err = c.SignBatch(ctx, msgs)
+	require.True(t, errors.Is(err, ps.ErrLimitExceeded))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = c.SignBatch(ctx, msgs[:2])
+	require.Nil(t, err)
+
+	req, _, err := ps.PrepareBlindSign(suite, c.PublicKey(), c.BlindingKey(), msgs, random.New())
+	require.Nil(t, err)
+	_, err = c.BlindSign(ctx, req)
+	require.True(t, errors.Is(err, ps.ErrLimitExceeded))
+}
+
+func TestErrors(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	priv, _, err := ps.GenerateKey(suite, 1, nil)
+	require.Nil(t, err)
+	c, conn := dial(t, priv, ps.Config{})
+	ctx := context.Background()
+
+	_, err = c.SignBatch(ctx, nil)
+	require.True(t, errors.Is(err, ps.ErrNoMessages))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = c.SignBatch(ctx, [][]byte{{}})
+	require.True(t, errors.Is(err, ps.ErrEmptyMessage))
+	_, err = c.SignBatch(ctx, [][]byte{[]byte("a"), []byte("b")})
+	require.True(t, errors.Is(err, ps.ErrKeyLengthMismatch))
+
+	S, err := c.AggregateSign(ctx, nil, []byte("msg"))
+	require.Nil(t, err)
+	buf, err := S.MarshalBinary()
+	require.Nil(t, err)
+	_, err = NewSignerClient(conn).AggregateSign(ctx, &AggregateSignRequest{Signature: buf[:len(buf)-1], Message: []byte("msg")})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.True(t, errors.Is(fromStatus(err), ErrInvalidRequest))
+	_, err = NewSignerClient(conn).BlindSign(ctx, &BlindSignRequest{})
+	require.True(t, errors.Is(fromStatus(err), ErrInvalidRequest))
+
+	// Unexpected errors do not leak their message.
+	err = toStatus(errors.New("disk /var/keys/issuer unreadable"))
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.False(t, strings.Contains(err.Error(), "/var/keys"))
+	require.Equal(t, err, fromStatus(err))
+
+	_, err = NewServer(nil, ps.Config{})
+	require.Equal(t, ps.ErrNilKey, err)
+	_, err = NewClient(ctx, conn, nil)
+	require.Equal(t, ps.ErrNilSuite, err)
+}
@@ -0,0 +1,157 @@
+package psrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+)
+
+// Server serves the Signer service with one private key. It is safe for
+// concurrent use as long as the key is not modified.
+type Server struct {
+	UnimplementedSignerServer
+
+	priv   *ps.PrivateKey
+	pub    []byte
+	bk     []byte
+	config ps.Config
+	opts   []ps.Option
+}
+
+var _ SignerServer = (*Server)(nil)
+
+// NewServer returns a server signing with priv. config limits the number of
+// messages a request may carry, as it does for ps.BatchSign; opts, e.g.
+// ps.WithDST, apply to SignBatch and AggregateSign.
+func NewServer(priv *ps.PrivateKey, config ps.Config, opts ...ps.Option) (*Server, error) {
+	pub := priv.Public()
+	if pub == nil {
+		return nil, ps.ErrNilKey
+	}
+	pubBuf, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	bkBuf, err := priv.BlindingKey().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		priv:   priv,
+		pub:    pubBuf,
+		bk:     bkBuf,
+		config: config,
+		opts:   append(append([]ps.Option{}, opts...), ps.WithConfig(config)),
+	}, nil
+}
+
+// allow refuses op unless the policy of the key allows it.
+func (s *Server) allow(op ps.KeyOperation) error {
+	if p := s.priv.Policy(); p != nil && p.Operations&op == 0 {
+		return fmt.Errorf("%w: %v not allowed", ps.ErrPolicyViolation, op)
+	}
+	return nil
+}
+
+// checkMessages enforces the attribute limit of the server on n messages.
+func (s *Server) checkMessages(n int) error {
+	max := s.config.MaxAttributes
+	if max == 0 {
+		max = ps.DefaultMaxAttributes
+	}
+	if max > 0 && n > max {
+		return fmt.Errorf("%w: %d messages, at most %d", ps.ErrLimitExceeded, n, max)
+	}
+	return nil
+}
+
+// PublicKey returns the public key and blinding key of the server.
+func (s *Server) PublicKey(context.Context, *PublicKeyRequest) (*PublicKeyResponse, error) {
+	return &PublicKeyResponse{PublicKey: s.pub, BlindingKey: s.bk}, nil
+}
+
+// SignBatch signs the messages of req, as ps.PrivateKey.BatchSign does.
+func (s *Server) SignBatch(_ context.Context, req *SignBatchRequest) (*SignBatchResponse, error) {
+	sig, err := s.signBatch(req.GetMessages())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &SignBatchResponse{Signature: sig}, nil
+}
+
+func (s *Server) signBatch(msgs [][]byte) ([]byte, error) {
+	if err := s.allow(ps.OpSign); err != nil {
+		return nil, err
+	}
+	if err := s.checkMessages(len(msgs)); err != nil {
+		return nil, err
+	}
+	S, err := s.priv.BatchSign(msgs, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ps.NewSignature(s.priv.Suite(), S)
+	if err != nil {
+		return nil, err
+	}
+	return sig.MarshalBinary()
+}
+
+// BlindSign signs the commitment of the blind sign request of req, as
+// ps.BlindSign does.
+func (s *Server) BlindSign(_ context.Context, req *BlindSignRequest) (*BlindSignResponse, error) {
+	sig, err := s.blindSign(req.GetRequest())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &BlindSignResponse{Signature: sig}, nil
+}
+
+func (s *Server) blindSign(data []byte) ([]byte, error) {
+	if err := s.allow(ps.OpBlindSign); err != nil {
+		return nil, err
+	}
+	suite := s.priv.Suite()
+	breq, err := ps.UnmarshalBlindSignRequest(suite, data)
+	if err != nil {
+		return nil, &requestError{err}
+	}
+	if err := s.checkMessages(len(breq.Responses) - 1); err != nil {
+		return nil, err
+	}
+	sig, err := ps.BlindSign(suite, s.priv, breq)
+	if err != nil {
+		return nil, err
+	}
+	return sig.MarshalBinary()
+}
+
+// AggregateSign adds the message of req to its aggregate, as
+// ps.AggregateSignAcross does.
+func (s *Server) AggregateSign(_ context.Context, req *AggregateSignRequest) (*AggregateSignResponse, error) {
+	sig, err := s.aggregateSign(req.GetSignature(), req.GetMessage())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &AggregateSignResponse{Signature: sig}, nil
+}
+
+func (s *Server) aggregateSign(data, msg []byte) ([]byte, error) {
+	if err := s.allow(ps.OpAggregate); err != nil {
+		return nil, err
+	}
+	suite := s.priv.Suite()
+	var S *ps.Signature
+	if len(data) > 0 {
+		var err error
+		if S, err = ps.UnmarshalSignature(suite, data); err != nil {
+			return nil, &requestError{err}
+		}
+	}
+	sig, err := ps.AggregateSignAcross(suite, s.priv, S, msg, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	return sig.MarshalBinary()
+}
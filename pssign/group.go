@@ -0,0 +1,315 @@
+package pssign
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// KeyScalar is a pair (x, y) of scalars, an element of Z_n x Z_n. Operations
+// act on both components; a plain scalar s used as an operand stands for
+// (s, s). KeyScalars are made by a Scheme.
+type KeyScalar struct {
+	g    kyber.Group
+	X, Y kyber.Scalar
+}
+
+// KeyPoint is a pair (X, Y) of points of G2, an element of G2 x G2.
+// Operations act on both components, and a scalar multiplies both as a
+// KeyScalar does. KeyPoints are made by a Scheme.
+type KeyPoint struct {
+	g    kyber.Group
+	X, Y kyber.Point
+}
+
+// Scalar returns the zero KeyScalar of the scheme.
+func (s *Scheme) Scalar() kyber.Scalar {
+	return (&KeyScalar{g: s.suite.G1()}).Zero()
+}
+
+// Point returns the neutral KeyPoint of the scheme.
+func (s *Scheme) Point() kyber.Point {
+	return (&KeyPoint{g: s.suite.G2()}).Null()
+}
+
+// scalarPair returns the components of a, or a twice for a plain scalar.
+func scalarPair(a kyber.Scalar) (kyber.Scalar, kyber.Scalar) {
+	if k, ok := a.(*KeyScalar); ok {
+		return k.X, k.Y
+	}
+	return a, a
+}
+
+// pointPair returns the components of a, which must be a KeyPoint.
+func pointPair(a kyber.Point) (kyber.Point, kyber.Point) {
+	k, ok := a.(*KeyPoint)
+	if !ok {
+		panic(fmt.Sprintf("pssign: %T is not a KeyPoint", a))
+	}
+	return k.X, k.Y
+}
+
+func (s *KeyScalar) set(x, y kyber.Scalar) kyber.Scalar {
+	s.X, s.Y = x, y
+	return s
+}
+
+func (s *KeyScalar) String() string {
+	return fmt.Sprintf("(%v, %v)", s.X, s.Y)
+}
+
+// MarshalSize returns the length of x || y.
+func (s *KeyScalar) MarshalSize() int {
+	return 2 * s.g.ScalarLen()
+}
+
+// MarshalBinary encodes the scalar as x || y.
+func (s *KeyScalar) MarshalBinary() ([]byte, error) {
+	x, err := s.X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	y, err := s.Y.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(x, y...), nil
+}
+
+// UnmarshalBinary decodes x || y.
+func (s *KeyScalar) UnmarshalBinary(data []byte) error {
+	n := s.g.ScalarLen()
+	if len(data) != 2*n {
+		return fmt.Errorf("pssign: %d-byte key scalar, expected %d", len(data), 2*n)
+	}
+	x, y := s.g.Scalar(), s.g.Scalar()
+	if err := x.UnmarshalBinary(data[:n]); err != nil {
+		return err
+	}
+	if err := y.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	s.set(x, y)
+	return nil
+}
+
+// MarshalTo writes the encoding of the scalar to w.
+func (s *KeyScalar) MarshalTo(w io.Writer) (int, error) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// UnmarshalFrom reads the encoding of a scalar from r.
+func (s *KeyScalar) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, s.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, s.UnmarshalBinary(buf)
+}
+
+func (s *KeyScalar) Equal(a kyber.Scalar) bool {
+	ax, ay := scalarPair(a)
+	return s.X.Equal(ax) && s.Y.Equal(ay)
+}
+
+func (s *KeyScalar) Set(a kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	return s.set(ax.Clone(), ay.Clone())
+}
+
+func (s *KeyScalar) Clone() kyber.Scalar {
+	return (&KeyScalar{g: s.g}).Set(s)
+}
+
+func (s *KeyScalar) SetInt64(v int64) kyber.Scalar {
+	return s.set(s.g.Scalar().SetInt64(v), s.g.Scalar().SetInt64(v))
+}
+
+func (s *KeyScalar) Zero() kyber.Scalar {
+	return s.set(s.g.Scalar().Zero(), s.g.Scalar().Zero())
+}
+
+func (s *KeyScalar) One() kyber.Scalar {
+	return s.set(s.g.Scalar().One(), s.g.Scalar().One())
+}
+
+func (s *KeyScalar) Add(a, b kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	bx, by := scalarPair(b)
+	return s.set(s.g.Scalar().Add(ax, bx), s.g.Scalar().Add(ay, by))
+}
+
+func (s *KeyScalar) Sub(a, b kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	bx, by := scalarPair(b)
+	return s.set(s.g.Scalar().Sub(ax, bx), s.g.Scalar().Sub(ay, by))
+}
+
+func (s *KeyScalar) Neg(a kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	return s.set(s.g.Scalar().Neg(ax), s.g.Scalar().Neg(ay))
+}
+
+func (s *KeyScalar) Mul(a, b kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	bx, by := scalarPair(b)
+	return s.set(s.g.Scalar().Mul(ax, bx), s.g.Scalar().Mul(ay, by))
+}
+
+func (s *KeyScalar) Div(a, b kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	bx, by := scalarPair(b)
+	return s.set(s.g.Scalar().Div(ax, bx), s.g.Scalar().Div(ay, by))
+}
+
+func (s *KeyScalar) Inv(a kyber.Scalar) kyber.Scalar {
+	ax, ay := scalarPair(a)
+	return s.set(s.g.Scalar().Inv(ax), s.g.Scalar().Inv(ay))
+}
+
+func (s *KeyScalar) Pick(rand cipher.Stream) kyber.Scalar {
+	return s.set(s.g.Scalar().Pick(rand), s.g.Scalar().Pick(rand))
+}
+
+// SetBytes sets both components to the scalar of buf.
+func (s *KeyScalar) SetBytes(buf []byte) kyber.Scalar {
+	return s.set(s.g.Scalar().SetBytes(buf), s.g.Scalar().SetBytes(buf))
+}
+
+func (p *KeyPoint) set(x, y kyber.Point) kyber.Point {
+	p.X, p.Y = x, y
+	return p
+}
+
+func (p *KeyPoint) String() string {
+	return fmt.Sprintf("(%v, %v)", p.X, p.Y)
+}
+
+// MarshalSize returns the length of X || Y.
+func (p *KeyPoint) MarshalSize() int {
+	return 2 * p.g.PointLen()
+}
+
+// MarshalBinary encodes the point as X || Y.
+func (p *KeyPoint) MarshalBinary() ([]byte, error) {
+	x, err := p.X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	y, err := p.Y.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(x, y...), nil
+}
+
+// UnmarshalBinary decodes X || Y.
+func (p *KeyPoint) UnmarshalBinary(data []byte) error {
+	n := p.g.PointLen()
+	if len(data) != 2*n {
+		return fmt.Errorf("pssign: %d-byte key point, expected %d", len(data), 2*n)
+	}
+	x, y := p.g.Point(), p.g.Point()
+	if err := x.UnmarshalBinary(data[:n]); err != nil {
+		return err
+	}
+	if err := y.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	p.set(x, y)
+	return nil
+}
+
+// MarshalTo writes the encoding of the point to w.
+func (p *KeyPoint) MarshalTo(w io.Writer) (int, error) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// UnmarshalFrom reads the encoding of a point from r.
+func (p *KeyPoint) UnmarshalFrom(r io.Reader) (int, error) {
+	buf := make([]byte, p.MarshalSize())
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return n, err
+	}
+	return n, p.UnmarshalBinary(buf)
+}
+
+func (p *KeyPoint) Equal(a kyber.Point) bool {
+	k, ok := a.(*KeyPoint)
+	return ok && p.X.Equal(k.X) && p.Y.Equal(k.Y)
+}
+
+func (p *KeyPoint) Null() kyber.Point {
+	return p.set(p.g.Point().Null(), p.g.Point().Null())
+}
+
+func (p *KeyPoint) Base() kyber.Point {
+	return p.set(p.g.Point().Base(), p.g.Point().Base())
+}
+
+func (p *KeyPoint) Pick(rand cipher.Stream) kyber.Point {
+	return p.set(p.g.Point().Pick(rand), p.g.Point().Pick(rand))
+}
+
+func (p *KeyPoint) Set(a kyber.Point) kyber.Point {
+	ax, ay := pointPair(a)
+	return p.set(ax.Clone(), ay.Clone())
+}
+
+func (p *KeyPoint) Clone() kyber.Point {
+	return (&KeyPoint{g: p.g}).Set(p)
+}
+
+// EmbedLen returns the number of bytes embeddable in X.
+func (p *KeyPoint) EmbedLen() int {
+	return p.g.Point().EmbedLen()
+}
+
+// Embed embeds data in X and picks Y at random.
+func (p *KeyPoint) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	return p.set(p.g.Point().Embed(data, rand), p.g.Point().Pick(rand))
+}
+
+// Data returns the data embedded in X.
+func (p *KeyPoint) Data() ([]byte, error) {
+	return p.X.Data()
+}
+
+func (p *KeyPoint) Add(a, b kyber.Point) kyber.Point {
+	ax, ay := pointPair(a)
+	bx, by := pointPair(b)
+	return p.set(p.g.Point().Add(ax, bx), p.g.Point().Add(ay, by))
+}
+
+func (p *KeyPoint) Sub(a, b kyber.Point) kyber.Point {
+	ax, ay := pointPair(a)
+	bx, by := pointPair(b)
+	return p.set(p.g.Point().Sub(ax, bx), p.g.Point().Sub(ay, by))
+}
+
+func (p *KeyPoint) Neg(a kyber.Point) kyber.Point {
+	ax, ay := pointPair(a)
+	return p.set(p.g.Point().Neg(ax), p.g.Point().Neg(ay))
+}
+
+// Mul multiplies a by s component-wise, or the base if a is nil.
+func (p *KeyPoint) Mul(s kyber.Scalar, a kyber.Point) kyber.Point {
+	sx, sy := scalarPair(s)
+	if a == nil {
+		return p.set(p.g.Point().Mul(sx, nil), p.g.Point().Mul(sy, nil))
+	}
+	ax, ay := pointPair(a)
+	return p.set(p.g.Point().Mul(sx, ax), p.g.Point().Mul(sy, ay))
+}
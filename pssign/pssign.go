@@ -0,0 +1,198 @@
+// Package pssign adapts single-message PS signatures to the Scheme and
+// AggregatableScheme interfaces that later kyber releases define in package
+// sign, for generic code such as collective signing services and test
+// harnesses. Kyber v3 has no such interfaces, so code written against it
+// declares them itself; Scheme has their method set.
+//
+// A PS key for one message holds two secrets, x and y, and two points, X
+// and Y, where the interfaces have room for one scalar and one point. The
+// scheme therefore uses KeyScalar and KeyPoint, the elements of the product
+// groups Z_n x Z_n and G2 x G2 with component-wise operations, so that the
+// public key (X, Y) is the private key (x, y) times the base (g, g). Keys
+// convert to and from *ps.PrivateKey and *ps.PublicKey with one attribute
+// slot; keys with more slots do not map and are refused with ErrKeySlots.
+//
+// Signatures are multi-signatures on the base derived from the message, see
+// ps.MultiSign, encoded as sigma_1 || sigma_2. Signatures of several keys on
+// one message therefore aggregate into a signature under the sum of the
+// keys, as BLS signatures do; signatures on different messages do not, and
+// AggregateSignatures refuses them. As for BLS, aggregated keys are exposed
+// to rogue keys unless their owners proved possession of the secrets, see
+// ps.ProvePossession.
+package pssign
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+var (
+	// ErrKeyType is returned for keys that are not a KeyScalar or KeyPoint
+	// of the scheme's suite.
+	ErrKeyType = errors.New("pssign: key is not a PS key of the scheme")
+
+	// ErrKeySlots is returned when converting PS keys with more than one
+	// attribute slot.
+	ErrKeySlots = errors.New("pssign: key has more than one attribute slot")
+
+	// ErrInvalidSignatureEncoding is returned for signatures that are not
+	// two marshalled G1 points.
+	ErrInvalidSignatureEncoding = errors.New("pssign: invalid signature encoding")
+)
+
+// Scheme signs single messages with PS keys of a fixed suite.
+type Scheme struct {
+	suite pairing.Suite
+	opts  []ps.Option
+}
+
+// NewScheme returns the scheme of suite. The options, e.g. ps.WithDST, apply
+// to every signature and verification.
+func NewScheme(suite pairing.Suite, opts ...ps.Option) (*Scheme, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if _, err := ps.MultiSigBase(suite, []byte{0}, opts...); err != nil {
+		return nil, err
+	}
+	return &Scheme{suite: suite, opts: opts}, nil
+}
+
+// NewKeyPair returns a private KeyScalar and its public KeyPoint, drawing
+// both secrets from random. It panics if random fails, since the interface
+// has no room for the error.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	scalars, points, err := ps.NewKeyPairPoints(s.suite, []cipher.Stream{random, random})
+	if err != nil {
+		panic(err)
+	}
+	return &KeyScalar{g: s.suite.G1(), X: scalars[0], Y: scalars[1]}, &KeyPoint{g: s.suite.G2(), X: points[0], Y: points[1]}
+}
+
+// Sign signs msg with private, which must be a KeyScalar.
+func (s *Scheme) Sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	priv, err := s.PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	h, err := ps.MultiSigBase(s.suite, msg, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ps.MultiSign(s.suite, priv, h, msg, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSignature(sig)
+}
+
+// Verify checks a signature on msg under public, which must be a KeyPoint.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	pub, err := s.PublicKey(public)
+	if err != nil {
+		return err
+	}
+	dec, err := s.decodeSignature(sig)
+	if err != nil {
+		return err
+	}
+	return ps.VerifyMultiSig(s.suite, pub, msg, dec, s.opts...)
+}
+
+// AggregateSignatures combines signatures on one message into a signature
+// under the sum of the signers' keys. Signatures on different messages have
+// different bases and give ps.ErrInvalidSignature.
+func (s *Scheme) AggregateSignatures(sigs ...[]byte) ([]byte, error) {
+	decoded := make([]*ps.Signature, len(sigs))
+	for i, sig := range sigs {
+		var err error
+		if decoded[i], err = s.decodeSignature(sig); err != nil {
+			return nil, fmt.Errorf("%w: signature %d", err, i)
+		}
+	}
+	agg, err := ps.CombineSignatures(s.suite, decoded)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSignature(agg)
+}
+
+// AggregatePublicKeys returns the sum of the KeyPoints Xs, the key that
+// verifies their aggregated signatures. It panics on other points, as kyber
+// points do on operands of another group.
+func (s *Scheme) AggregatePublicKeys(Xs ...kyber.Point) kyber.Point {
+	agg := (&KeyPoint{g: s.suite.G2()}).Null()
+	for _, X := range Xs {
+		agg.Add(agg, X)
+	}
+	return agg
+}
+
+// PrivateKey converts a KeyScalar to a PS private key with one slot.
+func (s *Scheme) PrivateKey(private kyber.Scalar) (*ps.PrivateKey, error) {
+	k, ok := private.(*KeyScalar)
+	if !ok || k == nil || k.X == nil || k.Y == nil {
+		return nil, ErrKeyType
+	}
+	return ps.NewPrivateKey(s.suite, []kyber.Scalar{k.X, k.Y})
+}
+
+// PublicKey converts a KeyPoint to a PS public key with one slot.
+func (s *Scheme) PublicKey(public kyber.Point) (*ps.PublicKey, error) {
+	k, ok := public.(*KeyPoint)
+	if !ok || k == nil || k.X == nil || k.Y == nil || k.g.String() != s.suite.G2().String() {
+		return nil, ErrKeyType
+	}
+	return ps.NewPublicKey(s.suite, []kyber.Point{k.X, k.Y})
+}
+
+// FromPrivateKey converts a PS private key with one slot to a KeyScalar.
+func FromPrivateKey(priv *ps.PrivateKey) (kyber.Scalar, error) {
+	scalars := priv.Scalars()
+	if len(scalars) < 2 {
+		return nil, ps.ErrNilKey
+	}
+	if len(scalars) > 2 {
+		return nil, fmt.Errorf("%w: %d slots", ErrKeySlots, len(scalars)-1)
+	}
+	return &KeyScalar{g: priv.Suite().G1(), X: scalars[0].Clone(), Y: scalars[1].Clone()}, nil
+}
+
+// FromPublicKey converts a PS public key with one slot to a KeyPoint.
+func FromPublicKey(pub *ps.PublicKey) (kyber.Point, error) {
+	points := pub.Points()
+	if len(points) < 2 {
+		return nil, ps.ErrNilKey
+	}
+	if len(points) > 2 {
+		return nil, fmt.Errorf("%w: %d slots", ErrKeySlots, len(points)-1)
+	}
+	return &KeyPoint{g: pub.Suite().G2(), X: points[0].Clone(), Y: points[1].Clone()}, nil
+}
+
+// encodeSignature returns sigma_1 || sigma_2.
+func encodeSignature(sig *ps.Signature) ([]byte, error) {
+	S, err := sig.Components()
+	if err != nil {
+		return nil, err
+	}
+	return append(S[0], S[1]...), nil
+}
+
+// decodeSignature parses sigma_1 || sigma_2.
+func (s *Scheme) decodeSignature(sig []byte) (*ps.Signature, error) {
+	size := s.suite.G1().PointLen()
+	if len(sig) != 2*size {
+		return nil, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidSignatureEncoding, len(sig), 2*size)
+	}
+	dec, err := ps.NewSignature(s.suite, [][]byte{sig[:size], sig[size:]})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	return dec, nil
+}
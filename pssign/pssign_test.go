@@ -0,0 +1,161 @@
+package pssign
+
+import (
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func forEachScheme(t *testing.T, f func(t *testing.T, s *Scheme)) {
+	for _, suite := range []pairing.Suite{pairing.NewSuiteBn256(), bls12381.NewSuite()} {
+		s, err := NewScheme(suite)
+		require.Nil(t, err)
+		t.Run(suite.G1().String(), func(t *testing.T) { f(t, s) })
+	}
+}
+
+// signScheme and aggregatableScheme are the Scheme and AggregatableScheme
+// interfaces of kyber's sign package, on the v3 types.
+type signScheme interface {
+	NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point)
+	Sign(private kyber.Scalar, msg []byte) ([]byte, error)
+	Verify(public kyber.Point, msg, sig []byte) error
+}
+
+type aggregatableScheme interface {
+	signScheme
+	AggregateSignatures(sigs ...[]byte) ([]byte, error)
+	AggregatePublicKeys(Xs ...kyber.Point) kyber.Point
+}
+
+// schemeTesting runs the generic checks kyber applies to its signature
+// schemes, knowing only the interface.
+func schemeTesting(t *testing.T, s signScheme) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	private, public := s.NewKeyPair(random.New())
+	sig, err := s.Sign(private, msg)
+	require.Nil(t, err)
+	require.Nil(t, s.Verify(public, msg, sig))
+
+	require.NotNil(t, s.Verify(public, []byte("other message"), sig))
+	_, other := s.NewKeyPair(random.New())
+	require.NotNil(t, s.Verify(other, msg, sig))
+	sig[len(sig)-1] ^= 1
+	require.NotNil(t, s.Verify(public, msg, sig))
+	require.NotNil(t, s.Verify(public, msg, sig[:len(sig)-1]))
+}
+
+// aggregateTesting runs the aggregation checks kyber applies to BLS.
+func aggregateTesting(t *testing.T, s aggregatableScheme) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	private1, public1 := s.NewKeyPair(random.New())
+	private2, public2 := s.NewKeyPair(random.New())
+	sig1, err := s.Sign(private1, msg)
+	require.Nil(t, err)
+	sig2, err := s.Sign(private2, msg)
+	require.Nil(t, err)
+	aggSig, err := s.AggregateSignatures(sig1, sig2)
+	require.Nil(t, err)
+	aggKey := s.AggregatePublicKeys(public1, public2)
+	require.Nil(t, s.Verify(aggKey, msg, aggSig))
+
+	require.NotNil(t, s.Verify(public1, msg, aggSig))
+	require.NotNil(t, s.Verify(aggKey, []byte("other message"), aggSig))
+	sig3, err := s.Sign(private1, msg)
+	require.Nil(t, err)
+	aggSig, err = s.AggregateSignatures(sig1, sig3)
+	require.Nil(t, err)
+	require.NotNil(t, s.Verify(aggKey, msg, aggSig))
+}
+
+func TestScheme(t *testing.T) {
+	forEachScheme(t, func(t *testing.T, s *Scheme) {
+		schemeTesting(t, s)
+		aggregateTesting(t, s)
+	})
+}
+
+func TestSchemeMismatches(t *testing.T) {
+	forEachScheme(t, func(t *testing.T, s *Scheme) {
+		private, public := s.NewKeyPair(random.New())
+		msg := []byte("message")
+
+		_, err := s.Sign(s.suite.G1().Scalar().Pick(random.New()), msg)
+		require.Equal(t, ErrKeyType, err)
+		sig, err := s.Sign(private, msg)
+		require.Nil(t, err)
+		require.Equal(t, ErrKeyType, s.Verify(s.suite.G2().Point().Base(), msg, sig))
+
+		// Signatures on different messages have different bases.
+		other, err := s.Sign(private, []byte("other message"))
+		require.Nil(t, err)
+		_, err = s.AggregateSignatures(sig, other)
+		require.True(t, errors.Is(err, ps.ErrInvalidSignature))
+
+		priv, err := s.PrivateKey(private)
+		require.Nil(t, err)
+		back, err := FromPrivateKey(priv)
+		require.Nil(t, err)
+		require.True(t, back.Equal(private))
+		pub, err := s.PublicKey(public)
+		require.Nil(t, err)
+		require.Nil(t, ps.VerifyMultiSig(s.suite, pub, msg, mustDecode(t, s, sig)))
+		p, err := FromPublicKey(pub)
+		require.Nil(t, err)
+		require.True(t, p.Equal(public))
+
+		// Keys with more slots do not map.
+		r := random.New()
+		scalars, points, err := ps.NewKeyPairPoints(s.suite, []cipher.Stream{r, r, r})
+		require.Nil(t, err)
+		wide, err := ps.NewPrivateKey(s.suite, scalars)
+		require.Nil(t, err)
+		_, err = FromPrivateKey(wide)
+		require.True(t, errors.Is(err, ErrKeySlots))
+		widePub, err := ps.NewPublicKey(s.suite, points)
+		require.Nil(t, err)
+		_, err = FromPublicKey(widePub)
+		require.True(t, errors.Is(err, ErrKeySlots))
+	})
+}
+
+func mustDecode(t *testing.T, s *Scheme, sig []byte) *ps.Signature {
+	dec, err := s.decodeSignature(sig)
+	require.Nil(t, err)
+	return dec
+}
+
+func TestKeyGroup(t *testing.T) {
+	forEachScheme(t, func(t *testing.T, s *Scheme) {
+		private, public := s.NewKeyPair(random.New())
+		require.True(t, s.Point().Mul(private, nil).Equal(public))
+
+		two := s.suite.G1().Scalar().SetInt64(2)
+		doubled := s.Scalar().Mul(private, two)
+		require.True(t, s.Point().Mul(doubled, nil).Equal(s.Point().Add(public, public)))
+		require.True(t, s.Scalar().Sub(doubled, private).Equal(private))
+		require.True(t, s.Point().Sub(public, public).Equal(s.Point()))
+
+		buf, err := private.MarshalBinary()
+		require.Nil(t, err)
+		require.Len(t, buf, private.MarshalSize())
+		dec := s.Scalar()
+		require.Nil(t, dec.UnmarshalBinary(buf))
+		require.True(t, dec.Equal(private))
+
+		buf, err = public.MarshalBinary()
+		require.Nil(t, err)
+		require.Len(t, buf, public.MarshalSize())
+		decPub := s.Point()
+		require.Nil(t, decPub.UnmarshalBinary(buf))
+		require.True(t, decPub.Equal(public))
+		require.NotNil(t, decPub.UnmarshalBinary(buf[1:]))
+	})
+}
@@ -0,0 +1,85 @@
+package pstest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// The fixtures below are stable across versions of this package: the same
+// seed gives the same key stream, and the same suite and attribute count
+// give the same keys, messages and signature. A change to either is a
+// breaking change, which the golden test of the package guards against.
+//
+// ConstantStream keys AES-256 in counter mode, with a zero IV, with
+// SHA-256(seed). The stream has no practical length limit, so one stream may
+// serve any number of Pick calls; each call consumes the stream in order,
+// which is what makes separate runs agree. Fixture draws x from
+// ConstantStream("pstest fixture key 0") and y_i from
+// ConstantStream("pstest fixture key i"), signs the messages
+// "pstest fixture message i", i = 1..attrs, in slots 1..attrs with
+// ps.DeterministicSign, and so does not depend on the suite's random stream.
+
+// ConstantStream returns a new deterministic cipher.Stream seeded from seed,
+// for reproducible keys and signatures in tests. It must never be used to
+// generate real keys.
+func ConstantStream(seed []byte) cipher.Stream {
+	key := sha256.Sum256(seed)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		// AES accepts every 32-byte key.
+		panic(err)
+	}
+	return cipher.NewCTR(block, make([]byte, aes.BlockSize))
+}
+
+// KeyFixture is a deterministic key pair with a signature on its messages.
+type KeyFixture struct {
+	Private   *ps.PrivateKey
+	Public    *ps.PublicKey
+	Messages  [][]byte
+	Signature *ps.Signature
+}
+
+// Fixture returns the fixture of suite with attrs attribute slots, all of
+// them signed, failing t on error.
+func Fixture(t testing.TB, suite pairing.Suite, attrs int) *KeyFixture {
+	t.Helper()
+	if attrs < 1 {
+		t.Fatalf("pstest: fixture with %d attributes", attrs)
+	}
+	streams := make([]cipher.Stream, attrs+1)
+	msgs := make([][]byte, attrs)
+	for i := range streams {
+		streams[i] = ConstantStream([]byte(fmt.Sprintf("pstest fixture key %d", i)))
+		if i > 0 {
+			msgs[i-1] = []byte(fmt.Sprintf("pstest fixture message %d", i))
+		}
+	}
+	scalars, points, err := ps.NewKeyPairPoints(suite, streams)
+	if err != nil {
+		t.Fatalf("pstest: fixture key pair: %v", err)
+	}
+	priv, err := ps.NewPrivateKey(suite, scalars)
+	if err != nil {
+		t.Fatalf("pstest: fixture private key: %v", err)
+	}
+	pub, err := ps.NewPublicKey(suite, points)
+	if err != nil {
+		t.Fatalf("pstest: fixture public key: %v", err)
+	}
+	S, err := priv.BatchSign(msgs, ps.DeterministicSign())
+	if err != nil {
+		t.Fatalf("pstest: fixture signature: %v", err)
+	}
+	sig, err := ps.NewSignature(suite, S)
+	if err != nil {
+		t.Fatalf("pstest: fixture signature: %v", err)
+	}
+	return &KeyFixture{Private: priv, Public: pub, Messages: msgs, Signature: sig}
+}
@@ -0,0 +1,68 @@
+package pstest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// fixtureDigest returns the hex SHA-256 of the fixture's public key and
+// signature encodings.
+func fixtureDigest(t *testing.T, f *KeyFixture) string {
+	t.Helper()
+	pub, err := f.Public.MarshalBinary()
+	noError(t, err, "marshal public key")
+	sig, err := f.Signature.MarshalBinary()
+	noError(t, err, "marshal signature")
+	h := sha256.Sum256(append(pub, sig...))
+	return hex.EncodeToString(h[:])
+}
+
+func TestConstantStreamGolden(t *testing.T) {
+	buf := make([]byte, 16)
+	ConstantStream([]byte("seed")).XORKeyStream(buf, buf)
+	if got := hex.EncodeToString(buf); got != "1024e03ef1672193f39622137b645616" {
+		t.Fatalf("ConstantStream changed: %s", got)
+	}
+
+	// Reading in pieces gives the same stream as reading at once.
+	s := ConstantStream([]byte("seed"))
+	pieces := make([]byte, 16)
+	s.XORKeyStream(pieces[:5], pieces[:5])
+	s.XORKeyStream(pieces[5:], pieces[5:])
+	if !bytes.Equal(pieces, buf) {
+		t.Fatal("ConstantStream depends on the read sizes")
+	}
+}
+
+// TestFixtureGolden pins the fixtures, so that changes to key generation,
+// hashing or deterministic signing do not go unnoticed.
+func TestFixtureGolden(t *testing.T) {
+	for _, tc := range []struct {
+		suite  pairing.Suite
+		digest string
+	}{
+		{pairing.NewSuiteBn256(), "f42a27ec67d50ace38a519fe83dbe0d467d0d05545019fa04b218f666573f93a"},
+		{bls12381.NewSuite(), "695e132285030ab31a03510a161874dccb4503680978768dfd1ff9a6d58fd62e"},
+	} {
+		suite := tc.suite
+		f := Fixture(t, suite, 2)
+		if got := fixtureDigest(t, f); got != tc.digest {
+			t.Fatalf("%s: fixture changed: %s", suite.G1(), got)
+		}
+		S, err := f.Signature.Components()
+		noError(t, err, "signature components")
+		noError(t, ps.PSBatchVerify(suite, f.Public.Points(), f.Messages, S), "verify fixture")
+
+		// Wider fixtures extend narrower ones.
+		wide := Fixture(t, suite, 3)
+		if !wide.Public.X.Equal(f.Public.X) || !wide.Public.Y[1].Equal(f.Public.Y[1]) {
+			t.Fatalf("%s: fixture keys depend on the attribute count", suite.G1())
+		}
+	}
+}
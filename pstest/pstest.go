@@ -0,0 +1,298 @@
+// Package pstest is a conformance suite for PS signing backends: pairing
+// suites added to the registry and Signer implementations that keep the key
+// elsewhere, such as a remote service or a hardware module.
+//
+// RunSchemeTests checks round trips of signing and verification, rejection
+// of tampered signatures, messages and keys, batch signing and verification,
+// sequential aggregation across signers, serialization round trips and the
+// handling of nil and empty arguments. It reports through the testing
+// package alone, so that downstreams may use it whatever their test stack.
+package pstest
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"fmt"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// Signer is the interface of the backends under test.
+type Signer = ps.Signer
+
+// SignerFactory returns a new Signer of suite whose key has slots attribute
+// slots. Every call must return a signer with a fresh key.
+type SignerFactory func(suite pairing.Suite, slots int) (Signer, error)
+
+// localSigner is a Signer holding its key in memory.
+type localSigner struct {
+	priv *ps.PrivateKey
+}
+
+// LocalSigner is the SignerFactory of keys held in memory, drawn from the
+// suite's random stream.
+func LocalSigner(suite pairing.Suite, slots int) (Signer, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	streams := make([]cipher.Stream, slots+1)
+	for i := range streams {
+		streams[i] = suite.RandomStream()
+	}
+	scalars, _, err := ps.NewKeyPairPoints(suite, streams)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := ps.NewPrivateKey(suite, scalars)
+	if err != nil {
+		return nil, err
+	}
+	return &localSigner{priv: priv}, nil
+}
+
+func (s *localSigner) PublicKey() *ps.PublicKey { return s.priv.Public() }
+
+func (s *localSigner) Sign(msgs [][]byte) ([][]byte, error) { return s.priv.BatchSign(msgs) }
+
+func (s *localSigner) SignAcross(S *ps.Signature, msg []byte) (*ps.Signature, error) {
+	return ps.AggregateSignAcross(s.priv.Suite(), s.priv, S, msg)
+}
+
+// RunSchemeTests runs the conformance suite for suite, with signers made by
+// newSigner.
+func RunSchemeTests(t *testing.T, suite pairing.Suite, newSigner SignerFactory) {
+	c := &conformance{suite: suite, newSigner: newSigner}
+	t.Run("RoundTrip", c.testRoundTrip)
+	t.Run("Tamper", c.testTamper)
+	t.Run("Batch", c.testBatch)
+	t.Run("Aggregation", c.testAggregation)
+	t.Run("Serialization", c.testSerialization)
+	t.Run("NilArguments", c.testNilArguments)
+}
+
+type conformance struct {
+	suite     pairing.Suite
+	newSigner SignerFactory
+}
+
+var testMessages = [][]byte{
+	[]byte("Hello PS Signature"),
+	[]byte("attribute 2"),
+	[]byte("attribute 3"),
+}
+
+// signer returns a new signer with slots slots, failing t on error.
+func (c *conformance) signer(t *testing.T, slots int) Signer {
+	t.Helper()
+	s, err := c.newSigner(c.suite, slots)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	if pub := s.PublicKey(); pub == nil || len(pub.Y) != slots {
+		t.Fatalf("new signer: public key does not have %d slots", slots)
+	}
+	return s
+}
+
+// sign signs msgs with s, failing t on error.
+func sign(t *testing.T, s Signer, msgs [][]byte) [][]byte {
+	t.Helper()
+	S, err := s.Sign(msgs)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return S
+}
+
+func noError(t *testing.T, err error, what string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+}
+
+func isError(t *testing.T, err error, what string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("%s: succeeded unexpectedly", what)
+	}
+}
+
+// clone returns a deep copy of S, for tampering.
+func clone(S [][]byte) [][]byte {
+	out := make([][]byte, len(S))
+	for i, c := range S {
+		out[i] = append([]byte{}, c...)
+	}
+	return out
+}
+
+func (c *conformance) testRoundTrip(t *testing.T) {
+	s := c.signer(t, 1)
+	pub := s.PublicKey()
+	msg := testMessages[0]
+	S := sign(t, s, [][]byte{msg})
+	noError(t, ps.Verify(c.suite, pub.Points(), msg, S), "verify")
+	noError(t, ps.PSBatchVerify(c.suite, pub.Points(), [][]byte{msg}, S), "batch verify one message")
+
+	// Signatures are randomized, and each verifies.
+	again := sign(t, s, [][]byte{msg})
+	if bytes.Equal(S[0], again[0]) {
+		t.Fatal("sign: two signatures share sigma_1")
+	}
+	noError(t, ps.Verify(c.suite, pub.Points(), msg, again), "verify second signature")
+
+	v, err := ps.NewVerifier(c.suite, pub)
+	noError(t, err, "new verifier")
+	noError(t, v.Verify(msg, S), "verifier")
+}
+
+func (c *conformance) testTamper(t *testing.T) {
+	s := c.signer(t, 1)
+	pub := s.PublicKey()
+	msg := testMessages[0]
+	S := sign(t, s, [][]byte{msg})
+
+	for i := range S {
+		for _, pos := range []int{0, len(S[i]) / 2, len(S[i]) - 1} {
+			bad := clone(S)
+			bad[i][pos] ^= 0x01
+			isError(t, ps.Verify(c.suite, pub.Points(), msg, bad), fmt.Sprintf("verify with byte %d of component %d flipped", pos, i))
+		}
+	}
+	isError(t, ps.Verify(c.suite, pub.Points(), msg, [][]byte{S[1], S[0]}), "verify with swapped components")
+	isError(t, ps.Verify(c.suite, pub.Points(), []byte("Hello PS Signaturf"), S), "verify another message")
+
+	other := c.signer(t, 1)
+	isError(t, ps.Verify(c.suite, other.PublicKey().Points(), msg, S), "verify under another key")
+
+	null, err := c.suite.G1().Point().Null().MarshalBinary()
+	noError(t, err, "marshal identity")
+	isError(t, ps.Verify(c.suite, pub.Points(), msg, [][]byte{null, null}), "verify identity signature")
+}
+
+func (c *conformance) testBatch(t *testing.T) {
+	s := c.signer(t, len(testMessages)+1)
+	pub := s.PublicKey()
+	S := sign(t, s, testMessages)
+	noError(t, ps.PSBatchVerify(c.suite, pub.Points(), testMessages, S), "batch verify")
+
+	swapped := [][]byte{testMessages[1], testMessages[0], testMessages[2]}
+	isError(t, ps.PSBatchVerify(c.suite, pub.Points(), swapped, S), "batch verify reordered messages")
+	isError(t, ps.PSBatchVerify(c.suite, pub.Points(), testMessages[:2], S), "batch verify fewer messages")
+	bad := clone(S)
+	bad[1][0] ^= 0x01
+	isError(t, ps.PSBatchVerify(c.suite, pub.Points(), testMessages, bad), "batch verify tampered signature")
+
+	// Many single-message signatures under one key, checked at once.
+	one := c.signer(t, 1)
+	sigs := make([]*ps.Signature, len(testMessages))
+	for i, msg := range testMessages {
+		var err error
+		sigs[i], err = ps.NewSignature(c.suite, sign(t, one, [][]byte{msg}))
+		noError(t, err, "new signature")
+	}
+	noError(t, ps.VerifyBatch(c.suite, one.PublicKey().Points(), testMessages, sigs, random.New()), "verify batch")
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	isError(t, ps.VerifyBatch(c.suite, one.PublicKey().Points(), testMessages, sigs, random.New()), "verify batch with swapped signatures")
+}
+
+func (c *conformance) testAggregation(t *testing.T) {
+	signers := make([]Signer, len(testMessages))
+	pubs := make([]*ps.PublicKey, len(signers))
+	var S *ps.Signature
+	for i := range signers {
+		signers[i] = c.signer(t, 1)
+		pubs[i] = signers[i].PublicKey()
+		var err error
+		S, err = signers[i].SignAcross(S, testMessages[i])
+		noError(t, err, fmt.Sprintf("sign across, signer %d", i))
+	}
+	noError(t, ps.AggregateVerify(c.suite, pubs, testMessages, S), "aggregate verify")
+
+	isError(t, ps.AggregateVerify(c.suite, []*ps.PublicKey{pubs[1], pubs[0], pubs[2]}, testMessages, S), "aggregate verify with keys swapped")
+	isError(t, ps.AggregateVerify(c.suite, pubs[:2], testMessages[:2], S), "aggregate verify without the last signer")
+	msgs := [][]byte{testMessages[0], testMessages[1], []byte("attribute 4")}
+	isError(t, ps.AggregateVerify(c.suite, pubs, msgs, S), "aggregate verify another message")
+}
+
+func (c *conformance) testSerialization(t *testing.T) {
+	s := c.signer(t, 2)
+	pub := s.PublicKey()
+	S := sign(t, s, testMessages[:2])
+	sig, err := ps.NewSignature(c.suite, S)
+	noError(t, err, "new signature")
+
+	buf, err := sig.MarshalBinary()
+	noError(t, err, "marshal signature")
+	dec, err := ps.UnmarshalSignature(c.suite, buf)
+	noError(t, err, "unmarshal signature")
+	decS, err := dec.Components()
+	noError(t, err, "signature components")
+	for i := range S {
+		if !bytes.Equal(S[i], decS[i]) {
+			t.Fatalf("signature round trip changed component %d", i)
+		}
+	}
+	_, err = ps.UnmarshalSignature(c.suite, buf[:len(buf)-1])
+	isError(t, err, "unmarshal truncated signature")
+
+	text, err := sig.MarshalText()
+	noError(t, err, "marshal signature text")
+	var fromText ps.Signature
+	noError(t, fromText.UnmarshalText(text), "unmarshal signature text")
+	textS, err := fromText.Components()
+	noError(t, err, "signature components")
+	noError(t, ps.PSBatchVerify(c.suite, pub.Points(), testMessages[:2], textS), "verify signature from text")
+
+	buf, err = pub.MarshalBinary()
+	noError(t, err, "marshal public key")
+	decPub, err := ps.UnmarshalPublicKey(c.suite, buf)
+	noError(t, err, "unmarshal public key")
+	if !decPub.Equal(pub) {
+		t.Fatal("public key round trip changed the key")
+	}
+	noError(t, ps.PSBatchVerify(c.suite, decPub.Points(), testMessages[:2], decS), "verify with decoded key")
+	_, err = ps.UnmarshalPublicKey(c.suite, buf[:len(buf)-1])
+	isError(t, err, "unmarshal truncated public key")
+}
+
+func (c *conformance) testNilArguments(t *testing.T) {
+	s := c.signer(t, 1)
+	pub := s.PublicKey()
+	msg := testMessages[0]
+	S := sign(t, s, [][]byte{msg})
+
+	calls := map[string]func() error{
+		"Sign nil messages":      func() error { _, err := s.Sign(nil); return err },
+		"Sign nil message":       func() error { _, err := s.Sign([][]byte{nil}); return err },
+		"Sign empty message":     func() error { _, err := s.Sign([][]byte{{}}); return err },
+		"Sign too many messages": func() error { _, err := s.Sign(testMessages); return err },
+		"SignAcross nil message": func() error { _, err := s.SignAcross(nil, nil); return err },
+		"Verify suite":           func() error { return ps.Verify(nil, pub.Points(), msg, S) },
+		"Verify key":             func() error { return ps.Verify(c.suite, nil, msg, S) },
+		"Verify key point":       func() error { return ps.Verify(c.suite, append(pub.Points()[:1], nil), msg, S) },
+		"Verify message":         func() error { return ps.Verify(c.suite, pub.Points(), nil, S) },
+		"Verify signature":       func() error { return ps.Verify(c.suite, pub.Points(), msg, nil) },
+		"Verify component":       func() error { return ps.Verify(c.suite, pub.Points(), msg, [][]byte{S[0], nil}) },
+		"PSBatchVerify messages": func() error { return ps.PSBatchVerify(c.suite, pub.Points(), nil, S) },
+		"AggregateVerify keys":   func() error { return ps.AggregateVerify(c.suite, nil, [][]byte{msg}, nil) },
+		"NewVerifier key":        func() error { _, err := ps.NewVerifier(c.suite, nil); return err },
+		"NewSignature nil":       func() error { _, err := ps.NewSignature(c.suite, nil); return err },
+		"UnmarshalSignature nil": func() error { _, err := ps.UnmarshalSignature(c.suite, nil); return err },
+		"UnmarshalPublicKey nil": func() error { _, err := ps.UnmarshalPublicKey(c.suite, nil); return err },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("panic: %v", r)
+				}
+			}()
+			isError(t, call(), name)
+		})
+	}
+}
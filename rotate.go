@@ -0,0 +1,124 @@
+package ps
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// When an issuer rotates its key, the old key vouches for the new one with a
+// TransitionRecord: a single-message signature by the old key on
+//
+//	fingerprint || validFrom
+//
+// where fingerprint is the Fingerprint of the new key and validFrom the time
+// the new key takes over, in Unix seconds as a big-endian int64. It is
+// signed under the tag protocolDST(suite, "ROTATE"), so that it cannot pass
+// for a credential signature on the same bytes, and verifies with the first
+// Y component of the old key. Records chain: old -> mid -> new is vouched
+// for by a record of old on mid and one of mid on new, with increasing
+// validity times.
+
+// ErrInvalidTransition is returned for transition records that do not name
+// the presented new key, or chains of them that do not link up.
+var ErrInvalidTransition = errors.New("ps: invalid key transition")
+
+// TransitionRecord states that the key with fingerprint Fingerprint succeeds
+// the key that made Signature from ValidFrom on.
+type TransitionRecord struct {
+	Fingerprint []byte
+	ValidFrom   time.Time
+	Signature   *Signature
+}
+
+// transitionMessage returns the message signed by a transition record.
+func transitionMessage(fingerprint []byte, validFrom time.Time) []byte {
+	msg := make([]byte, len(fingerprint)+8)
+	copy(msg, fingerprint)
+	binary.BigEndian.PutUint64(msg[len(fingerprint):], uint64(validFrom.Unix()))
+	return msg
+}
+
+// RotateKey returns the record by which oldPriv hands over to newPub from
+// validFrom on, to the second. The policy of oldPriv must allow OpSign; its
+// reserved slots do not apply, the record being signed under its own tag.
+func RotateKey(oldPriv *PrivateKey, newPub *PublicKey, validFrom time.Time) (*TransitionRecord, error) {
+	if err := oldPriv.check(); err != nil {
+		return nil, err
+	}
+	if err := oldPriv.policy.allowCount(OpSign, 1); err != nil {
+		return nil, err
+	}
+	if validFrom.IsZero() {
+		return nil, fmt.Errorf("%w: no validity time", ErrInvalidTransition)
+	}
+	fp, err := newPub.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	oldFP, err := oldPriv.Public().Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(fp, oldFP) {
+		return nil, fmt.Errorf("%w: key succeeds itself", ErrInvalidTransition)
+	}
+	validFrom = validFrom.UTC().Truncate(time.Second)
+	suite := oldPriv.suite
+	S, err := Sign(suite, oldPriv.Scalars(), transitionMessage(fp, validFrom), WithDST(protocolDST(suite, "ROTATE")))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := NewSignature(suite, S)
+	if err != nil {
+		return nil, err
+	}
+	return &TransitionRecord{Fingerprint: fp, ValidFrom: validFrom, Signature: sig}, nil
+}
+
+// VerifyTransition checks that record was made by oldPub and names newPub
+// as its successor. A record naming another key gives ErrInvalidTransition.
+func VerifyTransition(oldPub *PublicKey, record *TransitionRecord, newPub *PublicKey) error {
+	if err := oldPub.check(); err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrNilSignature
+	}
+	if err := record.Signature.check(); err != nil {
+		return err
+	}
+	fp, err := newPub.Fingerprint()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(fp, record.Fingerprint) {
+		return fmt.Errorf("%w: record names another key", ErrInvalidTransition)
+	}
+	S, err := record.Signature.Components()
+	if err != nil {
+		return err
+	}
+	suite := oldPub.suite
+	return Verify(suite, oldPub.Points(), transitionMessage(fp, record.ValidFrom), S, WithDST(protocolDST(suite, "ROTATE")))
+}
+
+// VerifyTransitionChain checks that records[i] hands keys[i] over to
+// keys[i+1] for every i, at strictly increasing validity times. The error of
+// a broken link is a *BatchError naming it.
+func VerifyTransitionChain(keys []*PublicKey, records []*TransitionRecord) error {
+	if len(keys) < 2 || len(records) != len(keys)-1 {
+		return fmt.Errorf("%w: %d records for %d keys", ErrInvalidTransition, len(records), len(keys))
+	}
+	for i, record := range records {
+		if err := VerifyTransition(keys[i], record, keys[i+1]); err != nil {
+			return itemError("verify transition", i, err)
+		}
+		if i > 0 && !record.ValidFrom.After(records[i-1].ValidFrom) {
+			return itemError("verify transition", i, fmt.Errorf("%w: valid from %v, before the previous transition", ErrInvalidTransition, record.ValidFrom))
+		}
+	}
+	return nil
+}
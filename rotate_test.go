@@ -0,0 +1,60 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestRotateKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		oldPriv, oldPub := newTestKeys(t, suite, 2)
+		midPriv, midPub := newTestKeys(t, suite, 3)
+		_, newPub := newTestKeys(t, suite, 3)
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		first, err := RotateKey(oldPriv, midPub, start)
+		require.Nil(t, err)
+		require.Nil(t, VerifyTransition(oldPub, first, midPub))
+		second, err := RotateKey(midPriv, newPub, start.AddDate(0, 6, 0))
+		require.Nil(t, err)
+		require.Nil(t, VerifyTransitionChain([]*PublicKey{oldPub, midPub, newPub}, []*TransitionRecord{first, second}))
+
+		// The record does not name the key presented as the successor.
+		err = VerifyTransition(oldPub, first, newPub)
+		require.True(t, errors.Is(err, ErrInvalidTransition))
+		require.Equal(t, ErrInvalidSignature, VerifyTransition(midPub, first, midPub))
+
+		// A record backdated, or signed by another key, breaks its link.
+		moved := *first
+		moved.ValidFrom = start.Add(-time.Hour)
+		require.Equal(t, ErrInvalidSignature, VerifyTransition(oldPub, &moved, midPub))
+		forged, err := RotateKey(oldPriv, newPub, start.AddDate(0, 6, 0))
+		require.Nil(t, err)
+		err = VerifyTransitionChain([]*PublicKey{oldPub, midPub, newPub}, []*TransitionRecord{first, forged})
+		var be *BatchError
+		require.True(t, errors.As(err, &be))
+		require.Equal(t, 1, be.Index)
+		require.Equal(t, ErrInvalidSignature, be.Err)
+
+		late, err := RotateKey(oldPriv, midPub, start.AddDate(1, 0, 0))
+		require.Nil(t, err)
+		err = VerifyTransitionChain([]*PublicKey{oldPub, midPub, newPub}, []*TransitionRecord{late, second})
+		require.True(t, errors.Is(err, ErrInvalidTransition))
+		err = VerifyTransitionChain([]*PublicKey{oldPub, newPub}, []*TransitionRecord{first, second})
+		require.True(t, errors.Is(err, ErrInvalidTransition))
+
+		// The record is no credential signature on the same bytes.
+		S, err := first.Signature.Components()
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, Verify(suite, oldPub.Points(), transitionMessage(first.Fingerprint, first.ValidFrom), S))
+
+		_, err = RotateKey(oldPriv, oldPub, start)
+		require.True(t, errors.Is(err, ErrInvalidTransition))
+		_, err = RotateKey(oldPriv, midPub, time.Time{})
+		require.True(t, errors.Is(err, ErrInvalidTransition))
+	})
+}
@@ -0,0 +1,178 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// An attribute with few possible values, such as yes/no or a small enum,
+// can be guessed from anything that depends on its message alone: a
+// verifier holding the hash of a hidden attribute, or a commitment to it,
+// tries every value. A salted attribute signs the message
+//
+//	"PS-SALT" || uint8(len(salt)) || salt || value
+//
+// instead, with a random salt of at least MinSaltLen bytes kept by the
+// holder, so that guessing the value means guessing the salt too. The salt
+// is revealed along with the value on disclosure, and a value disclosed
+// with the wrong salt does not verify.
+//
+// Attributes of a schema are salted by setting AttributeDef.Salted. The
+// issuer draws their salts with SignSchemaSalted, and the holder stores them
+// with the credential, as Salts, to encode the attributes again on
+// disclosure.
+
+// MinSaltLen is the shortest salt accepted, and SaltLen the length of the
+// salts drawn by NewSalt.
+const (
+	MinSaltLen = 16
+	SaltLen    = 32
+)
+
+// saltTag prefixes salted attributes.
+const saltTag = "PS-SALT"
+
+// ErrInvalidSalt is returned for salts that are missing, too short or too
+// long, and for salts given to unsalted attributes.
+var ErrInvalidSalt = errors.New("ps: invalid attribute salt")
+
+// NewSalt returns SaltLen bytes read from r, or from crypto/rand if r is nil.
+func NewSalt(r io.Reader) ([]byte, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	salt := make([]byte, SaltLen)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEntropyFailure, err)
+	}
+	return salt, nil
+}
+
+// SaltedAttribute returns the message signing value with salt.
+func SaltedAttribute(value, salt []byte) ([]byte, error) {
+	if len(salt) < MinSaltLen || len(salt) > 0xff {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSalt, len(salt))
+	}
+	msg := make([]byte, 0, len(saltTag)+1+len(salt)+len(value))
+	msg = append(append(msg, saltTag...), byte(len(salt)))
+	return append(append(msg, salt...), value...), nil
+}
+
+// UnsaltAttribute splits a message made by SaltedAttribute into its value
+// and salt.
+func UnsaltAttribute(msg []byte) (value, salt []byte, err error) {
+	if !bytes.HasPrefix(msg, []byte(saltTag)) || len(msg) < len(saltTag)+1 {
+		return nil, nil, fmt.Errorf("%w: not a salted attribute", ErrAttributeType)
+	}
+	body := msg[len(saltTag):]
+	n := int(body[0])
+	if n < MinSaltLen || len(body) < 1+n {
+		return nil, nil, fmt.Errorf("%w: %d bytes", ErrInvalidSalt, n)
+	}
+	return append([]byte{}, body[1+n:]...), append([]byte{}, body[1:1+n]...), nil
+}
+
+// Salts are the salts of the salted attributes of a credential, by name.
+type Salts map[string][]byte
+
+// NewSalts draws a salt for every salted attribute of the schema with
+// NewSalt.
+func (s *Schema) NewSalts(r io.Reader) (Salts, error) {
+	salts := make(Salts)
+	for _, a := range s.attrs {
+		if !a.Salted {
+			continue
+		}
+		salt, err := NewSalt(r)
+		if err != nil {
+			return nil, err
+		}
+		salts[a.Name] = salt
+	}
+	return salts, nil
+}
+
+// EncodeSalted is Encode for schemas with salted attributes, salting each
+// with its salt in salts.
+func (s *Schema) EncodeSalted(values map[string]interface{}, salts Salts) ([][]byte, error) {
+	for name := range salts {
+		i, ok := s.index[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: salt for %q", ErrUnknownAttribute, name)
+		}
+		if !s.attrs[i].Salted {
+			return nil, fmt.Errorf("%w: %q is not salted", ErrInvalidSalt, name)
+		}
+	}
+	return s.encode(values, salts)
+}
+
+// EncodeAttribute returns the message of the named attribute with value v,
+// salted with salt if the attribute is salted, as a verifier needs it for a
+// disclosed attribute.
+func (s *Schema) EncodeAttribute(name string, v interface{}, salt []byte) ([]byte, error) {
+	i, ok := s.index[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAttribute, name)
+	}
+	a := s.attrs[i]
+	if !a.Salted && salt != nil {
+		return nil, fmt.Errorf("%w: %q is not salted", ErrInvalidSalt, name)
+	}
+	return encodeSalted(a, v, Salts{name: salt})
+}
+
+// encodeSalted encodes v as a value of a, salted with its salt in salts if
+// a is salted.
+func encodeSalted(a AttributeDef, v interface{}, salts Salts) ([]byte, error) {
+	msg, err := encodeAttribute(a, v)
+	if err != nil || !a.Salted {
+		return msg, err
+	}
+	salt, ok := salts[a.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q has no salt", ErrInvalidSalt, a.Name)
+	}
+	return SaltedAttribute(msg, salt)
+}
+
+// SignSchemaSalted is SignSchema drawing fresh salts for the salted
+// attributes of schema, which it returns for the holder to store with the
+// signature.
+func SignSchemaSalted(suite pairing.Suite, priKey []kyber.Scalar, schema *Schema, values map[string]interface{}, opts ...Option) ([][]byte, Salts, error) {
+	if schema == nil {
+		return nil, nil, ErrInvalidSchema
+	}
+	salts, err := schema.NewSalts(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	msgs, err := schema.EncodeSalted(values, salts)
+	if err != nil {
+		return nil, nil, err
+	}
+	S, err := BatchSign(suite, priKey, msgs, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return S, salts, nil
+}
+
+// VerifySchemaSalted checks a signature S made by SignSchemaSalted on values
+// with salts.
+func VerifySchemaSalted(suite pairing.Suite, pubKey []kyber.Point, schema *Schema, values map[string]interface{}, salts Salts, S [][]byte, opts ...Option) error {
+	if schema == nil {
+		return ErrInvalidSchema
+	}
+	msgs, err := schema.EncodeSalted(values, salts)
+	if err != nil {
+		return err
+	}
+	return PSBatchVerify(suite, pubKey, msgs, S, opts...)
+}
@@ -0,0 +1,112 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestSaltedAttribute(t *testing.T) {
+	salt1, err := NewSalt(nil)
+	require.Nil(t, err)
+	salt2, err := NewSalt(nil)
+	require.Nil(t, err)
+	require.NotEqual(t, salt1, salt2)
+
+	yes1, err := SaltedAttribute([]byte("yes"), salt1)
+	require.Nil(t, err)
+	yes2, err := SaltedAttribute([]byte("yes"), salt2)
+	require.Nil(t, err)
+	require.NotEqual(t, yes1, yes2)
+	value, salt, err := UnsaltAttribute(yes1)
+	require.Nil(t, err)
+	require.Equal(t, []byte("yes"), value)
+	require.Equal(t, salt1, salt)
+
+	// The salt length is part of the encoding, so salt and value do not
+	// trade bytes.
+	shifted, err := SaltedAttribute([]byte("es"), append(salt1, 'y'))
+	require.Nil(t, err)
+	require.NotEqual(t, yes1, shifted)
+
+	_, err = SaltedAttribute([]byte("yes"), salt1[:MinSaltLen-1])
+	require.True(t, errors.Is(err, ErrInvalidSalt))
+	_, err = SaltedAttribute([]byte("yes"), make([]byte, 256))
+	require.True(t, errors.Is(err, ErrInvalidSalt))
+	for _, bad := range [][]byte{nil, []byte("yes"), yes1[:len(saltTag)+MinSaltLen]} {
+		_, _, err = UnsaltAttribute(bad)
+		require.NotNil(t, err, "%x", bad)
+	}
+	_, err = NewSalt(bytes.NewReader(make([]byte, SaltLen-1)))
+	require.True(t, errors.Is(err, ErrEntropyFailure))
+}
+
+func TestSignSchemaSalted(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		schema, err := NewSchema(
+			AttributeDef{Name: "name", Kind: KindString},
+			AttributeDef{Name: "member", Kind: KindFlags, Salted: true},
+			AttributeDef{Name: "tier", Kind: KindString, Salted: true},
+		)
+		require.Nil(t, err)
+		unsalted, err := NewSchema(
+			AttributeDef{Name: "name", Kind: KindString},
+			AttributeDef{Name: "member", Kind: KindFlags},
+			AttributeDef{Name: "tier", Kind: KindString},
+		)
+		require.Nil(t, err)
+		require.NotEqual(t, unsalted.Hash(), schema.Hash())
+
+		priv, pub := newTestKeys(t, suite, schema.Len())
+		values := map[string]interface{}{"name": "alice", "member": []bool{true}, "tier": "gold"}
+		S, salts, err := SignSchemaSalted(suite, priv.Scalars(), schema, values)
+		require.Nil(t, err)
+		require.Equal(t, 2, len(salts))
+		require.Nil(t, VerifySchemaSalted(suite, pub.Points(), schema, values, salts, S))
+
+		// The same values get fresh salts, hence other attributes.
+		_, again, err := SignSchemaSalted(suite, priv.Scalars(), schema, values)
+		require.Nil(t, err)
+		m1, err := schema.EncodeAttribute("tier", "gold", salts["tier"])
+		require.Nil(t, err)
+		m2, err := schema.EncodeAttribute("tier", "gold", again["tier"])
+		require.Nil(t, err)
+		require.NotEqual(t, m1, m2)
+		require.Equal(t, ErrInvalidSignature, VerifySchemaSalted(suite, pub.Points(), schema, values, again, S))
+
+		// Disclose the tier with its salt, hiding the rest.
+		msgs, err := schema.EncodeSalted(values, salts)
+		require.Nil(t, err)
+		decoded, err := schema.Decode(msgs)
+		require.Nil(t, err)
+		require.Equal(t, values, decoded)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		i, _ := schema.Index("tier")
+		nonce := []byte("session 1")
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{i: true}, nonce)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartial(suite, pub, map[int][]byte{i: m1}, proof, nonce))
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, map[int][]byte{i: m2}, proof, nonce))
+		plain, err := unsalted.EncodeAttribute("tier", "gold", nil)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, map[int][]byte{i: plain}, proof, nonce))
+
+		// Salts are required for salted attributes and refused for others.
+		_, err = schema.Encode(values)
+		require.True(t, errors.Is(err, ErrInvalidSalt))
+		_, err = schema.EncodeAttribute("tier", "gold", nil)
+		require.True(t, errors.Is(err, ErrInvalidSalt))
+		_, err = schema.EncodeAttribute("name", "alice", salts["tier"])
+		require.True(t, errors.Is(err, ErrInvalidSalt))
+		_, err = schema.EncodeSalted(values, Salts{"name": salts["tier"], "member": salts["member"], "tier": salts["tier"]})
+		require.True(t, errors.Is(err, ErrInvalidSalt))
+		_, err = schema.EncodeSalted(values, Salts{"nickname": salts["tier"]})
+		require.True(t, errors.Is(err, ErrUnknownAttribute))
+		_, err = schema.DecodeAttribute("tier", plain)
+		require.True(t, errors.Is(err, ErrAttributeType))
+	})
+}
@@ -0,0 +1,46 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// PS multiplies the same scalars into points of G1 and G2: secret keys,
+// messages and blinding factors alike. This package draws all of them from
+// the scalar field of G1, which is only sound if G2 has the same order. The
+// pairing.Suite interface does not promise that, so suites are checked
+// before use.
+
+// ErrIncompatibleSuite is returned for suites whose groups G1 and G2 do not
+// share a scalar field.
+var ErrIncompatibleSuite = errors.New("ps: incompatible suite")
+
+// newScalar returns a scalar of the field shared by G1 and G2 of suite.
+func newScalar(suite pairing.Suite) kyber.Scalar {
+	return suite.G1().Scalar()
+}
+
+// checkScalarField ensures that G1 and G2 of suite have the same order. The
+// order n is compared through the encoding of n-1, the largest scalar.
+func checkScalarField(suite pairing.Suite) error {
+	g1, g2 := suite.G1(), suite.G2()
+	if g1.ScalarLen() != g2.ScalarLen() {
+		return fmt.Errorf("%w: %s and %s scalars are %d and %d bytes", ErrIncompatibleSuite, g1, g2, g1.ScalarLen(), g2.ScalarLen())
+	}
+	max1, err := g1.Scalar().SetInt64(-1).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	max2, err := g2.Scalar().SetInt64(-1).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(max1, max2) {
+		return fmt.Errorf("%w: %s and %s have different orders", ErrIncompatibleSuite, g1, g2)
+	}
+	return nil
+}
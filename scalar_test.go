@@ -0,0 +1,59 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// mixedSuite pairs G1 of one suite with G2 of another.
+type mixedSuite struct {
+	pairing.Suite
+	g2 kyber.Group
+}
+
+func (s mixedSuite) G2() kyber.Group {
+	return s.g2
+}
+
+func TestScalarField(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		require.Nil(t, checkScalarField(suite))
+
+		// Scalars of the shared field act alike on both groups.
+		a := newScalar(suite).Pick(random.New())
+		b := newScalar(suite).Pick(random.New())
+		ab := newScalar(suite).Mul(a, b)
+		g2 := suite.G2().Point().Mul(ab, nil)
+		require.True(t, g2.Equal(suite.G2().Point().Mul(a, suite.G2().Point().Mul(b, nil))))
+		left := suite.Pair(suite.G1().Point().Mul(a, nil), suite.G2().Point().Mul(b, nil))
+		right := suite.Pair(suite.G1().Point().Base(), g2)
+		require.True(t, left.Equal(right))
+	})
+}
+
+func TestIncompatibleSuite(t *testing.T) {
+	bn := pairing.NewSuiteBn256()
+	suite := mixedSuite{Suite: bn, g2: bls12381.NewSuite().G2()}
+	msg := []byte("Hello PS Signature")
+	priv, pub := newTestKeys(t, bn, 1)
+	S, err := Sign(bn, priv.Scalars(), msg)
+	require.Nil(t, err)
+
+	_, _, err = NewKeyPair(suite, []cipher.Stream{random.New(), random.New()})
+	require.True(t, errors.Is(err, ErrIncompatibleSuite))
+	_, err = Sign(suite, priv.Scalars(), msg)
+	require.True(t, errors.Is(err, ErrIncompatibleSuite))
+	_, err = SignScalar(suite, priv.Scalars(), newScalar(bn).One())
+	require.True(t, errors.Is(err, ErrIncompatibleSuite))
+	require.True(t, errors.Is(Verify(suite, pub.Points(), msg, S), ErrIncompatibleSuite))
+	require.True(t, errors.Is(RegisterSuite(0x7e, "mixed", suite), ErrIncompatibleSuite))
+	_, err = NewKeyGenerator(suite)
+	require.True(t, errors.Is(err, ErrIncompatibleSuite))
+}
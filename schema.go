@@ -0,0 +1,398 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A Schema names the attributes of a credential and gives each a kind, so
+// that services agree on what every slot holds instead of on bare positions.
+// Schema.Encode turns a map from names to Go values into the attribute
+// vector, in schema order, and Schema.Decode reverses it on disclosed
+// messages. Every attribute is encoded as the message
+//
+//	kind || value
+//
+// with kind a single byte, so that values of different kinds never encode
+// alike, and value
+//
+//	string   the UTF-8 bytes
+//	int64    8 bytes, big-endian, offset by 2^63, see AttributeFromInt64
+//	uint64   8 bytes, big-endian
+//	bytes    the bytes themselves
+//	date     the day since 1970-01-01, see AttributeFromTime
+//	flags    the number of flags and their bits, see PackFlags
+//
+// A schema is identified by the SHA-256 hash of its definition,
+//
+//	"PS-SCHEMA-V1" || uint32(n) || (uint32(len(name_i)) || name_i || kind_i)...
+//
+// with the high bit of kind_i set for salted attributes.
+//
+// Schemas are bound: they reserve attribute 0 for SchemaAttribute, the
+// message "PS-SCHEMA" || hash, so that a credential of one schema does not
+// verify as a credential of another with the same values. Renaming,
+// reordering or retyping any attribute changes the hash and invalidates
+// the signatures made under the old definition. Unbind drops the reserved
+// attribute, for credentials whose slots must hold the values alone, such
+// as ones issued by raw BatchSign; their meaning then rests on the
+// verifier knowing the schema out of band.
+
+var (
+	// ErrUnknownAttribute is returned for values named by no attribute of
+	// the schema.
+	ErrUnknownAttribute = errors.New("ps: unknown attribute")
+
+	// ErrAttributeType is returned for values that do not match the kind of
+	// their attribute, or encodings that do not decode as it.
+	ErrAttributeType = errors.New("ps: attribute type mismatch")
+
+	// ErrInvalidSchema is returned for schemas without attributes or with
+	// empty or repeated names.
+	ErrInvalidSchema = errors.New("ps: invalid schema")
+
+	// ErrSchemaMismatch is returned when decoding the attributes of another
+	// schema.
+	ErrSchemaMismatch = errors.New("ps: schema mismatch")
+)
+
+// AttributeKind is the type of the values of an attribute.
+type AttributeKind byte
+
+// The kinds of attribute values, and the Go types they take: string, int64,
+// []byte, time.Time, uint64 and []bool.
+const (
+	KindString AttributeKind = iota + 1
+	KindInt64
+	KindBytes
+	KindDate
+	KindUint64
+	KindFlags
+)
+
+func (k AttributeKind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt64:
+		return "int64"
+	case KindBytes:
+		return "bytes"
+	case KindDate:
+		return "date"
+	case KindUint64:
+		return "uint64"
+	case KindFlags:
+		return "flags"
+	}
+	return fmt.Sprintf("AttributeKind(%d)", byte(k))
+}
+
+// AttributeDef is a named attribute of a schema. A Salted attribute is
+// signed with a salt, see SaltedAttribute.
+type AttributeDef struct {
+	Name   string
+	Kind   AttributeKind
+	Salted bool
+}
+
+// schemaTag prefixes the schema hash in its attribute encoding.
+const schemaTag = "PS-SCHEMA"
+
+// Schema is an ordered list of named, typed attributes. It is immutable and
+// safe for concurrent use.
+type Schema struct {
+	attrs []AttributeDef
+	index map[string]int
+	hash  [sha256.Size]byte
+	bound bool
+}
+
+// NewSchema returns the bound schema of attrs, in order.
+func NewSchema(attrs ...AttributeDef) (*Schema, error) {
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("%w: no attributes", ErrInvalidSchema)
+	}
+	s := &Schema{attrs: append([]AttributeDef{}, attrs...), index: make(map[string]int, len(attrs)), bound: true}
+	h := sha256.New()
+	h.Write([]byte(schemaTag + "-V1"))
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(attrs)))
+	h.Write(n[:])
+	for i, a := range attrs {
+		if a.Name == "" || !utf8.ValidString(a.Name) {
+			return nil, fmt.Errorf("%w: attribute %d has an invalid name", ErrInvalidSchema, i)
+		}
+		if a.Kind < KindString || a.Kind > KindFlags {
+			return nil, fmt.Errorf("%w: attribute %q has unknown kind %v", ErrInvalidSchema, a.Name, a.Kind)
+		}
+		if _, ok := s.index[a.Name]; ok {
+			return nil, fmt.Errorf("%w: attribute %q given twice", ErrInvalidSchema, a.Name)
+		}
+		s.index[a.Name] = i
+		binary.BigEndian.PutUint32(n[:], uint32(len(a.Name)))
+		h.Write(n[:])
+		h.Write([]byte(a.Name))
+		kind := byte(a.Kind)
+		if a.Salted {
+			kind |= 0x80
+		}
+		h.Write([]byte{kind})
+	}
+	copy(s.hash[:], h.Sum(nil))
+	return s, nil
+}
+
+// Bind returns the schema with attribute 0 reserved for SchemaAttribute,
+// ahead of the named attributes, as NewSchema returns it.
+func (s *Schema) Bind() *Schema {
+	bound := *s
+	bound.bound = true
+	return &bound
+}
+
+// Unbind returns the schema without the reserved attribute, encoding the
+// named attributes alone from attribute 0. Its signatures do not commit to
+// the schema.
+func (s *Schema) Unbind() *Schema {
+	unbound := *s
+	unbound.bound = false
+	return &unbound
+}
+
+// Bound reports whether attribute 0 is reserved for SchemaAttribute.
+func (s *Schema) Bound() bool {
+	return s.bound
+}
+
+// Attributes returns the attributes of the schema, in order.
+func (s *Schema) Attributes() []AttributeDef {
+	return append([]AttributeDef{}, s.attrs...)
+}
+
+// Len returns the number of messages the schema encodes to, including the
+// schema attribute of a bound schema.
+func (s *Schema) Len() int {
+	if s.bound {
+		return len(s.attrs) + 1
+	}
+	return len(s.attrs)
+}
+
+// Index returns the position of the named attribute in the encoded vector.
+func (s *Schema) Index(name string) (int, bool) {
+	i, ok := s.index[name]
+	if ok && s.bound {
+		i++
+	}
+	return i, ok
+}
+
+// Hash returns the hash identifying the schema definition.
+func (s *Schema) Hash() []byte {
+	return append([]byte{}, s.hash[:]...)
+}
+
+// SchemaAttribute returns the message a bound schema reserves attribute 0
+// for.
+func (s *Schema) SchemaAttribute() []byte {
+	return append([]byte(schemaTag), s.hash[:]...)
+}
+
+// Encode returns the attribute vector of values, which must name every
+// attribute of the schema and nothing else. Schemas with salted attributes
+// encode with EncodeSalted.
+func (s *Schema) Encode(values map[string]interface{}) ([][]byte, error) {
+	return s.encode(values, nil)
+}
+
+// encode is Encode, salting the salted attributes with salts.
+func (s *Schema) encode(values map[string]interface{}, salts Salts) ([][]byte, error) {
+	for _, name := range sortedNames(values) {
+		if _, ok := s.index[name]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownAttribute, name)
+		}
+	}
+	var msgs [][]byte
+	if s.bound {
+		msgs = append(msgs, s.SchemaAttribute())
+	}
+	for _, a := range s.attrs {
+		v, ok := values[a.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingAttribute, a.Name)
+		}
+		msg, err := encodeSalted(a, v, salts)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// Decode returns the values encoded in msgs by Encode.
+func (s *Schema) Decode(msgs [][]byte) (map[string]interface{}, error) {
+	if len(msgs) != s.Len() {
+		return nil, fmt.Errorf("%w: %d attributes, schema has %d", ErrSchemaMismatch, len(msgs), s.Len())
+	}
+	if s.bound {
+		if !bytes.Equal(msgs[0], s.SchemaAttribute()) {
+			return nil, fmt.Errorf("%w: attribute 0", ErrSchemaMismatch)
+		}
+		msgs = msgs[1:]
+	}
+	values := make(map[string]interface{}, len(s.attrs))
+	for i, a := range s.attrs {
+		v, err := decodeAttribute(a, msgs[i])
+		if err != nil {
+			return nil, err
+		}
+		values[a.Name] = v
+	}
+	return values, nil
+}
+
+// DecodeAttribute returns the value of the named attribute encoded in msg,
+// such as a message disclosed in a signature proof.
+func (s *Schema) DecodeAttribute(name string, msg []byte) (interface{}, error) {
+	i, ok := s.index[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAttribute, name)
+	}
+	return decodeAttribute(s.attrs[i], msg)
+}
+
+// SignSchema signs the attribute vector schema encodes values to, as
+// BatchSign does.
+func SignSchema(suite pairing.Suite, priKey []kyber.Scalar, schema *Schema, values map[string]interface{}, opts ...Option) ([][]byte, error) {
+	if schema == nil {
+		return nil, ErrInvalidSchema
+	}
+	msgs, err := schema.Encode(values)
+	if err != nil {
+		return nil, err
+	}
+	return BatchSign(suite, priKey, msgs, opts...)
+}
+
+// VerifySchema checks a signature S made by SignSchema on values.
+func VerifySchema(suite pairing.Suite, pubKey []kyber.Point, schema *Schema, values map[string]interface{}, S [][]byte, opts ...Option) error {
+	if schema == nil {
+		return ErrInvalidSchema
+	}
+	msgs, err := schema.Encode(values)
+	if err != nil {
+		return err
+	}
+	return PSBatchVerify(suite, pubKey, msgs, S, opts...)
+}
+
+// encodeAttribute encodes v as a value of a.
+func encodeAttribute(a AttributeDef, v interface{}) ([]byte, error) {
+	msg := []byte{byte(a.Kind)}
+	switch a.Kind {
+	case KindString:
+		if s, ok := v.(string); ok && utf8.ValidString(s) {
+			return append(msg, s...), nil
+		}
+	case KindInt64:
+		var n int64
+		switch x := v.(type) {
+		case int64:
+			n = x
+		case int:
+			n = int64(x)
+		default:
+			return nil, typeError(a, v)
+		}
+		return AttributeFromInt64(n), nil
+	case KindUint64:
+		if n, ok := v.(uint64); ok {
+			return AttributeFromUint64(n), nil
+		}
+	case KindBytes:
+		if b, ok := v.([]byte); ok {
+			return append(msg, b...), nil
+		}
+	case KindDate:
+		if t, ok := v.(time.Time); ok {
+			return AttributeFromTime(t, GranularityDay)
+		}
+	case KindFlags:
+		if flags, ok := v.([]bool); ok {
+			return PackFlags(flags)
+		}
+	}
+	return nil, typeError(a, v)
+}
+
+// decodeAttribute decodes msg as a value of a, dropping its salt if a is
+// salted.
+func decodeAttribute(a AttributeDef, msg []byte) (interface{}, error) {
+	if a.Salted {
+		value, _, err := UnsaltAttribute(msg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, a.Name)
+		}
+		msg = value
+	}
+	if len(msg) == 0 || msg[0] != byte(a.Kind) {
+		return nil, fmt.Errorf("%w: %q is not encoded as %v", ErrAttributeType, a.Name, a.Kind)
+	}
+	body := msg[1:]
+	switch a.Kind {
+	case KindString:
+		if utf8.Valid(body) {
+			return string(body), nil
+		}
+	case KindInt64:
+		if len(body) == 8 {
+			return Int64FromAttribute(msg)
+		}
+	case KindUint64:
+		if len(body) == 8 {
+			return Uint64FromAttribute(msg)
+		}
+	case KindBytes:
+		return append([]byte{}, body...), nil
+	case KindDate:
+		if t, g, err := TimeFromAttribute(msg); err == nil && g == GranularityDay {
+			return t, nil
+		}
+	case KindFlags:
+		if flags, err := UnpackFlags(msg); err == nil {
+			return flags, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q is not a valid %v", ErrAttributeType, a.Name, a.Kind)
+}
+
+func typeError(a AttributeDef, v interface{}) error {
+	return fmt.Errorf("%w: %q is %v, got %T", ErrAttributeType, a.Name, a.Kind, v)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+// sortedNames returns the keys of values in increasing order.
+func sortedNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,201 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// testSchema is a credential schema with an attribute of every kind.
+func testSchema(t testing.TB) *Schema {
+	s, err := NewSchema(
+		AttributeDef{Name: "name", Kind: KindString},
+		AttributeDef{Name: "birthdate", Kind: KindDate},
+		AttributeDef{Name: "balance", Kind: KindInt64},
+		AttributeDef{Name: "photo", Kind: KindBytes},
+	)
+	require.Nil(t, err)
+	return s
+}
+
+func TestSchemaRoundTrip(t *testing.T) {
+	s := testSchema(t)
+	for _, values := range []map[string]interface{}{
+		{
+			"name":      "Zoë Ñúñez 🦀",
+			"birthdate": time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC),
+			"balance":   int64(-42),
+			"photo":     []byte{0x00, 0xff, 0x10},
+		},
+		{
+			"name":      "",
+			"birthdate": time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+			"balance":   int64(-1 << 63),
+			"photo":     []byte{},
+		},
+		{
+			"name":      "x",
+			"birthdate": time.Date(2262, 4, 11, 0, 0, 0, 0, time.UTC),
+			"balance":   int64(1<<63 - 1),
+			"photo":     make([]byte, 1000),
+		},
+	} {
+		msgs, err := s.Encode(values)
+		require.Nil(t, err)
+		require.Equal(t, 5, len(msgs))
+		require.Equal(t, s.SchemaAttribute(), msgs[0])
+		decoded, err := s.Decode(msgs)
+		require.Nil(t, err)
+		require.Equal(t, values, decoded)
+
+		v, err := s.DecodeAttribute("balance", msgs[3])
+		require.Nil(t, err)
+		require.Equal(t, values["balance"], v)
+	}
+
+	// Dates keep the day only, must be in UTC, and ints are accepted as
+	// int64.
+	values := map[string]interface{}{
+		"name":      "Alice",
+		"birthdate": time.Date(1990, 4, 1, 23, 30, 0, 0, time.FixedZone("CET", 3600)),
+		"balance":   7,
+		"photo":     []byte("jpeg"),
+	}
+	_, err := s.Encode(values)
+	require.True(t, errors.Is(err, ErrNotUTC))
+	values["birthdate"] = time.Date(1990, 4, 1, 23, 30, 0, 0, time.UTC)
+	msgs, err := s.Encode(values)
+	require.Nil(t, err)
+	decoded, err := s.Decode(msgs)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC), decoded["birthdate"])
+	require.Equal(t, int64(7), decoded["balance"])
+
+	// Kinds do not decode as one another.
+	_, err = s.DecodeAttribute("name", msgs[3])
+	require.True(t, errors.Is(err, ErrAttributeType))
+	_, err = s.Decode(msgs[:3])
+	require.True(t, errors.Is(err, ErrSchemaMismatch))
+}
+
+func TestSchemaErrors(t *testing.T) {
+	s := testSchema(t)
+	values := map[string]interface{}{
+		"name":      "Alice",
+		"birthdate": time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC),
+		"balance":   int64(100),
+		"photo":     []byte("jpeg"),
+	}
+	with := func(name string, v interface{}) map[string]interface{} {
+		m := make(map[string]interface{})
+		for k, x := range values {
+			m[k] = x
+		}
+		if v == nil {
+			delete(m, name)
+		} else {
+			m[name] = v
+		}
+		return m
+	}
+	_, err := s.Encode(with("country", "NL"))
+	require.True(t, errors.Is(err, ErrUnknownAttribute))
+	require.Contains(t, err.Error(), `"country"`)
+	_, err = s.Encode(with("photo", nil))
+	require.True(t, errors.Is(err, ErrMissingAttribute))
+	_, err = s.Encode(with("balance", "100"))
+	require.True(t, errors.Is(err, ErrAttributeType))
+	_, err = s.Encode(with("name", string([]byte{0xff})))
+	require.True(t, errors.Is(err, ErrAttributeType))
+
+	for _, attrs := range [][]AttributeDef{
+		nil,
+		{{Name: "", Kind: KindString}},
+		{{Name: "a", Kind: KindString}, {Name: "a", Kind: KindBytes}},
+		{{Name: "a", Kind: 0}},
+	} {
+		_, err := NewSchema(attrs...)
+		require.True(t, errors.Is(err, ErrInvalidSchema), "%v", attrs)
+	}
+}
+
+func TestSignSchema(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		s := testSchema(t)
+		require.True(t, s.Bound())
+		priv, pub := newTestKeys(t, suite, s.Len())
+		values := map[string]interface{}{
+			"name":      "Alice",
+			"birthdate": time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC),
+			"balance":   int64(100),
+			"photo":     []byte("jpeg"),
+		}
+		S, err := SignSchema(suite, priv.Scalars(), s, values)
+		require.Nil(t, err)
+		require.Nil(t, VerifySchema(suite, pub.Points(), s, values, S))
+
+		msgs, err := s.Encode(values)
+		require.Nil(t, err)
+		require.Equal(t, s.SchemaAttribute(), msgs[0])
+		i, ok := s.Index("birthdate")
+		require.True(t, ok)
+		require.Equal(t, 2, i)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+		values["balance"] = int64(101)
+		require.Equal(t, ErrInvalidSignature, VerifySchema(suite, pub.Points(), s, values, S))
+		values["balance"] = int64(100)
+
+		// Renaming, reordering or retyping an attribute changes the schema
+		// and invalidates the signature on the same values.
+		renamed, err := NewSchema(
+			AttributeDef{Name: "name", Kind: KindString},
+			AttributeDef{Name: "birthdate", Kind: KindDate},
+			AttributeDef{Name: "balance", Kind: KindInt64},
+			AttributeDef{Name: "avatar", Kind: KindBytes},
+		)
+		require.Nil(t, err)
+		require.NotEqual(t, s.Hash(), renamed.Hash())
+		renamedValues := map[string]interface{}{"avatar": values["photo"]}
+		for _, name := range []string{"name", "birthdate", "balance"} {
+			renamedValues[name] = values[name]
+		}
+		require.Equal(t, ErrInvalidSignature, VerifySchema(suite, pub.Points(), renamed, renamedValues, S))
+		reordered, err := NewSchema(
+			AttributeDef{Name: "name", Kind: KindString},
+			AttributeDef{Name: "birthdate", Kind: KindDate},
+			AttributeDef{Name: "photo", Kind: KindBytes},
+			AttributeDef{Name: "balance", Kind: KindInt64},
+		)
+		require.Nil(t, err)
+		require.NotEqual(t, s.Hash(), reordered.Hash())
+		require.Equal(t, ErrInvalidSignature, VerifySchema(suite, pub.Points(), reordered, values, S))
+		retyped, err := NewSchema(
+			AttributeDef{Name: "name", Kind: KindBytes},
+			AttributeDef{Name: "birthdate", Kind: KindDate},
+			AttributeDef{Name: "balance", Kind: KindInt64},
+			AttributeDef{Name: "photo", Kind: KindBytes},
+		)
+		require.Nil(t, err)
+		require.NotEqual(t, s.Hash(), retyped.Hash())
+		_, err = SignSchema(suite, priv.Scalars(), s, renamedValues)
+		require.True(t, errors.Is(err, ErrUnknownAttribute))
+
+		// Unbound schemas sign the values alone, interchangeably with raw
+		// signing and with any schema of the same kinds.
+		unbound := s.Unbind()
+		require.False(t, unbound.Bound())
+		require.Equal(t, 4, unbound.Len())
+		raw, err := SignSchema(suite, priv.Scalars(), unbound, values)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifySchema(suite, pub.Points(), s, values, raw))
+		require.Nil(t, VerifySchema(suite, pub.Points(), renamed.Unbind(), renamedValues, raw))
+		plain, err := unbound.Encode(values)
+		require.Nil(t, err)
+		require.Equal(t, msgs[1:], plain)
+		require.True(t, unbound.Bind().Bound())
+	})
+}
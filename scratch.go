@@ -0,0 +1,123 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// VerifyScratch holds the temporaries of a single-message verification: the
+// message scalar, the points the signature is decoded into and those the
+// verification equation is computed in. Reusing one across calls to
+// VerifyInto saves allocating them on every call. A VerifyScratch must not be
+// used by several goroutines at once; give each its own.
+type VerifyScratch struct {
+	suite          pairing.Suite
+	m, minusOne    kyber.Scalar
+	x, g           kyber.Point
+	sigma1, sigma2 kyber.Point
+	mul, neg, null kyber.Point
+	p1, p2         []kyber.Point
+}
+
+// NewVerifyScratch returns the temporaries of VerifyInto for suite.
+func NewVerifyScratch(suite pairing.Suite) (*VerifyScratch, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	s := &VerifyScratch{
+		suite:    suite,
+		m:        newScalar(suite),
+		minusOne: suite.G1().Scalar().One(),
+		x:        suite.G2().Point(),
+		g:        suite.G2().Point().Base(),
+		sigma1:   suite.G1().Point(),
+		sigma2:   suite.G1().Point(),
+		mul:      suite.G1().Point(),
+		neg:      suite.G1().Point(),
+		null:     suite.G1().Point().Null(),
+	}
+	s.minusOne.Neg(s.minusOne)
+	s.p1 = []kyber.Point{s.sigma1, s.neg}
+	s.p2 = []kyber.Point{s.x, s.g}
+	return s, nil
+}
+
+// VerifyInto is Verify computing in the temporaries of scratch, which must
+// belong to suite. A nil scratch is allocated for the call. The result is
+// the same as that of Verify.
+func VerifyInto(suite pairing.Suite, pubKey []kyber.Point, msg []byte, S [][]byte, scratch *VerifyScratch, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkMessages(msg); err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	if S == nil {
+		return ErrNilSignature
+	}
+	if scratch == nil {
+		if scratch, err = NewVerifyScratch(suite); err != nil {
+			return err
+		}
+	} else if scratch.suite != suite {
+		return fmt.Errorf("%w: scratch of another suite", ErrSuiteMismatch)
+	}
+	return scratch.verify(pubKey, msg, S, o)
+}
+
+// verify checks e(sigma_1, X * Y^m) = e(sigma_2, g) as verifyScalars does.
+func (s *VerifyScratch) verify(pubKey []kyber.Point, msg []byte, S [][]byte, o *options) error {
+	if err := s.decode(S, o.validatePoints); err != nil {
+		return err
+	}
+	if s.sigma1.Equal(s.null) || s.sigma2.Equal(s.null) {
+		return ErrInvalidSignature
+	}
+	hashToScalarInto(s.m, o.dst, msg)
+	s.x.Mul(s.m, pubKey[1])
+	s.x.Add(s.x, pubKey[0])
+
+	if pc, ok := s.suite.(PairingChecker); ok {
+		// e(sigma_1, X) * e(-sigma_2, g) = 1
+		s.neg.Neg(s.sigma2)
+		if !pc.PairingCheck(s.p1, s.p2) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	if !s.suite.Pair(s.sigma1, s.x).Equal(s.suite.Pair(s.sigma2, s.g)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// decode parses S into sigma_1 and sigma_2 as parseSignature does.
+func (s *VerifyScratch) decode(S [][]byte, validate bool) error {
+	if len(S) != 2 {
+		return fmt.Errorf("%w: %d components, expected 2", ErrMalformedSignature, len(S))
+	}
+	size := s.suite.G1().PointLen()
+	for i, p := range [2]kyber.Point{s.sigma1, s.sigma2} {
+		if len(S[i]) != size {
+			return fmt.Errorf("%w: component %d is %d bytes, expected %d", ErrMalformedSignature, i, len(S[i]), size)
+		}
+		if err := p.UnmarshalBinary(S[i]); err != nil {
+			return fmt.Errorf("%w: component %d: %v", ErrMalformedSignature, i, err)
+		}
+		if validate {
+			if err := checkSubgroupWith(s.minusOne, p, s.mul, s.neg); err != nil {
+				return fmt.Errorf("%w: component %d", err, i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestVerifyInto(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		_, otherPub := newTestKeys(t, suite, 1)
+		S, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		scratch, err := NewVerifyScratch(suite)
+		require.Nil(t, err)
+
+		// One scratch serves any number of calls, with the same results as
+		// the scalar path.
+		cases := []struct {
+			pubKey *PublicKey
+			msg    []byte
+			S      [][]byte
+		}{
+			{pub, msg, S},
+			{otherPub, msg, S},
+			{pub, []byte("Hello PS"), S},
+			{pub, msg, [][]byte{S[1], S[0]}},
+			{pub, msg, [][]byte{S[0]}},
+			{pub, msg, [][]byte{S[0], S[1][1:]}},
+			{pub, msg, S},
+		}
+		for i, c := range cases {
+			want := VerifyScalar(suite, c.pubKey.Points(), hashToScalar(suite, DefaultDST(suite), c.msg), c.S)
+			require.Equal(t, want, VerifyInto(suite, c.pubKey.Points(), c.msg, c.S, scratch), "case %d", i)
+			require.Equal(t, want, Verify(suite, c.pubKey.Points(), c.msg, c.S), "case %d", i)
+		}
+		require.Nil(t, VerifyInto(suite, pub.Points(), msg, S, scratch, ValidatePoints(false)))
+
+		for _, ts := range testSuites {
+			if _, err := SuiteIDOf(ts.suite); err == nil && ts.suite.G1().String() != suite.G1().String() {
+				other, err := NewVerifyScratch(ts.suite)
+				require.Nil(t, err)
+				err = VerifyInto(suite, pub.Points(), msg, S, other)
+				require.True(t, errors.Is(err, ErrSuiteMismatch))
+			}
+		}
+	})
+}
+
+func TestVerifyIntoAllocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("counts allocations over repeated verifications")
+	}
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(t, suite, 1)
+		S, err := Sign(suite, priv.Scalars(), msg)
+		require.Nil(t, err)
+		scratch, err := NewVerifyScratch(suite)
+		require.Nil(t, err)
+
+		withScratch := testing.AllocsPerRun(10, func() {
+			if err := VerifyInto(suite, pub.Points(), msg, S, scratch); err != nil {
+				t.Fatal(err)
+			}
+		})
+		direct := testing.AllocsPerRun(10, func() {
+			m := hashToScalar(suite, DefaultDST(suite), msg)
+			if err := VerifyScalar(suite, pub.Points(), m, S); err != nil {
+				t.Fatal(err)
+			}
+		})
+		require.True(t, withScratch <= direct, "%v allocations with scratch, %v without", withScratch, direct)
+		// The field arithmetic of bn256 allocates big integers on every
+		// operation, which no scratch avoids; the budget only holds for
+		// suites with fixed-size arithmetic.
+		if suite.G1().String() == "bn256.G1" {
+			return
+		}
+		require.True(t, withScratch <= 40, "%v allocations with scratch", withScratch)
+	})
+}
+
+func BenchmarkVerifyInto(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msg := []byte("Hello PS Signature")
+		priv, pub := newTestKeys(b, suite, 1)
+		S, _ := Sign(suite, priv.Scalars(), msg)
+		scratch, _ := NewVerifyScratch(suite)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			VerifyInto(suite, pub.Points(), msg, S, scratch)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// BatchSign and PSBatchVerify take the messages as a slice, which a caller
+// decoding hundreds of megabytes of attributes from a stream has to build in
+// full first. BatchSignSeq and BatchVerifySeq instead pull the messages one
+// at a time from a MessageSeq, hash each as it arrives and keep only the
+// running sum, so the messages never need to be in memory together. They
+// accept the same inputs and give the same results as the slice functions.
+//
+// MessageSeq has the shape of iter.Seq2[[]byte, error]. The iter package
+// needs Go 1.23, above the module's minimum, so the module does not use it,
+// but on Go 1.23 and later an iterator converts directly:
+//
+//	S, err := ps.BatchSignSeq(suite, key, ps.MessageSeq(decoder.All()))
+//
+// The sequence is consumed once. A non-nil error yielded by the sequence,
+// such as a decoding failure, stops the consumption and is returned as a
+// *BatchError holding the index of the message that could not be read.
+
+// MessageSeq yields messages, or an error if the next message cannot be
+// produced, until yield returns false.
+type MessageSeq func(yield func(msg []byte, err error) bool)
+
+// BatchSignSeq is BatchSign on the messages of msgs, in order.
+func BatchSignSeq(suite pairing.Suite, priKey []kyber.Scalar, msgs MessageSeq, opts ...Option) ([][]byte, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(priKey, 2); err != nil {
+		return nil, err
+	}
+	sum := newScalar(suite)
+	// Deterministic signing derives h from every scalar, which are kept.
+	var m []kyber.Scalar
+	_, err = readMessages(suite, msgs, len(priKey)-1, o, func(i int, mi kyber.Scalar) {
+		if o.deterministic {
+			m = append(m, mi.Clone())
+		}
+		sum.Add(sum, mi.Mul(priKey[i+1], mi))
+	})
+	if err != nil {
+		return nil, err
+	}
+	rand, err := o.signingStream(suite, priKey, m)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signSum(suite, priKey[0], sum, rand)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Components()
+}
+
+// BatchVerifySeq is PSBatchVerify on the messages of msgs, in order. The
+// signature is parsed before the first message is read.
+func BatchVerifySeq(suite pairing.Suite, pubKey []kyber.Point, msgs MessageSeq, S [][]byte, opts ...Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkPublicKey(pubKey, 2); err != nil {
+		return err
+	}
+	sig, i, err := parseComponents(suite, S, o.validatePoints)
+	if i >= 0 {
+		return itemError("unmarshal component", i, err)
+	}
+	if err != nil {
+		return err
+	}
+	g2 := suite.G2()
+	X := pubKey[0].Clone()
+	n, err := readMessages(suite, msgs, len(pubKey)-1, o, func(i int, mi kyber.Scalar) {
+		X.Add(X, g2.Point().Mul(mi, pubKey[i+1]))
+	})
+	if err != nil {
+		return err
+	}
+	if o.checkIndices {
+		if err := checkIndices(o.indices, n); err != nil {
+			return err
+		}
+	}
+	return verifySignature(suite, X, g2.Point().Base(), sig)
+}
+
+// readMessages passes the scalar of every message of seq to add, with its
+// index, and returns the number of messages. It stops at the first message
+// that cannot be read, is empty, or exceeds the slots of the key or the
+// MaxAttributes limit.
+func readMessages(suite pairing.Suite, seq MessageSeq, slots int, o *options, add func(i int, m kyber.Scalar)) (int, error) {
+	if seq == nil {
+		return 0, ErrNilMessage
+	}
+	n := 0
+	var err error
+	seq(func(msg []byte, readErr error) bool {
+		if err != nil {
+			// The sequence went on after being told to stop.
+			return false
+		}
+		switch {
+		case readErr != nil:
+			err = itemError("read message", n, readErr)
+		case n >= slots:
+			err = fmt.Errorf("%w: message %d needs %d key components, got %d", ErrKeyLengthMismatch, n, n+2, slots+1)
+		case len(msg) == 0:
+			err = itemError("hash message", n, ErrEmptyMessage)
+		default:
+			err = o.checkAttributes(n + 1)
+		}
+		if err != nil {
+			return false
+		}
+		add(n, hashToScalar(suite, o.dst, msg))
+		n++
+		return true
+	})
+	if err != nil {
+		return n, err
+	}
+	if n == 0 {
+		return 0, ErrNoMessages
+	}
+	return n, nil
+}
@@ -0,0 +1,105 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// sliceSeq yields msgs in order, then fail if it is set, and counts the
+// messages read.
+func sliceSeq(msgs [][]byte, fail error, read *int) MessageSeq {
+	return func(yield func([]byte, error) bool) {
+		for _, msg := range msgs {
+			*read++
+			if !yield(msg, nil) {
+				return
+			}
+		}
+		if fail != nil {
+			yield(nil, fail)
+		}
+	}
+}
+
+func TestBatchSignSeq(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 6)
+		msgs := [][]byte{[]byte("Alice"), []byte("1990-04-01"), []byte("Paris"), []byte("NL")}
+		var read int
+
+		S, err := BatchSign(suite, priv.Scalars(), msgs, DeterministicSign())
+		require.Nil(t, err)
+		T, err := BatchSignSeq(suite, priv.Scalars(), sliceSeq(msgs, nil, &read), DeterministicSign())
+		require.Nil(t, err)
+		require.Equal(t, S, T)
+		require.Equal(t, len(msgs), read)
+
+		T, err = BatchSignSeq(suite, priv.Scalars(), sliceSeq(msgs, nil, &read))
+		require.Nil(t, err)
+		require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, T))
+		require.Nil(t, BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs, nil, &read), S))
+		require.Equal(t, ErrInvalidSignature, BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs[:3], nil, &read), S))
+		require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs[:3], S))
+
+		// Aggregates verify with their header as with PSBatchVerify.
+		agg, err := NewAggregate(suite, priv, msgs[:2])
+		require.Nil(t, err)
+		require.Nil(t, BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs[:2], nil, &read), agg.S, WithIndices(agg.Indices)))
+		err = BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs[:3], nil, &read), agg.S, WithIndices(agg.Indices))
+		require.True(t, errors.Is(err, ErrAggregationOrder))
+
+		_, err = BatchSignSeq(suite, priv.Scalars(), sliceSeq(nil, nil, &read))
+		require.Equal(t, ErrNoMessages, err)
+		_, err = BatchSignSeq(suite, priv.Scalars(), nil)
+		require.Equal(t, ErrNilMessage, err)
+	})
+}
+
+func TestMessageSeqAbort(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 8)
+		msgs := make([][]byte, 12)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("attribute %d", i))
+		}
+		S, err := BatchSign(suite, priv.Scalars(), msgs[:8])
+		require.Nil(t, err)
+
+		// A decoding error halfway through.
+		decode := errors.New("unexpected EOF")
+		read := 0
+		_, err = BatchSignSeq(suite, priv.Scalars(), sliceSeq(msgs[:4], decode, &read))
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 4, batchErr.Index)
+		require.True(t, errors.Is(err, decode))
+		err = BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs[:4], decode, &read), S)
+		require.True(t, errors.Is(err, decode))
+
+		// The key runs out at the ninth message, and reading stops there.
+		read = 0
+		_, err = BatchSignSeq(suite, priv.Scalars(), sliceSeq(msgs, nil, &read))
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Contains(t, err.Error(), "message 8")
+		require.Equal(t, 9, read)
+		read = 0
+		err = BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs, nil, &read), S)
+		require.True(t, errors.Is(err, ErrKeyLengthMismatch))
+		require.Equal(t, 9, read)
+
+		read = 0
+		err = BatchVerifySeq(suite, pub.Points(), sliceSeq(msgs[:8], nil, &read), S, WithConfig(Config{MaxAttributes: 5}))
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Equal(t, 6, read)
+
+		withEmpty := [][]byte{msgs[0], msgs[1], nil}
+		_, err = BatchSignSeq(suite, priv.Scalars(), sliceSeq(withEmpty, nil, &read))
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 2, batchErr.Index)
+		require.True(t, errors.Is(err, ErrEmptyMessage))
+	})
+}
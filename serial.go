@@ -0,0 +1,199 @@
+package ps
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Serial numbers detect credentials shown twice where single use is
+// required. The serial of a secret message m in a context is
+//
+//	serial = H(context)^m
+//
+// in G1, where H hashes the context to a point of unknown discrete
+// logarithm. It is deterministic, so two shows in one context yield the same
+// serial, while serials in different contexts are unlinkable under the DDH
+// assumption in G1. ProveSerial extends a signature proof with a proof that
+// the serial is computed on a hidden message of the signature: the serial
+// statement shares the nonce and the response of that message,
+//
+//	R = H(context)^(a_j),  z_j = a_j + c*m_j,
+//
+// and R enters the challenge, so that the verifier checks H(context)^(z_j) =
+// R * serial^c alongside the signature proof.
+
+// serialStatement is the statement serial = base^(m_index) proven about the
+// hidden message index of a signature proof.
+type serialStatement struct {
+	index  int
+	base   kyber.Point
+	serial kyber.Point
+}
+
+// transcript encodes the statement and the commitment R for the challenge.
+func (st *serialStatement) transcript(suite pairing.Suite, R kyber.Point) ([]byte, error) {
+	buf, err := appendCount(nil, st.index)
+	if err != nil {
+		return nil, err
+	}
+	return appendPoints(buf, suite.G1(), false, st.base, st.serial, R)
+}
+
+// serialBase hashes context to the base of serials.
+func serialBase(suite pairing.Suite, context []byte) (kyber.Point, error) {
+	return hashToPoint(suite, protocolDST(suite, "SERIAL"), context)
+}
+
+// DeriveSerial returns the serial of the secret message secretAttr in
+// context, H(context)^secretAttr. The message is the scalar a signature is
+// computed on, e.g. from HashMessages.
+func DeriveSerial(suite pairing.Suite, secretAttr kyber.Scalar, context []byte) (kyber.Point, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return nil, err
+	}
+	if err := checkScalars([]kyber.Scalar{secretAttr}); err != nil {
+		return nil, err
+	}
+	base, err := serialBase(suite, context)
+	if err != nil {
+		return nil, err
+	}
+	return base.Mul(secretAttr, base), nil
+}
+
+// SerialProof is a signature proof extended with the serial of the hidden
+// message Index.
+type SerialProof struct {
+	suite  pairing.Suite
+	Index  int
+	Serial kyber.Point
+	Proof  *SignatureProof
+}
+
+// check reports whether the proof is complete.
+func (p *SerialProof) check() error {
+	if p == nil || p.Serial == nil {
+		return ErrNilSignature
+	}
+	if p.suite == nil {
+		return ErrNilSuite
+	}
+	return p.Proof.check()
+}
+
+// ProveSerial proves possession of sig, as ProveSignature does, together
+// with the serial of msgs[index] in context, which must not be disclosed.
+// Verifiers detect a credential shown twice in one context by comparing
+// serials.
+func ProveSerial(suite pairing.Suite, pubKey *PublicKey, sig *Signature, msgs [][]byte, index int, disclose map[int]bool, context, nonce []byte, opts ...Option) (*SerialProof, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(msgs) {
+		return nil, fmt.Errorf("%w: serial index %d outside 0..%d", ErrInvalidDisclosure, index, len(msgs)-1)
+	}
+	if err := checkMessages(msgs[index]); err != nil {
+		return nil, err
+	}
+	base, err := serialBase(suite, context)
+	if err != nil {
+		return nil, err
+	}
+	serial := suite.G1().Point().Mul(hashToScalar(suite, o.dst, msgs[index]), base)
+	st := &serialStatement{index: index, base: base, serial: serial}
+	proof, err := proveSignature(suite, pubKey, sig, msgs, disclose, nonce, st, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SerialProof{suite: suite, Index: index, Serial: serial, Proof: proof}, nil
+}
+
+// VerifySerial checks a proof made by ProveSerial under pubKey, context and
+// nonce, as VerifyPartial does with revealed. On success, proof.Serial is
+// the serial of the hidden message proof.Index in context.
+func VerifySerial(suite pairing.Suite, pubKey *PublicKey, proof *SerialProof, revealed map[int][]byte, context, nonce []byte, opts ...Option) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	if err := proof.check(); err != nil {
+		return err
+	}
+	if isIdentity(suite.G1(), proof.Serial) {
+		return ErrInvalidSignature
+	}
+	if err := checkSubgroup(suite.G1(), proof.Serial); err != nil {
+		return err
+	}
+	base, err := serialBase(suite, context)
+	if err != nil {
+		return err
+	}
+	st := &serialStatement{index: proof.Index, base: base, serial: proof.Serial}
+	return verifyPartial(suite, pubKey, revealed, proof.Proof, nonce, st, opts)
+}
+
+// MarshalBinary encodes the proof as header || index || serial || proof,
+// the last in the encoding of SignatureProof.
+func (p *SerialProof) MarshalBinary() ([]byte, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(p.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, p.Index); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, p.suite.G1(), false, p.Serial); err != nil {
+		return nil, err
+	}
+	proof, err := p.Proof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, proof...), nil
+}
+
+// UnmarshalBinary decodes a proof, see PrivateKey.UnmarshalBinary.
+func (p *SerialProof) UnmarshalBinary(data []byte) error {
+	if p == nil {
+		return ErrNilSignature
+	}
+	suite, body, _, err := readHeader(p.suite, data)
+	if err != nil {
+		return err
+	}
+	index, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 1, false)
+	if err != nil {
+		return err
+	}
+	proof, err := UnmarshalSignatureProof(suite, body)
+	if err != nil {
+		return err
+	}
+	*p = SerialProof{suite: suite, Index: index, Serial: points[0], Proof: proof}
+	return nil
+}
+
+// UnmarshalSerialProof decodes a proof produced under suite.
+func UnmarshalSerialProof(suite pairing.Suite, data []byte) (*SerialProof, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	p := &SerialProof{suite: suite}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
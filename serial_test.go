@@ -0,0 +1,87 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestSerial(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("type=ticket"), []byte("secret=6f1c2a"), []byte("seat=12B")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		disclose := map[int]bool{0: true}
+		revealed := map[int][]byte{0: msgs[0]}
+		show := func(context, nonce []byte) *SerialProof {
+			proof, err := ProveSerial(suite, pub, sig, msgs, 1, disclose, context, nonce)
+			require.Nil(t, err)
+			buf, err := proof.MarshalBinary()
+			require.Nil(t, err)
+			received, err := UnmarshalSerialProof(suite, buf)
+			require.Nil(t, err)
+			require.Nil(t, VerifySerial(suite, pub, received, revealed, context, nonce))
+			return received
+		}
+
+		// Two shows in one context carry the same serial.
+		first := show([]byte("concert 2026-10-16"), []byte("session 1"))
+		second := show([]byte("concert 2026-10-16"), []byte("session 2"))
+		require.True(t, first.Serial.Equal(second.Serial))
+		require.False(t, first.Proof.Sigma1.Equal(second.Proof.Sigma1))
+		m, err := HashMessages(suite, msgs[1:2])
+		require.Nil(t, err)
+		serial, err := DeriveSerial(suite, m[0], []byte("concert 2026-10-16"))
+		require.Nil(t, err)
+		require.True(t, serial.Equal(first.Serial))
+
+		// Shows in other contexts do not.
+		other := show([]byte("concert 2026-10-17"), []byte("session 3"))
+		require.False(t, first.Serial.Equal(other.Serial))
+	})
+}
+
+func TestSerialInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("type=ticket"), []byte("secret=6f1c2a")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		context, nonce := []byte("concert"), []byte("session 1")
+		proof, err := ProveSerial(suite, pub, sig, msgs, 1, nil, context, nonce)
+		require.Nil(t, err)
+
+		// The serial is bound to the context and to the hidden message.
+		require.Equal(t, ErrInvalidSignature, VerifySerial(suite, pub, proof, nil, []byte("other"), nonce))
+		forged := *proof
+		forged.Serial, err = DeriveSerial(suite, suite.G1().Scalar().One(), context)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifySerial(suite, pub, &forged, nil, context, nonce))
+		forged = *proof
+		forged.Index = 0
+		require.Equal(t, ErrInvalidSignature, VerifySerial(suite, pub, &forged, nil, context, nonce))
+
+		// A serial proof is not a plain signature proof, nor the reverse.
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, nil, proof.Proof, nonce))
+		plain, err := ProveSignature(suite, pub, sig, msgs, nil, nonce)
+		require.Nil(t, err)
+		forged = *proof
+		forged.Proof = plain
+		require.Equal(t, ErrInvalidSignature, VerifySerial(suite, pub, &forged, nil, context, nonce))
+
+		// The serial message must stay hidden.
+		_, err = ProveSerial(suite, pub, sig, msgs, 1, map[int]bool{1: true}, context, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		_, err = ProveSerial(suite, pub, sig, msgs, 2, nil, context, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		_, err = DeriveSerial(suite, suite.G1().Scalar().Zero(), context)
+		require.True(t, errors.Is(err, ErrZeroAttribute))
+	})
+}
@@ -0,0 +1,521 @@
+package ps
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// The show protocol follows section 6.2 of Pointcheval and Sanders, "Short
+// Randomizable Signatures". To prove possession of a signature (sigma_1,
+// sigma_2) on hidden messages m_1,...,m_k, the holder picks r and t and
+// reveals the randomized pair
+//
+//	(s_1, s_2) = (sigma_1^r, (sigma_2 * sigma_1^t)^r),
+//
+// which is unlinkable to the original signature and satisfies
+//
+//	e(s_2, g) / e(s_1, X) = e(s_1, g)^t * e(s_1, Y_1)^(m_1) * ... * e(s_1, Y_k)^(m_k).
+//
+// Messages may be disclosed selectively. Those in the disclosed set D are
+// moved to the left-hand side as part of the statement,
+//
+//	e(s_2, g) / e(s_1, X * prod_(i in D) Y_i^(m_i)) = e(s_1, g)^t * prod_(i not in D) e(s_1, Y_i)^(m_i),
+//
+// and a Schnorr proof of knowledge of t and the hidden messages for this
+// relation in GT completes the proof. Its challenge is derived by
+// Fiat-Shamir from the public key, the number of messages, the disclosed
+// indices and messages, (s_1, s_2), the commitment and a verifier-chosen
+// nonce, so that a proof does not verify under another key, disclosure or
+// nonce. Disclosing every message reduces the proof to one of knowledge of t.
+//
+// Indices count from 0: index i refers to msgs[i], signed with y_(i+1).
+
+// ErrEmptyNonce is returned when proving or verifying without a nonce.
+var ErrEmptyNonce = errors.New("ps: empty nonce")
+
+// ErrInvalidDisclosure is returned for disclosures naming messages that do
+// not exist.
+var ErrInvalidDisclosure = errors.New("ps: invalid disclosure")
+
+// SignatureProof is a zero-knowledge proof of possession of a signature on
+// Messages messages: the randomized signature (Sigma1, Sigma2) and the proof
+// (c, z_0, z_1,...), z_0 answering for t and the others for the hidden
+// messages, whose indices Hidden lists in increasing order. KeyID names the
+// issuer key, for verifiers to pick it with SelectKey; it is empty for
+// proofs encoded before version 1.3.
+type SignatureProof struct {
+	suite     pairing.Suite
+	KeyID     string
+	Messages  int
+	Hidden    []int
+	Sigma1    kyber.Point
+	Sigma2    kyber.Point
+	Challenge kyber.Scalar
+	Responses []kyber.Scalar
+}
+
+// disclosure is a disclosed message and its index.
+type disclosure struct {
+	index int
+	m     kyber.Scalar
+}
+
+// check reports whether the proof is complete.
+func (p *SignatureProof) check() error {
+	if p == nil || p.Sigma1 == nil || p.Sigma2 == nil || p.Challenge == nil {
+		return ErrNilSignature
+	}
+	if p.suite == nil {
+		return ErrNilSuite
+	}
+	if p.Messages < 1 || len(p.Responses) < 1 || len(p.Responses) > p.Messages+1 {
+		return fmt.Errorf("%w: proof has %d responses for %d messages", ErrInvalidSignature, len(p.Responses), p.Messages)
+	}
+	for i, z := range p.Responses {
+		if z == nil {
+			return fmt.Errorf("%w: response %d", ErrNilSignature, i)
+		}
+	}
+	if len(p.Hidden) != len(p.Responses)-1 {
+		return fmt.Errorf("%w: proof hides %d messages with %d responses", ErrInvalidSignature, len(p.Hidden), len(p.Responses))
+	}
+	for j, i := range p.Hidden {
+		if i < 0 || i >= p.Messages || (j > 0 && i <= p.Hidden[j-1]) {
+			return fmt.Errorf("%w: hidden indices are not increasing in 0..%d", ErrInvalidSignature, p.Messages-1)
+		}
+	}
+	return nil
+}
+
+// hiddenIndices lists the indices below k that are not disclosed.
+func hiddenIndices(k int, disclosed []disclosure) []int {
+	open := make(map[int]bool, len(disclosed))
+	for _, d := range disclosed {
+		open[d.index] = true
+	}
+	var hidden []int
+	for i := 0; i < k; i++ {
+		if !open[i] {
+			hidden = append(hidden, i)
+		}
+	}
+	return hidden
+}
+
+// hiddenPosition returns the position of index i among hidden, or -1.
+func hiddenPosition(hidden []int, i int) int {
+	for j, h := range hidden {
+		if h == i {
+			return j
+		}
+	}
+	return -1
+}
+
+// showBase returns g^(s_0) * prod_j Y_(hidden_j + 1)^(s_(j+1)) in G2.
+func showBase(suite pairing.Suite, pub *PublicKey, hidden []int, s []kyber.Scalar) kyber.Point {
+	b := suite.G2().Point().Mul(s[0], nil)
+	for j, i := range hidden {
+		b.Add(b, suite.G2().Point().Mul(s[j+1], pub.Y[i]))
+	}
+	return b
+}
+
+// showChallenge derives the Fiat-Shamir challenge of a signature proof on k
+// messages from the public key, the disclosed messages in increasing index
+// order, the randomized signature, the commitment T and the nonce. The
+// transcript of a serial statement proven alongside, if any, is hashed
+// under its own tag before the nonce.
+func showChallenge(suite pairing.Suite, pub *PublicKey, k int, disclosed []disclosure, s1, s2, T kyber.Point, serial, nonce []byte) (kyber.Scalar, error) {
+	buf, err := pub.canonical()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, k); err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(disclosed)); err != nil {
+		return nil, err
+	}
+	for _, d := range disclosed {
+		if buf, err = appendCount(buf, d.index); err != nil {
+			return nil, err
+		}
+		if buf, err = appendScalars(buf, suite.G1(), d.m); err != nil {
+			return nil, err
+		}
+	}
+	if buf, err = appendPoints(buf, suite.G1(), false, s1, s2); err != nil {
+		return nil, err
+	}
+	if buf, err = appendPoints(buf, suite.GT(), false, T); err != nil {
+		return nil, err
+	}
+	protocol := "SHOW"
+	if serial != nil {
+		protocol = "SHOW-SERIAL"
+		buf = append(buf, serial...)
+	}
+	return hashToScalar(suite, protocolDST(suite, protocol), append(buf, nonce...)), nil
+}
+
+// ProveSignature proves possession of sig, a valid signature on msgs under
+// pubKey, revealing only the messages whose index maps to true in disclose.
+// The other messages and the signature stay hidden. The nonce is chosen by
+// the verifier to prevent replay. Messages are hashed as by BatchSign, under
+// the options given.
+func ProveSignature(suite pairing.Suite, pubKey *PublicKey, sig *Signature, msgs [][]byte, disclose map[int]bool, nonce []byte, opts ...Option) (*SignatureProof, error) {
+	return proveSignature(suite, pubKey, sig, msgs, disclose, nonce, nil, opts)
+}
+
+// proveSignature is ProveSignature, also proving the serial statement st
+// about a hidden message if st is not nil.
+func proveSignature(suite pairing.Suite, pubKey *PublicKey, sig *Signature, msgs [][]byte, disclose map[int]bool, nonce []byte, st *serialStatement, opts []Option) (*SignatureProof, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := pubKey.check(); err != nil {
+		return nil, err
+	}
+	if len(nonce) == 0 {
+		return nil, ErrEmptyNonce
+	}
+	S, err := sig.Components()
+	if err != nil {
+		return nil, err
+	}
+	if err := PSBatchVerify(suite, pubKey.Points(), msgs, S, opts...); err != nil {
+		return nil, err
+	}
+	m := make([]kyber.Scalar, len(msgs))
+	for i, msg := range msgs {
+		m[i] = hashToScalar(suite, o.dst, msg)
+	}
+	return proveScalars(suite, pubKey, sig, m, disclose, nonce, st)
+}
+
+// proveScalars is proveSignature on the attributes m, which sig has been
+// checked to sign.
+func proveScalars(suite pairing.Suite, pubKey *PublicKey, sig *Signature, m []kyber.Scalar, disclose map[int]bool, nonce []byte, st *serialStatement) (*SignatureProof, error) {
+	var disclosed []disclosure
+	for i := range m {
+		if disclose[i] {
+			disclosed = append(disclosed, disclosure{i, m[i]})
+		}
+	}
+	for i := range disclose {
+		if i < 0 || i >= len(m) {
+			return nil, fmt.Errorf("%w: index %d outside 0..%d", ErrInvalidDisclosure, i, len(m)-1)
+		}
+	}
+	hidden := hiddenIndices(len(m), disclosed)
+
+	rand := suite.RandomStream()
+	r, err := pickScalar(suite, rand)
+	if err != nil {
+		return nil, err
+	}
+	// witness = (t, hidden messages), nonces = (a_0, a_1,...)
+	witness := make([]kyber.Scalar, len(hidden)+1)
+	nonces := make([]kyber.Scalar, len(hidden)+1)
+	if witness[0], err = pickScalar(suite, rand); err != nil {
+		return nil, err
+	}
+	for j, i := range hidden {
+		witness[j+1] = m[i].Clone()
+	}
+	for i := range nonces {
+		if nonces[i], err = pickScalar(suite, rand); err != nil {
+			return nil, err
+		}
+	}
+
+	s1 := suite.G1().Point().Mul(r, sig.Sigma1)
+	s2 := suite.G1().Point().Mul(witness[0], sig.Sigma1)
+	s2.Add(s2, sig.Sigma2)
+	s2.Mul(r, s2)
+	T := suite.Pair(s1, showBase(suite, pubKey, hidden, nonces))
+	var serial []byte
+	if st != nil {
+		j := hiddenPosition(hidden, st.index)
+		if j < 0 {
+			return nil, fmt.Errorf("%w: message %d is not hidden", ErrInvalidDisclosure, st.index)
+		}
+		// R = base^(a_j), sharing the nonce of the hidden message
+		if serial, err = st.transcript(suite, suite.G1().Point().Mul(nonces[j+1], st.base)); err != nil {
+			return nil, err
+		}
+	}
+	c, err := showChallenge(suite, pubKey, len(m), disclosed, s1, s2, T, serial, nonce)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]kyber.Scalar, len(nonces))
+	for i := range responses {
+		responses[i] = newScalar(suite).Mul(c, witness[i])
+		responses[i].Add(responses[i], nonces[i])
+		nonces[i].Zero()
+	}
+	witness[0].Zero()
+	id, err := pubKey.KeyID()
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureProof{suite: suite, KeyID: id, Messages: len(m), Hidden: hidden, Sigma1: s1, Sigma2: s2, Challenge: c, Responses: responses}, nil
+}
+
+// VerifySignatureProof checks a proof made by ProveSignature under pubKey
+// and nonce, where disclosed maps the index of every revealed message to its
+// value. It is VerifyPartial, under the name of the show protocol.
+func VerifySignatureProof(suite pairing.Suite, pubKey *PublicKey, proof *SignatureProof, disclosed map[int][]byte, nonce []byte, opts ...Option) error {
+	return VerifyPartial(suite, pubKey, disclosed, proof, nonce, opts...)
+}
+
+// VerifyPartial checks in one call a signature on messages of which the
+// verifier knows some, given in revealed by index, and the holder proves
+// the rest in zero knowledge. The revealed messages are folded into X *
+// prod Y_i^(m_i) and proof answers for the others under nonce. A revealed
+// index that the proof hides is rejected with ErrInvalidDisclosure, as is
+// any message neither revealed nor hidden. It returns ErrInvalidSignature if
+// the proof does not verify.
+func VerifyPartial(suite pairing.Suite, pubKey *PublicKey, revealed map[int][]byte, proof *SignatureProof, nonce []byte, opts ...Option) error {
+	return verifyPartial(suite, pubKey, revealed, proof, nonce, nil, opts)
+}
+
+// verifyPartial is VerifyPartial, also checking the serial statement st
+// about a hidden message if st is not nil.
+func verifyPartial(suite pairing.Suite, pubKey *PublicKey, revealed map[int][]byte, proof *SignatureProof, nonce []byte, st *serialStatement, opts []Option) error {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return err
+	}
+	indices := make([]int, 0, len(revealed))
+	for i := range revealed {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	m := make(map[int]kyber.Scalar, len(revealed))
+	for _, i := range indices {
+		if err := checkMessages(revealed[i]); err != nil {
+			return fmt.Errorf("%w: index %d", err, i)
+		}
+		m[i] = hashToScalar(suite, o.dst, revealed[i])
+	}
+	return verifyRevealed(suite, pubKey, m, proof, nonce, st)
+}
+
+// verifyRevealed is verifyPartial on the revealed attributes m.
+func verifyRevealed(suite pairing.Suite, pubKey *PublicKey, revealed map[int]kyber.Scalar, proof *SignatureProof, nonce []byte, st *serialStatement) error {
+	if err := pubKey.check(); err != nil {
+		return err
+	}
+	if err := proof.check(); err != nil {
+		return err
+	}
+	if len(nonce) == 0 {
+		return ErrEmptyNonce
+	}
+	if proof.KeyID != "" {
+		id, err := pubKey.KeyID()
+		if err != nil {
+			return err
+		}
+		if id != proof.KeyID {
+			return ErrInvalidSignature
+		}
+	}
+	k := proof.Messages
+	if err := checkMessageCount(len(pubKey.Y)+1, k); err != nil {
+		return err
+	}
+	var open []disclosure
+	for i := 0; i < k; i++ {
+		if m, ok := revealed[i]; ok {
+			if m == nil {
+				return fmt.Errorf("%w: index %d", ErrNilMessage, i)
+			}
+			open = append(open, disclosure{i, m})
+		}
+	}
+	if len(open) != len(revealed) {
+		for i := range revealed {
+			if i < 0 || i >= k {
+				return fmt.Errorf("%w: index %d outside 0..%d", ErrInvalidDisclosure, i, k-1)
+			}
+		}
+	}
+	for _, i := range proof.Hidden {
+		if _, ok := revealed[i]; ok {
+			return fmt.Errorf("%w: index %d is both revealed and hidden", ErrInvalidDisclosure, i)
+		}
+	}
+	hidden := hiddenIndices(k, open)
+	if len(proof.Responses) != len(hidden)+1 {
+		return fmt.Errorf("%w: %d messages hidden, proof covers %d", ErrInvalidDisclosure, len(hidden), len(proof.Responses)-1)
+	}
+	for j, i := range hidden {
+		if proof.Hidden[j] != i {
+			return fmt.Errorf("%w: index %d is neither revealed nor hidden", ErrInvalidDisclosure, i)
+		}
+	}
+	s1, s2 := proof.Sigma1, proof.Sigma2
+	if isIdentity(suite.G1(), s1) || isIdentity(suite.G1(), s2) {
+		return ErrInvalidSignature
+	}
+	for _, p := range []kyber.Point{s1, s2} {
+		if err := checkSubgroup(suite.G1(), p); err != nil {
+			return err
+		}
+	}
+
+	// T = e(s_1, g^(z_0) * prod Y_i^(z_i)) / (e(s_2, g) / e(s_1, X * prod_D Y_i^(m_i)))^c
+	X := pubKey.X.Clone()
+	for _, d := range open {
+		X.Add(X, suite.G2().Point().Mul(d.m, pubKey.Y[d.index]))
+	}
+	lhs := suite.GT().Point().Sub(suite.Pair(s2, suite.G2().Point().Base()), suite.Pair(s1, X))
+	T := suite.Pair(s1, showBase(suite, pubKey, hidden, proof.Responses))
+	T.Sub(T, suite.GT().Point().Mul(proof.Challenge, lhs))
+	var serial []byte
+	if st != nil {
+		j := hiddenPosition(hidden, st.index)
+		if j < 0 {
+			return fmt.Errorf("%w: message %d is not hidden", ErrInvalidDisclosure, st.index)
+		}
+		// R = base^(z_j) / serial^c
+		R := suite.G1().Point().Mul(proof.Responses[j+1], st.base)
+		R.Sub(R, suite.G1().Point().Mul(proof.Challenge, st.serial))
+		var err error
+		if serial, err = st.transcript(suite, R); err != nil {
+			return err
+		}
+	}
+	c, err := showChallenge(suite, pubKey, k, open, s1, s2, T, serial, nonce)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(proof.Challenge) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MarshalBinary encodes the proof as header || k || h || i_1 || ... || i_h ||
+// s_1 || s_2 || c || z_0 || ... || z_h || n || kid, for k messages of which
+// the h with indices i_1,...,i_h are hidden and the n-byte key ID kid, n
+// being 0 without one.
+func (p *SignatureProof) MarshalBinary() ([]byte, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(p.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, p.Messages); err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(p.Hidden)); err != nil {
+		return nil, err
+	}
+	for _, i := range p.Hidden {
+		if buf, err = appendCount(buf, i); err != nil {
+			return nil, err
+		}
+	}
+	if buf, err = appendPoints(buf, p.suite.G1(), false, p.Sigma1, p.Sigma2); err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalars(buf, p.suite.G1(), append([]kyber.Scalar{p.Challenge}, p.Responses...)...); err != nil {
+		return nil, err
+	}
+	return appendKeyID(buf, p.KeyID)
+}
+
+// appendKeyID encodes the hex key ID id, which may be empty, with
+// appendBytes.
+func appendKeyID(buf []byte, id string) ([]byte, error) {
+	raw, err := hex.DecodeString(id)
+	if err != nil || (len(raw) != 0 && len(raw) != keyIDLen) {
+		return nil, fmt.Errorf("%w: key ID %q", ErrInvalidHex, id)
+	}
+	return appendBytes(buf, raw)
+}
+
+// readKeyID decodes a key ID encoded by appendKeyID.
+func readKeyID(data []byte) (string, []byte, error) {
+	raw, rest, err := readBytes(data)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(raw) != 0 && len(raw) != keyIDLen {
+		return "", nil, fmt.Errorf("%w: key ID of %d bytes", ErrInvalidSignature, len(raw))
+	}
+	return hex.EncodeToString(raw), rest, nil
+}
+
+// UnmarshalBinary decodes a proof, see PrivateKey.UnmarshalBinary.
+func (p *SignatureProof) UnmarshalBinary(data []byte) error {
+	if p == nil {
+		return ErrNilSignature
+	}
+	suite, body, newer, err := readHeader(p.suite, data)
+	if err != nil {
+		return err
+	}
+	k, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	h, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	hidden := make([]int, h)
+	for j := range hidden {
+		if hidden[j], body, err = readCount(body); err != nil {
+			return err
+		}
+	}
+	points, body, err := decodePoints(suite.G1(), body, 2, false)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, h+2)
+	if err != nil {
+		return err
+	}
+	var id string
+	if data[0] == 1 && data[1] >= keyIDMinor {
+		if id, rest, err = readKeyID(rest); err != nil {
+			return err
+		}
+	}
+	if err := checkTrailing(rest, newer); err != nil {
+		return err
+	}
+	dec := &SignatureProof{suite: suite, KeyID: id, Messages: k, Hidden: hidden, Sigma1: points[0], Sigma2: points[1], Challenge: scalars[0], Responses: scalars[1:]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*p = *dec
+	return nil
+}
+
+// UnmarshalSignatureProof decodes a proof produced under suite.
+func UnmarshalSignatureProof(suite pairing.Suite, data []byte) (*SignatureProof, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	p := &SignatureProof{suite: suite}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,247 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestSignatureProof(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+
+		proof, err := ProveSignature(suite, pub, sig, msgs, nil, nonce)
+		require.Nil(t, err)
+		require.Nil(t, VerifySignatureProof(suite, pub, proof, nil, nonce))
+		require.False(t, proof.Sigma1.Equal(sig.Sigma1))
+
+		buf, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalSignatureProof(suite, buf)
+		require.Nil(t, err)
+		require.Nil(t, VerifySignatureProof(suite, pub, dec, nil, nonce))
+
+		// Proving over fewer messages than key slots.
+		S, err = BatchSign(suite, priv.Scalars(), msgs[:1])
+		require.Nil(t, err)
+		short, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		proof, err = ProveSignature(suite, pub, short, msgs[:1], nil, nonce)
+		require.Nil(t, err)
+		require.Nil(t, VerifySignatureProof(suite, pub, proof, nil, nonce))
+	})
+}
+
+func TestSignatureProofKeyID(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		_, otherPub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{0: true}, nonce)
+		require.Nil(t, err)
+		id, err := pub.KeyID()
+		require.Nil(t, err)
+		require.Equal(t, id, proof.KeyID)
+
+		// The verifier picks the issuer key from its set by the key ID.
+		buf, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		dec, err := UnmarshalSignatureProof(suite, buf)
+		require.Nil(t, err)
+		key, err := SelectKey([]*PublicKey{otherPub, pub}, dec.KeyID)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartial(suite, key, map[int][]byte{0: msgs[0]}, dec, nonce))
+
+		// A proof claiming another key is refused.
+		otherID, err := otherPub.KeyID()
+		require.Nil(t, err)
+		dec.KeyID = otherID
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, map[int][]byte{0: msgs[0]}, dec, nonce))
+
+		// Proofs encoded before key IDs decode without one and verify.
+		old := append([]byte{}, buf[:len(buf)-2-keyIDLen]...)
+		old[1] = keyIDMinor - 1
+		dec, err = UnmarshalSignatureProof(suite, old)
+		require.Nil(t, err)
+		require.Equal(t, "", dec.KeyID)
+		require.Nil(t, VerifyPartial(suite, pub, map[int][]byte{0: msgs[0]}, dec, nonce))
+		_, err = UnmarshalSignatureProof(suite, buf[:len(buf)-1])
+		require.NotNil(t, err)
+	})
+}
+
+func TestSignatureProofInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		_, otherPub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+
+		proof, err := ProveSignature(suite, pub, sig, msgs, nil, nonce)
+		require.Nil(t, err)
+
+		// Replay under another nonce, or another key.
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, proof, nil, []byte("session 2")))
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, otherPub, proof, nil, nonce))
+		require.Equal(t, ErrEmptyNonce, VerifySignatureProof(suite, pub, proof, nil, nil))
+
+		// Forged proofs.
+		forged := *proof
+		forged.Responses = append([]kyber.Scalar{}, proof.Responses...)
+		forged.Responses[2] = newScalar(suite).Pick(random.New())
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, &forged, nil, nonce))
+
+		forged = *proof
+		forged.Sigma2 = suite.G1().Point().Pick(random.New())
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, &forged, nil, nonce))
+
+		forged = *proof
+		forged.Sigma1 = suite.G1().Point().Null()
+		forged.Sigma2 = suite.G1().Point().Null()
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, &forged, nil, nonce))
+
+		// A random signature and proof, made without a key.
+		h := suite.G1().Point().Pick(random.New())
+		fake := &SignatureProof{
+			suite:     suite,
+			Messages:  1,
+			Hidden:    []int{0},
+			Sigma1:    h,
+			Sigma2:    suite.G1().Point().Mul(newScalar(suite).Pick(random.New()), h),
+			Challenge: newScalar(suite).Pick(random.New()),
+			Responses: []kyber.Scalar{newScalar(suite).Pick(random.New()), newScalar(suite).Pick(random.New())},
+		}
+		require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, fake, nil, nonce))
+
+		// Holders cannot prove possession of signatures that do not verify.
+		_, err = ProveSignature(suite, otherPub, sig, msgs, nil, nonce)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+		_, err = ProveSignature(suite, pub, sig, msgs, nil, nil)
+		require.Equal(t, ErrEmptyNonce, err)
+	})
+}
+
+func TestSignatureProofDisclosure(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{
+			[]byte("name=Alice"), []byte("age=31"), []byte("country=FR"),
+			[]byte("member=yes"), []byte("expiry=2030"),
+		}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+
+		for _, indices := range [][]int{{}, {1, 4}, {0, 1, 2, 3, 4}} {
+			disclose := make(map[int]bool)
+			disclosed := make(map[int][]byte)
+			for _, i := range indices {
+				disclose[i] = true
+				disclosed[i] = msgs[i]
+			}
+			proof, err := ProveSignature(suite, pub, sig, msgs, disclose, nonce)
+			require.Nil(t, err, "%v", indices)
+			require.Equal(t, len(msgs)-len(indices)+1, len(proof.Responses))
+			require.Nil(t, VerifySignatureProof(suite, pub, proof, disclosed, nonce), "%v", indices)
+
+			buf, err := proof.MarshalBinary()
+			require.Nil(t, err)
+			dec, err := UnmarshalSignatureProof(suite, buf)
+			require.Nil(t, err)
+			require.Nil(t, VerifySignatureProof(suite, pub, dec, disclosed, nonce), "%v", indices)
+
+			if len(indices) == 0 {
+				continue
+			}
+			// A wrong disclosed value, or withholding a disclosed message.
+			lie := make(map[int][]byte)
+			for i, m := range disclosed {
+				lie[i] = m
+			}
+			lie[indices[0]] = []byte("age=18")
+			require.Equal(t, ErrInvalidSignature, VerifySignatureProof(suite, pub, proof, lie, nonce))
+			delete(lie, indices[0])
+			err = VerifySignatureProof(suite, pub, proof, lie, nonce)
+			require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		}
+
+		// Disclosing other messages than the prover did.
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{1: true}, nonce)
+		require.Nil(t, err)
+		err = VerifySignatureProof(suite, pub, proof, map[int][]byte{2: msgs[2]}, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+
+		// Out-of-range indices.
+		_, err = ProveSignature(suite, pub, sig, msgs, map[int]bool{5: true}, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		_, err = ProveSignature(suite, pub, sig, msgs, map[int]bool{-1: true}, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		err = VerifySignatureProof(suite, pub, proof, map[int][]byte{1: msgs[1], 7: msgs[1]}, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+	})
+}
+
+func TestVerifyPartial(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{
+			[]byte("type=membership"), []byte("name=Alice"), []byte("issuer=club"), []byte("age=31"),
+		}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		nonce := []byte("session 1")
+
+		proof, err := ProveSignature(suite, pub, sig, msgs, map[int]bool{0: true, 2: true}, nonce)
+		require.Nil(t, err)
+		require.Equal(t, []int{1, 3}, proof.Hidden)
+		buf, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		proof, err = UnmarshalSignatureProof(suite, buf)
+		require.Nil(t, err)
+		require.Equal(t, []int{1, 3}, proof.Hidden)
+
+		revealed := map[int][]byte{0: msgs[0], 2: msgs[2]}
+		require.Nil(t, VerifyPartial(suite, pub, revealed, proof, nonce))
+
+		// A wrong revealed value.
+		wrong := map[int][]byte{0: msgs[0], 2: []byte("issuer=other")}
+		require.Equal(t, ErrInvalidSignature, VerifyPartial(suite, pub, wrong, proof, nonce))
+
+		// Revealing a message the proof hides, or leaving one uncovered.
+		both := map[int][]byte{0: msgs[0], 1: msgs[1], 2: msgs[2]}
+		err = VerifyPartial(suite, pub, both, proof, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+		swapped := map[int][]byte{1: msgs[1], 2: msgs[2]}
+		err = VerifyPartial(suite, pub, swapped, proof, nonce)
+		require.True(t, errors.Is(err, ErrInvalidDisclosure))
+
+		// Tampered hidden indices are caught before the proof is checked.
+		forged := *proof
+		forged.Hidden = []int{3, 1}
+		err = VerifyPartial(suite, pub, revealed, &forged, nonce)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+	})
+}
@@ -0,0 +1,152 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Signature is a PS signature (sigma_1, sigma_2), a pair of points on the
+// curve G1.
+type Signature struct {
+	suite  pairing.Suite
+	Sigma1 kyber.Point
+	Sigma2 kyber.Point
+}
+
+// ErrMalformedSignature is returned for signatures that do not consist of
+// exactly two encoded G1 points.
+var ErrMalformedSignature = errors.New("ps: malformed signature")
+
+// NewSignature parses a signature in the [][]byte layout returned by Sign,
+// BatchSign and the aggregation functions. S must hold exactly two
+// components of the suite's G1 point length.
+func NewSignature(suite pairing.Suite, S [][]byte) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	return parseSignature(suite, S, true)
+}
+
+// parseSignature implements NewSignature, checking subgroup membership of the
+// points only if validate is set.
+func parseSignature(suite pairing.Suite, S [][]byte, validate bool) (*Signature, error) {
+	sig, _, err := parseComponents(suite, S, validate)
+	return sig, err
+}
+
+// parseComponents implements parseSignature, also returning the index of the
+// component that failed to parse, or -1 if the failure concerns S as a whole.
+func parseComponents(suite pairing.Suite, S [][]byte, validate bool) (*Signature, int, error) {
+	if S == nil {
+		return nil, -1, ErrNilSignature
+	}
+	if len(S) != 2 {
+		return nil, -1, fmt.Errorf("%w: %d components, expected 2", ErrMalformedSignature, len(S))
+	}
+	sig := &Signature{suite: suite, Sigma1: suite.G1().Point(), Sigma2: suite.G1().Point()}
+	for i, p := range []kyber.Point{sig.Sigma1, sig.Sigma2} {
+		if len(S[i]) != suite.G1().PointLen() {
+			return nil, i, fmt.Errorf("%w: component %d is %d bytes, expected %d", ErrMalformedSignature, i, len(S[i]), suite.G1().PointLen())
+		}
+		if err := p.UnmarshalBinary(S[i]); err != nil {
+			return nil, i, fmt.Errorf("%w: component %d: %v", ErrMalformedSignature, i, err)
+		}
+		if validate {
+			if err := checkSubgroup(suite.G1(), p); err != nil {
+				return nil, i, fmt.Errorf("%w: component %d", err, i)
+			}
+		}
+	}
+	return sig, -1, nil
+}
+
+// check reports whether the signature is complete: non-nil, with a suite and
+// both components.
+func (s *Signature) check() error {
+	if s == nil || s.Sigma1 == nil || s.Sigma2 == nil {
+		return ErrNilSignature
+	}
+	if s.suite == nil {
+		return ErrNilSuite
+	}
+	return nil
+}
+
+// checkPoints checks that the signature is complete and, if validate is set,
+// that both points lie in the prime-order subgroup of G1.
+func (s *Signature) checkPoints(validate bool) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	if !validate {
+		return nil
+	}
+	for i, p := range []kyber.Point{s.Sigma1, s.Sigma2} {
+		if err := checkSubgroup(s.suite.G1(), p); err != nil {
+			return fmt.Errorf("%w: component %d", err, i)
+		}
+	}
+	return nil
+}
+
+// Suite returns the pairing suite the signature belongs to.
+func (s *Signature) Suite() pairing.Suite {
+	if s == nil {
+		return nil
+	}
+	return s.suite
+}
+
+// Components returns the signature in the [][]byte layout accepted by Verify,
+// PSBatchVerify and AggregatePSSign.
+func (s *Signature) Components() ([][]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	s1, err := s.Sigma1.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s2, err := s.Sigma2.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{s1, s2}, nil
+}
+
+// IsEquivalent reports whether s and other are randomizations of each other,
+// that is other = (sigma_1^t, sigma_2^t) for some scalar t, as valid PS
+// signatures on msgs under pubKey.
+//
+// PS signatures are re-randomizable: anyone can turn a signature into a fresh
+// one on the same messages, so two valid signatures on the same messages
+// generally differ in every byte. Comparing encodings therefore says nothing
+// about whether two signatures certify the same thing, and must not be used
+// for deduplication or replay detection. Deciding equivalence from the points
+// alone is not possible either: the supported pairings map G1 x G2, not
+// G1 x G1, so testing whether two G1 pairs share the ratio x + sum(y_i*m_i)
+// is the decisional Diffie-Hellman problem in G1. Every valid signature on
+// msgs under pubKey has that ratio, however, so s and other are equivalent
+// exactly when both verify. An invalid signature yields false and no error.
+func (s *Signature) IsEquivalent(suite pairing.Suite, other *Signature, pubKey []kyber.Point, msgs [][]byte, opts ...Option) (bool, error) {
+	if suite == nil {
+		return false, ErrNilSuite
+	}
+	for _, sig := range []*Signature{s, other} {
+		S, err := sig.Components()
+		if err != nil {
+			return false, err
+		}
+		err = PSBatchVerify(suite, pubKey, msgs, S, opts...)
+		if errors.Is(err, ErrInvalidSignature) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package ps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestSignatureIsEquivalent(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		priv, pub := newTestKeys(t, suite, 2)
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		sig, err := NewSignature(suite, S)
+		require.Nil(t, err)
+
+		r := newScalar(suite).Pick(random.New())
+		randomized := &Signature{
+			suite:  suite,
+			Sigma1: suite.G1().Point().Mul(r, sig.Sigma1),
+			Sigma2: suite.G1().Point().Mul(r, sig.Sigma2),
+		}
+		a, err := sig.MarshalBinary()
+		require.Nil(t, err)
+		b, err := randomized.MarshalBinary()
+		require.Nil(t, err)
+		require.NotEqual(t, a, b)
+
+		ok, err := sig.IsEquivalent(suite, randomized, pub.Points(), msgs)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		// A fresh signature on the same messages is a randomization too.
+		S, err = BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		fresh, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		ok, err = sig.IsEquivalent(suite, fresh, pub.Points(), msgs)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		otherMsgs := [][]byte{[]byte("attribute 1"), []byte("attribute 3")}
+		S, err = BatchSign(suite, priv.Scalars(), otherMsgs)
+		require.Nil(t, err)
+		other, err := NewSignature(suite, S)
+		require.Nil(t, err)
+		ok, err = sig.IsEquivalent(suite, other, pub.Points(), msgs)
+		require.Nil(t, err)
+		require.False(t, ok)
+
+		_, err = sig.IsEquivalent(suite, nil, pub.Points(), msgs)
+		require.Equal(t, ErrNilSignature, err)
+	})
+}
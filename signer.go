@@ -0,0 +1,18 @@
+package ps
+
+// Signer is a signing backend holding one private key, which may live in
+// another process or on another host, so that code issuing signatures need
+// not know where the key is. The pstest package checks implementations, and
+// the psrpc package provides one over gRPC.
+type Signer interface {
+	// PublicKey returns the public key of the signer.
+	PublicKey() *PublicKey
+
+	// Sign signs msgs in the first len(msgs) slots, as
+	// PrivateKey.BatchSign does.
+	Sign(msgs [][]byte) ([][]byte, error)
+
+	// SignAcross adds msg to the aggregate S of other signers, or starts
+	// one if S is nil, as AggregateSignAcross does.
+	SignAcross(S *Signature, msg []byte) (*Signature, error)
+}
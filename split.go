@@ -0,0 +1,175 @@
+package ps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Additive splitting puts a key under dual control without threshold
+// machinery: the scalars of the key are split into n shares,
+//
+//	x = x_1 + ... + x_n,   y_i = y_i1 + ... + y_in,
+//
+// the first n-1 of them random, so that any n-1 shares are independent of
+// the key. On a common base h every share holder j contributes
+//
+//	(h, h^(x_j + y_1j*m_1 + ... + y_kj*m_k)),
+//
+// and the product of the second components, see CombineSignatures, is a
+// signature under the original public key. As for multi-signatures the base
+// is derived from the messages, by AdditiveBase, so that the partial
+// signatures of one holder on different messages cannot be combined into
+// other signatures. All n shares are needed: a share is just a random key,
+// and signatures made with fewer shares do not verify.
+
+// ErrInvalidShares is returned for share counts below 2 and for shares that
+// do not belong together.
+var ErrInvalidShares = errors.New("ps: invalid key shares")
+
+// SplitKeyAdditive splits priv into n >= 2 shares whose scalars sum to those
+// of priv, drawing the first n-1 from rand or, if rand is nil, the suite's
+// random stream. The shares carry the policy of priv.
+func SplitKeyAdditive(priv *PrivateKey, n int, rand cipher.Stream) ([]*PrivateKey, error) {
+	if err := priv.check(); err != nil {
+		return nil, err
+	}
+	if n < 2 {
+		return nil, fmt.Errorf("%w: %d shares", ErrInvalidShares, n)
+	}
+	if rand == nil {
+		rand = priv.suite.RandomStream()
+	}
+	scalars := priv.Scalars()
+	last := make([]kyber.Scalar, len(scalars))
+	for i, s := range scalars {
+		last[i] = s.Clone()
+	}
+	shares := make([]*PrivateKey, n)
+	for j := 0; j < n-1; j++ {
+		share := make([]kyber.Scalar, len(scalars))
+		for i := range share {
+			s, err := pickScalar(priv.suite, rand)
+			if err != nil {
+				return nil, err
+			}
+			share[i] = s
+			last[i].Sub(last[i], s)
+		}
+		shares[j] = &PrivateKey{suite: priv.suite, X: share[0], Y: share[1:], policy: priv.Policy()}
+	}
+	shares[n-1] = &PrivateKey{suite: priv.suite, X: last[0], Y: last[1:], policy: priv.Policy()}
+	return shares, nil
+}
+
+// RecombineKey sums the shares made by SplitKeyAdditive back into the key,
+// with the policy of the first share.
+func RecombineKey(shares []*PrivateKey) (*PrivateKey, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("%w: %d shares", ErrInvalidShares, len(shares))
+	}
+	if err := shares[0].check(); err != nil {
+		return nil, err
+	}
+	suite := shares[0].suite
+	sum := make([]kyber.Scalar, len(shares[0].Y)+1)
+	for i := range sum {
+		sum[i] = newScalar(suite).Zero()
+	}
+	for j, share := range shares {
+		if err := share.check(); err != nil {
+			return nil, fmt.Errorf("%w: share %d", err, j)
+		}
+		if share.suite.G1().String() != suite.G1().String() || len(share.Y) != len(shares[0].Y) {
+			return nil, fmt.Errorf("%w: share %d does not match share 0", ErrInvalidShares, j)
+		}
+		for i, s := range share.Scalars() {
+			sum[i].Add(sum[i], s)
+		}
+	}
+	return &PrivateKey{suite: suite, X: sum[0], Y: sum[1:], policy: shares[0].Policy()}, nil
+}
+
+// AdditiveBase returns the common base h of the partial signatures on msgs,
+// which is hashed to G1 from the message scalars under the tag set by opts.
+func AdditiveBase(suite pairing.Suite, msgs [][]byte, opts ...Option) (kyber.Point, error) {
+	m, err := HashMessages(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := appendCount(nil, len(m))
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendScalars(buf, suite.G1(), m...); err != nil {
+		return nil, err
+	}
+	return hashToPoint(suite, protocolDST(suite, "ASPLIT"), buf)
+}
+
+// SignAdditiveShare returns the partial signature of share on msgs over the
+// base h, after checking that h is AdditiveBase of msgs. The messages take
+// the first len(msgs) slots, as with BatchSign.
+func SignAdditiveShare(suite pairing.Suite, share *PrivateKey, h kyber.Point, msgs [][]byte, opts ...Option) (*Signature, error) {
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if err := share.check(); err != nil {
+		return nil, err
+	}
+	if share.suite.G1().String() != suite.G1().String() {
+		return nil, ErrSuiteMismatch
+	}
+	if err := share.policy.allow(OpSign, slotRange(0, len(msgs))...); err != nil {
+		return nil, err
+	}
+	if len(msgs) > len(share.Y) {
+		return nil, fmt.Errorf("%w: %d messages need %d key components, got %d", ErrKeyLengthMismatch, len(msgs), len(msgs)+1, len(share.Y)+1)
+	}
+	if h == nil {
+		return nil, fmt.Errorf("%w: nil base", ErrInvalidPoint)
+	}
+	want, err := AdditiveBase(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !want.Equal(h) {
+		return nil, fmt.Errorf("%w: base does not belong to the messages", ErrInvalidPoint)
+	}
+	m, err := HashMessages(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	e := share.X.Clone()
+	for i := range m {
+		e.Add(e, m[i].Mul(m[i], share.Y[i]))
+	}
+	return &Signature{suite: suite, Sigma1: want, Sigma2: suite.G1().Point().Mul(e, want)}, nil
+}
+
+// SignWithShares signs msgs with every share in turn and combines the
+// partial signatures, for share holders signing in one place. It returns
+// the signature in the layout of BatchSign.
+func SignWithShares(suite pairing.Suite, shares []*PrivateKey, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("%w: %d shares", ErrInvalidShares, len(shares))
+	}
+	h, err := AdditiveBase(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	partials := make([]*Signature, len(shares))
+	for j, share := range shares {
+		if partials[j], err = SignAdditiveShare(suite, share, h, msgs, opts...); err != nil {
+			return nil, itemError("sign share", j, err)
+		}
+	}
+	sig, err := CombineSignatures(suite, partials)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Components()
+}
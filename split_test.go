@@ -0,0 +1,93 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestSplitKeyAdditive(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 2)
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		for _, n := range []int{2, 3} {
+			shares, err := SplitKeyAdditive(priv, n, nil)
+			require.Nil(t, err)
+			require.Equal(t, n, len(shares))
+
+			S, err := SignWithShares(suite, shares, msgs)
+			require.Nil(t, err)
+			require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+			// Share holders sign apart on the agreed base.
+			h, err := AdditiveBase(suite, msgs)
+			require.Nil(t, err)
+			var partials []*Signature
+			for _, share := range shares {
+				partial, err := SignAdditiveShare(suite, share, h, msgs)
+				require.Nil(t, err)
+				partials = append(partials, partial)
+			}
+			sig, err := CombineSignatures(suite, partials)
+			require.Nil(t, err)
+			S, err = sig.Components()
+			require.Nil(t, err)
+			require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+
+			// Without any one share, no valid signature comes out.
+			for j := range shares {
+				rest := append(append([]*PrivateKey{}, shares[:j]...), shares[j+1:]...)
+				if len(rest) >= 2 {
+					S, err := SignWithShares(suite, rest, msgs)
+					require.Nil(t, err)
+					require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, S))
+				}
+				S, err := BatchSign(suite, shares[j].Scalars(), msgs)
+				require.Nil(t, err)
+				require.Equal(t, ErrInvalidSignature, PSBatchVerify(suite, pub.Points(), msgs, S))
+			}
+
+			// Recovery restores the key.
+			recovered, err := RecombineKey(shares)
+			require.Nil(t, err)
+			for i, s := range priv.Scalars() {
+				require.True(t, s.Equal(recovered.Scalars()[i]))
+			}
+			S, err = BatchSign(suite, recovered.Scalars(), msgs)
+			require.Nil(t, err)
+			require.Nil(t, PSBatchVerify(suite, pub.Points(), msgs, S))
+		}
+	})
+}
+
+func TestSplitKeyAdditiveInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, _ := newTestKeys(t, suite, 2)
+		other, _ := newTestKeys(t, suite, 1)
+		msgs := [][]byte{[]byte("alice"), []byte("paris")}
+		_, err := SplitKeyAdditive(priv, 1, nil)
+		require.True(t, errors.Is(err, ErrInvalidShares))
+		shares, err := SplitKeyAdditive(priv, 2, nil)
+		require.Nil(t, err)
+
+		_, err = RecombineKey(shares[:1])
+		require.True(t, errors.Is(err, ErrInvalidShares))
+		_, err = RecombineKey([]*PrivateKey{shares[0], other})
+		require.True(t, errors.Is(err, ErrInvalidShares))
+
+		// A base for other messages is refused.
+		h, err := AdditiveBase(suite, msgs[:1])
+		require.Nil(t, err)
+		_, err = SignAdditiveShare(suite, shares[0], h, msgs)
+		require.True(t, errors.Is(err, ErrInvalidPoint))
+
+		// The shares keep the policy of the key.
+		require.Nil(t, priv.SetPolicy(&KeyPolicy{Operations: OpSign, Reserved: []int{0}}))
+		shares, err = SplitKeyAdditive(priv, 2, nil)
+		require.Nil(t, err)
+		_, err = SignWithShares(suite, shares, msgs)
+		require.True(t, errors.Is(err, ErrPolicyViolation))
+	})
+}
@@ -0,0 +1,117 @@
+package ps
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bithinalangot/ps/bls12381"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// SuiteID identifies a pairing suite in the registry.
+type SuiteID byte
+
+// Identifiers of the suites registered by default.
+const (
+	SuiteBN256    SuiteID = 0x01
+	SuiteBLS12381 SuiteID = 0x02
+)
+
+type suiteEntry struct {
+	id     SuiteID
+	name   string
+	g1Name string
+	suite  pairing.Suite
+}
+
+var registry = struct {
+	sync.RWMutex
+	byID map[SuiteID]*suiteEntry
+}{byID: make(map[SuiteID]*suiteEntry)}
+
+func init() {
+	if err := RegisterSuite(SuiteBN256, "bn256", pairing.NewSuiteBn256()); err != nil {
+		panic(err)
+	}
+	if err := RegisterSuite(SuiteBLS12381, "bls12381", bls12381.NewSuite()); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterSuite adds a pairing suite to the registry under the given
+// identifier and name. Suites are told apart by the name of their G1 group, so
+// two registered suites must not share it. Suites whose G1 and G2 differ in
+// order are refused with ErrIncompatibleSuite.
+func RegisterSuite(id SuiteID, name string, suite pairing.Suite) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	if err := checkScalarField(suite); err != nil {
+		return err
+	}
+	registry.Lock()
+	defer registry.Unlock()
+
+	g1Name := suite.G1().String()
+	for _, e := range registry.byID {
+		if e.id == id || e.name == name || e.g1Name == g1Name {
+			return fmt.Errorf("ps: suite %s (0x%02x) already registered", e.name, byte(e.id))
+		}
+	}
+	registry.byID[id] = &suiteEntry{id: id, name: name, g1Name: g1Name, suite: suite}
+	return nil
+}
+
+// SuiteByID returns the registered suite with the given identifier.
+func SuiteByID(id SuiteID) (pairing.Suite, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	e, ok := registry.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("ps: unknown suite 0x%02x", byte(id))
+	}
+	return e.suite, nil
+}
+
+// SuiteByName returns the registered suite with the given name.
+func SuiteByName(name string) (pairing.Suite, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, e := range registry.byID {
+		if e.name == name {
+			return e.suite, nil
+		}
+	}
+	return nil, fmt.Errorf("ps: unknown suite %q", name)
+}
+
+// SuiteIDOf returns the identifier under which suite is registered.
+func SuiteIDOf(suite pairing.Suite) (SuiteID, error) {
+	if suite == nil {
+		return 0, ErrNilSuite
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+
+	g1Name := suite.G1().String()
+	for _, e := range registry.byID {
+		if e.g1Name == g1Name {
+			return e.id, nil
+		}
+	}
+	return 0, fmt.Errorf("ps: suite with group %s is not registered", g1Name)
+}
+
+// SuiteName returns the registered name of the suite with the given
+// identifier, or a hexadecimal placeholder if it is unknown.
+func SuiteName(id SuiteID) string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if e, ok := registry.byID[id]; ok {
+		return e.name
+	}
+	return fmt.Sprintf("0x%02x", byte(id))
+}
@@ -0,0 +1,145 @@
+package ps
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Times are signed at a granularity of a day, an hour or a second, as the
+// number of whole units elapsed since 1970-01-01T00:00:00Z:
+//
+//	KindDate || granularity || units
+//
+// with units encoded as for AttributeFromInt64, so that later times encode
+// to larger byte strings. Whatever lies below the granularity is truncated
+// towards the past, before 1970 as after, and times must be given in UTC:
+// a time in another location is rejected rather than converted, so that the
+// day of a birthdate is the one the issuer meant.
+//
+// An expiring credential signs NotAfterAttribute in its first slot, ahead of
+// the caller's messages, like an epoch. It is valid up to the end of that
+// unit: a credential not after 2027-01-31 at day granularity is valid all of
+// that day, UTC.
+
+var (
+	// ErrNotUTC is returned for times not in UTC.
+	ErrNotUTC = errors.New("ps: time not in UTC")
+
+	// ErrExpired is returned when verifying a credential after its not-after
+	// time.
+	ErrExpired = errors.New("ps: credential expired")
+)
+
+// TimeGranularity is the unit times are truncated to when signed.
+type TimeGranularity byte
+
+// The granularities of time attributes.
+const (
+	GranularityDay TimeGranularity = iota + 1
+	GranularityHour
+	GranularitySecond
+)
+
+// seconds returns the length of the unit, or 0 for unknown granularities.
+func (g TimeGranularity) seconds() int64 {
+	switch g {
+	case GranularityDay:
+		return 24 * 60 * 60
+	case GranularityHour:
+		return 60 * 60
+	case GranularitySecond:
+		return 1
+	}
+	return 0
+}
+
+// AttributeFromTime returns the message encoding t, which must be in UTC,
+// truncated to g.
+func AttributeFromTime(t time.Time, g TimeGranularity) ([]byte, error) {
+	unit := g.seconds()
+	if unit == 0 {
+		return nil, fmt.Errorf("%w: unknown time granularity %d", ErrAttributeType, g)
+	}
+	if t.Location() != time.UTC {
+		return nil, fmt.Errorf("%w: %v", ErrNotUTC, t.Location())
+	}
+	secs := t.Unix()
+	units := secs / unit
+	if secs%unit < 0 {
+		units--
+	}
+	return appendUint64([]byte{byte(KindDate), byte(g)}, uint64(units)^(1<<63)), nil
+}
+
+// TimeFromAttribute decodes a time attribute made by AttributeFromTime,
+// returning the start of its unit, in UTC, and its granularity.
+func TimeFromAttribute(msg []byte) (time.Time, TimeGranularity, error) {
+	if len(msg) != 10 || msg[0] != byte(KindDate) {
+		return time.Time{}, 0, fmt.Errorf("%w: not a time attribute", ErrAttributeType)
+	}
+	g := TimeGranularity(msg[1])
+	unit := g.seconds()
+	if unit == 0 {
+		return time.Time{}, 0, fmt.Errorf("%w: unknown time granularity %d", ErrAttributeType, g)
+	}
+	units := int64(binary.BigEndian.Uint64(msg[2:]) ^ (1 << 63))
+	if units > maxUnixSeconds/unit || units < -maxUnixSeconds/unit {
+		return time.Time{}, 0, fmt.Errorf("%w: time out of range", ErrAttributeRange)
+	}
+	return time.Unix(units*unit, 0).UTC(), g, nil
+}
+
+// maxUnixSeconds bounds decoded times to about 146 billion years either way
+// of 1970, well within what time.Time represents.
+const maxUnixSeconds = 1 << 62
+
+// NotAfterAttribute returns the attribute of a credential valid up to the
+// end of the unit of g containing notAfter.
+func NotAfterAttribute(notAfter time.Time, g TimeGranularity) ([]byte, error) {
+	return AttributeFromTime(notAfter, g)
+}
+
+// CheckNotAfter returns ErrExpired if now, in UTC, is past the unit of the
+// not-after attribute.
+func CheckNotAfter(notAfter []byte, now time.Time) error {
+	end, g, err := TimeFromAttribute(notAfter)
+	if err != nil {
+		return err
+	}
+	end = end.Add(time.Duration(g.seconds()) * time.Second)
+	if !now.Before(end) {
+		return fmt.Errorf("%w: not after %v", ErrExpired, end.Add(-time.Second).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// SignWithExpiry signs msgs as BatchSign does on notAfter, made by
+// NotAfterAttribute, followed by msgs. The key needs one Y component more
+// than there are messages.
+func SignWithExpiry(suite pairing.Suite, priKey []kyber.Scalar, notAfter []byte, msgs [][]byte, opts ...Option) ([][]byte, error) {
+	if _, _, err := TimeFromAttribute(notAfter); err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+	return BatchSign(suite, priKey, append([][]byte{notAfter}, msgs...), opts...)
+}
+
+// VerifyWithExpiry checks a signature made by SignWithExpiry on msgs and
+// notAfter, and that now is not past notAfter. Expiry is checked first, and
+// reported as ErrExpired.
+func VerifyWithExpiry(suite pairing.Suite, pubKey []kyber.Point, notAfter []byte, now time.Time, msgs [][]byte, S [][]byte, opts ...Option) error {
+	if err := CheckNotAfter(notAfter, now); err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return ErrNoMessages
+	}
+	return PSBatchVerify(suite, pubKey, append([][]byte{notAfter}, msgs...), S, opts...)
+}
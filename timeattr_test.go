@@ -0,0 +1,154 @@
+package ps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+func TestTimeAttribute(t *testing.T) {
+	for _, tc := range []struct {
+		in   time.Time
+		g    TimeGranularity
+		want time.Time
+	}{
+		{time.Date(2026, 10, 16, 13, 45, 12, 999, time.UTC), GranularityDay, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 10, 16, 13, 45, 12, 999, time.UTC), GranularityHour, time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 10, 16, 13, 45, 12, 999, time.UTC), GranularitySecond, time.Date(2026, 10, 16, 13, 45, 12, 0, time.UTC)},
+		// Truncation is towards the past before 1970 too.
+		{time.Date(1969, 12, 31, 23, 59, 59, 500, time.UTC), GranularityDay, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1969, 12, 31, 23, 59, 59, 500, time.UTC), GranularityHour, time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC)},
+		{time.Date(1969, 12, 31, 23, 59, 59, 500, time.UTC), GranularitySecond, time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)},
+		// Either side of the 32-bit time_t overflow.
+		{time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC), GranularitySecond, time.Unix(1<<31-1, 0).UTC()},
+		{time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC), GranularitySecond, time.Unix(1<<31, 0).UTC()},
+		{time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC), GranularityHour, time.Date(2038, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), GranularitySecond, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)},
+	} {
+		msg, err := AttributeFromTime(tc.in, tc.g)
+		require.Nil(t, err)
+		got, g, err := TimeFromAttribute(msg)
+		require.Nil(t, err)
+		require.Equal(t, tc.g, g)
+		require.Equal(t, tc.want, got, "%v at %d", tc.in, tc.g)
+		again, err := AttributeFromTime(got, g)
+		require.Nil(t, err)
+		require.Equal(t, msg, again)
+	}
+
+	// Later times encode to larger byte strings, across 2038 as well.
+	var prev []byte
+	for _, secs := range []int64{-1 << 40, -1, 0, 1<<31 - 1, 1 << 31, 1 << 32, 1 << 40} {
+		msg, err := AttributeFromTime(time.Unix(secs, 0).UTC(), GranularitySecond)
+		require.Nil(t, err)
+		require.True(t, bytes.Compare(prev, msg) < 0, "%d", secs)
+		prev = msg
+	}
+
+	// Granularities do not decode as one another.
+	day, err := AttributeFromTime(time.Unix(0, 0).UTC(), GranularityDay)
+	require.Nil(t, err)
+	sec, err := AttributeFromTime(time.Unix(0, 0).UTC(), GranularitySecond)
+	require.Nil(t, err)
+	require.NotEqual(t, day, sec)
+
+	_, err = AttributeFromTime(time.Now(), GranularitySecond)
+	require.True(t, errors.Is(err, ErrNotUTC))
+	_, err = AttributeFromTime(time.Unix(0, 0).UTC(), 0)
+	require.True(t, errors.Is(err, ErrAttributeType))
+	for _, bad := range [][]byte{nil, AttributeFromInt64(0), day[:9], {byte(KindDate), 4, 0, 0, 0, 0, 0, 0, 0, 0}} {
+		_, _, err = TimeFromAttribute(bad)
+		require.True(t, errors.Is(err, ErrAttributeType), "%x", bad)
+	}
+	_, _, err = TimeFromAttribute(appendUint64([]byte{byte(KindDate), byte(GranularityDay)}, 0))
+	require.True(t, errors.Is(err, ErrAttributeRange))
+}
+
+// TestTimeAttributeDST checks that local times either side of a daylight
+// saving change, converted to UTC, encode by elapsed time and not by wall
+// clock.
+func TestTimeAttributeDST(t *testing.T) {
+	ams, err := time.LoadLocation("Europe/Amsterdam")
+	require.Nil(t, err)
+	hourOf := func(local time.Time) time.Time {
+		msg, err := AttributeFromTime(local.UTC(), GranularityHour)
+		require.Nil(t, err)
+		got, _, err := TimeFromAttribute(msg)
+		require.Nil(t, err)
+		return got
+	}
+
+	// Clocks skip from 02:00 to 03:00 on 2026-03-29: the hours either side
+	// of the gap are consecutive.
+	before := hourOf(time.Date(2026, 3, 29, 1, 59, 0, 0, ams))
+	after := hourOf(time.Date(2026, 3, 29, 3, 0, 0, 0, ams))
+	require.Equal(t, time.Hour, after.Sub(before))
+
+	// Clocks fall back from 03:00 to 02:00 on 2026-10-25: the two 02:30s
+	// are an hour apart.
+	first := time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC).In(ams)
+	second := first.Add(time.Hour)
+	require.Equal(t, first.Hour(), second.Hour())
+	require.Equal(t, time.Hour, hourOf(second).Sub(hourOf(first)))
+
+	// The day is the UTC day, whatever the offset.
+	msg, err := AttributeFromTime(time.Date(2026, 3, 29, 0, 30, 0, 0, ams).UTC(), GranularityDay)
+	require.Nil(t, err)
+	got, _, err := TimeFromAttribute(msg)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC), got)
+}
+
+func TestCheckNotAfter(t *testing.T) {
+	end := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		g    TimeGranularity
+		last time.Time
+	}{
+		{GranularityDay, time.Date(2027, 1, 31, 23, 59, 59, 999999999, time.UTC)},
+		{GranularityHour, time.Date(2027, 1, 31, 0, 59, 59, 999999999, time.UTC)},
+		{GranularitySecond, time.Date(2027, 1, 31, 0, 0, 0, 999999999, time.UTC)},
+	} {
+		notAfter, err := NotAfterAttribute(end, tc.g)
+		require.Nil(t, err)
+		require.Nil(t, CheckNotAfter(notAfter, end.AddDate(-1, 0, 0)))
+		require.Nil(t, CheckNotAfter(notAfter, tc.last))
+		// The time to check against need not be in UTC.
+		require.Nil(t, CheckNotAfter(notAfter, tc.last.In(time.FixedZone("UTC+5", 5*3600))))
+		require.True(t, errors.Is(CheckNotAfter(notAfter, tc.last.Add(time.Nanosecond)), ErrExpired), "%d", tc.g)
+	}
+}
+
+func TestSignWithExpiry(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		priv, pub := newTestKeys(t, suite, 3)
+		msgs := [][]byte{[]byte("alice"), []byte("member")}
+		notAfter, err := NotAfterAttribute(time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC), GranularityDay)
+		require.Nil(t, err)
+		S, err := SignWithExpiry(suite, priv.Scalars(), notAfter, msgs)
+		require.Nil(t, err)
+
+		now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		require.Nil(t, VerifyWithExpiry(suite, pub.Points(), notAfter, now, msgs, S))
+		require.True(t, errors.Is(VerifyWithExpiry(suite, pub.Points(), notAfter, now.AddDate(1, 0, 0), msgs, S), ErrExpired))
+
+		// A later not-after, or the same day at another granularity, was not
+		// signed.
+		extended, err := NotAfterAttribute(time.Date(2028, 1, 31, 0, 0, 0, 0, time.UTC), GranularityDay)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyWithExpiry(suite, pub.Points(), extended, now, msgs, S))
+		hourly, err := NotAfterAttribute(time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC), GranularityHour)
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, VerifyWithExpiry(suite, pub.Points(), hourly, now, msgs, S))
+
+		_, err = SignWithExpiry(suite, priv.Scalars(), []byte("2027-01-31"), msgs)
+		require.True(t, errors.Is(err, ErrAttributeType))
+		_, err = SignWithExpiry(suite, priv.Scalars(), notAfter, nil)
+		require.Equal(t, ErrNoMessages, err)
+	})
+}
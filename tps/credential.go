@@ -0,0 +1,498 @@
+package tps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Credential issuance follows Coconut (Sonnino et al., "Coconut: Threshold
+// Issuance Selective Disclosure Credentials with Applications to Distributed
+// Ledgers", NDSS 2019), with authorities holding the key shares of this
+// package. The user commits to its attributes m_1,...,m_k as
+//
+//	cm = g^o * h_1^(m_1) * ... * h_k^(m_k)
+//
+// for a random o and fixed generators h_i hashed to G1, derives the common
+// base h by hashing cm to G1, and encrypts every h^(m_i) under a fresh
+// ElGamal key (d, gamma = g^d) as (a_i, b_i) = (g^(k_i), gamma^(k_i) *
+// h^(m_i)). A Schnorr proof made non-interactive by Fiat-Shamir shows that
+// the ciphertexts hold the committed attributes. Authority j answers with
+//
+//	(a~, b~) = (a_1^(y_1j) * ... * a_k^(y_kj), h^(x_j) * b_1^(y_1j) * ... * b_k^(y_kj)),
+//
+// an encryption of its signature share h^(x_j + y_1j*m_1 + ... + y_kj*m_k),
+// which the user decrypts with d. Any t decrypted shares combine as in
+// CombineShares into a PS signature on the attributes, the credential. The
+// user shows it with ps.ProveSignature, which randomizes it and discloses
+// attributes selectively; since h is bound to cm, which the authorities never
+// see opened, the credential does not link issuance and showing.
+//
+// The flow is RequestCredential, IssueCredentialShare at each authority,
+// AggregateCredential, then ShowCredential and VerifyCredential. Attributes
+// are hashed to scalars as by ps.BatchSign, under the options given.
+
+// ErrInvalidCredentialRequest is returned by IssueCredentialShare for
+// requests whose proof does not verify.
+var ErrInvalidCredentialRequest = errors.New("tps: invalid credential request")
+
+// CredentialRequest is what the user sends to every authority: the
+// commitment cm, the ElGamal public key gamma, the ciphertexts (A_i, B_i) of
+// the attributes and the proof (c, z_o, z_m_1..z_m_k, z_k_1..z_k_k) that
+// they match the commitment.
+type CredentialRequest struct {
+	suite      pairing.Suite
+	Commitment kyber.Point
+	Gamma      kyber.Point
+	A, B       []kyber.Point
+	Challenge  kyber.Scalar
+	Responses  []kyber.Scalar
+}
+
+// check reports whether the request is complete and consistent.
+func (r *CredentialRequest) check() error {
+	if r == nil || r.Commitment == nil || r.Gamma == nil || r.Challenge == nil {
+		return fmt.Errorf("%w: incomplete", ErrInvalidCredentialRequest)
+	}
+	if r.suite == nil {
+		return ps.ErrNilSuite
+	}
+	k := len(r.A)
+	if k == 0 || len(r.B) != k || len(r.Responses) != 2*k+1 {
+		return fmt.Errorf("%w: %d ciphertexts and %d responses", ErrInvalidCredentialRequest, k, len(r.Responses))
+	}
+	for i := 0; i < k; i++ {
+		if r.A[i] == nil || r.B[i] == nil {
+			return fmt.Errorf("%w: ciphertext %d is nil", ErrInvalidCredentialRequest, i)
+		}
+	}
+	for i, z := range r.Responses {
+		if z == nil {
+			return fmt.Errorf("%w: response %d is nil", ErrInvalidCredentialRequest, i)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the request as header || k || cm || gamma || A_1 ||
+// ... || A_k || B_1 || ... || B_k || c || z_0 || ... || z_2k.
+func (r *CredentialRequest) MarshalBinary() ([]byte, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(r.suite)
+	if err != nil {
+		return nil, err
+	}
+	if buf, err = appendCount(buf, len(r.A)); err != nil {
+		return nil, err
+	}
+	points := append([]kyber.Point{r.Commitment, r.Gamma}, r.A...)
+	if buf, err = appendPoints(buf, append(points, r.B...)...); err != nil {
+		return nil, err
+	}
+	return appendScalars(buf, append([]kyber.Scalar{r.Challenge}, r.Responses...)...)
+}
+
+// UnmarshalBinary decodes a request, see KeyShare.UnmarshalBinary.
+func (r *CredentialRequest) UnmarshalBinary(data []byte) error {
+	if r == nil {
+		return ErrInvalidCredentialRequest
+	}
+	suite, body, err := readHeader(r.suite, data)
+	if err != nil {
+		return err
+	}
+	k, body, err := readCount(body)
+	if err != nil {
+		return err
+	}
+	points, body, err := decodePoints(suite.G1(), body, 2*k+2)
+	if err != nil {
+		return err
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, 2*k+2)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest); err != nil {
+		return err
+	}
+	dec := &CredentialRequest{
+		suite:      suite,
+		Commitment: points[0],
+		Gamma:      points[1],
+		A:          points[2 : k+2],
+		B:          points[k+2:],
+		Challenge:  scalars[0],
+		Responses:  scalars[1:],
+	}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*r = *dec
+	return nil
+}
+
+// UnmarshalCredentialRequest decodes a request produced under suite.
+func UnmarshalCredentialRequest(suite pairing.Suite, data []byte) (*CredentialRequest, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	r := &CredentialRequest{suite: suite}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CredentialState is the user's secret from RequestCredential, needed to
+// decrypt the authorities' answers. It must not leave the user.
+type CredentialState struct {
+	suite pairing.Suite
+	d     kyber.Scalar
+	h     kyber.Point
+	msgs  [][]byte
+	opts  []ps.Option
+}
+
+// CredentialShare is one authority's answer to a credential request: the
+// ElGamal encryption (A, B) of its signature share on the base H.
+type CredentialShare struct {
+	suite pairing.Suite
+	Index int
+	T     int
+	H     kyber.Point
+	A, B  kyber.Point
+}
+
+// check reports whether the share is complete.
+func (s *CredentialShare) check() error {
+	if s == nil || s.H == nil || s.A == nil || s.B == nil {
+		return ps.ErrNilSignature
+	}
+	if s.suite == nil {
+		return ps.ErrNilSuite
+	}
+	if s.Index < 0 || s.Index >= maxShares || s.T < 1 || s.T > maxShares {
+		return fmt.Errorf("%w: share %d of threshold %d", ErrInvalidShare, s.Index, s.T)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the share as header || index || t || H || A || B.
+func (s *CredentialShare) MarshalBinary() ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(s.suite)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range []int{s.Index, s.T} {
+		if buf, err = appendCount(buf, n); err != nil {
+			return nil, err
+		}
+	}
+	return appendPoints(buf, s.H, s.A, s.B)
+}
+
+// UnmarshalBinary decodes a share, see KeyShare.UnmarshalBinary.
+func (s *CredentialShare) UnmarshalBinary(data []byte) error {
+	if s == nil {
+		return ps.ErrNilSignature
+	}
+	suite, body, err := readHeader(s.suite, data)
+	if err != nil {
+		return err
+	}
+	counts := make([]int, 2)
+	for i := range counts {
+		if counts[i], body, err = readCount(body); err != nil {
+			return err
+		}
+	}
+	points, rest, err := decodePoints(suite.G1(), body, 3)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest); err != nil {
+		return err
+	}
+	dec := &CredentialShare{suite: suite, Index: counts[0], T: counts[1], H: points[0], A: points[1], B: points[2]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*s = *dec
+	return nil
+}
+
+// UnmarshalCredentialShare decodes a share produced under suite.
+func UnmarshalCredentialShare(suite pairing.Suite, data []byte) (*CredentialShare, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	s := &CredentialShare{suite: suite}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// credentialGenerators returns the generators h_1,...,h_k of attribute
+// commitments.
+func credentialGenerators(suite pairing.Suite, k int) ([]kyber.Point, error) {
+	gens := make([]kyber.Point, k)
+	for i := range gens {
+		var err error
+		if gens[i], err = hashToPoint(suite, "TCRED-GEN", []byte{byte(i >> 8), byte(i)}); err != nil {
+			return nil, err
+		}
+	}
+	return gens, nil
+}
+
+// credentialBase derives the common base h from the commitment cm.
+func credentialBase(suite pairing.Suite, cm kyber.Point) (kyber.Point, error) {
+	buf, err := cm.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hashToPoint(suite, "TCRED-BASE", buf)
+}
+
+// credentialChallenge derives the Fiat-Shamir challenge of a request from
+// its statement and the prover's commitments Cw, Aw_i and Bw_i.
+func credentialChallenge(suite pairing.Suite, r *CredentialRequest, h, Cw kyber.Point, Aw, Bw []kyber.Point) (kyber.Scalar, error) {
+	buf, err := appendCount(nil, len(r.A))
+	if err != nil {
+		return nil, err
+	}
+	points := []kyber.Point{r.Commitment, r.Gamma, h}
+	points = append(append(points, r.A...), r.B...)
+	points = append(append(append(points, Cw), Aw...), Bw...)
+	if buf, err = appendPoints(buf, points...); err != nil {
+		return nil, err
+	}
+	c, err := ps.HashMessages(suite, [][]byte{buf}, ps.WithDST(protocolTag(suite, "TCRED")))
+	if err != nil {
+		return nil, err
+	}
+	return c[0], nil
+}
+
+// pick draws a scalar from rand, refusing a missing source.
+func pick(suite pairing.Suite, rand cipher.Stream) (kyber.Scalar, error) {
+	if rand == nil {
+		return nil, ps.ErrEntropyFailure
+	}
+	return suite.G1().Scalar().Pick(rand), nil
+}
+
+// RequestCredential blinds the attributes msgs, which take slots 1 to
+// len(msgs) of the authorities' key, and returns the request for the
+// authorities along with the state to decrypt their answers. Randomness is
+// read from rand.
+func RequestCredential(suite pairing.Suite, msgs [][]byte, rand cipher.Stream, opts ...ps.Option) (*CredentialRequest, *CredentialState, error) {
+	m, err := ps.HashMessages(suite, msgs, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := len(m)
+	gens, err := credentialGenerators(suite, k)
+	if err != nil {
+		return nil, nil, err
+	}
+	// witness = (o, m_1,...,m_k, k_1,...,k_k), nonces alike, plus d
+	witness := make([]kyber.Scalar, 2*k+1)
+	nonces := make([]kyber.Scalar, 2*k+1)
+	for i := range nonces {
+		if nonces[i], err = pick(suite, rand); err != nil {
+			return nil, nil, err
+		}
+	}
+	if witness[0], err = pick(suite, rand); err != nil {
+		return nil, nil, err
+	}
+	copy(witness[1:], m)
+	for i := 0; i < k; i++ {
+		if witness[k+1+i], err = pick(suite, rand); err != nil {
+			return nil, nil, err
+		}
+	}
+	d, err := pick(suite, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g1 := suite.G1()
+	req := &CredentialRequest{suite: suite, Gamma: g1.Point().Mul(d, nil)}
+	req.Commitment = commitAttributes(suite, gens, witness[:k+1])
+	h, err := credentialBase(suite, req.Commitment)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.A, req.B = encryptAttributes(suite, req.Gamma, h, witness[1:k+1], witness[k+1:])
+
+	Cw := commitAttributes(suite, gens, nonces[:k+1])
+	Aw, Bw := encryptAttributes(suite, req.Gamma, h, nonces[1:k+1], nonces[k+1:])
+	if req.Challenge, err = credentialChallenge(suite, req, h, Cw, Aw, Bw); err != nil {
+		return nil, nil, err
+	}
+	req.Responses = make([]kyber.Scalar, len(nonces))
+	for i := range nonces {
+		req.Responses[i] = g1.Scalar().Mul(req.Challenge, witness[i])
+		req.Responses[i].Add(req.Responses[i], nonces[i])
+		nonces[i].Zero()
+	}
+
+	state := &CredentialState{
+		suite: suite,
+		d:     d,
+		h:     h,
+		msgs:  append([][]byte{}, msgs...),
+		opts:  append([]ps.Option{}, opts...),
+	}
+	return req, state, nil
+}
+
+// commitAttributes computes g^s_0 * h_1^(s_1) * ... * h_k^(s_k).
+func commitAttributes(suite pairing.Suite, gens []kyber.Point, s []kyber.Scalar) kyber.Point {
+	c := suite.G1().Point().Mul(s[0], nil)
+	for i, si := range s[1:] {
+		c.Add(c, suite.G1().Point().Mul(si, gens[i]))
+	}
+	return c
+}
+
+// encryptAttributes computes the pairs (g^(k_i), gamma^(k_i) * h^(m_i)).
+func encryptAttributes(suite pairing.Suite, gamma, h kyber.Point, m, k []kyber.Scalar) ([]kyber.Point, []kyber.Point) {
+	g1 := suite.G1()
+	A := make([]kyber.Point, len(m))
+	B := make([]kyber.Point, len(m))
+	for i := range m {
+		A[i] = g1.Point().Mul(k[i], nil)
+		B[i] = g1.Point().Mul(k[i], gamma)
+		B[i].Add(B[i], g1.Point().Mul(m[i], h))
+	}
+	return A, B
+}
+
+// IssueCredentialShare checks the proof of a credential request and answers
+// it with the authority's key share, without learning the attributes.
+func IssueCredentialShare(suite pairing.Suite, s *KeyShare, req *CredentialRequest) (*CredentialShare, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if s.suite.G1().String() != suite.G1().String() {
+		return nil, ps.ErrSuiteMismatch
+	}
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	k := len(req.A)
+	if k > len(s.Y) {
+		return nil, fmt.Errorf("%w: %d attributes need %d key components, got %d", ps.ErrKeyLengthMismatch, k, k+1, len(s.Y)+1)
+	}
+	g1 := suite.G1()
+	for _, p := range []kyber.Point{req.Commitment, req.Gamma} {
+		if p.Equal(g1.Point().Null()) {
+			return nil, fmt.Errorf("%w: identity point", ErrInvalidCredentialRequest)
+		}
+	}
+
+	gens, err := credentialGenerators(suite, k)
+	if err != nil {
+		return nil, err
+	}
+	h, err := credentialBase(suite, req.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	c, z := req.Challenge, req.Responses
+	// Cw = g^(z_o) * h_1^(z_m_1) * ... / cm^c, Aw_i = g^(z_k_i) / A_i^c and
+	// Bw_i = gamma^(z_k_i) * h^(z_m_i) / B_i^c
+	Cw := commitAttributes(suite, gens, z[:k+1])
+	Cw.Sub(Cw, g1.Point().Mul(c, req.Commitment))
+	Aw, Bw := encryptAttributes(suite, req.Gamma, h, z[1:k+1], z[k+1:])
+	for i := 0; i < k; i++ {
+		Aw[i].Sub(Aw[i], g1.Point().Mul(c, req.A[i]))
+		Bw[i].Sub(Bw[i], g1.Point().Mul(c, req.B[i]))
+	}
+	want, err := credentialChallenge(suite, req, h, Cw, Aw, Bw)
+	if err != nil {
+		return nil, err
+	}
+	if !want.Equal(c) {
+		return nil, fmt.Errorf("%w: proof does not verify", ErrInvalidCredentialRequest)
+	}
+
+	a := g1.Point().Null()
+	b := g1.Point().Mul(s.X, h)
+	for i := 0; i < k; i++ {
+		a.Add(a, g1.Point().Mul(s.Y[i], req.A[i]))
+		b.Add(b, g1.Point().Mul(s.Y[i], req.B[i]))
+	}
+	return &CredentialShare{suite: suite, Index: s.Index, T: s.T, H: h, A: a, B: b}, nil
+}
+
+// Decrypt turns an authority's answer into its signature share, which
+// VerifyShare checks against the authority's public share.
+func (st *CredentialState) Decrypt(s *CredentialShare) (*SigShare, error) {
+	if st == nil || st.d == nil {
+		return nil, errors.New("tps: nil credential state")
+	}
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if !s.H.Equal(st.h) {
+		return nil, fmt.Errorf("%w: authority %d answered another request", ErrInvalidShare, s.Index)
+	}
+	g1 := st.suite.G1()
+	sigma2 := g1.Point().Mul(st.d, s.A)
+	sigma2.Sub(s.B, sigma2)
+	return &SigShare{Index: s.Index, T: s.T, H: g1.Point().Set(st.h), Sigma2: sigma2}, nil
+}
+
+// AggregateCredential decrypts the authorities' answers and combines them
+// into the credential, a PS signature on the requested attributes under the
+// authorities' public key pubKey. It fails like CombineShares; Decrypt and
+// VerifyShare single out bad authorities.
+func AggregateCredential(suite pairing.Suite, state *CredentialState, pubKey *ps.PublicKey, shares []*CredentialShare) (*ps.Signature, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if state == nil {
+		return nil, errors.New("tps: nil credential state")
+	}
+	sigs := make([]*SigShare, len(shares))
+	for i, s := range shares {
+		var err error
+		if sigs[i], err = state.Decrypt(s); err != nil {
+			return nil, err
+		}
+	}
+	return CombineShares(suite, pubKey, sigs, state.msgs, state.opts...)
+}
+
+// ShowCredential proves possession of the credential cred on msgs under the
+// authorities' public key, disclosing the attributes whose index maps to true
+// in disclose. It is ps.ProveSignature, whose randomization makes shows
+// unlinkable to each other and to issuance.
+func ShowCredential(suite pairing.Suite, pubKey *ps.PublicKey, cred *ps.Signature, msgs [][]byte, disclose map[int]bool, nonce []byte, opts ...ps.Option) (*ps.SignatureProof, error) {
+	return ps.ProveSignature(suite, pubKey, cred, msgs, disclose, nonce, opts...)
+}
+
+// VerifyCredential checks a show of a credential under the authorities'
+// public key and the verifier's nonce, where disclosed maps the index of
+// every revealed attribute to its value. It is ps.VerifySignatureProof.
+func VerifyCredential(suite pairing.Suite, pubKey *ps.PublicKey, proof *ps.SignatureProof, disclosed map[int][]byte, nonce []byte, opts ...ps.Option) error {
+	return ps.VerifySignatureProof(suite, pubKey, proof, disclosed, nonce, opts...)
+}
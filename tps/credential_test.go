@@ -0,0 +1,144 @@
+package tps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestCredentialIssuance(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		const th, n = 3, 5
+		attrs := [][]byte{[]byte("name=Alice"), []byte("age=31"), []byte("country=FR")}
+		keys, pub, err := ThresholdKeyGen(suite, th, n, len(attrs), random.New())
+		require.Nil(t, err)
+
+		// The user blinds its attributes and sends the request to all
+		// authorities.
+		req, state, err := RequestCredential(suite, attrs, random.New())
+		require.Nil(t, err)
+		buf, err := req.MarshalBinary()
+		require.Nil(t, err)
+
+		// Authority 1 is offline; the others answer.
+		var answers []*CredentialShare
+		for j, key := range keys {
+			if j == 1 {
+				continue
+			}
+			received, err := UnmarshalCredentialRequest(suite, buf)
+			require.Nil(t, err)
+			share, err := IssueCredentialShare(suite, key, received)
+			require.Nil(t, err)
+			out, err := share.MarshalBinary()
+			require.Nil(t, err)
+			back, err := UnmarshalCredentialShare(suite, out)
+			require.Nil(t, err)
+			answers = append(answers, back)
+		}
+
+		// The user checks the answers and combines t of them.
+		for i, a := range answers {
+			s, err := state.Decrypt(a)
+			require.Nil(t, err)
+			require.Nil(t, VerifyShare(suite, keys[a.Index].Public(), s.H, attrs, s), "answer %d", i)
+		}
+		cred, err := AggregateCredential(suite, state, pub, answers[1:])
+		require.Nil(t, err)
+		S, err := cred.Components()
+		require.Nil(t, err)
+		require.Nil(t, ps.PSBatchVerify(suite, pub.Points(), attrs, S))
+		_, err = AggregateCredential(suite, state, pub, answers[:th-1])
+		require.True(t, errors.Is(err, ErrNotEnoughShares))
+
+		// The user shows its credential, disclosing its country only.
+		nonce := []byte("verifier session 1")
+		proof, err := ShowCredential(suite, pub, cred, attrs, map[int]bool{2: true}, nonce)
+		require.Nil(t, err)
+		out, err := proof.MarshalBinary()
+		require.Nil(t, err)
+		received, err := ps.UnmarshalSignatureProof(suite, out)
+		require.Nil(t, err)
+		disclosed := map[int][]byte{2: attrs[2]}
+		require.Nil(t, VerifyCredential(suite, pub, received, disclosed, nonce))
+		require.False(t, received.Sigma1.Equal(cred.Sigma1))
+
+		// A show bound to another nonce, or claiming another country, fails.
+		require.Equal(t, ps.ErrInvalidSignature, VerifyCredential(suite, pub, received, disclosed, []byte("verifier session 2")))
+		lie := map[int][]byte{2: []byte("country=NZ")}
+		require.Equal(t, ps.ErrInvalidSignature, VerifyCredential(suite, pub, received, lie, nonce))
+	})
+}
+
+func TestCredentialIssuanceInvalid(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		attrs := [][]byte{[]byte("name=Alice"), []byte("age=31")}
+		keys, pub, err := ThresholdKeyGen(suite, 2, 3, len(attrs), random.New())
+		require.Nil(t, err)
+		req, state, err := RequestCredential(suite, attrs, random.New())
+		require.Nil(t, err)
+
+		// Tampered requests do not verify.
+		forged := *req
+		forged.B = append([]kyber.Point{}, req.B...)
+		forged.B[1] = suite.G1().Point().Pick(random.New())
+		_, err = IssueCredentialShare(suite, keys[0], &forged)
+		require.True(t, errors.Is(err, ErrInvalidCredentialRequest))
+
+		forged = *req
+		forged.Responses = append([]kyber.Scalar{}, req.Responses...)
+		forged.Responses[0] = suite.G1().Scalar().Pick(random.New())
+		_, err = IssueCredentialShare(suite, keys[0], &forged)
+		require.True(t, errors.Is(err, ErrInvalidCredentialRequest))
+
+		forged = *req
+		forged.Gamma = suite.G1().Point().Null()
+		_, err = IssueCredentialShare(suite, keys[0], &forged)
+		require.True(t, errors.Is(err, ErrInvalidCredentialRequest))
+
+		// Too many attributes for the key.
+		long, _, err := RequestCredential(suite, append(attrs, []byte("extra")), random.New())
+		require.Nil(t, err)
+		_, err = IssueCredentialShare(suite, keys[0], long)
+		require.True(t, errors.Is(err, ps.ErrKeyLengthMismatch))
+
+		// A corrupted answer is caught by VerifyShare and spoils the
+		// combination.
+		good, err := IssueCredentialShare(suite, keys[0], req)
+		require.Nil(t, err)
+		bad, err := IssueCredentialShare(suite, keys[1], req)
+		require.Nil(t, err)
+		bad.B = suite.G1().Point().Pick(random.New())
+		s, err := state.Decrypt(bad)
+		require.Nil(t, err)
+		err = VerifyShare(suite, keys[1].Public(), s.H, attrs, s)
+		require.True(t, errors.Is(err, ErrInvalidShare))
+		_, err = AggregateCredential(suite, state, pub, []*CredentialShare{good, bad})
+		require.Equal(t, ps.ErrInvalidSignature, err)
+
+		// Answers to another request do not decrypt.
+		other, _, err := RequestCredential(suite, attrs, random.New())
+		require.Nil(t, err)
+		stray, err := IssueCredentialShare(suite, keys[2], other)
+		require.Nil(t, err)
+		_, err = state.Decrypt(stray)
+		require.True(t, errors.Is(err, ErrInvalidShare))
+
+		_, _, err = RequestCredential(suite, attrs, nil)
+		require.Equal(t, ps.ErrEntropyFailure, err)
+		_, _, err = RequestCredential(suite, nil, random.New())
+		require.Equal(t, ps.ErrNoMessages, err)
+
+		buf, err := req.MarshalBinary()
+		require.Nil(t, err)
+		_, err = UnmarshalCredentialRequest(suite, buf[:len(buf)-1])
+		require.True(t, errors.Is(err, ErrMalformed))
+		_, err = UnmarshalCredentialRequest(suite, append(buf, 0))
+		require.True(t, errors.Is(err, ErrMalformed))
+	})
+}
@@ -0,0 +1,241 @@
+package tps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+// ErrDKGMessage is returned for DKG messages that do not carry one entry per
+// key scalar from a single sender.
+var ErrDKGMessage = errors.New("tps: malformed DKG message")
+
+// dkgSuite runs kyber's DKG in G2, where PS public keys live, with the hash,
+// XOF and randomness of the pairing suite.
+type dkgSuite struct {
+	kyber.Group
+	suite pairing.Suite
+}
+
+func (s dkgSuite) Hash() hash.Hash             { return s.suite.Hash() }
+func (s dkgSuite) XOF(seed []byte) kyber.XOF   { return s.suite.XOF(seed) }
+func (s dkgSuite) RandomStream() cipher.Stream { return s.suite.RandomStream() }
+
+// DKG is one party's side of a distributed generation of a threshold PS
+// key, so that no dealer ever holds the whole key. It runs one Pedersen DKG
+// from kyber's share/dkg/pedersen per key scalar, x and each y_i, and bundles
+// their messages: every message below is a slice with one entry per
+// scalar, in key order.
+//
+// The rounds are those of the underlying DKG. Each party sends the deals
+// returned by Deals to their recipients, processes the deals it receives
+// with ProcessDeals and broadcasts the responses, then processes everybody
+// else's responses with ProcessResponses and, if complaints arise, their
+// justifications. Parties that stay silent are excluded once SetTimeout is
+// called. When Certified reports true, KeyShare returns the party's share
+// and the public key, which all parties obtain alike.
+type DKG struct {
+	suite pairing.Suite
+	index int
+	t     int
+	gens  []*dkg.DistKeyGenerator
+}
+
+// NewDKG sets up the generation of a key for attrs attributes, t of whose
+// shares can sign, among participants. Long-term keys are G2 points
+// g2^longterm, and the party's index is the position of its key in
+// participants.
+func NewDKG(suite pairing.Suite, longterm kyber.Scalar, participants []kyber.Point, t, attrs int) (*DKG, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if longterm == nil {
+		return nil, ps.ErrNilKey
+	}
+	n := len(participants)
+	if t < 1 || t > n {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidThreshold, t, n)
+	}
+	if n > maxShares {
+		return nil, fmt.Errorf("%w: %d signers exceed the limit of %d", ErrInvalidThreshold, n, maxShares)
+	}
+	if attrs < 1 {
+		return nil, fmt.Errorf("%w: %d attributes", ps.ErrKeyTooShort, attrs)
+	}
+	pub := suite.G2().Point().Mul(longterm, nil)
+	index := -1
+	for i, p := range participants {
+		if p != nil && p.Equal(pub) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("tps: long-term key not among the participants")
+	}
+	d := &DKG{suite: suite, index: index, t: t}
+	s := dkgSuite{Group: suite.G2(), suite: suite}
+	for k := 0; k <= attrs; k++ {
+		gen, err := dkg.NewDistKeyGenerator(s, longterm, participants, t)
+		if err != nil {
+			return nil, fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+		d.gens = append(d.gens, gen)
+	}
+	return d, nil
+}
+
+// Index returns the party's position among the participants, which is also
+// the index of its key share.
+func (d *DKG) Index() int {
+	return d.index
+}
+
+// Deals returns the deals for every other participant, keyed by its index.
+func (d *DKG) Deals() (map[int][]*dkg.Deal, error) {
+	out := make(map[int][]*dkg.Deal)
+	for k, gen := range d.gens {
+		deals, err := gen.Deals()
+		if err != nil {
+			return nil, fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+		for i, deal := range deals {
+			if len(out[i]) != k {
+				return nil, fmt.Errorf("tps: scalar %d: no deal for participant %d", k, i)
+			}
+			out[i] = append(out[i], deal)
+		}
+	}
+	return out, nil
+}
+
+// ProcessDeals processes the deals of one dealer and returns the responses
+// to broadcast to all participants.
+func (d *DKG) ProcessDeals(deals []*dkg.Deal) ([]*dkg.Response, error) {
+	if err := d.checkBundle(len(deals)); err != nil {
+		return nil, err
+	}
+	resps := make([]*dkg.Response, len(deals))
+	for k, deal := range deals {
+		if deal == nil || deal.Index != deals[0].Index {
+			return nil, fmt.Errorf("%w: deal %d", ErrDKGMessage, k)
+		}
+		resp, err := d.gens[k].ProcessDeal(deal)
+		if err != nil {
+			return nil, fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+		resps[k] = resp
+	}
+	return resps, nil
+}
+
+// ProcessResponses processes the responses broadcast by one participant.
+// The party's own responses are skipped, so all responses can be fed to
+// every party. If a response is a complaint against this party's deal, the
+// justifications to broadcast are returned; otherwise the result is nil.
+func (d *DKG) ProcessResponses(resps []*dkg.Response) ([]*dkg.Justification, error) {
+	if err := d.checkBundle(len(resps)); err != nil {
+		return nil, err
+	}
+	var (
+		justs     []*dkg.Justification
+		complaint bool
+	)
+	for k, resp := range resps {
+		if resp == nil || resp.Response == nil || resp.Response.Index != resps[0].Response.Index {
+			return nil, fmt.Errorf("%w: response %d", ErrDKGMessage, k)
+		}
+		if int(resp.Response.Index) == d.index {
+			justs = append(justs, nil)
+			continue
+		}
+		j, err := d.gens[k].ProcessResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+		justs = append(justs, j)
+		complaint = complaint || j != nil
+	}
+	if !complaint {
+		return nil, nil
+	}
+	return justs, nil
+}
+
+// ProcessJustifications processes the justifications broadcast by a dealer.
+// Entries are nil for the scalars without complaints.
+func (d *DKG) ProcessJustifications(justs []*dkg.Justification) error {
+	if err := d.checkBundle(len(justs)); err != nil {
+		return err
+	}
+	for k, j := range justs {
+		if j == nil {
+			continue
+		}
+		if err := d.gens[k].ProcessJustification(j); err != nil {
+			return fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// SetTimeout ends the waiting for responses: participants that did not
+// answer are counted as complaining, which excludes dealers that went
+// offline while keeping the generation alive with the others.
+func (d *DKG) SetTimeout() {
+	for _, gen := range d.gens {
+		gen.SetTimeout()
+	}
+}
+
+// Certified reports whether every generation has at least t qualified
+// dealers, so that KeyShare can succeed. Before SetTimeout a dealer only
+// qualifies once every participant approved its deal.
+func (d *DKG) Certified() bool {
+	for _, gen := range d.gens {
+		if !gen.ThresholdCertified() {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyShare returns the party's share of the generated key, usable with
+// SignShare, and the public key, which is the same for all parties.
+func (d *DKG) KeyShare() (*KeyShare, *ps.PublicKey, error) {
+	s := &KeyShare{suite: d.suite, Index: d.index, T: d.t}
+	points := make([]kyber.Point, len(d.gens))
+	for k, gen := range d.gens {
+		dks, err := gen.DistKeyShare()
+		if err != nil {
+			return nil, nil, fmt.Errorf("tps: scalar %d: %w", k, err)
+		}
+		if dks.Share.I != d.index {
+			return nil, nil, fmt.Errorf("tps: scalar %d: share %d for participant %d", k, dks.Share.I, d.index)
+		}
+		if k == 0 {
+			s.X = dks.Share.V
+		} else {
+			s.Y = append(s.Y, dks.Share.V)
+		}
+		points[k] = dks.Public()
+	}
+	pub, err := ps.NewPublicKey(d.suite, points)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, pub, nil
+}
+
+// checkBundle ensures a message carries one entry per key scalar.
+func (d *DKG) checkBundle(n int) error {
+	if n != len(d.gens) {
+		return fmt.Errorf("%w: %d entries for %d scalars", ErrDKGMessage, n, len(d.gens))
+	}
+	return nil
+}
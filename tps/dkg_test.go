@@ -0,0 +1,158 @@
+package tps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// runDKG simulates a distributed generation among n parties in process.
+// Parties listed in offline never send or receive anything.
+func runDKG(t *testing.T, suite pairing.Suite, th, n, attrs int, offline map[int]bool) ([]*KeyShare, []*ps.PublicKey) {
+	longterms := make([]kyber.Scalar, n)
+	participants := make([]kyber.Point, n)
+	for i := range longterms {
+		longterms[i] = suite.G2().Scalar().Pick(random.New())
+		participants[i] = suite.G2().Point().Mul(longterms[i], nil)
+	}
+	parties := make([]*DKG, n)
+	for i := range parties {
+		if offline[i] {
+			continue
+		}
+		d, err := NewDKG(suite, longterms[i], participants, th, attrs)
+		require.Nil(t, err)
+		require.Equal(t, i, d.Index())
+		parties[i] = d
+	}
+
+	var resps [][]*dkg.Response
+	for _, dealer := range parties {
+		if dealer == nil {
+			continue
+		}
+		deals, err := dealer.Deals()
+		require.Nil(t, err)
+		require.Len(t, deals, n-1)
+		for i, bundle := range deals {
+			if parties[i] == nil {
+				continue
+			}
+			r, err := parties[i].ProcessDeals(bundle)
+			require.Nil(t, err)
+			resps = append(resps, r)
+		}
+	}
+	for _, r := range resps {
+		for _, p := range parties {
+			if p == nil {
+				continue
+			}
+			justs, err := p.ProcessResponses(r)
+			require.Nil(t, err)
+			require.Nil(t, justs)
+		}
+	}
+
+	var shares []*KeyShare
+	var pubs []*ps.PublicKey
+	for _, p := range parties {
+		if p == nil {
+			continue
+		}
+		if len(offline) > 0 {
+			require.False(t, p.Certified())
+			p.SetTimeout()
+		}
+		require.True(t, p.Certified())
+		s, pub, err := p.KeyShare()
+		require.Nil(t, err)
+		shares = append(shares, s)
+		pubs = append(pubs, pub)
+	}
+	return shares, pubs
+}
+
+func TestDKG(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		const th, n = 3, 5
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		shares, pubs := runDKG(t, suite, th, n, len(msgs), map[int]bool{2: true})
+		require.Len(t, shares, n-1)
+
+		// All parties agree on the public key.
+		pub := pubs[0]
+		for _, other := range pubs[1:] {
+			require.True(t, other.X.Equal(pub.X))
+			for i := range pub.Y {
+				require.True(t, other.Y[i].Equal(pub.Y[i]))
+			}
+		}
+
+		// Any t of the online parties issue signatures.
+		h, err := MessageBase(suite, msgs)
+		require.Nil(t, err)
+		for _, subset := range [][]int{{0, 1, 2}, {1, 2, 3}, {0, 3, 1}} {
+			var sigs []*SigShare
+			for _, j := range subset {
+				s, err := SignShare(suite, shares[j], h, msgs)
+				require.Nil(t, err)
+				require.Nil(t, VerifyShare(suite, shares[j].Public(), h, msgs, s))
+				sigs = append(sigs, s)
+			}
+			sig, err := CombineShares(suite, pub, sigs, msgs)
+			require.Nil(t, err, "%v", subset)
+			S, err := sig.Components()
+			require.Nil(t, err)
+			require.Nil(t, ps.PSBatchVerify(suite, pub.Points(), msgs, S))
+		}
+
+		// The shares interpolate to the key behind the public key.
+		priv := recoverKey(t, suite, shares[:th], th, n)
+		require.True(t, priv.Public().X.Equal(pub.X))
+	})
+}
+
+func TestDKGAllOnline(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	shares, pubs := runDKG(t, suite, 2, 3, 1, nil)
+	require.Len(t, shares, 3)
+	priv := recoverKey(t, suite, shares[1:], 2, 3)
+	require.True(t, priv.Public().Y[0].Equal(pubs[0].Y[0]))
+}
+
+func TestDKGInvalid(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	longterm := suite.G2().Scalar().Pick(random.New())
+	participants := []kyber.Point{
+		suite.G2().Point().Mul(longterm, nil),
+		suite.G2().Point().Pick(random.New()),
+		suite.G2().Point().Pick(random.New()),
+	}
+	_, err := NewDKG(suite, longterm, participants, 4, 2)
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+	_, err = NewDKG(suite, longterm, participants, 2, 0)
+	require.True(t, errors.Is(err, ps.ErrKeyTooShort))
+	_, err = NewDKG(suite, longterm, participants[1:], 2, 2)
+	require.NotNil(t, err)
+	_, err = NewDKG(nil, longterm, participants, 2, 2)
+	require.Equal(t, ps.ErrNilSuite, err)
+
+	d, err := NewDKG(suite, longterm, participants, 2, 2)
+	require.Nil(t, err)
+	require.False(t, d.Certified())
+	_, _, err = d.KeyShare()
+	require.NotNil(t, err)
+	_, err = d.ProcessDeals(make([]*dkg.Deal, 2))
+	require.True(t, errors.Is(err, ErrDKGMessage))
+	_, err = d.ProcessDeals(make([]*dkg.Deal, 3))
+	require.True(t, errors.Is(err, ErrDKGMessage))
+	_, err = d.ProcessResponses(nil)
+	require.True(t, errors.Is(err, ErrDKGMessage))
+}
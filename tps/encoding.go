@@ -0,0 +1,145 @@
+package tps
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Encodings of this package share the layout of package ps: a header with the
+// format version and the suite identifier, then 16-bit big-endian counts and
+// fixed-size points and scalars. Points are checked to lie in the
+// prime-order subgroup when decoded.
+
+// ErrMalformed is returned for encodings that cannot be decoded.
+var ErrMalformed = errors.New("tps: malformed encoding")
+
+const headerLen = 3
+
+// writeHeader starts an encoding under suite.
+func writeHeader(suite pairing.Suite) ([]byte, error) {
+	id, err := ps.SuiteIDOf(suite)
+	if err != nil {
+		return nil, err
+	}
+	return []byte{ps.FormatMajor, ps.FormatMinor, byte(id)}, nil
+}
+
+// readHeader checks the header of an encoding and returns its suite and
+// body. A nil suite is looked up in the registry of package ps, otherwise
+// the encoding must carry its identifier.
+func readHeader(suite pairing.Suite, data []byte) (pairing.Suite, []byte, error) {
+	if len(data) < headerLen {
+		return nil, nil, fmt.Errorf("%w: truncated header", ErrMalformed)
+	}
+	if data[0] != ps.FormatMajor {
+		return nil, nil, fmt.Errorf("%w: %d.%d", ps.ErrUnsupportedVersion, data[0], data[1])
+	}
+	id := ps.SuiteID(data[2])
+	got, err := ps.SuiteByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if suite != nil && suite.G1().String() != got.G1().String() {
+		return nil, nil, fmt.Errorf("%w: encoding for %s", ps.ErrSuiteMismatch, ps.SuiteName(id))
+	}
+	return got, data[headerLen:], nil
+}
+
+func appendCount(buf []byte, n int) ([]byte, error) {
+	if n < 0 || n > 0xffff {
+		return nil, fmt.Errorf("tps: count %d exceeds the encoding limit", n)
+	}
+	return append(buf, byte(n>>8), byte(n)), nil
+}
+
+func readCount(data []byte) (int, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, fmt.Errorf("%w: truncated count", ErrMalformed)
+	}
+	return int(binary.BigEndian.Uint16(data)), data[2:], nil
+}
+
+func appendPoints(buf []byte, points ...kyber.Point) ([]byte, error) {
+	for _, p := range points {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// decodePoints reads n points of g off data and returns the remaining bytes.
+func decodePoints(g kyber.Group, data []byte, n int) ([]kyber.Point, []byte, error) {
+	size := g.PointLen()
+	if len(data) < n*size {
+		return nil, nil, fmt.Errorf("%w: %d bytes for %d points", ErrMalformed, len(data), n)
+	}
+	points := make([]kyber.Point, n)
+	for i := range points {
+		points[i] = g.Point()
+		if err := points[i].UnmarshalBinary(data[i*size : (i+1)*size]); err != nil {
+			return nil, nil, fmt.Errorf("%w: point %d: %v", ErrMalformed, i, err)
+		}
+		if err := checkSubgroup(g, points[i]); err != nil {
+			return nil, nil, fmt.Errorf("%w: point %d", err, i)
+		}
+	}
+	return points, data[n*size:], nil
+}
+
+// checkSubgroup reports whether p lies in the prime-order subgroup of g, as
+// the function of the same name in package ps does.
+func checkSubgroup(g kyber.Group, p kyber.Point) error {
+	minusOne := g.Scalar().One()
+	minusOne.Neg(minusOne)
+	if !g.Point().Mul(minusOne, p).Equal(g.Point().Neg(p)) {
+		return ps.ErrInvalidPoint
+	}
+	return nil
+}
+
+// appendScalars encodes the scalars, zeroing the temporary encodings.
+func appendScalars(buf []byte, scalars ...kyber.Scalar) ([]byte, error) {
+	for _, s := range scalars {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+		for i := range b {
+			b[i] = 0
+		}
+	}
+	return buf, nil
+}
+
+// decodeScalars reads n scalars of g off data and returns the remaining
+// bytes.
+func decodeScalars(g kyber.Group, data []byte, n int) ([]kyber.Scalar, []byte, error) {
+	size := g.ScalarLen()
+	if len(data) < n*size {
+		return nil, nil, fmt.Errorf("%w: %d bytes for %d scalars", ErrMalformed, len(data), n)
+	}
+	scalars := make([]kyber.Scalar, n)
+	for i := range scalars {
+		scalars[i] = g.Scalar()
+		if err := scalars[i].UnmarshalBinary(data[i*size : (i+1)*size]); err != nil {
+			return nil, nil, fmt.Errorf("%w: scalar %d: %v", ErrMalformed, i, err)
+		}
+	}
+	return scalars, data[n*size:], nil
+}
+
+func checkTrailing(rest []byte) error {
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrMalformed, len(rest))
+	}
+	return nil
+}
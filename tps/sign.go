@@ -0,0 +1,246 @@
+package tps
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var (
+	// ErrInvalidShare is returned by VerifyShare for a signature share that
+	// does not match the signer's public share.
+	ErrInvalidShare = errors.New("tps: invalid signature share")
+
+	// ErrNotEnoughShares is returned when combining fewer signature shares
+	// than the threshold.
+	ErrNotEnoughShares = errors.New("tps: not enough signature shares")
+)
+
+// PublicShare is the verification key of one signer: the PS public key of
+// its KeyShare, (g2^x_j, g2^y_1j, ..., g2^y_rj).
+type PublicShare struct {
+	Index int
+	Key   *ps.PublicKey
+}
+
+// Public returns the verification key of the share, or nil if the share is
+// incomplete.
+func (s *KeyShare) Public() *PublicShare {
+	if s.check() != nil {
+		return nil
+	}
+	g2 := s.suite.G2()
+	points := []kyber.Point{g2.Point().Mul(s.X, nil)}
+	for _, y := range s.Y {
+		points = append(points, g2.Point().Mul(y, nil))
+	}
+	key, err := ps.NewPublicKey(s.suite, points)
+	if err != nil {
+		return nil
+	}
+	return &PublicShare{Index: s.Index, Key: key}
+}
+
+// SigShare is one signer's contribution to a threshold signature:
+// Sigma2 = H^(x_j + y_1j*m_1 + ... + y_rj*m_r) on the common base H.
+type SigShare struct {
+	Index  int
+	T      int
+	H      kyber.Point
+	Sigma2 kyber.Point
+}
+
+// MessageBase derives the common base h of a threshold signature on msgs by
+// hashing the message scalars to G1. Signers must not share a base across
+// different message sets, since two signatures on the same h combine into
+// signatures on other messages; deriving it from the messages, as Coconut
+// does, rules that out without a round of agreement. The suite's G1 points
+// must hash to the group, as those of bn256 and bls12381 do.
+func MessageBase(suite pairing.Suite, msgs [][]byte, opts ...ps.Option) (kyber.Point, error) {
+	m, err := ps.HashMessages(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := appendScalars(nil, m...)
+	if err != nil {
+		return nil, err
+	}
+	return hashToPoint(suite, "TBASE", buf)
+}
+
+// protocolTag returns "PS-" || protocol || "-" || curve || "-V1", the tags
+// of package ps for the protocols of this package.
+func protocolTag(suite pairing.Suite, protocol string) []byte {
+	curve := strings.TrimSuffix(suite.G1().String(), ".G1")
+	return []byte("PS-" + protocol + "-" + strings.ToUpper(curve) + "-V1")
+}
+
+// hashablePoint is a point that hashes to its group.
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// hashToPoint maps data to a point of G1 with unknown discrete logarithm,
+// under the tag of protocol.
+func hashToPoint(suite pairing.Suite, protocol string, data []byte) (kyber.Point, error) {
+	hashable, ok := suite.G1().Point().(hashablePoint)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s cannot hash to points", ps.ErrIncompatibleSuite, suite.G1())
+	}
+	return hashable.Hash(append(protocolTag(suite, protocol), data...)), nil
+}
+
+// SignShare signs msgs with the key share s on the common base h, usually
+// obtained from MessageBase. The messages take the first len(msgs) key
+// slots, as in ps.BatchSign.
+func SignShare(suite pairing.Suite, s *KeyShare, h kyber.Point, msgs [][]byte, opts ...ps.Option) (*SigShare, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if s.suite.G1().String() != suite.G1().String() {
+		return nil, ps.ErrSuiteMismatch
+	}
+	if err := checkBase(suite, h); err != nil {
+		return nil, err
+	}
+	if len(msgs) > len(s.Y) {
+		return nil, fmt.Errorf("%w: %d messages need %d key components, got %d", ps.ErrKeyLengthMismatch, len(msgs), len(msgs)+1, len(s.Y)+1)
+	}
+	m, err := ps.HashMessages(suite, msgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	e := suite.G1().Scalar().Set(s.X)
+	for i := range m {
+		e.Add(e, m[i].Mul(m[i], s.Y[i]))
+	}
+	return &SigShare{
+		Index:  s.Index,
+		T:      s.T,
+		H:      suite.G1().Point().Set(h),
+		Sigma2: suite.G1().Point().Mul(e, h),
+	}, nil
+}
+
+// checkBase rejects a missing or identity base, on which every share would
+// verify.
+func checkBase(suite pairing.Suite, h kyber.Point) error {
+	if h == nil || h.Equal(suite.G1().Point().Null()) {
+		return fmt.Errorf("%w: base", ps.ErrInvalidPoint)
+	}
+	return nil
+}
+
+// VerifyShare checks a signature share on msgs and the base h against the
+// public share of its signer, so that bad shares can be singled out before
+// CombineShares. It returns ErrInvalidShare for a share that does not verify.
+func VerifyShare(suite pairing.Suite, pub *PublicShare, h kyber.Point, msgs [][]byte, sig *SigShare, opts ...ps.Option) error {
+	if suite == nil {
+		return ps.ErrNilSuite
+	}
+	if pub == nil || pub.Key == nil {
+		return ps.ErrNilKey
+	}
+	if sig == nil || sig.H == nil || sig.Sigma2 == nil {
+		return ps.ErrNilSignature
+	}
+	if err := checkBase(suite, h); err != nil {
+		return err
+	}
+	if sig.Index != pub.Index {
+		return fmt.Errorf("%w: share of signer %d checked against signer %d", ErrInvalidShare, sig.Index, pub.Index)
+	}
+	if !sig.H.Equal(h) {
+		return fmt.Errorf("%w: signer %d used another base", ErrInvalidShare, sig.Index)
+	}
+	S, err := components(sig.H, sig.Sigma2)
+	if err != nil {
+		return err
+	}
+	err = ps.PSBatchVerify(suite, pub.Key.Points(), msgs, S, opts...)
+	if errors.Is(err, ps.ErrInvalidSignature) || errors.Is(err, ps.ErrInvalidPoint) {
+		return fmt.Errorf("%w: signer %d", ErrInvalidShare, sig.Index)
+	}
+	return err
+}
+
+// CombineShares interpolates the signature shares in the exponent into the
+// PS signature (h, h^(x + y_1*m_1 + ... + y_r*m_r)) of the shared key. It
+// needs at least T shares from distinct signers on the same base and uses
+// the first T of them. The result is checked against pubKey and msgs, and
+// ps.ErrInvalidSignature means a share was bad; VerifyShare tells which.
+func CombineShares(suite pairing.Suite, pubKey *ps.PublicKey, shares []*SigShare, msgs [][]byte, opts ...ps.Option) (*ps.Signature, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	if pubKey == nil {
+		return nil, ps.ErrNilKey
+	}
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	var (
+		t      int
+		h      kyber.Point
+		seen   = make(map[int]bool)
+		points []*share.PubShare
+	)
+	for i, s := range shares {
+		if s == nil || s.H == nil || s.Sigma2 == nil {
+			return nil, fmt.Errorf("%w: share %d", ps.ErrNilSignature, i)
+		}
+		if i == 0 {
+			t, h = s.T, s.H
+		}
+		if s.T != t || !s.H.Equal(h) {
+			return nil, fmt.Errorf("%w: share %d belongs to another signing session", ErrInvalidShare, i)
+		}
+		if s.Index < 0 || s.Index >= maxShares {
+			return nil, fmt.Errorf("%w: share index %d", ErrInvalidShare, s.Index)
+		}
+		if seen[s.Index] {
+			continue
+		}
+		seen[s.Index] = true
+		points = append(points, &share.PubShare{I: s.Index, V: s.Sigma2})
+	}
+	if t < 1 || len(points) < t {
+		return nil, fmt.Errorf("%w: %d of %d", ErrNotEnoughShares, len(points), t)
+	}
+	if err := checkBase(suite, h); err != nil {
+		return nil, err
+	}
+	sigma2, err := share.RecoverCommit(suite.G1(), points[:t], t, maxShares)
+	if err != nil {
+		return nil, err
+	}
+	S, err := components(h, sigma2)
+	if err != nil {
+		return nil, err
+	}
+	if err := ps.PSBatchVerify(suite, pubKey.Points(), msgs, S, opts...); err != nil {
+		return nil, err
+	}
+	return ps.NewSignature(suite, S)
+}
+
+// components encodes a signature in the [][]byte layout of package ps.
+func components(sigma1, sigma2 kyber.Point) ([][]byte, error) {
+	b1, err := sigma1.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b2, err := sigma2.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{b1, b2}, nil
+}
@@ -0,0 +1,131 @@
+package tps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestThresholdSign(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		const th, n = 3, 5
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		shares, pub, err := ThresholdKeyGen(suite, th, n, len(msgs), random.New())
+		require.Nil(t, err)
+		h, err := MessageBase(suite, msgs)
+		require.Nil(t, err)
+
+		sigs := make([]*SigShare, n)
+		for j, s := range shares {
+			sigs[j], err = SignShare(suite, s, h, msgs)
+			require.Nil(t, err)
+			require.Nil(t, VerifyShare(suite, s.Public(), h, msgs, sigs[j]))
+		}
+
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 2, 3, 4}} {
+			var picked []*SigShare
+			for _, j := range subset {
+				picked = append(picked, sigs[j])
+			}
+			sig, err := CombineShares(suite, pub, picked, msgs)
+			require.Nil(t, err, "%v", subset)
+			S, err := sig.Components()
+			require.Nil(t, err)
+			require.Nil(t, ps.PSBatchVerify(suite, pub.Points(), msgs, S))
+			require.True(t, sig.Sigma1.Equal(h))
+		}
+
+		// Fewer than t shares, counting repeated signers once.
+		_, err = CombineShares(suite, pub, sigs[:th-1], msgs)
+		require.True(t, errors.Is(err, ErrNotEnoughShares))
+		_, err = CombineShares(suite, pub, []*SigShare{sigs[0], sigs[1], sigs[1]}, msgs)
+		require.True(t, errors.Is(err, ErrNotEnoughShares))
+	})
+}
+
+func TestThresholdSignMaliciousShare(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		const th, n = 3, 5
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+		shares, pub, err := ThresholdKeyGen(suite, th, n, len(msgs), random.New())
+		require.Nil(t, err)
+		h, err := MessageBase(suite, msgs)
+		require.Nil(t, err)
+
+		sigs := make([]*SigShare, n)
+		for j, s := range shares {
+			sigs[j], err = SignShare(suite, s, h, msgs)
+			require.Nil(t, err)
+		}
+		// Signer 1 sends garbage.
+		bad := *sigs[1]
+		bad.Sigma2 = suite.G1().Point().Pick(random.New())
+		sigs[1] = &bad
+
+		_, err = CombineShares(suite, pub, sigs[:th], msgs)
+		require.Equal(t, ps.ErrInvalidSignature, err)
+
+		// VerifyShare singles it out, and the remaining shares suffice.
+		var good []*SigShare
+		for j, s := range sigs {
+			err := VerifyShare(suite, shares[j].Public(), h, msgs, s)
+			if j == 1 {
+				require.True(t, errors.Is(err, ErrInvalidShare))
+				continue
+			}
+			require.Nil(t, err)
+			good = append(good, s)
+		}
+		_, err = CombineShares(suite, pub, good, msgs)
+		require.Nil(t, err)
+
+		// A share checked against another signer, on another base, or on
+		// other messages.
+		err = VerifyShare(suite, shares[2].Public(), h, msgs, sigs[0])
+		require.True(t, errors.Is(err, ErrInvalidShare))
+		other, err := MessageBase(suite, msgs[:1])
+		require.Nil(t, err)
+		err = VerifyShare(suite, shares[0].Public(), other, msgs, sigs[0])
+		require.True(t, errors.Is(err, ErrInvalidShare))
+		err = VerifyShare(suite, shares[0].Public(), h, msgs[:1], sigs[0])
+		require.True(t, errors.Is(err, ErrInvalidShare))
+
+		// Shares from different sessions do not mix.
+		s, err := SignShare(suite, shares[3], other, msgs)
+		require.Nil(t, err)
+		_, err = CombineShares(suite, pub, []*SigShare{sigs[0], sigs[2], s}, msgs)
+		require.True(t, errors.Is(err, ErrInvalidShare))
+	})
+}
+
+func TestSignShareInvalid(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+	shares, _, err := ThresholdKeyGen(suite, 2, 3, 1, random.New())
+	require.Nil(t, err)
+	h, err := MessageBase(suite, msgs)
+	require.Nil(t, err)
+
+	_, err = SignShare(suite, shares[0], h, msgs)
+	require.True(t, errors.Is(err, ps.ErrKeyLengthMismatch))
+	_, err = SignShare(suite, shares[0], suite.G1().Point().Null(), msgs[:1])
+	require.True(t, errors.Is(err, ps.ErrInvalidPoint))
+	_, err = SignShare(suite, shares[0], nil, msgs[:1])
+	require.True(t, errors.Is(err, ps.ErrInvalidPoint))
+	_, err = SignShare(suite, shares[0], h, nil)
+	require.Equal(t, ps.ErrNoMessages, err)
+	_, err = SignShare(nil, shares[0], h, msgs[:1])
+	require.Equal(t, ps.ErrNilSuite, err)
+	_, err = SignShare(suite, nil, h, msgs[:1])
+	require.Equal(t, ps.ErrNilKey, err)
+
+	var nilShare *KeyShare
+	require.Nil(t, nilShare.Public())
+	_, err = CombineShares(suite, nil, nil, msgs)
+	require.Equal(t, ps.ErrNilKey, err)
+	require.Equal(t, ps.ErrNilSignature, VerifyShare(suite, shares[0].Public(), h, msgs, nil))
+}
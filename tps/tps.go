@@ -0,0 +1,193 @@
+// Package tps implements threshold PS signatures: the issuance key is shared
+// among n signers with Shamir's scheme, so that any t of them can sign
+// together while fewer learn nothing about it.
+//
+// Every scalar of a PS key, x and each y_i, is shared independently with a
+// random polynomial of degree t-1 from kyber's share package, whose constant
+// term is the scalar. Signer j holds the evaluations at j+1 of all these
+// polynomials, its KeyShare. The public key is that of the shared scalars and
+// is indistinguishable from one made by ps.NewKeyPair.
+//
+// To sign, every signer raises a common base h, derived from the messages
+// with MessageBase, to its share of x + y_1*m_1 + ... + y_r*m_r. Any t such
+// SigShares interpolate in the exponent into an ordinary PS signature, which
+// ps.PSBatchVerify accepts under the public key.
+package tps
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/bithinalangot/ps"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// ErrInvalidThreshold is returned for thresholds outside 1..n.
+var ErrInvalidThreshold = errors.New("tps: invalid threshold")
+
+// KeyShare is one signer's share of a threshold PS key: the evaluations at
+// Index+1 of the polynomials sharing x and y_1,...,y_r. Index counts from 0,
+// as in kyber's share package, and T is the number of shares needed to sign.
+type KeyShare struct {
+	suite pairing.Suite
+	Index int
+	T     int
+	X     kyber.Scalar
+	Y     []kyber.Scalar
+}
+
+// ThresholdKeyGen deals a PS key for attrs attributes among n signers, any
+// t of which can sign. It returns the key share of every signer, in index
+// order, and the public key. The dealer learns the whole key and must
+// forget it once the shares are handed out; NewDKG generates shares without
+// a dealer.
+func ThresholdKeyGen(suite pairing.Suite, t, n, attrs int, rand cipher.Stream) ([]*KeyShare, *ps.PublicKey, error) {
+	if suite == nil {
+		return nil, nil, ps.ErrNilSuite
+	}
+	if t < 1 || t > n {
+		return nil, nil, fmt.Errorf("%w: %d of %d", ErrInvalidThreshold, t, n)
+	}
+	if n > maxShares {
+		return nil, nil, fmt.Errorf("%w: %d signers exceed the limit of %d", ErrInvalidThreshold, n, maxShares)
+	}
+	if attrs < 1 {
+		return nil, nil, fmt.Errorf("%w: %d attributes", ps.ErrKeyTooShort, attrs)
+	}
+	if rand == nil {
+		return nil, nil, ps.ErrEntropyFailure
+	}
+
+	shares := make([]*KeyShare, n)
+	for j := range shares {
+		shares[j] = &KeyShare{suite: suite, Index: j, T: t}
+	}
+	points := make([]kyber.Point, attrs+1)
+	for c := range points {
+		poly := share.NewPriPoly(suite.G1(), t, nil, rand)
+		points[c] = suite.G2().Point().Mul(poly.Secret(), nil)
+		for j, s := range poly.Shares(n) {
+			if c == 0 {
+				shares[j].X = s.V
+			} else {
+				shares[j].Y = append(shares[j].Y, s.V)
+			}
+		}
+	}
+	pub, err := ps.NewPublicKey(suite, points)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shares, pub, nil
+}
+
+// check reports whether the share is complete.
+func (s *KeyShare) check() error {
+	if s == nil || s.X == nil || len(s.Y) == 0 {
+		return ps.ErrNilKey
+	}
+	if s.suite == nil {
+		return ps.ErrNilSuite
+	}
+	if s.Index < 0 || s.Index >= maxShares {
+		return fmt.Errorf("%w: share index %d", ErrInvalidThreshold, s.Index)
+	}
+	if s.T < 1 || s.T > maxShares {
+		return fmt.Errorf("%w: threshold %d", ErrInvalidThreshold, s.T)
+	}
+	for i, y := range s.Y {
+		if y == nil {
+			return fmt.Errorf("%w: scalar %d", ps.ErrNilKey, i+1)
+		}
+	}
+	return nil
+}
+
+// Suite returns the pairing suite the share belongs to.
+func (s *KeyShare) Suite() pairing.Suite {
+	if s == nil {
+		return nil
+	}
+	return s.suite
+}
+
+// PriShares returns the share of x followed by those of y_1,...,y_r, in the
+// form used by share.RecoverSecret.
+func (s *KeyShare) PriShares() []*share.PriShare {
+	if s == nil {
+		return nil
+	}
+	shares := []*share.PriShare{{I: s.Index, V: s.X}}
+	for _, y := range s.Y {
+		shares = append(shares, &share.PriShare{I: s.Index, V: y})
+	}
+	return shares
+}
+
+// maxShares bounds share indices so that they fit the encoding.
+const maxShares = 0xffff
+
+// MarshalBinary encodes the share as header || index || t || r || x ||
+// y_1 || ... || y_r, see the encoding notes of this package.
+func (s *KeyShare) MarshalBinary() ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	buf, err := writeHeader(s.suite)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range []int{s.Index, s.T, len(s.Y)} {
+		if buf, err = appendCount(buf, n); err != nil {
+			return nil, err
+		}
+	}
+	return appendScalars(buf, append([]kyber.Scalar{s.X}, s.Y...)...)
+}
+
+// UnmarshalBinary decodes a share. If the share already has a suite the
+// encoding must carry its identifier, otherwise the suite is looked up in the
+// registry of package ps.
+func (s *KeyShare) UnmarshalBinary(data []byte) error {
+	if s == nil {
+		return ps.ErrNilKey
+	}
+	suite, body, err := readHeader(s.suite, data)
+	if err != nil {
+		return err
+	}
+	counts := make([]int, 3)
+	for i := range counts {
+		if counts[i], body, err = readCount(body); err != nil {
+			return err
+		}
+	}
+	scalars, rest, err := decodeScalars(suite.G1(), body, counts[2]+1)
+	if err != nil {
+		return err
+	}
+	if err := checkTrailing(rest); err != nil {
+		return err
+	}
+	dec := &KeyShare{suite: suite, Index: counts[0], T: counts[1], X: scalars[0], Y: scalars[1:]}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*s = *dec
+	return nil
+}
+
+// UnmarshalKeyShare decodes a key share produced under suite.
+func UnmarshalKeyShare(suite pairing.Suite, data []byte) (*KeyShare, error) {
+	if suite == nil {
+		return nil, ps.ErrNilSuite
+	}
+	s := &KeyShare{suite: suite}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,151 @@
+package tps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bithinalangot/ps"
+	"github.com/bithinalangot/ps/bls12381"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func forEachSuite(t *testing.T, f func(t *testing.T, suite pairing.Suite)) {
+	for name, suite := range map[string]pairing.Suite{
+		"bn256":    pairing.NewSuiteBn256(),
+		"bls12381": bls12381.NewSuite(),
+	} {
+		t.Run(name, func(t *testing.T) { f(t, suite) })
+	}
+}
+
+// recoverKey interpolates the private key from the given shares.
+func recoverKey(t *testing.T, suite pairing.Suite, shares []*KeyShare, th, n int) *ps.PrivateKey {
+	components := make([][]*share.PriShare, len(shares[0].Y)+1)
+	for _, s := range shares {
+		for c, sh := range s.PriShares() {
+			components[c] = append(components[c], sh)
+		}
+	}
+	scalars := make([]kyber.Scalar, len(components))
+	for c := range components {
+		secret, err := share.RecoverSecret(suite.G1(), components[c], th, n)
+		require.Nil(t, err)
+		scalars[c] = secret
+	}
+	priv, err := ps.NewPrivateKey(suite, scalars)
+	require.Nil(t, err)
+	return priv
+}
+
+func TestThresholdKeyGen(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		const th, n, attrs = 3, 5, 2
+		shares, pub, err := ThresholdKeyGen(suite, th, n, attrs, random.New())
+		require.Nil(t, err)
+		require.Len(t, shares, n)
+		require.Len(t, pub.Y, attrs)
+		for j, s := range shares {
+			require.Equal(t, j, s.Index)
+			require.Equal(t, th, s.T)
+			require.Len(t, s.Y, attrs)
+		}
+
+		// Any t shares give the key behind the public key.
+		for _, subset := range [][]int{{0, 1, 2}, {2, 3, 4}, {0, 2, 4}, {0, 1, 2, 3, 4}} {
+			var picked []*KeyShare
+			for _, j := range subset {
+				picked = append(picked, shares[j])
+			}
+			priv := recoverKey(t, suite, picked, th, n)
+			got := priv.Public()
+			require.True(t, got.X.Equal(pub.X), "%v", subset)
+			for i := range pub.Y {
+				require.True(t, got.Y[i].Equal(pub.Y[i]), "%v", subset)
+			}
+
+			msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2")}
+			S, err := ps.BatchSign(suite, priv.Scalars(), msgs)
+			require.Nil(t, err)
+			require.Nil(t, ps.PSBatchVerify(suite, pub.Points(), msgs, S))
+		}
+
+		// Fewer than t shares do not.
+		few := []*share.PriShare{shares[0].PriShares()[0], shares[1].PriShares()[0]}
+		_, err = share.RecoverSecret(suite.G1(), few, th, n)
+		require.NotNil(t, err)
+	})
+}
+
+func TestThresholdKeyGenInvalid(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	for _, c := range []struct{ t, n int }{{0, 3}, {4, 3}, {-1, 3}, {1, 0}, {2, maxShares + 1}} {
+		_, _, err := ThresholdKeyGen(suite, c.t, c.n, 2, random.New())
+		require.True(t, errors.Is(err, ErrInvalidThreshold), "%d of %d", c.t, c.n)
+	}
+	_, _, err := ThresholdKeyGen(suite, 2, 3, 0, random.New())
+	require.True(t, errors.Is(err, ps.ErrKeyTooShort))
+	_, _, err = ThresholdKeyGen(nil, 2, 3, 2, random.New())
+	require.Equal(t, ps.ErrNilSuite, err)
+	_, _, err = ThresholdKeyGen(suite, 2, 3, 2, nil)
+	require.Equal(t, ps.ErrEntropyFailure, err)
+
+	// A threshold of one gives every signer the whole key.
+	shares, pub, err := ThresholdKeyGen(suite, 1, 3, 1, random.New())
+	require.Nil(t, err)
+	for _, s := range shares {
+		require.True(t, suite.G2().Point().Mul(s.X, nil).Equal(pub.X))
+	}
+}
+
+func TestKeyShareEncoding(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		shares, _, err := ThresholdKeyGen(suite, 2, 3, 3, random.New())
+		require.Nil(t, err)
+
+		for _, s := range shares {
+			buf, err := s.MarshalBinary()
+			require.Nil(t, err)
+			dec, err := UnmarshalKeyShare(suite, buf)
+			require.Nil(t, err)
+			require.Equal(t, s.Index, dec.Index)
+			require.Equal(t, s.T, dec.T)
+			require.True(t, s.X.Equal(dec.X))
+			for i := range s.Y {
+				require.True(t, s.Y[i].Equal(dec.Y[i]))
+			}
+
+			// Without a suite, the identifier in the encoding selects it.
+			var bare KeyShare
+			require.Nil(t, bare.UnmarshalBinary(buf))
+			require.Equal(t, suite.G1().String(), bare.Suite().G1().String())
+
+			_, err = UnmarshalKeyShare(suite, buf[:len(buf)-1])
+			require.NotNil(t, err)
+			_, err = UnmarshalKeyShare(suite, append(buf, 0))
+			require.NotNil(t, err)
+			_, err = UnmarshalKeyShare(suite, buf[:5])
+			require.NotNil(t, err)
+		}
+
+		other := pairing.NewSuiteBn256()
+		if suite.G1().String() == other.G1().String() {
+			other = nil
+		}
+		if other != nil {
+			buf, err := shares[0].MarshalBinary()
+			require.Nil(t, err)
+			_, err = UnmarshalKeyShare(other, buf)
+			require.True(t, errors.Is(err, ps.ErrSuiteMismatch))
+		}
+
+		_, err = (&KeyShare{}).MarshalBinary()
+		require.Equal(t, ps.ErrNilKey, err)
+		var nilShare *KeyShare
+		require.Nil(t, nilShare.Suite())
+		require.Nil(t, nilShare.PriShares())
+	})
+}
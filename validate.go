@@ -0,0 +1,107 @@
+package ps
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// A public key received from elsewhere is only as good as its structure. A
+// point outside the prime-order subgroup breaks the security argument of the
+// scheme, an identity X or Y_i makes the corresponding secret zero, and two
+// equal components Y_i = Y_j make slots i and j interchangeable: a signature
+// on (..., m_i, ..., m_j, ...) then also verifies with m_i and m_j swapped.
+// ValidatePublicKey rejects such keys; NewVerifier and the decoders of
+// public keys run it on every key they accept.
+
+// ErrWeakPublicKey is returned for public keys with identity or repeated
+// components.
+var ErrWeakPublicKey = errors.New("ps: weak public key")
+
+// Checks made by ValidatePublicKey, as named by KeyValidationError.Check.
+const (
+	CheckAttributeCount = "attribute count"
+	CheckSubgroup       = "subgroup"
+	CheckIdentity       = "identity"
+	CheckDuplicate      = "duplicate"
+)
+
+// KeyValidationError reports the check a public key failed. Index is the
+// failing component in the layout of PublicKey.Points, 0 for X and i for
+// Y_i, or -1 for the key as a whole. For CheckDuplicate it is the later of
+// the two equal components and Other the earlier one.
+type KeyValidationError struct {
+	Check string
+	Index int
+	Other int
+	Err   error
+}
+
+func (e *KeyValidationError) Error() string {
+	switch {
+	case e.Index < 0:
+		return fmt.Sprintf("%v (%s check)", e.Err, e.Check)
+	case e.Check == CheckDuplicate:
+		return fmt.Sprintf("%v (%s check, components %d and %d)", e.Err, e.Check, e.Other, e.Index)
+	default:
+		return fmt.Sprintf("%v (%s check, component %d)", e.Err, e.Check, e.Index)
+	}
+}
+
+// Unwrap returns the underlying error, so that errors.Is matches
+// ErrKeyLengthMismatch, ErrInvalidPoint or ErrWeakPublicKey.
+func (e *KeyValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePublicKey checks that pub is a key of suite with expectedAttrs Y
+// components, or any number if expectedAttrs is 0, whose points all lie in
+// the prime-order subgroup of G2, none of them the identity and no two Y_i
+// equal. A failed check is reported as a *KeyValidationError.
+func ValidatePublicKey(suite pairing.Suite, pub *PublicKey, expectedAttrs int) error {
+	if suite == nil {
+		return ErrNilSuite
+	}
+	if err := pub.check(); err != nil {
+		return err
+	}
+	if pub.suite.G2().String() != suite.G2().String() {
+		return ErrSuiteMismatch
+	}
+	return validatePublicKey(pub, expectedAttrs, true)
+}
+
+// validatePublicKey implements ValidatePublicKey for a complete key. The
+// subgroup check is left out unless subgroup is set, for points that have
+// already been through unmarshalPoint.
+func validatePublicKey(pub *PublicKey, expectedAttrs int, subgroup bool) error {
+	if expectedAttrs < 0 || expectedAttrs > 0 && len(pub.Y) != expectedAttrs {
+		return &KeyValidationError{Check: CheckAttributeCount, Index: -1,
+			Err: fmt.Errorf("%w: key has %d attributes, want %d", ErrKeyLengthMismatch, len(pub.Y), expectedAttrs)}
+	}
+	g := pub.suite.G2()
+	seen := make(map[string]int, len(pub.Y))
+	for i, p := range pub.Points() {
+		if subgroup {
+			if err := checkSubgroup(g, p); err != nil {
+				return &KeyValidationError{Check: CheckSubgroup, Index: i, Err: err}
+			}
+		}
+		if isIdentity(g, p) {
+			return &KeyValidationError{Check: CheckIdentity, Index: i, Err: ErrWeakPublicKey}
+		}
+		if i == 0 {
+			continue
+		}
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if j, ok := seen[string(b)]; ok {
+			return &KeyValidationError{Check: CheckDuplicate, Index: i, Other: j, Err: ErrWeakPublicKey}
+		}
+		seen[string(b)] = i
+	}
+	return nil
+}
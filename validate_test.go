@@ -0,0 +1,106 @@
+package ps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// requireKeyCheck asserts that err is a *KeyValidationError of check at
+// component index, wrapping want.
+func requireKeyCheck(t *testing.T, err error, check string, index int, want error) {
+	var ke *KeyValidationError
+	require.True(t, errors.As(err, &ke), "%v", err)
+	require.Equal(t, check, ke.Check)
+	require.Equal(t, index, ke.Index)
+	require.True(t, errors.Is(err, want))
+}
+
+func TestValidatePublicKey(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 3)
+		require.Nil(t, ValidatePublicKey(suite, pub, 0))
+		require.Nil(t, ValidatePublicKey(suite, pub, 3))
+
+		err := ValidatePublicKey(suite, pub, 2)
+		requireKeyCheck(t, err, CheckAttributeCount, -1, ErrKeyLengthMismatch)
+
+		weak := func(f func(points []kyber.Point)) *PublicKey {
+			points := pub.Points()
+			f(points)
+			k, err := NewPublicKey(suite, points)
+			require.Nil(t, err)
+			return k
+		}
+		identityX := weak(func(p []kyber.Point) { p[0] = suite.G2().Point().Null() })
+		requireKeyCheck(t, ValidatePublicKey(suite, identityX, 3), CheckIdentity, 0, ErrWeakPublicKey)
+		identityY := weak(func(p []kyber.Point) { p[2] = suite.G2().Point().Null() })
+		requireKeyCheck(t, ValidatePublicKey(suite, identityY, 3), CheckIdentity, 2, ErrWeakPublicKey)
+
+		duplicate := weak(func(p []kyber.Point) { p[3] = p[1].Clone() })
+		err = ValidatePublicKey(suite, duplicate, 3)
+		requireKeyCheck(t, err, CheckDuplicate, 3, ErrWeakPublicKey)
+		var ke *KeyValidationError
+		require.True(t, errors.As(err, &ke))
+		require.Equal(t, 1, ke.Other)
+
+		// X may equal a Y_i: that makes no two slots interchangeable.
+		require.Nil(t, ValidatePublicKey(suite, weak(func(p []kyber.Point) { p[1] = p[0].Clone() }), 3))
+	})
+}
+
+func TestValidatePublicKeyDefaults(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		_, pub := newTestKeys(t, suite, 2)
+		points := pub.Points()
+		points[1] = suite.G2().Point().Null()
+		weak, err := NewPublicKey(suite, points)
+		require.Nil(t, err)
+
+		_, err = NewVerifier(suite, weak)
+		requireKeyCheck(t, err, CheckIdentity, 1, ErrWeakPublicKey)
+
+		buf, err := weak.MarshalBinary()
+		require.Nil(t, err)
+		_, err = UnmarshalPublicKey(suite, buf)
+		requireKeyCheck(t, err, CheckIdentity, 1, ErrWeakPublicKey)
+
+		points[1] = points[2].Clone()
+		dup, err := NewPublicKey(suite, points)
+		require.Nil(t, err)
+		_, err = NewVerifier(suite, dup)
+		requireKeyCheck(t, err, CheckDuplicate, 2, ErrWeakPublicKey)
+		buf, err = dup.MarshalBinary()
+		require.Nil(t, err)
+		_, err = UnmarshalPublicKey(suite, buf)
+		requireKeyCheck(t, err, CheckDuplicate, 2, ErrWeakPublicKey)
+	})
+}
+
+func TestValidatePublicKeySubgroup(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	p := suite.G2().Point()
+	if p.UnmarshalBinary(twistPointOutsideG2(t)) != nil {
+		t.Skip("the bn256 decoder already rejects points outside G2")
+	}
+	_, pub := newTestKeys(t, suite, 2)
+	points := pub.Points()
+	points[2] = p
+	k, err := NewPublicKey(suite, points)
+	require.Nil(t, err)
+	requireKeyCheck(t, ValidatePublicKey(suite, k, 2), CheckSubgroup, 2, ErrInvalidPoint)
+	_, err = NewVerifier(suite, k)
+	requireKeyCheck(t, err, CheckSubgroup, 2, ErrInvalidPoint)
+}
+
+func TestValidatePublicKeySuite(t *testing.T) {
+	_, pub := newTestKeys(t, pairing.NewSuiteBn256(), 1)
+	for _, ts := range testSuites {
+		if ts.suite.G2().String() != pub.suite.G2().String() {
+			require.Equal(t, ErrSuiteMismatch, ValidatePublicKey(ts.suite, pub, 1))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ps
+
+import (
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Verifier checks signatures under one public key, for services verifying
+// many signatures against a few keys. It validates the key, see
+// ValidatePublicKey, and precomputes fixed-base tables for its Y components
+// once, so that accumulating the messages into X * prod Y_i^(m_i) costs
+// additions only. A Verifier is
+// immutable and safe for concurrent use by multiple goroutines.
+type Verifier struct {
+	suite pairing.Suite
+	o     *options
+	x     kyber.Point
+	g2    kyber.Point
+	y     []*fixedBaseTable
+	ob    observer
+}
+
+// NewVerifier returns a verifier for pubKey. The options given, e.g. WithDST,
+// ValidatePoints or WithConfig, apply to every verification.
+func NewVerifier(suite pairing.Suite, pubKey *PublicKey, opts ...Option) (*Verifier, error) {
+	o, err := newOptions(suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidatePublicKey(suite, pubKey, 0); err != nil {
+		return nil, err
+	}
+	v := &Verifier{
+		suite: suite,
+		o:     o,
+		x:     pubKey.X.Clone(),
+		g2:    suite.G2().Point().Base(),
+		y:     make([]*fixedBaseTable, len(pubKey.Y)),
+		ob:    observer{o.inst},
+	}
+	for i, y := range pubKey.Y {
+		if v.y[i], err = newFixedBaseTable(suite.G2(), y); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Verify checks a signature S on msg, as the free function Verify does.
+func (v *Verifier) Verify(msg []byte, S [][]byte) error {
+	start := v.ob.start()
+	err := checkMessages(msg)
+	if err == nil {
+		err = v.verify([][]byte{msg}, S)
+	}
+	v.ob.verified(start, err, 1)
+	return err
+}
+
+// BatchVerify checks a signature S on msgs, as PSBatchVerify does.
+func (v *Verifier) BatchVerify(msgs [][]byte, S [][]byte) error {
+	start := v.ob.start()
+	err := v.batchVerify(msgs, S)
+	v.ob.verified(start, err, len(msgs))
+	return err
+}
+
+func (v *Verifier) batchVerify(msgs [][]byte, S [][]byte) error {
+	if err := checkMessageCount(len(v.y)+1, len(msgs)); err != nil {
+		return err
+	}
+	if err := checkMessages(msgs...); err != nil {
+		return err
+	}
+	if err := v.o.checkAttributes(len(msgs)); err != nil {
+		return err
+	}
+	return v.verify(msgs, S)
+}
+
+func (v *Verifier) verify(msgs [][]byte, S [][]byte) error {
+	if S == nil {
+		return ErrNilSignature
+	}
+	X := v.x.Clone()
+	for i, msg := range msgs {
+		p, err := v.y[i].mul(hashToScalar(v.suite, v.o.dst, msg))
+		if err != nil {
+			return err
+		}
+		X.Add(X, p)
+	}
+	return verifyAgainst(v.suite, X, v.g2, S, v.o.validatePoints)
+}
@@ -0,0 +1,102 @@
+package ps
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestFixedBaseTable(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		p := suite.G2().Point().Pick(random.New())
+		table, err := newFixedBaseTable(suite.G2(), p)
+		require.Nil(t, err)
+		for _, s := range []int64{0, 1, 15, 16, 255, -1} {
+			got, err := table.mul(newScalar(suite).SetInt64(s))
+			require.Nil(t, err)
+			require.True(t, got.Equal(suite.G2().Point().Mul(newScalar(suite).SetInt64(s), p)), "%d", s)
+		}
+		for i := 0; i < 8; i++ {
+			s := newScalar(suite).Pick(random.New())
+			got, err := table.mul(s)
+			require.Nil(t, err)
+			require.True(t, got.Equal(suite.G2().Point().Mul(s, p)))
+		}
+	})
+}
+
+func TestVerifier(t *testing.T) {
+	forEachSuite(t, func(t *testing.T, suite pairing.Suite) {
+		msgs := [][]byte{[]byte("attribute 1"), []byte("attribute 2"), []byte("attribute 3")}
+		priv, pub := newTestKeys(t, suite, len(msgs))
+		v, err := NewVerifier(suite, pub)
+		require.Nil(t, err)
+
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(t, err)
+		single, err := Sign(suite, priv.Scalars(), msgs[0])
+		require.Nil(t, err)
+
+		// Concurrent use gives the results of the free functions.
+		var wg sync.WaitGroup
+		errs := make([]error, 4*4)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[4*i] = v.BatchVerify(msgs, S)
+				errs[4*i+1] = v.Verify(msgs[0], single)
+				errs[4*i+2] = v.Verify(msgs[1], single)
+				errs[4*i+3] = v.BatchVerify([][]byte{msgs[1], msgs[0], msgs[2]}, S)
+			}(i)
+		}
+		wg.Wait()
+		for i := 0; i < len(errs); i += 4 {
+			require.Nil(t, errs[i])
+			require.Nil(t, errs[i+1])
+			require.Equal(t, ErrInvalidSignature, errs[i+2])
+			require.Equal(t, ErrInvalidSignature, errs[i+3])
+		}
+		require.Equal(t, PSBatchVerify(suite, pub.Points(), msgs[:2], S), v.BatchVerify(msgs[:2], S))
+
+		// Options are fixed at construction.
+		tagged, err := NewVerifier(suite, pub, WithDST([]byte("other")))
+		require.Nil(t, err)
+		require.Equal(t, ErrInvalidSignature, tagged.Verify(msgs[0], single))
+		require.Equal(t, ErrNilSignature, v.Verify(msgs[0], nil))
+		require.Equal(t, ErrEmptyMessage, v.Verify(nil, single))
+		_, err = NewVerifier(suite, pub, WithDST(nil))
+		require.Equal(t, ErrEmptyDST, err)
+	})
+}
+
+func BenchmarkVerifier(b *testing.B) {
+	benchEachSuite(b, func(b *testing.B, suite pairing.Suite) {
+		msgs := make([][]byte, 10)
+		for i := range msgs {
+			msgs[i] = []byte("attribute")
+		}
+		priv, pub := newTestKeys(b, suite, len(msgs))
+		S, err := BatchSign(suite, priv.Scalars(), msgs)
+		require.Nil(b, err)
+		v, err := NewVerifier(suite, pub)
+		require.Nil(b, err)
+		b.Run("PSBatchVerify", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := PSBatchVerify(suite, pub.Points(), msgs, S); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("Verifier", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := v.BatchVerify(msgs, S); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}